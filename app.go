@@ -10,7 +10,9 @@ import (
 	"time"
 
 	"github.com/run-bigpig/jcp/internal/adk"
+	"github.com/run-bigpig/jcp/internal/adk/llmcache"
 	"github.com/run-bigpig/jcp/internal/adk/mcp"
+	"github.com/run-bigpig/jcp/internal/adk/ollama"
 	"github.com/run-bigpig/jcp/internal/adk/tools"
 	"github.com/run-bigpig/jcp/internal/agent"
 	"github.com/run-bigpig/jcp/internal/logger"
@@ -18,12 +20,14 @@ import (
 	"github.com/run-bigpig/jcp/internal/memory"
 	"github.com/run-bigpig/jcp/internal/models"
 	"github.com/run-bigpig/jcp/internal/openclaw"
+	"github.com/run-bigpig/jcp/internal/pkg/klinechart"
 	"github.com/run-bigpig/jcp/internal/pkg/paths"
 	"github.com/run-bigpig/jcp/internal/pkg/proxy"
 	"github.com/run-bigpig/jcp/internal/services"
 	"github.com/run-bigpig/jcp/internal/services/hottrend"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"google.golang.org/adk/model"
 )
 
 var log = logger.New("app")
@@ -44,6 +48,18 @@ type App struct {
 	f10Service        *services.F10Service
 	hotTrendService   *hottrend.HotTrendService
 	longHuBangService *services.LongHuBangService
+	screenerService   *services.ScreenerService
+	reviewService     *services.MarketReviewService
+	sentimentService  *services.MarketSentimentService
+	consensusService  *services.ConsensusService
+	announceWatcher   *services.AnnouncementWatcher
+	earningsService   *services.EarningsCalendarService
+	riskRadarService  *services.RiskRadarService
+	lhbSeatService    *services.LhbSeatService
+	northboundService *services.NorthboundService
+	marginService     *services.MarginService
+	peerService       *services.PeerService
+	llmCache          *llmcache.Cache
 	marketPusher      *services.MarketDataPusher
 	meetingService    *meeting.Service
 	sessionService    *services.SessionService
@@ -62,6 +78,10 @@ type App struct {
 	coreContextCacheTTL time.Duration
 	coreContextCache    map[string]coreContextCacheEntry
 	coreContextCacheMu  sync.RWMutex
+
+	// Ollama 模型拉取状态
+	ollamaPulls   map[string]ollama.PullStatus
+	ollamaPullsMu sync.RWMutex
 }
 
 // NewApp creates a new App application struct
@@ -79,6 +99,16 @@ func NewApp() *App {
 	if err != nil {
 		panic(err)
 	}
+	// 路由模型按 ID 解析成员配置，始终读取最新配置
+	adk.SetAIConfigLookup(func(id string) *models.AIConfig {
+		cfg := configService.GetConfig()
+		for i := range cfg.AIConfigs {
+			if cfg.AIConfigs[i].ID == id {
+				return &cfg.AIConfigs[i]
+			}
+		}
+		return nil
+	})
 
 	// 初始化研报服务
 	researchReportService := services.NewResearchReportService()
@@ -101,6 +131,62 @@ func NewApp() *App {
 	// 初始化工具注册中心
 	toolRegistry := tools.NewRegistry(marketService, newsService, configService, researchReportService, f10Service, hotTrendSvc, longHuBangService)
 
+	// 初始化条件选股服务
+	screenerService := services.NewScreenerService(dataDir, marketService, f10Service)
+	toolRegistry.SetScreenerService(screenerService)
+
+	// 初始化市场情绪服务
+	sentimentService := services.NewMarketSentimentService(dataDir, marketService)
+	toolRegistry.SetSentimentService(sentimentService)
+
+	// 初始化分析师一致预期服务
+	consensusService := services.NewConsensusService(dataDir, researchReportService)
+	toolRegistry.SetConsensusService(consensusService)
+
+	// 初始化研报索引服务
+	reportIndexService := services.NewReportIndexService(dataDir, researchReportService)
+	toolRegistry.SetReportIndexService(reportIndexService)
+
+	// 初始化自选股公告监控服务
+	announceWatcher := services.NewAnnouncementWatcher(dataDir, marketService, configService)
+
+	// 初始化龙虎榜席位数据库服务
+	lhbSeatService := services.NewLhbSeatService(dataDir, longHuBangService, marketService)
+	toolRegistry.SetLhbSeatService(lhbSeatService)
+
+	// 初始化北向资金与融资融券服务
+	northboundService := services.NewNorthboundService(f10Service)
+	toolRegistry.SetNorthboundService(northboundService)
+	marginService := services.NewMarginService(f10Service)
+	toolRegistry.SetMarginService(marginService)
+
+	// 初始化同业对比服务
+	peerService := services.NewPeerService(f10Service, marketService)
+	toolRegistry.SetPeerService(peerService)
+
+	// 初始化 LLM 响应缓存（收盘后行情不再变化，使用更长的有效期）
+	llmCache, err := llmcache.New(filepath.Join(dataDir, "llm_cache"))
+	if err != nil {
+		log.Warn("LLM cache init error: %v", err)
+		llmCache = nil
+	} else {
+		llmCache.SetEnabled(configService.GetConfig().LLMCache.Enabled)
+		llmCache.SetTTLFunc(func() time.Duration {
+			cfg := configService.GetConfig().LLMCache
+			if marketService.GetMarketStatus().Status == "closed" {
+				return time.Duration(cfg.ClosedTTLMinutes) * time.Minute
+			}
+			return time.Duration(cfg.TradingTTLMinutes) * time.Minute
+		})
+		adk.SetResponseCache(llmCache)
+	}
+
+	// 初始化财报日历服务
+	earningsService := services.NewEarningsCalendarService(dataDir, f10Service, consensusService, configService)
+
+	// 初始化每日复盘服务
+	reviewService := services.NewMarketReviewService(dataDir, marketService, longHuBangService, newsService, hotTrendSvc)
+
 	// 初始化 MCP 管理器
 	mcpManager := mcp.NewManager()
 	if err := mcpManager.LoadConfigs(configService.GetConfig().MCPServers); err != nil {
@@ -154,6 +240,9 @@ func NewApp() *App {
 	// 初始化Session服务
 	sessionService := services.NewSessionService(dataDir)
 
+	// 初始化风险雷达服务
+	riskRadarService := services.NewRiskRadarService(dataDir, f10Service, marketService, configService, sessionService)
+
 	// 初始化策略服务
 	strategyService := services.NewStrategyService(dataDir)
 
@@ -196,6 +285,18 @@ func NewApp() *App {
 		f10Service:          f10Service,
 		hotTrendService:     hotTrendSvc,
 		longHuBangService:   longHuBangService,
+		screenerService:     screenerService,
+		reviewService:       reviewService,
+		sentimentService:    sentimentService,
+		consensusService:    consensusService,
+		announceWatcher:     announceWatcher,
+		earningsService:     earningsService,
+		riskRadarService:    riskRadarService,
+		lhbSeatService:      lhbSeatService,
+		northboundService:   northboundService,
+		marginService:       marginService,
+		peerService:         peerService,
+		llmCache:            llmCache,
 		meetingService:      meetingService,
 		sessionService:      sessionService,
 		strategyService:     strategyService,
@@ -206,6 +307,7 @@ func NewApp() *App {
 		updateService:       updateService,
 		openClawServer:      openClawServer,
 		meetingCancels:      make(map[string]context.CancelFunc),
+		ollamaPulls:         make(map[string]ollama.PullStatus),
 		coreContextCacheTTL: defaultCoreContextCacheTTL,
 		coreContextCache:    make(map[string]coreContextCacheEntry),
 	}
@@ -229,6 +331,7 @@ func (a *App) startup(ctx context.Context) {
 	// 设置 Meeting 服务的 AI 配置解析器
 	if a.meetingService != nil {
 		a.meetingService.SetAIConfigResolver(a.getAIConfigByID)
+		a.meetingService.SetChartProvider(a.renderKLineChart)
 	}
 
 	// 初始化更新服务
@@ -241,6 +344,52 @@ func (a *App) startup(ctx context.Context) {
 	a.marketPusher.Start(ctx)
 	log.Info("市场数据推送服务已启动")
 
+	// 启动定时选股任务
+	if a.screenerService != nil {
+		a.screenerService.SetResultHandler(func(result models.ScreenResult) {
+			runtime.EventsEmit(a.ctx, "screener:result", result)
+		})
+		a.screenerService.Start(ctx)
+	}
+
+	// 启动收盘情绪记录任务
+	if a.sentimentService != nil {
+		a.sentimentService.Start()
+	}
+
+	// 启动自选股公告监控
+	if a.announceWatcher != nil {
+		a.announceWatcher.SetEventHandler(a.handleAnnouncementEvent)
+		a.announceWatcher.SetLLMFactory(func(ctx context.Context) (model.LLM, error) {
+			aiConfig := a.getModeratorAIConfig(a.configService.GetConfig())
+			if aiConfig == nil {
+				return nil, fmt.Errorf("no AI config found")
+			}
+			return adk.NewModelFactory().CreateModel(ctx, aiConfig)
+		})
+		a.announceWatcher.Start(ctx)
+	}
+
+	// 启动财报日历任务
+	if a.earningsService != nil {
+		a.earningsService.SetBriefingHandler(a.runEarningsBriefing)
+		a.earningsService.SetComparisonHandler(a.handleEarningsComparison)
+		a.earningsService.Start()
+	}
+
+	// 启动风险雷达定时扫描
+	if a.riskRadarService != nil {
+		a.riskRadarService.SetAlertHandler(func(alert models.RiskAlert) {
+			runtime.EventsEmit(a.ctx, "risk:alert", alert)
+		})
+		a.riskRadarService.Start()
+	}
+
+	// 启动龙虎榜席位采集任务
+	if a.lhbSeatService != nil {
+		a.lhbSeatService.Start()
+	}
+
 	// 启动 OpenClaw 服务（如果已启用）
 	cfg := a.configService.GetConfig()
 	if cfg.OpenClaw.Enabled && cfg.OpenClaw.Port > 0 {
@@ -259,6 +408,24 @@ func (a *App) shutdown(ctx context.Context) {
 	if a.marketPusher != nil {
 		a.marketPusher.Stop()
 	}
+	if a.screenerService != nil {
+		a.screenerService.Stop()
+	}
+	if a.sentimentService != nil {
+		a.sentimentService.Stop()
+	}
+	if a.announceWatcher != nil {
+		a.announceWatcher.Stop()
+	}
+	if a.earningsService != nil {
+		a.earningsService.Stop()
+	}
+	if a.riskRadarService != nil {
+		a.riskRadarService.Stop()
+	}
+	if a.lhbSeatService != nil {
+		a.lhbSeatService.Stop()
+	}
 	logger.Close()
 }
 
@@ -313,6 +480,9 @@ func (a *App) UpdateConfig(config *models.AppConfig) string {
 		a.meetingService.SetAgentSelectionStyle(config.AgentSelectionStyle)
 		a.meetingService.SetEnableSecondReview(config.EnableSecondReview)
 	}
+	if a.llmCache != nil {
+		a.llmCache.SetEnabled(config.LLMCache.Enabled)
+	}
 	// 更新 OpenClaw 服务配置（热更新）
 	a.applyOpenClawConfig(&config.OpenClaw)
 	return "success"
@@ -351,6 +521,26 @@ func (a *App) GetOpenClawStatus() map[string]any {
 	}
 }
 
+// GetLLMCacheStats 获取 LLM 响应缓存命中统计
+func (a *App) GetLLMCacheStats() llmcache.Stats {
+	if a.llmCache == nil {
+		return llmcache.Stats{}
+	}
+	return a.llmCache.Stats()
+}
+
+// ClearLLMCache 清空 LLM 响应缓存
+func (a *App) ClearLLMCache() string {
+	if a.llmCache == nil {
+		return "LLM 缓存未初始化"
+	}
+	if err := a.llmCache.Clear(); err != nil {
+		log.Error("clear llm cache error: %v", err)
+		return err.Error()
+	}
+	return "success"
+}
+
 // mergeRealtimeStock 合并实时行情字段，保留本地静态字段
 func (a *App) mergeRealtimeStock(base models.Stock, rt models.Stock) models.Stock {
 	merged := base
@@ -442,6 +632,18 @@ func (a *App) GetKLineData(code string, period string, days int) []models.KLineD
 	return data
 }
 
+// chartKLineDays 专家附图取数天数，多出的部分用于指标预热
+const chartKLineDays = 180
+
+// renderKLineChart 渲染日K线图 PNG，指标按当前配置绘制
+func (a *App) renderKLineChart(code string) ([]byte, error) {
+	klines, err := a.marketService.GetKLineData(code, "1d", chartKLineDays)
+	if err != nil {
+		return nil, err
+	}
+	return klinechart.RenderPNG(klines, klinechart.Options{Indicators: a.configService.GetConfig().Indicators})
+}
+
 // GetOrderBook 获取盘口数据（真实五档）
 func (a *App) GetOrderBook(code string) models.OrderBook {
 	orderBook, _ := a.marketService.GetRealOrderBook(code)
@@ -494,6 +696,30 @@ func (a *App) GetF10Valuation(code string) models.StockValuation {
 	return valuation
 }
 
+// GetFinancialAnalysis 获取财务分析（比率、杜邦、TTM与异常信号）
+func (a *App) GetFinancialAnalysis(code string) models.FinancialAnalysis {
+	if a.f10Service == nil {
+		return models.FinancialAnalysis{Code: code}
+	}
+	result, err := a.f10Service.GetFinancialAnalysisByCode(code)
+	if err != nil {
+		log.Error("GetFinancialAnalysis error: %v", err)
+	}
+	return result
+}
+
+// GetIntrinsicValue 估算内在价值（DCF、DDM、PE/PB估值带）
+func (a *App) GetIntrinsicValue(code string, params models.ValuationParams) models.IntrinsicValuation {
+	if a.f10Service == nil {
+		return models.IntrinsicValuation{Code: code, Errors: map[string]string{"service": "F10 服务未初始化"}}
+	}
+	result, err := a.f10Service.GetIntrinsicValue(code, params)
+	if err != nil {
+		log.Error("GetIntrinsicValue error: %v", err)
+	}
+	return result
+}
+
 // getDefaultAIConfig 获取默认AI配置
 func (a *App) getDefaultAIConfig(config *models.AppConfig) *models.AIConfig {
 	for i := range config.AIConfigs {
@@ -510,6 +736,18 @@ func (a *App) getDefaultAIConfig(config *models.AppConfig) *models.AIConfig {
 	return nil
 }
 
+// getModeratorAIConfig 获取小韭菜使用的AI配置，未单独配置时返回默认配置
+func (a *App) getModeratorAIConfig(config *models.AppConfig) *models.AIConfig {
+	if config.ModeratorAIID != "" {
+		for i := range config.AIConfigs {
+			if config.AIConfigs[i].ID == config.ModeratorAIID {
+				return &config.AIConfigs[i]
+			}
+		}
+	}
+	return a.getDefaultAIConfig(config)
+}
+
 // getAIConfigByID 根据ID获取AI配置，找不到则返回默认配置
 func (a *App) getAIConfigByID(aiConfigID string) *models.AIConfig {
 	config := a.configService.GetConfig()
@@ -582,15 +820,18 @@ func (a *App) GetAgentConfigs() []models.AgentConfig {
 // AddAgentConfig 添加Agent配置到当前策略
 func (a *App) AddAgentConfig(config models.AgentConfig) string {
 	agent := models.StrategyAgent{
-		ID:          config.ID,
-		Name:        config.Name,
-		Role:        config.Role,
-		Avatar:      config.Avatar,
-		Color:       config.Color,
-		Instruction: config.Instruction,
-		Tools:       config.Tools,
-		MCPServers:  config.MCPServers,
-		Enabled:     config.Enabled,
+		ID:           config.ID,
+		Name:         config.Name,
+		Role:         config.Role,
+		Avatar:       config.Avatar,
+		Color:        config.Color,
+		Instruction:  config.Instruction,
+		Tools:        config.Tools,
+		MCPServers:   config.MCPServers,
+		Enabled:      config.Enabled,
+		Reasoning:    config.Reasoning,
+		Vision:       config.Vision,
+		MCPResources: config.MCPResources,
 	}
 	if err := a.strategyService.AddAgentToActiveStrategy(agent); err != nil {
 		return err.Error()
@@ -602,15 +843,18 @@ func (a *App) AddAgentConfig(config models.AgentConfig) string {
 // UpdateAgentConfig 更新当前策略中的Agent配置
 func (a *App) UpdateAgentConfig(config models.AgentConfig) string {
 	agent := models.StrategyAgent{
-		ID:          config.ID,
-		Name:        config.Name,
-		Role:        config.Role,
-		Avatar:      config.Avatar,
-		Color:       config.Color,
-		Instruction: config.Instruction,
-		Tools:       config.Tools,
-		MCPServers:  config.MCPServers,
-		Enabled:     config.Enabled,
+		ID:           config.ID,
+		Name:         config.Name,
+		Role:         config.Role,
+		Avatar:       config.Avatar,
+		Color:        config.Color,
+		Instruction:  config.Instruction,
+		Tools:        config.Tools,
+		MCPServers:   config.MCPServers,
+		Enabled:      config.Enabled,
+		Reasoning:    config.Reasoning,
+		Vision:       config.Vision,
+		MCPResources: config.MCPResources,
 	}
 	if err := a.strategyService.UpdateAgentInActiveStrategy(agent); err != nil {
 		return err.Error()
@@ -840,6 +1084,7 @@ type MeetingMessageRequest struct {
 	MentionIds   []string `json:"mentionIds"`
 	ReplyToId    string   `json:"replyToId"`
 	ReplyContent string   `json:"replyContent"`
+	NoCache      bool     `json:"noCache"` // 本次会议跳过 LLM 响应缓存
 }
 
 // cancelMeetingInternal 内部取消会议方法
@@ -868,21 +1113,39 @@ func (a *App) SendMeetingMessage(req MeetingMessageRequest) []models.ChatMessage
 		return []models.ChatMessage{}
 	}
 
-	// 取消之前该股票的会议（如果有）
-	a.cancelMeetingInternal(req.StockCode)
+	// 取消之前该股票的会议（如果有）并登记本次会议
+	meetingCtx, _ := a.claimMeeting(req.StockCode, true)
+	defer a.releaseMeeting(req.StockCode)
+	return a.sendMeetingMessage(meetingCtx, req)
+}
 
-	// 创建可取消的 context
-	meetingCtx, cancel := context.WithCancel(a.ctx)
+// claimMeeting 在同一把锁内检查并登记股票会议；preempt 为 false 且已有会议进行中时返回 false
+func (a *App) claimMeeting(stockCode string, preempt bool) (context.Context, bool) {
+	a.meetingCancelsMu.Lock()
+	defer a.meetingCancelsMu.Unlock()
+	if cancel, ok := a.meetingCancels[stockCode]; ok {
+		if !preempt {
+			return nil, false
+		}
+		cancel()
+	}
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.meetingCancels[stockCode] = cancel
+	return ctx, true
+}
+
+// releaseMeeting 会议结束后清理登记
+func (a *App) releaseMeeting(stockCode string) {
 	a.meetingCancelsMu.Lock()
-	a.meetingCancels[req.StockCode] = cancel
+	delete(a.meetingCancels, stockCode)
 	a.meetingCancelsMu.Unlock()
+}
 
-	// 会议结束后清理
-	defer func() {
-		a.meetingCancelsMu.Lock()
-		delete(a.meetingCancels, req.StockCode)
-		a.meetingCancelsMu.Unlock()
-	}()
+// sendMeetingMessage 在已登记的会议 context 中处理消息
+func (a *App) sendMeetingMessage(meetingCtx context.Context, req MeetingMessageRequest) []models.ChatMessage {
+	if req.NoCache {
+		meetingCtx = llmcache.WithBypass(meetingCtx)
+	}
 
 	// 先保存用户消息
 	userMsg := models.ChatMessage{
@@ -1028,127 +1291,517 @@ func (a *App) convertSaveAndEmitResponses(stockCode string, responses []meeting.
 	return messages
 }
 
-func (a *App) buildCoreContext(stockCode string, stock models.Stock, position *models.StockPosition) string {
-	var sections []string
+// CompareMeetingRequest 多股对比会议请求
+type CompareMeetingRequest struct {
+	StockCodes []string `json:"stockCodes"`
+	Content    string   `json:"content"`
+	NoCache    bool     `json:"noCache"` // 本次会议跳过 LLM 响应缓存
+}
 
-	if section := buildCoreQuoteSection(stock); section != "" {
-		sections = append(sections, section)
+// normalizeCompareCodes 去除空白与重复的股票代码（保持原顺序）
+func normalizeCompareCodes(codes []string) []string {
+	seen := make(map[string]bool, len(codes))
+	result := make([]string, 0, len(codes))
+	for _, code := range codes {
+		code = strings.ToLower(strings.TrimSpace(code))
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		result = append(result, code)
 	}
-	if section := buildCorePositionSection(position, stock); section != "" {
-		sections = append(sections, section)
+	return result
+}
+
+// fetchCompareStocks 获取对比标的行情（按代码顺序返回，缺失的以代码占位）
+func (a *App) fetchCompareStocks(codes []string) []models.Stock {
+	quotes, err := a.marketService.GetStockRealTimeData(codes...)
+	if err != nil {
+		log.Warn("fetch compare stocks error: %v", err)
 	}
-	if a.marketService != nil {
-		if section := buildCoreMarketStatusSection(a.marketService.GetMarketStatus()); section != "" {
-			sections = append(sections, section)
-		}
+	quoteMap := make(map[string]models.Stock, len(quotes))
+	for _, q := range quotes {
+		quoteMap[q.Symbol] = q
 	}
-	if section := a.getOrBuildCoreRemoteContext(stockCode, func() (string, bool, error) {
-		return a.buildCoreRemoteContext(stockCode)
-	}); section != "" {
-		sections = append(sections, section)
+	stocks := make([]models.Stock, 0, len(codes))
+	for _, code := range codes {
+		stock, ok := quoteMap[code]
+		if !ok {
+			stock = models.Stock{Symbol: code, Name: code}
+		}
+		stocks = append(stocks, stock)
 	}
+	return stocks
+}
 
-	return strings.TrimSpace(strings.Join(sections, "\n\n"))
+// GetCompareSession 获取或创建多股对比会话
+func (a *App) GetCompareSession(stockCodes []string) *models.StockSession {
+	if a.sessionService == nil {
+		return nil
+	}
+	codes := normalizeCompareCodes(stockCodes)
+	if len(codes) < meeting.MinCompareStocks || len(codes) > meeting.MaxCompareStocks {
+		log.Warn("invalid compare stock count: %d", len(codes))
+		return nil
+	}
+	names := make([]string, 0, len(codes))
+	for _, stock := range a.fetchCompareStocks(codes) {
+		names = append(names, stock.Name)
+	}
+	session, err := a.sessionService.GetOrCreateCompareSession(codes, names)
+	if err != nil {
+		log.Error("create compare session error: %v", err)
+	}
+	return session
 }
 
-func (a *App) buildCoreRemoteContext(stockCode string) (string, bool, error) {
-	if a.marketService == nil && a.f10Service == nil {
-		return "", false, nil
+// SendCompareMeetingMessage 发送多股对比会议消息
+// 消息保存在独立的对比会话中，事件以会话键推送：meeting:message:<sessionKey>
+func (a *App) SendCompareMeetingMessage(req CompareMeetingRequest) []models.ChatMessage {
+	codes := normalizeCompareCodes(req.StockCodes)
+	if len(codes) < meeting.MinCompareStocks || len(codes) > meeting.MaxCompareStocks {
+		log.Warn("invalid compare stock count: %d", len(codes))
+		return []models.ChatMessage{}
 	}
 
-	var sections []string
-	var errs []error
-	hasRemoteData := false
+	stocks := a.fetchCompareStocks(codes)
+	names := make([]string, 0, len(stocks))
+	for _, stock := range stocks {
+		names = append(names, stock.Name)
+	}
+	session, err := a.sessionService.GetOrCreateCompareSession(codes, names)
+	if err != nil || session == nil {
+		log.Error("create compare session error: %v", err)
+		return []models.ChatMessage{}
+	}
+	sessionKey := session.StockCode
 
-	if a.marketService != nil {
-		if indices, err := a.marketService.GetMarketIndices(); err == nil {
-			if section := buildCoreIndicesSection(indices); section != "" {
-				sections = append(sections, section)
-				hasRemoteData = true
-			}
-		} else {
-			errs = append(errs, fmt.Errorf("indices: %w", err))
-		}
-		if announcements, err := a.marketService.GetStockAnnouncements(stockCode, 1, 3); err == nil {
-			if section := buildCoreAnnouncementsSection(announcements); section != "" {
-				sections = append(sections, section)
-				hasRemoteData = true
-			}
-		} else if strings.TrimSpace(stockCode) != "" {
-			errs = append(errs, fmt.Errorf("announcements: %w", err))
-		}
+	// 取消之前该对比会话的会议（如果有）
+	a.cancelMeetingInternal(sessionKey)
+	meetingCtx, cancel := context.WithCancel(a.ctx)
+	if req.NoCache {
+		meetingCtx = llmcache.WithBypass(meetingCtx)
 	}
-	if a.f10Service != nil {
-		if valuation, err := a.f10Service.GetValuationByCode(stockCode); err == nil {
-			if section := buildCoreValuationSection(valuation); section != "" {
-				sections = append(sections, section)
-				hasRemoteData = true
-			}
-		} else if strings.TrimSpace(stockCode) != "" {
-			errs = append(errs, fmt.Errorf("valuation: %w", err))
-		}
+	a.meetingCancelsMu.Lock()
+	a.meetingCancels[sessionKey] = cancel
+	a.meetingCancelsMu.Unlock()
+	defer func() {
+		a.meetingCancelsMu.Lock()
+		delete(a.meetingCancels, sessionKey)
+		a.meetingCancelsMu.Unlock()
+	}()
+
+	a.sessionService.AddMessage(sessionKey, models.ChatMessage{
+		AgentID:     "user",
+		AgentName:   "老韭菜",
+		Content:     req.Content,
+		MeetingMode: meeting.MeetingModeCompare,
+	})
+
+	config := a.configService.GetConfig()
+	aiConfig := a.getDefaultAIConfig(config)
+	if aiConfig == nil {
+		log.Warn("no AI config found")
+		return []models.ChatMessage{}
 	}
 
-	return strings.TrimSpace(strings.Join(sections, "\n\n")), hasRemoteData, errors.Join(errs...)
-}
+	// 逐只构建核心数据包（复用单股会议的数据组装与缓存）
+	compareStocks := make([]meeting.CompareStock, 0, len(codes))
+	for i, code := range codes {
+		position := a.sessionService.GetPosition(code)
+		compareStocks = append(compareStocks, meeting.CompareStock{
+			StockCode:   code,
+			Stock:       stocks[i],
+			CoreContext: a.buildCoreContext(code, stocks[i], position),
+			Position:    position,
+		})
+	}
 
-func (a *App) getOrBuildCoreRemoteContext(stockCode string, builder func() (string, bool, error)) string {
-	if strings.TrimSpace(stockCode) == "" {
-		contextText, _, _ := builder()
-		return contextText
+	compareReq := meeting.CompareRequest{
+		Stocks:     compareStocks,
+		Query:      req.Content,
+		AllAgents:  a.strategyService.GetEnabledAgents(),
+		PeerMatrix: a.buildComparePeerMatrix(codes),
 	}
 
-	if cached, ok := a.getCoreContextCache(stockCode); ok {
-		if time.Since(cached.timestamp) <= a.coreContextCacheTTL {
-			return cached.context
+	var messages []models.ChatMessage
+	respCallback := func(resp meeting.ChatResponse) {
+		msg := models.ChatMessage{
+			AgentID:     resp.AgentID,
+			AgentName:   resp.AgentName,
+			Role:        resp.Role,
+			Content:     resp.Content,
+			Round:       resp.Round,
+			MsgType:     resp.MsgType,
+			Error:       resp.Error,
+			MeetingMode: resp.MeetingMode,
 		}
+		a.sessionService.AddMessage(sessionKey, msg)
+		runtime.EventsEmit(a.ctx, "meeting:message:"+sessionKey, msg)
+		messages = append(messages, msg)
+	}
+	progressCallback := func(event meeting.ProgressEvent) {
+		runtime.EventsEmit(a.ctx, "meeting:progress:"+sessionKey, event)
 	}
 
-	contextText, hasRemoteData, err := builder()
-	if strings.TrimSpace(contextText) != "" && (err == nil || hasRemoteData) {
-		a.setCoreContextCache(stockCode, contextText)
-		return contextText
+	if _, err := a.meetingService.RunCompareMeetingWithCallback(meetingCtx, aiConfig, compareReq, respCallback, progressCallback); err != nil {
+		log.Error("runCompareMeeting error: %v", err)
 	}
-	if err != nil {
-		if cached, ok := a.getCoreContextCache(stockCode); ok && strings.TrimSpace(cached.context) != "" {
-			log.Warn("coreContext refresh failed for %s, use stale cache: %v", stockCode, err)
-			return cached.context
-		}
-		log.Warn("coreContext refresh failed for %s: %v", stockCode, err)
+	if messages == nil {
+		return []models.ChatMessage{}
 	}
-	return contextText
+	return messages
 }
 
-func (a *App) getCoreContextCache(stockCode string) (coreContextCacheEntry, bool) {
-	a.coreContextCacheMu.RLock()
-	defer a.coreContextCacheMu.RUnlock()
-	entry, ok := a.coreContextCache[stockCode]
-	return entry, ok
+// BoardMeetingRequest 板块会议请求
+type BoardMeetingRequest struct {
+	BoardCode string `json:"boardCode"`
+	BoardName string `json:"boardName"`
+	Content   string `json:"content"`
+	NoCache   bool   `json:"noCache"` // 本次会议跳过 LLM 响应缓存
 }
 
-func (a *App) setCoreContextCache(stockCode string, contextText string) {
-	a.coreContextCacheMu.Lock()
-	defer a.coreContextCacheMu.Unlock()
-	a.coreContextCache[stockCode] = coreContextCacheEntry{
-		context:   contextText,
-		timestamp: time.Now(),
+// GetBoardSession 获取或创建板块会话
+func (a *App) GetBoardSession(boardCode, boardName string) *models.StockSession {
+	if a.sessionService == nil {
+		return nil
+	}
+	session, err := a.sessionService.GetOrCreateBoardSession(boardCode, boardName)
+	if err != nil {
+		log.Error("create board session error: %v", err)
 	}
+	return session
 }
 
-func buildCoreQuoteSection(stock models.Stock) string {
-	if strings.TrimSpace(stock.Symbol) == "" && strings.TrimSpace(stock.Name) == "" {
-		return ""
+// SendBoardMeetingMessage 发送板块会议消息
+// 消息保存在板块会话中，事件以会话键推送：meeting:message:<sessionKey>
+func (a *App) SendBoardMeetingMessage(req BoardMeetingRequest) []models.ChatMessage {
+	if a.marketService == nil || a.sessionService == nil {
+		return []models.ChatMessage{}
 	}
-	lines := []string{
-		fmt.Sprintf("【标的快照】%s (%s)", stock.Name, stock.Symbol),
-		fmt.Sprintf("现价 %.2f，涨跌幅 %.2f%%，涨跌 %.2f", stock.Price, stock.ChangePercent, stock.Change),
+
+	board, err := a.marketService.GetBoardSnapshot(req.BoardCode)
+	if err != nil {
+		log.Warn("get board snapshot error: %v", err)
+		board = models.BoardFundFlowItem{Code: strings.ToUpper(strings.TrimSpace(req.BoardCode)), Name: req.BoardName}
 	}
-	rangeParts := make([]string, 0, 4)
-	if stock.Open > 0 {
-		rangeParts = append(rangeParts, fmt.Sprintf("开盘 %.2f", stock.Open))
+	if board.Name == "" {
+		board.Name = req.BoardName
 	}
-	if stock.High > 0 {
-		rangeParts = append(rangeParts, fmt.Sprintf("最高 %.2f", stock.High))
+	session, err := a.sessionService.GetOrCreateBoardSession(board.Code, board.Name)
+	if err != nil || session == nil {
+		log.Error("create board session error: %v", err)
+		return []models.ChatMessage{}
+	}
+	sessionKey := session.StockCode
+
+	a.cancelMeetingInternal(sessionKey)
+	meetingCtx, cancel := context.WithCancel(a.ctx)
+	if req.NoCache {
+		meetingCtx = llmcache.WithBypass(meetingCtx)
+	}
+	a.meetingCancelsMu.Lock()
+	a.meetingCancels[sessionKey] = cancel
+	a.meetingCancelsMu.Unlock()
+	defer func() {
+		a.meetingCancelsMu.Lock()
+		delete(a.meetingCancels, sessionKey)
+		a.meetingCancelsMu.Unlock()
+	}()
+
+	a.sessionService.AddMessage(sessionKey, models.ChatMessage{
+		AgentID:     "user",
+		AgentName:   "老韭菜",
+		Content:     req.Content,
+		MeetingMode: meeting.MeetingModeBoard,
+	})
+
+	config := a.configService.GetConfig()
+	aiConfig := a.getDefaultAIConfig(config)
+	if aiConfig == nil {
+		log.Warn("no AI config found")
+		return []models.ChatMessage{}
+	}
+
+	boardReq := meeting.BoardRequest{
+		MemoryKey:   sessionKey,
+		Board:       board,
+		Query:       req.Content,
+		CoreContext: a.buildBoardCoreContext(board),
+		AllAgents:   a.strategyService.GetEnabledAgents(),
+	}
+
+	var messages []models.ChatMessage
+	respCallback := func(resp meeting.ChatResponse) {
+		msg := models.ChatMessage{
+			AgentID:     resp.AgentID,
+			AgentName:   resp.AgentName,
+			Role:        resp.Role,
+			Content:     resp.Content,
+			Round:       resp.Round,
+			MsgType:     resp.MsgType,
+			Error:       resp.Error,
+			MeetingMode: resp.MeetingMode,
+		}
+		a.sessionService.AddMessage(sessionKey, msg)
+		runtime.EventsEmit(a.ctx, "meeting:message:"+sessionKey, msg)
+		messages = append(messages, msg)
+	}
+	progressCallback := func(event meeting.ProgressEvent) {
+		runtime.EventsEmit(a.ctx, "meeting:progress:"+sessionKey, event)
+	}
+
+	if _, err := a.meetingService.RunBoardMeetingWithCallback(meetingCtx, aiConfig, boardReq, respCallback, progressCallback); err != nil {
+		log.Error("runBoardMeeting error: %v", err)
+	}
+	if messages == nil {
+		return []models.ChatMessage{}
+	}
+	return messages
+}
+
+// buildBoardCoreContext 构建板块核心数据包（快照、涨跌分布、龙头梯队、资金曲线）
+func (a *App) buildBoardCoreContext(board models.BoardFundFlowItem) string {
+	var sections []string
+	if section := buildCoreBoardSnapshotSection(board); section != "" {
+		sections = append(sections, section)
+	}
+	sections = append(sections, buildCoreMarketStatusSection(a.marketService.GetMarketStatus()))
+	if section := a.getOrBuildCoreRemoteContext(services.BoardSessionKey(board.Code), func() (string, bool, error) {
+		return a.buildBoardRemoteContext(board.Code)
+	}); section != "" {
+		sections = append(sections, section)
+	}
+	return strings.TrimSpace(strings.Join(sections, "\n\n"))
+}
+
+func (a *App) buildBoardRemoteContext(boardCode string) (string, bool, error) {
+	var sections []string
+	var errs []error
+	hasRemoteData := false
+
+	if indices, err := a.marketService.GetMarketIndices(); err == nil {
+		if section := buildCoreIndicesSection(indices); section != "" {
+			sections = append(sections, section)
+			hasRemoteData = true
+		}
+	} else {
+		errs = append(errs, fmt.Errorf("indices: %w", err))
+	}
+	if breadth, err := a.marketService.GetBoardBreadth(boardCode); err == nil {
+		if section := buildCoreBoardBreadthSection(breadth); section != "" {
+			sections = append(sections, section)
+			hasRemoteData = true
+		}
+	} else {
+		errs = append(errs, fmt.Errorf("breadth: %w", err))
+	}
+	if leaders, err := a.marketService.GetBoardLeaders(boardCode, 8); err == nil {
+		if section := buildCoreBoardLeadersSection(leaders); section != "" {
+			sections = append(sections, section)
+			hasRemoteData = true
+		}
+	} else {
+		errs = append(errs, fmt.Errorf("leaders: %w", err))
+	}
+	if series, err := a.marketService.GetIndexFundFlowSeries(boardCode, "101", 5); err == nil {
+		if section := buildCoreBoardFlowSection(series); section != "" {
+			sections = append(sections, section)
+			hasRemoteData = true
+		}
+	} else {
+		errs = append(errs, fmt.Errorf("fundflow: %w", err))
+	}
+
+	return strings.TrimSpace(strings.Join(sections, "\n\n")), hasRemoteData, errors.Join(errs...)
+}
+
+func buildCoreBoardSnapshotSection(board models.BoardFundFlowItem) string {
+	if strings.TrimSpace(board.Code) == "" && strings.TrimSpace(board.Name) == "" {
+		return ""
+	}
+	lines := []string{
+		fmt.Sprintf("【板块快照】%s (%s)", board.Name, board.Code),
+		fmt.Sprintf("板块指数 %.2f，涨跌幅 %.2f%%", board.Price, board.ChangePercent),
+		fmt.Sprintf("主力净流入 %.2f亿（占比 %.2f%%），超大单 %.2f亿，大单 %.2f亿，中单 %.2f亿，小单 %.2f亿",
+			board.MainNetInflow/1e8, board.MainNetInflowRatio, board.SuperNetInflow/1e8, board.LargeNetInflow/1e8,
+			board.MediumNetInflow/1e8, board.SmallNetInflow/1e8),
+	}
+	return strings.Join(lines, "\n")
+}
+
+func buildCoreBoardBreadthSection(breadth models.BoardBreadth) string {
+	if breadth.Total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("【涨跌分布】成分股 %d 只：上涨 %d，下跌 %d，平盘 %d，涨停 %d，跌停 %d；上涨占比 %.2f%%，平均涨跌幅 %.2f%%，中位数 %.2f%%",
+		breadth.Total, breadth.Up, breadth.Down, breadth.Flat, breadth.LimitUp, breadth.LimitDown,
+		breadth.UpRatio, breadth.AvgChange, breadth.MedianChange)
+}
+
+func buildCoreBoardLeadersSection(leaders models.BoardLeaderList) string {
+	if len(leaders.Items) == 0 {
+		return ""
+	}
+	lines := []string{"【龙头梯队】"}
+	for _, item := range leaders.Items {
+		lines = append(lines, fmt.Sprintf("%d. %s(%s) 涨幅 %.2f%%，换手 %.2f%%，主力净流入 %.2f亿，评分 %.2f",
+			item.Rank, item.Name, item.Code, item.ChangePercent, item.TurnoverRate, item.MainNetInflow/1e8, item.Score))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func buildCoreBoardFlowSection(series models.FundFlowKLineSeries) string {
+	if len(series.KLines) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(series.KLines))
+	total := 0.0
+	for _, k := range series.KLines {
+		total += k.MainNetInflow
+		parts = append(parts, fmt.Sprintf("%s %.2f亿", k.Time, k.MainNetInflow/1e8))
+	}
+	return fmt.Sprintf("【资金轮动】近%d日主力净流入合计 %.2f亿：%s", len(series.KLines), total/1e8, strings.Join(parts, "；"))
+}
+
+func (a *App) buildCoreContext(stockCode string, stock models.Stock, position *models.StockPosition) string {
+	var sections []string
+
+	if section := buildCoreQuoteSection(stock); section != "" {
+		sections = append(sections, section)
+	}
+	if section := buildCorePositionSection(position, stock); section != "" {
+		sections = append(sections, section)
+	}
+	if a.marketService != nil {
+		if section := buildCoreMarketStatusSection(a.marketService.GetMarketStatus()); section != "" {
+			sections = append(sections, section)
+		}
+	}
+	if section := a.getOrBuildCoreRemoteContext(stockCode, func() (string, bool, error) {
+		return a.buildCoreRemoteContext(stockCode)
+	}); section != "" {
+		sections = append(sections, section)
+	}
+
+	return strings.TrimSpace(strings.Join(sections, "\n\n"))
+}
+
+func (a *App) buildCoreRemoteContext(stockCode string) (string, bool, error) {
+	if a.marketService == nil && a.f10Service == nil {
+		return "", false, nil
+	}
+
+	var sections []string
+	var errs []error
+	hasRemoteData := false
+
+	if a.marketService != nil {
+		if indices, err := a.marketService.GetMarketIndices(); err == nil {
+			if section := buildCoreIndicesSection(indices); section != "" {
+				sections = append(sections, section)
+				hasRemoteData = true
+			}
+		} else {
+			errs = append(errs, fmt.Errorf("indices: %w", err))
+		}
+		if announcements, err := a.marketService.GetStockAnnouncements(stockCode, 1, 3); err == nil {
+			if section := buildCoreAnnouncementsSection(announcements); section != "" {
+				sections = append(sections, section)
+				hasRemoteData = true
+			}
+		} else if strings.TrimSpace(stockCode) != "" {
+			errs = append(errs, fmt.Errorf("announcements: %w", err))
+		}
+	}
+	if a.f10Service != nil {
+		if valuation, err := a.f10Service.GetValuationByCode(stockCode); err == nil {
+			if section := buildCoreValuationSection(valuation); section != "" {
+				sections = append(sections, section)
+				hasRemoteData = true
+			}
+		} else if strings.TrimSpace(stockCode) != "" {
+			errs = append(errs, fmt.Errorf("valuation: %w", err))
+		}
+	}
+	// 北向与两融为可选数据：非沪深港通/两融标的无数据，不计入刷新错误
+	if a.northboundService != nil && strings.TrimSpace(stockCode) != "" {
+		if holdings, err := a.northboundService.GetHoldings(stockCode, 5); err == nil {
+			if section := buildCoreNorthboundSection(holdings); section != "" {
+				sections = append(sections, section)
+				hasRemoteData = true
+			}
+		}
+	}
+	if a.marginService != nil && strings.TrimSpace(stockCode) != "" {
+		if margin, err := a.marginService.GetMarginTrading(stockCode, 5); err == nil {
+			if section := buildCoreMarginSection(margin); section != "" {
+				sections = append(sections, section)
+				hasRemoteData = true
+			}
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(sections, "\n\n")), hasRemoteData, errors.Join(errs...)
+}
+
+func (a *App) getOrBuildCoreRemoteContext(stockCode string, builder func() (string, bool, error)) string {
+	if strings.TrimSpace(stockCode) == "" {
+		contextText, _, _ := builder()
+		return contextText
+	}
+
+	if cached, ok := a.getCoreContextCache(stockCode); ok {
+		if time.Since(cached.timestamp) <= a.coreContextCacheTTL {
+			return cached.context
+		}
+	}
+
+	contextText, hasRemoteData, err := builder()
+	if strings.TrimSpace(contextText) != "" && (err == nil || hasRemoteData) {
+		a.setCoreContextCache(stockCode, contextText)
+		return contextText
+	}
+	if err != nil {
+		if cached, ok := a.getCoreContextCache(stockCode); ok && strings.TrimSpace(cached.context) != "" {
+			log.Warn("coreContext refresh failed for %s, use stale cache: %v", stockCode, err)
+			return cached.context
+		}
+		log.Warn("coreContext refresh failed for %s: %v", stockCode, err)
+	}
+	return contextText
+}
+
+func (a *App) getCoreContextCache(stockCode string) (coreContextCacheEntry, bool) {
+	a.coreContextCacheMu.RLock()
+	defer a.coreContextCacheMu.RUnlock()
+	entry, ok := a.coreContextCache[stockCode]
+	return entry, ok
+}
+
+func (a *App) setCoreContextCache(stockCode string, contextText string) {
+	a.coreContextCacheMu.Lock()
+	defer a.coreContextCacheMu.Unlock()
+	a.coreContextCache[stockCode] = coreContextCacheEntry{
+		context:   contextText,
+		timestamp: time.Now(),
+	}
+}
+
+func buildCoreQuoteSection(stock models.Stock) string {
+	if strings.TrimSpace(stock.Symbol) == "" && strings.TrimSpace(stock.Name) == "" {
+		return ""
+	}
+	lines := []string{
+		fmt.Sprintf("【标的快照】%s (%s)", stock.Name, stock.Symbol),
+		fmt.Sprintf("现价 %.2f，涨跌幅 %.2f%%，涨跌 %.2f", stock.Price, stock.ChangePercent, stock.Change),
+	}
+	rangeParts := make([]string, 0, 4)
+	if stock.Open > 0 {
+		rangeParts = append(rangeParts, fmt.Sprintf("开盘 %.2f", stock.Open))
+	}
+	if stock.High > 0 {
+		rangeParts = append(rangeParts, fmt.Sprintf("最高 %.2f", stock.High))
 	}
 	if stock.Low > 0 {
 		rangeParts = append(rangeParts, fmt.Sprintf("最低 %.2f", stock.Low))
@@ -1236,6 +1889,73 @@ func buildCoreValuationSection(valuation models.StockValuation) string {
 	return "【估值摘要】" + strings.Join(parts, "，")
 }
 
+func buildCoreNorthboundSection(data models.NorthboundHoldings) string {
+	if len(data.Items) == 0 {
+		return ""
+	}
+	latest := data.Items[0]
+	parts := []string{
+		fmt.Sprintf("%s 持股 %.2f万股", latest.TradeDate, latest.HoldShares/1e4),
+	}
+	if data.Stale {
+		parts[0] = fmt.Sprintf("截至 %s 披露持股 %.2f万股（定期披露，非实时）", latest.TradeDate, latest.HoldShares/1e4)
+	}
+	if latest.HoldMarketCap != 0 {
+		parts = append(parts, fmt.Sprintf("市值 %.2f亿", latest.HoldMarketCap/1e8))
+	}
+	if latest.FreeSharesRatio != 0 {
+		parts = append(parts, fmt.Sprintf("占流通股 %.2f%%", latest.FreeSharesRatio))
+	}
+	if len(data.Items) > 1 {
+		parts = append(parts, fmt.Sprintf("较上次披露(%s) %+.2f万股", data.Items[1].TradeDate, latest.ShareChange/1e4))
+	}
+	if data.Change5D != nil {
+		parts = append(parts, fmt.Sprintf("近一周 %+.2f万股", *data.Change5D/1e4))
+	}
+	if data.Change20D != nil {
+		parts = append(parts, fmt.Sprintf("近四周 %+.2f万股", *data.Change20D/1e4))
+	}
+	return "【北向持股】" + strings.Join(parts, "，")
+}
+
+func buildCoreMarginSection(data models.MarginTrading) string {
+	if len(data.Items) == 0 {
+		return ""
+	}
+	latest := data.Items[0]
+	parts := []string{
+		fmt.Sprintf("%s 融资余额 %.2f亿", latest.TradeDate, latest.FinBalance/1e8),
+		fmt.Sprintf("融资净买入 %.2f亿", latest.FinNetBuy/1e8),
+	}
+	if latest.FinBalanceRatio != 0 {
+		parts = append(parts, fmt.Sprintf("占流通市值 %.2f%%", latest.FinBalanceRatio))
+	}
+	if latest.SecBalance != 0 {
+		parts = append(parts, fmt.Sprintf("融券余额 %.2f万", latest.SecBalance/1e4))
+	}
+	if len(data.Items) > 1 {
+		parts = append(parts, fmt.Sprintf("近5日融资余额 %+.2f亿", data.FinChange5D/1e8))
+	}
+	return "【融资融券】" + strings.Join(parts, "，")
+}
+
+// buildComparePeerMatrix 以对比标的互为同业构建横向对比矩阵，失败时返回空
+func (a *App) buildComparePeerMatrix(codes []string) string {
+	if a.peerService == nil || len(codes) < 2 {
+		return ""
+	}
+	analysis, err := a.peerService.Analyze(models.PeerRequest{
+		Code:   codes[0],
+		Source: models.PeerSourceCustom,
+		Peers:  codes[1:],
+	})
+	if err != nil {
+		log.Warn("构建对比矩阵失败: %v", err)
+		return ""
+	}
+	return services.FormatPeerMatrix(analysis)
+}
+
 func buildCoreAnnouncementsSection(data models.StockAnnouncements) string {
 	if len(data.Items) == 0 {
 		return ""
@@ -1300,7 +2020,8 @@ func (a *App) RetryAgent(stockCode string, agentId string, query string) models.
 		runtime.EventsEmit(a.ctx, "meeting:progress:"+stockCode, event)
 	}
 
-	resp, err := a.meetingService.RetrySingleAgent(a.ctx, aiConfig, &agentCfg, &stock, query, progressCallback, position)
+	// 重试总是重新生成，不读取缓存
+	resp, err := a.meetingService.RetrySingleAgent(llmcache.WithBypass(a.ctx), aiConfig, &agentCfg, &stock, query, progressCallback, position)
 
 	msg := models.ChatMessage{
 		AgentID:     resp.AgentID,
@@ -1521,6 +2242,69 @@ func (a *App) TestAIConnection(config models.AIConfig) string {
 	return "success"
 }
 
+// ListOllamaModels 获取 Ollama 本地已下载的模型列表
+func (a *App) ListOllamaModels(baseURL string) []ollama.ModelInfo {
+	list, err := adk.NewModelFactory().ListOllamaModels(context.Background(), baseURL)
+	if err != nil {
+		log.Error("获取 Ollama 模型列表失败: %v", err)
+		return []ollama.ModelInfo{}
+	}
+	return list
+}
+
+// PullOllamaModel 后台拉取 Ollama 模型，进度通过 ollama:pull 事件推送
+func (a *App) PullOllamaModel(baseURL, modelName string) string {
+	modelName = strings.TrimSpace(modelName)
+	if modelName == "" {
+		return "模型名称不能为空"
+	}
+	a.ollamaPullsMu.Lock()
+	if status, ok := a.ollamaPulls[modelName]; ok && !status.Done {
+		a.ollamaPullsMu.Unlock()
+		return "模型正在拉取中"
+	}
+	a.ollamaPulls[modelName] = ollama.PullStatus{Model: modelName, Status: "pending"}
+	a.ollamaPullsMu.Unlock()
+
+	go func() {
+		update := func(status ollama.PullStatus) {
+			a.ollamaPullsMu.Lock()
+			a.ollamaPulls[modelName] = status
+			a.ollamaPullsMu.Unlock()
+			if a.ctx != nil {
+				runtime.EventsEmit(a.ctx, "ollama:pull", status)
+			}
+		}
+		err := adk.NewModelFactory().PullOllamaModel(context.Background(), baseURL, modelName, func(p ollama.PullProgress) {
+			status := ollama.PullStatus{Model: modelName, Status: p.Status, Total: p.Total, Completed: p.Completed}
+			if p.Total > 0 {
+				status.Percent = float64(p.Completed) / float64(p.Total) * 100
+			}
+			update(status)
+		})
+		final := ollama.PullStatus{Model: modelName, Status: "success", Percent: 100, Done: true}
+		if err != nil {
+			log.Error("拉取 Ollama 模型失败 [%s]: %v", modelName, err)
+			final = ollama.PullStatus{Model: modelName, Status: "error", Done: true, Error: err.Error()}
+		} else {
+			log.Info("Ollama 模型拉取完成 [%s]", modelName)
+		}
+		update(final)
+	}()
+	return "success"
+}
+
+// GetOllamaPullStatus 获取 Ollama 模型拉取状态
+func (a *App) GetOllamaPullStatus(modelName string) *ollama.PullStatus {
+	a.ollamaPullsMu.RLock()
+	defer a.ollamaPullsMu.RUnlock()
+	status, ok := a.ollamaPulls[strings.TrimSpace(modelName)]
+	if !ok {
+		return nil
+	}
+	return &status
+}
+
 // GetMCPServerTools 获取指定 MCP 服务器的工具列表
 func (a *App) GetMCPServerTools(serverID string) []mcp.ToolInfo {
 	tools, err := a.mcpManager.GetServerTools(serverID)
@@ -1530,6 +2314,42 @@ func (a *App) GetMCPServerTools(serverID string) []mcp.ToolInfo {
 	return tools
 }
 
+// GetMCPServerResources 获取指定 MCP 服务器的资源列表
+func (a *App) GetMCPServerResources(serverID string) []mcp.ResourceInfo {
+	resources, err := a.mcpManager.GetServerResources(serverID)
+	if err != nil || resources == nil {
+		return []mcp.ResourceInfo{}
+	}
+	return resources
+}
+
+// ReadMCPResource 读取指定 MCP 资源的文本内容
+func (a *App) ReadMCPResource(serverID, uri string) mcp.TextResult {
+	text, err := a.mcpManager.ReadResource(serverID, uri)
+	if err != nil {
+		return mcp.TextResult{Error: err.Error()}
+	}
+	return mcp.TextResult{Text: text}
+}
+
+// GetMCPServerPrompts 获取指定 MCP 服务器的提示词列表
+func (a *App) GetMCPServerPrompts(serverID string) []mcp.PromptInfo {
+	prompts, err := a.mcpManager.GetServerPrompts(serverID)
+	if err != nil || prompts == nil {
+		return []mcp.PromptInfo{}
+	}
+	return prompts
+}
+
+// GetMCPPrompt 按参数渲染 MCP 提示词，可导入为专家指令
+func (a *App) GetMCPPrompt(serverID, name string, args map[string]string) mcp.TextResult {
+	text, err := a.mcpManager.GetPrompt(serverID, name, args)
+	if err != nil {
+		return mcp.TextResult{Error: err.Error()}
+	}
+	return mcp.TextResult{Text: text}
+}
+
 // ========== Window Control API ==========
 
 // WindowMinimize 最小化窗口
@@ -1696,9 +2516,408 @@ func (a *App) GetBoardLeaders(boardCode string, limit int) models.BoardLeaderLis
 	return data
 }
 
+// GetBoardBreadth 获取板块成分股涨跌分布
+func (a *App) GetBoardBreadth(boardCode string) models.BoardBreadth {
+	if a.marketService == nil {
+		return models.BoardBreadth{BoardCode: boardCode}
+	}
+	data, err := a.marketService.GetBoardBreadth(boardCode)
+	if err != nil {
+		log.Error("获取板块涨跌分布失败: %v", err)
+		return models.BoardBreadth{BoardCode: boardCode}
+	}
+	return data
+}
+
+// GetMarketSentiment 获取全市场宽度与情绪指标
+func (a *App) GetMarketSentiment() *models.MarketSentiment {
+	if a.sentimentService == nil {
+		return nil
+	}
+	data, err := a.sentimentService.GetSentiment()
+	if err != nil {
+		log.Error("获取市场情绪失败: %v", err)
+		return nil
+	}
+	return data
+}
+
+// GetMarketSentimentHistory 获取最近 days 个交易日的情绪序列
+func (a *App) GetMarketSentimentHistory(days int) []models.MarketSentiment {
+	if a.sentimentService == nil {
+		return nil
+	}
+	return a.sentimentService.GetHistory(days)
+}
+
+// GetAnalystConsensus 获取个股分析师一致预期，days 为统计窗口天数
+func (a *App) GetAnalystConsensus(code string, days int) *models.AnalystConsensus {
+	if a.consensusService == nil {
+		return nil
+	}
+	data, err := a.consensusService.GetConsensus(code, days)
+	if err != nil {
+		log.Error("获取一致预期失败: %v", err)
+		return nil
+	}
+	return data
+}
+
+// GetAnalystConsensusHistory 获取个股一致预期历史快照
+func (a *App) GetAnalystConsensusHistory(code string) []models.ConsensusSnapshot {
+	if a.consensusService == nil {
+		return nil
+	}
+	return a.consensusService.GetHistory(code)
+}
+
+// handleAnnouncementEvent 推送新公告事件，并将重要公告写入股票记忆
+func (a *App) handleAnnouncementEvent(event models.AnnouncementEvent) {
+	runtime.EventsEmit(a.ctx, "announcement:new", event)
+	if event.Severity != "low" {
+		runtime.EventsEmit(a.ctx, "announcement:alert", event)
+	}
+	if a.memoryManager == nil || event.Category == services.AnnounceOther {
+		return
+	}
+	content := fmt.Sprintf("公告(%s): %s", event.CategoryName, event.Title)
+	weight := 0.6
+	if event.Severity == "high" {
+		weight = 0.9
+	}
+	if err := a.memoryManager.AddDatedFact(event.StockCode, event.StockName, event.NoticeDate, content, "announcement", weight); err != nil {
+		log.Warn("写入公告记忆失败: %v", err)
+	}
+}
+
+// GetAnnouncementEvents 获取自选股公告事件，code 为空时返回全部
+func (a *App) GetAnnouncementEvents(code string, limit int) []models.AnnouncementEvent {
+	if a.announceWatcher == nil {
+		return nil
+	}
+	return a.announceWatcher.GetEvents(code, limit)
+}
+
+// CheckAnnouncementsNow 立即检查自选股新公告
+func (a *App) CheckAnnouncementsNow() []models.AnnouncementEvent {
+	if a.announceWatcher == nil {
+		return nil
+	}
+	return a.announceWatcher.CheckNow(a.ctx)
+}
+
+// errMeetingBusy 用户会议进行中，自动会议不抢占
+var errMeetingBusy = errors.New("会议进行中")
+
+// runEarningsBriefing 财报披露前自动召开前瞻会议；用户会议进行中时不打断，由调度下次重试
+func (a *App) runEarningsBriefing(item models.EarningsCalendarItem) error {
+	if a.sessionService == nil {
+		return errors.New("会话服务未初始化")
+	}
+	if _, err := a.sessionService.GetOrCreateSession(item.StockCode, item.StockName); err != nil {
+		return fmt.Errorf("创建财报前瞻会话失败: %w", err)
+	}
+	meetingCtx, ok := a.claimMeeting(item.StockCode, false)
+	if !ok {
+		return errMeetingBusy
+	}
+	defer a.releaseMeeting(item.StockCode)
+
+	runtime.EventsEmit(a.ctx, "earnings:briefing", item)
+	reportName := item.ReportType
+	if reportName == "" {
+		reportName = item.ReportDate + " 财报"
+	}
+	content := fmt.Sprintf("【财报前瞻】%s预计于%s披露%s，请结合业绩预告、分析师一致预期与近期经营情况，研判本期业绩可能的超预期/低于预期方向、关注要点及披露前后的应对策略。",
+		item.StockName, item.AppointDate, reportName)
+	if msgs := a.sendMeetingMessage(meetingCtx, MeetingMessageRequest{StockCode: item.StockCode, Content: content}); len(msgs) == 0 {
+		return errors.New("前瞻会议未产生发言")
+	}
+	return nil
+}
+
+// handleEarningsComparison 推送披露后业绩对比，并写入股票记忆
+func (a *App) handleEarningsComparison(comparison models.EarningsComparison) {
+	runtime.EventsEmit(a.ctx, "earnings:comparison", comparison)
+	if a.memoryManager == nil {
+		return
+	}
+	content := fmt.Sprintf("%s 财报: %s", comparison.ReportDate, comparison.Summary)
+	if err := a.memoryManager.AddDatedFact(comparison.StockCode, comparison.StockName, comparison.ActualDate, content, "earnings", 0.8); err != nil {
+		log.Warn("写入业绩对比记忆失败: %v", err)
+	}
+}
+
+// GetEarningsCalendar 获取自选股财报披露日历，days 为向后查看的天数
+func (a *App) GetEarningsCalendar(days int) []models.EarningsCalendarItem {
+	if a.earningsService == nil {
+		return nil
+	}
+	return a.earningsService.GetCalendar(days)
+}
+
+// GetEarningsComparisons 获取业绩对比记录，code 为空时返回全部
+func (a *App) GetEarningsComparisons(code string) []models.EarningsComparison {
+	if a.earningsService == nil {
+		return nil
+	}
+	return a.earningsService.GetComparisons(code)
+}
+
+// CompareEarnings 对个股最近一期已披露财报与公司预告、一致预期做对比
+func (a *App) CompareEarnings(code string) *models.EarningsComparison {
+	if a.earningsService == nil {
+		return nil
+	}
+	stock := models.Stock{Symbol: code, Name: code}
+	if stocks, err := a.marketService.GetStockRealTimeData(code); err == nil && len(stocks) > 0 {
+		stock = stocks[0]
+	}
+	result, err := a.earningsService.Compare(stock, "")
+	if err != nil {
+		log.Error("业绩对比失败: %v", err)
+		return nil
+	}
+	return result
+}
+
+// GetRiskRadar 获取自选股与持仓风险雷达（最近一次扫描结果）
+func (a *App) GetRiskRadar() *models.RiskRadar {
+	if a.riskRadarService == nil {
+		return nil
+	}
+	return a.riskRadarService.GetRadar()
+}
+
+// RefreshRiskRadar 立即重新扫描风险雷达
+func (a *App) RefreshRiskRadar() *models.RiskRadar {
+	if a.riskRadarService == nil {
+		return nil
+	}
+	return a.riskRadarService.Scan()
+}
+
+// GetLhbStockSeats 获取个股龙虎榜席位及各席位历史表现，tradeDate 为空时取最近一次上榜
+func (a *App) GetLhbStockSeats(code, tradeDate string) *models.StockSeatAnalysis {
+	if a.lhbSeatService == nil {
+		return nil
+	}
+	data, err := a.lhbSeatService.AnalyzeStock(code, tradeDate)
+	if err != nil {
+		log.Error("获取龙虎榜席位分析失败: %v", err)
+		return nil
+	}
+	return data
+}
+
+// GetLhbSeatProfile 获取龙虎榜席位画像
+func (a *App) GetLhbSeatProfile(name string) *models.SeatProfile {
+	if a.lhbSeatService == nil {
+		return nil
+	}
+	data, err := a.lhbSeatService.GetSeatProfile(name)
+	if err != nil {
+		log.Error("获取席位画像失败: %v", err)
+		return nil
+	}
+	return data
+}
+
+// GetLhbSeats 按上榜次数列出席位画像
+func (a *App) GetLhbSeats(keyword, seatType string, limit int) []models.SeatProfile {
+	if a.lhbSeatService == nil {
+		return nil
+	}
+	return a.lhbSeatService.ListSeats(keyword, seatType, limit)
+}
+
+// BackfillLhbSeats 补采最近 days 天的龙虎榜席位明细
+func (a *App) BackfillLhbSeats(days int) string {
+	if a.lhbSeatService == nil {
+		return "service not ready"
+	}
+	if _, err := a.lhbSeatService.Backfill(days); err != nil {
+		return err.Error()
+	}
+	return "success"
+}
+
+// GetNorthboundHoldings 获取个股北向资金持股序列
+func (a *App) GetNorthboundHoldings(code string, days int) *models.NorthboundHoldings {
+	if a.northboundService == nil {
+		return nil
+	}
+	data, err := a.northboundService.GetHoldings(code, days)
+	if err != nil {
+		log.Error("获取北向持股失败: %v", err)
+		if len(data.Items) == 0 {
+			return nil
+		}
+	}
+	return &data
+}
+
+// GetMarginTrading 获取个股融资融券序列
+func (a *App) GetMarginTrading(code string, days int) *models.MarginTrading {
+	if a.marginService == nil {
+		return nil
+	}
+	data, err := a.marginService.GetMarginTrading(code, days)
+	if err != nil {
+		log.Error("获取融资融券数据失败: %v", err)
+		if len(data.Items) == 0 {
+			return nil
+		}
+	}
+	return &data
+}
+
+// GetPeerComparison 获取同业对比矩阵
+func (a *App) GetPeerComparison(req models.PeerRequest) *models.PeerAnalysis {
+	if a.peerService == nil {
+		return nil
+	}
+	data, err := a.peerService.Analyze(req)
+	if err != nil {
+		log.Error("获取同业对比失败: %v", err)
+		return nil
+	}
+	return &data
+}
+
 // NotifyFrontendReady 前端通知已准备好，开始推送数据
 func (a *App) NotifyFrontendReady() {
 	if a.marketPusher != nil {
 		a.marketPusher.SetReady()
 	}
 }
+
+// GetScreenerFields 获取选股可用字段
+func (a *App) GetScreenerFields() []models.ScreenField {
+	if a.screenerService == nil {
+		return nil
+	}
+	return a.screenerService.GetFields()
+}
+
+// GetStockScreens 获取已保存的选股方案
+func (a *App) GetStockScreens() []models.StockScreen {
+	if a.screenerService == nil {
+		return nil
+	}
+	return a.screenerService.GetScreens()
+}
+
+// SaveStockScreen 新建或更新选股方案
+func (a *App) SaveStockScreen(screen models.StockScreen) string {
+	if a.screenerService == nil {
+		return "选股服务未初始化"
+	}
+	if _, err := a.screenerService.SaveScreen(screen); err != nil {
+		return err.Error()
+	}
+	return "success"
+}
+
+// DeleteStockScreen 删除选股方案
+func (a *App) DeleteStockScreen(id string) string {
+	if a.screenerService == nil {
+		return "选股服务未初始化"
+	}
+	if err := a.screenerService.DeleteScreen(id); err != nil {
+		return err.Error()
+	}
+	return "success"
+}
+
+// RunStockScreen 运行已保存的选股方案
+func (a *App) RunStockScreen(id string) models.ScreenResult {
+	if a.screenerService == nil {
+		return models.ScreenResult{}
+	}
+	result, err := a.screenerService.RunScreen(a.ctx, id)
+	if err != nil {
+		log.Error("运行选股方案失败: %v", err)
+		result.Errors = map[string]string{"service": err.Error()}
+	}
+	return result
+}
+
+// RunScreenExpression 按表达式直接选股
+func (a *App) RunScreenExpression(expression string, sortBy string, sortDesc bool, limit int) models.ScreenResult {
+	if a.screenerService == nil {
+		return models.ScreenResult{Expression: expression}
+	}
+	result, err := a.screenerService.Run(a.ctx, expression, sortBy, sortDesc, limit)
+	if err != nil {
+		log.Error("条件选股失败: %v", err)
+		result.Expression = expression
+		result.Errors = map[string]string{"service": err.Error()}
+	}
+	return result
+}
+
+// GetStockScreenResult 获取选股方案最近一次运行结果
+func (a *App) GetStockScreenResult(id string) models.ScreenResult {
+	if a.screenerService == nil {
+		return models.ScreenResult{}
+	}
+	result, _ := a.screenerService.GetLastResult(id)
+	return result
+}
+
+// GenerateMarketReview 生成当日市场复盘，narrate 为 true 时由小韭菜撰写点评
+func (a *App) GenerateMarketReview(narrate bool) *models.MarketReview {
+	if a.reviewService == nil {
+		return nil
+	}
+	var llm model.LLM
+	if narrate {
+		config := a.configService.GetConfig()
+		aiConfig := a.getModeratorAIConfig(config)
+		if aiConfig == nil {
+			log.Warn("no AI config found, generate review without narrative")
+		} else if created, err := adk.NewModelFactory().CreateModel(a.ctx, aiConfig); err != nil {
+			log.Error("create review LLM error: %v", err)
+		} else {
+			llm = created
+		}
+	}
+	review, err := a.reviewService.Generate(a.ctx, llm)
+	if err != nil {
+		log.Error("生成市场复盘失败: %v", err)
+	}
+	return review
+}
+
+// GetMarketReviews 获取历史复盘列表
+func (a *App) GetMarketReviews() []models.MarketReviewSummary {
+	if a.reviewService == nil {
+		return nil
+	}
+	return a.reviewService.ListReviews()
+}
+
+// GetMarketReview 获取指定日期的复盘
+func (a *App) GetMarketReview(date string) *models.MarketReview {
+	if a.reviewService == nil {
+		return nil
+	}
+	review, err := a.reviewService.GetReview(date)
+	if err != nil {
+		log.Error("获取市场复盘失败: %v", err)
+		return nil
+	}
+	return review
+}
+
+// DeleteMarketReview 删除指定日期的复盘
+func (a *App) DeleteMarketReview(date string) string {
+	if a.reviewService == nil {
+		return "复盘服务未初始化"
+	}
+	if err := a.reviewService.DeleteReview(date); err != nil {
+		return err.Error()
+	}
+	return "success"
+}
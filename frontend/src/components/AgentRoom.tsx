@@ -2,7 +2,7 @@ import React, { useState, useEffect, useRef } from 'react';
 import { Stock, KLineData } from '../types';
 import { getAgentConfigs, AgentConfig } from '../services/strategyService';
 import { StockSession, ChatMessage, sendMeetingMessage, MeetingMessageRequest, getSessionMessages, retryAgent, retryAgentAndContinue, cancelInterruptedMeeting } from '../services/sessionService';
-import { MessageSquare, Loader2, Send, User, Users, X, Reply, Trash2, Wrench, CheckCircle2, AlertCircle, Copy, Check, RotateCcw, Pencil, Square } from 'lucide-react';
+import { MessageSquare, Loader2, Send, User, Users, X, Reply, Trash2, Wrench, CheckCircle2, AlertCircle, Copy, Check, RotateCcw, Pencil, Square, Hourglass, RefreshCw, Brain, ChevronDown } from 'lucide-react';
 import { clearSessionMessages } from '../services/sessionService';
 import { NodeRenderer } from 'markstream-react';
 import { EventsOn, EventsOff } from '../../wailsjs/runtime/runtime';
@@ -13,7 +13,7 @@ import 'markstream-react/index.css';
 
 // 进度事件类型
 interface ProgressEvent {
-  type: 'agent_start' | 'agent_done' | 'tool_call' | 'tool_result' | 'streaming' | 'agent_error' | 'meeting_interrupted';
+  type: 'agent_start' | 'agent_done' | 'tool_call' | 'tool_result' | 'streaming' | 'agent_error' | 'meeting_interrupted' | 'quota_wait' | 'quota_ready' | 'thinking';
   agentId: string;
   agentName: string;
   detail?: string;
@@ -24,8 +24,9 @@ interface ProgressEvent {
 interface ProgressState {
   currentAgent: string | null;
   currentAgentName: string | null;
-  steps: { type: string; detail: string; done: boolean }[];
+  steps: { type: string; detail: string; done: boolean; key?: string }[];
   streamingText: string;
+  thinkingText: string; // 模型思考过程，折叠展示
 }
 
 interface AgentRoomProps {
@@ -41,6 +42,8 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
   const [messages, setMessages] = useState<ChatMessage[]>([]);
   const [simulatingMap, setSimulatingMap] = useState<Record<string, boolean>>({});
   const [userQuery, setUserQuery] = useState('');
+  // 本次提问跳过 LLM 响应缓存
+  const [noCache, setNoCache] = useState(false);
   const scrollRef = useRef<HTMLDivElement>(null);
   const inputRef = useRef<HTMLInputElement>(null);
 
@@ -85,6 +88,7 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
     currentAgentName: null,
     steps: [],
     streamingText: '',
+    thinkingText: '',
   });
 
   // 在聊天窗口中添加系统提示消息
@@ -113,6 +117,7 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
       currentAgentName: null,
       steps: [],
       streamingText: '',
+      thinkingText: '',
     });
     addSystemMessage('讨论已停止');
   };
@@ -210,9 +215,10 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
               currentAgentName: event.agentName,
               steps: [],
               streamingText: '',
+              thinkingText: '',
             };
           case 'agent_done':
-            return { ...prev, currentAgent: null, currentAgentName: null, steps: [], streamingText: '' };
+            return { ...prev, currentAgent: null, currentAgentName: null, steps: [], streamingText: '', thinkingText: '' };
           case 'tool_call':
             return {
               ...prev,
@@ -223,8 +229,26 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
               s.type === 'tool_call' && s.detail === event.detail ? { ...s, done: true } : s
             );
             return { ...prev, steps: updatedSteps };
+          case 'quota_wait':
+            // 同一配置的排队原因变化时替换原有提示
+            return {
+              ...prev,
+              steps: [
+                ...prev.steps.filter(s => !(s.type === 'quota_wait' && !s.done && s.key === event.content)),
+                { type: 'quota_wait', key: event.content, detail: event.detail || '等待配额', done: false },
+              ],
+            };
+          case 'quota_ready':
+            return {
+              ...prev,
+              steps: prev.steps.map(s =>
+                s.type === 'quota_wait' && s.key === event.detail ? { ...s, done: true } : s
+              ),
+            };
           case 'streaming':
             return { ...prev, streamingText: prev.streamingText + (event.content || '') };
+          case 'thinking':
+            return { ...prev, thinkingText: prev.thinkingText + (event.content || '') };
           case 'meeting_interrupted':
             return prev; // 状态在外部处理
           default:
@@ -284,7 +308,8 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
         content: query,
         mentionIds: mentions,
         replyToId: replyTo?.id || '',
-        replyContent: replyTo?.content || ''
+        replyContent: replyTo?.content || '',
+        noCache,
       };
 
       // 统一模式：无论智能模式还是直接@模式，消息都通过事件实时推送
@@ -695,6 +720,8 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
                       <div key={i} className="flex items-center gap-2 text-xs">
                         {step.done ? (
                           <CheckCircle2 className="h-3 w-3 text-green-400" />
+                        ) : step.type === 'quota_wait' ? (
+                          <Hourglass className="h-3 w-3 text-amber-400 animate-pulse" />
                         ) : (
                           <Wrench className="h-3 w-3 text-amber-400 animate-pulse" />
                         )}
@@ -705,6 +732,18 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
                     ))}
                   </div>
                 )}
+                {progress.thinkingText && (
+                  <details className="pl-6 group">
+                    <summary className={`flex items-center gap-2 text-xs cursor-pointer select-none list-none ${colors.isDark ? 'text-slate-400 hover:text-slate-300' : 'text-slate-500 hover:text-slate-600'}`}>
+                      <Brain className="h-3 w-3 text-accent-2" />
+                      <span>思考过程（{progress.thinkingText.length} 字）</span>
+                      <ChevronDown className="h-3 w-3 transition-transform group-open:rotate-180" />
+                    </summary>
+                    <div className={`mt-1 max-h-40 overflow-y-auto whitespace-pre-wrap text-xs leading-relaxed border-l-2 pl-2 ${colors.isDark ? 'text-slate-500 border-slate-700' : 'text-slate-400 border-slate-300'}`}>
+                      {progress.thinkingText}
+                    </div>
+                  </details>
+                )}
               </div>
             ) : (
               <div className="flex items-center gap-2 justify-center">
@@ -795,6 +834,17 @@ export const AgentRoom: React.FC<AgentRoomProps> = ({ session, onSessionUpdate }
 
           {/* 输入框 */}
           <form onSubmit={handleSubmit} className="flex gap-2">
+            <button
+              type="button"
+              onClick={() => setNoCache(v => !v)}
+              disabled={isSimulating}
+              className={`p-2 rounded-lg transition-colors flex items-center justify-center w-10 h-10 border fin-divider disabled:opacity-50 ${
+                noCache ? 'bg-accent/20 text-accent-2' : (colors.isDark ? 'text-slate-500 hover:text-slate-300' : 'text-slate-400 hover:text-slate-600')
+              }`}
+              title={noCache ? '已跳过缓存：专家将重新生成回复' : '使用缓存（点击切换为重新生成）'}
+            >
+              <RefreshCw size={14} />
+            </button>
             <input
                ref={inputRef}
                type="text"
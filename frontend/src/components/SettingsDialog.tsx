@@ -1,10 +1,10 @@
 import React, { useState, useEffect, useCallback, useRef } from 'react';
-import { X, Cpu, ChevronLeft, Plug, Plus, Trash2, Wrench, Check, Loader2, Brain, RefreshCw, Download, RotateCcw, Globe, Layers, Sliders, Star, MessageSquare, Copy, Sparkles } from 'lucide-react';
-import { getConfig, updateConfig, getAvailableTools, ToolInfo, testAIConnection } from '../services/configService';
+import { X, Cpu, ChevronLeft, Plug, Plus, Trash2, Wrench, Check, Loader2, Brain, RefreshCw, Download, RotateCcw, Globe, Layers, Sliders, Star, MessageSquare, Copy, Sparkles, FileText } from 'lucide-react';
+import { getConfig, updateConfig, getAvailableTools, ToolInfo, testAIConnection, listOllamaModels, pullOllamaModel, onOllamaPull, getLLMCacheStats, clearLLMCache } from '../services/configService';
 import { getAgentConfigs } from '../services/strategyService';
-import { getMCPServers, MCPServerConfig, MCPServerStatus, testMCPConnection, getMCPServerTools, MCPToolInfo } from '../services/mcpService';
+import { getMCPServers, MCPServerConfig, MCPAuthConfig, MCPAuthType, MCPServerStatus, testMCPConnection, getMCPServerTools, MCPToolInfo, getMCPServerResources, MCPResourceInfo, getMCPServerPrompts, getMCPPrompt, MCPPromptInfo } from '../services/mcpService';
 import { checkForUpdate, doUpdate, restartApp, getCurrentVersion, onUpdateProgress, UpdateInfo, UpdateProgress } from '../services/updateService';
-import { getStrategies, getActiveStrategyID, setActiveStrategy, deleteStrategy, generateStrategy, updateStrategy, enhancePrompt, Strategy, StrategyAgent } from '../services/strategyService';
+import { getStrategies, getActiveStrategyID, setActiveStrategy, deleteStrategy, generateStrategy, updateStrategy, enhancePrompt, Strategy, StrategyAgent, ReasoningConfig, MCPResourceRef } from '../services/strategyService';
 import { useTheme } from '../contexts/ThemeContext';
 import { useCandleColor, CandleColorMode } from '../contexts/CandleColorContext';
 import { useIndicator, IndicatorConfig, IndicatorType, DEFAULT_INDICATORS } from '../contexts/IndicatorContext';
@@ -27,6 +27,27 @@ interface AIConfig {
   project: string;
   location: string;
   credentialsJson: string;
+  // 客户端限流：并发流、每分钟请求数、每分钟 token，0 表示不限制
+  maxConcurrency?: number;
+  requestsPerMinute?: number;
+  tokensPerMinute?: number;
+  // 路由专用字段
+  routeConfigIds?: string[];
+  routeStrategy?: string;
+  routeFallbackOn?: string[];
+  // 推理设置，为空则不开启
+  reasoning?: ReasoningConfig;
+}
+
+interface LLMCacheConfig {
+  enabled: boolean;
+  tradingTtlMinutes: number;
+  closedTtlMinutes: number;
+}
+
+interface EarningsCalendarConfig {
+  autoBriefing: boolean;
+  briefingDaysBefore: number;
 }
 
 interface MemoryConfig {
@@ -129,8 +150,10 @@ export const SettingsDialog: React.FC<SettingsDialogProps> = ({ isOpen, onClose
   const [strategyAiId, setStrategyAiId] = useState<string>('');
   const [aiRetryCount, setAiRetryCount] = useState<number>(2);
   const [verboseAgentIO, setVerboseAgentIO] = useState<boolean>(false);
+  const [llmCacheConfig, setLLMCacheConfig] = useState<LLMCacheConfig>({ enabled: false, tradingTtlMinutes: 5, closedTtlMinutes: 720 });
   const [agentSelectionStyle, setAgentSelectionStyle] = useState<AgentSelectionStyle>('balanced');
   const [enableSecondReview, setEnableSecondReview] = useState<boolean>(false);
+  const [earningsCalendar, setEarningsCalendar] = useState<EarningsCalendarConfig>({ autoBriefing: false, briefingDaysBefore: 3 });
 
   // Toast 通知
   const { toast, showToast, hideToast } = useSettingsToast();
@@ -162,12 +185,14 @@ export const SettingsDialog: React.FC<SettingsDialogProps> = ({ isOpen, onClose
     }
     if (typeof (config as any).aiRetryCount === 'number') setAiRetryCount((config as any).aiRetryCount);
     if (typeof (config as any).verboseAgentIO === 'boolean') setVerboseAgentIO((config as any).verboseAgentIO);
+    if (config.llmCache) setLLMCacheConfig(config.llmCache);
     if (typeof (config as any).agentSelectionStyle === 'string') {
       setAgentSelectionStyle((config as any).agentSelectionStyle as AgentSelectionStyle);
     }
     if (typeof (config as any).enableSecondReview === 'boolean') {
       setEnableSecondReview((config as any).enableSecondReview);
     }
+    if ((config as any).earningsCalendar) setEarningsCalendar((config as any).earningsCalendar);
     if (config.moderatorAiId) setModeratorAiId(config.moderatorAiId);
     if (config.strategyAiId) setStrategyAiId(config.strategyAiId);
 
@@ -202,8 +227,10 @@ export const SettingsDialog: React.FC<SettingsDialogProps> = ({ isOpen, onClose
     strategyAiId: string;
     aiRetryCount: number;
     verboseAgentIO: boolean;
+    llmCache: LLMCacheConfig;
     agentSelectionStyle: AgentSelectionStyle;
     enableSecondReview: boolean;
+    earningsCalendar: EarningsCalendarConfig;
     indicators: any;
   }>>({});
 
@@ -240,8 +267,10 @@ export const SettingsDialog: React.FC<SettingsDialogProps> = ({ isOpen, onClose
     strategyAiId: string;
     aiRetryCount: number;
     verboseAgentIO: boolean;
+    llmCache: LLMCacheConfig;
     agentSelectionStyle: AgentSelectionStyle;
     enableSecondReview: boolean;
+    earningsCalendar: EarningsCalendarConfig;
     candleColorMode: string;
     indicators: any;
   }>) => {
@@ -329,6 +358,11 @@ export const SettingsDialog: React.FC<SettingsDialogProps> = ({ isOpen, onClose
                   setVerboseAgentIO(enabled);
                   saveConfig({ verboseAgentIO: enabled });
                 }}
+                llmCache={llmCacheConfig}
+                onLLMCacheChange={(cache) => {
+                  setLLMCacheConfig(cache);
+                  saveConfig({ llmCache: cache });
+                }}
               />
             )}
             {activeTab === 'intent' && (
@@ -337,6 +371,7 @@ export const SettingsDialog: React.FC<SettingsDialogProps> = ({ isOpen, onClose
                 moderatorAiId={moderatorAiId}
                 agentSelectionStyle={agentSelectionStyle}
                 enableSecondReview={enableSecondReview}
+                autoBriefing={earningsCalendar.autoBriefing}
                 onModeratorAiIdChange={(id) => {
                   setModeratorAiId(id);
                   saveConfig({ moderatorAiId: id });
@@ -349,6 +384,11 @@ export const SettingsDialog: React.FC<SettingsDialogProps> = ({ isOpen, onClose
                   setEnableSecondReview(enabled);
                   saveConfig({ enableSecondReview: enabled });
                 }}
+                onAutoBriefingChange={(enabled) => {
+                  const next = { ...earningsCalendar, autoBriefing: enabled };
+                  setEarningsCalendar(next);
+                  saveConfig({ earningsCalendar: next });
+                }}
               />
             )}
             {activeTab === 'strategy' && (
@@ -462,7 +502,7 @@ const Header: React.FC<{ onClose: () => void }> = ({ onClose }) => {
 };
 
 // ========== Provider 设置选项卡 ==========
-const PROVIDERS = ['openai', 'gemini', 'vertexai', 'anthropic'] as const;
+const PROVIDERS = ['openai', 'gemini', 'vertexai', 'anthropic', 'ollama', 'router'] as const;
 type ProviderType = typeof PROVIDERS[number];
 
 const PROVIDER_LABELS: Record<ProviderType, string> = {
@@ -470,8 +510,18 @@ const PROVIDER_LABELS: Record<ProviderType, string> = {
   gemini: 'Gemini',
   vertexai: 'Vertex AI',
   anthropic: 'Anthropic',
+  ollama: 'Ollama (本地)',
+  router: '路由 (回退链)',
 };
 
+// 路由回退触发的错误类别
+const ROUTE_FALLBACK_OPTIONS = [
+  { value: 'rate_limit', label: '限流 (429)' },
+  { value: 'server', label: '服务端错误 (5xx)' },
+  { value: 'timeout', label: '超时' },
+  { value: 'context_length', label: '上下文超长' },
+];
+
 interface ProviderSettingsProps {
   configs: AIConfig[];
   onChange: (configs: AIConfig[]) => void;
@@ -483,6 +533,8 @@ interface ProviderSettingsProps {
   verboseAgentIO: boolean;
   onRetryCountChange: (count: number) => void;
   onVerboseAgentIOChange: (enabled: boolean) => void;
+  llmCache: LLMCacheConfig;
+  onLLMCacheChange: (cache: LLMCacheConfig) => void;
 }
 
 // 视图类型
@@ -499,6 +551,8 @@ const ProviderSettings: React.FC<ProviderSettingsProps> = ({
   verboseAgentIO,
   onRetryCountChange,
   onVerboseAgentIOChange,
+  llmCache,
+  onLLMCacheChange,
 }) => {
   const [view, setView] = useState<ProviderView>('list');
   const [selectedConfig, setSelectedConfig] = useState<AIConfig | null>(null);
@@ -523,6 +577,12 @@ const ProviderSettings: React.FC<ProviderSettingsProps> = ({
       project: '',
       location: 'us-central1',
       credentialsJson: '',
+      maxConcurrency: 0,
+      requestsPerMinute: 0,
+      tokensPerMinute: 0,
+      routeConfigIds: [],
+      routeStrategy: 'ordered',
+      routeFallbackOn: [],
     };
     onChange([...configs, newConfig]);
     setSelectedConfig(newConfig);
@@ -573,6 +633,11 @@ const ProviderSettings: React.FC<ProviderSettingsProps> = ({
     if (moderatorAiId === id) usages.push('意图分析');
     if (strategyAiId === id) usages.push('策略生成');
     if (memoryAiId === id) usages.push('记忆功能');
+    for (const c of configs) {
+      if (c.provider === 'router' && (c.routeConfigIds || []).includes(id)) {
+        usages.push(`路由"${c.name}"`);
+      }
+    }
     // 检查策略中的 agent 是否使用此配置
     for (const strategy of strategies) {
       for (const agent of strategy.agents || []) {
@@ -592,6 +657,7 @@ const ProviderSettings: React.FC<ProviderSettingsProps> = ({
     return (
       <ProviderEditView
         config={selectedConfig}
+        allConfigs={configs}
         onBack={() => { setView('list'); setSelectedConfig(null); }}
         onChange={handleUpdate}
         onDelete={() => handleDelete(selectedConfig.id)}
@@ -618,6 +684,8 @@ const ProviderSettings: React.FC<ProviderSettingsProps> = ({
       verboseAgentIO={verboseAgentIO}
       onRetryCountChange={onRetryCountChange}
       onVerboseAgentIOChange={onVerboseAgentIOChange}
+      llmCache={llmCache}
+      onLLMCacheChange={onLLMCacheChange}
     />
   );
 };
@@ -640,12 +708,14 @@ interface ProviderListViewProps {
   verboseAgentIO: boolean;
   onRetryCountChange: (count: number) => void;
   onVerboseAgentIOChange: (enabled: boolean) => void;
+  llmCache: LLMCacheConfig;
+  onLLMCacheChange: (cache: LLMCacheConfig) => void;
 }
 
 const ProviderListView: React.FC<ProviderListViewProps> = ({
   configs, onSelect, onSetDefault, onDelete, onCopy, onAdd,
   showAddModal, newProviderType, onSelectType, onConfirmAdd, onCancelAdd, getDeleteDisabledReason,
-  aiRetryCount, verboseAgentIO, onRetryCountChange, onVerboseAgentIOChange
+  aiRetryCount, verboseAgentIO, onRetryCountChange, onVerboseAgentIOChange, llmCache, onLLMCacheChange
 }) => {
   const { colors } = useTheme();
   const defaultCount = configs.filter(c => c.isDefault).length;
@@ -695,6 +765,10 @@ const ProviderListView: React.FC<ProviderListViewProps> = ({
           </div>
           <ToggleSwitch checked={verboseAgentIO} onChange={onVerboseAgentIOChange} />
         </div>
+
+        <div className="h-px fin-divider" />
+
+        <LLMCachePanel config={llmCache} onChange={onLLMCacheChange} />
       </div>
 
       {/* 配置列表 */}
@@ -749,12 +823,12 @@ const AddAIConfigModal: React.FC<AddAIConfigModalProps> = ({ selectedType, onSel
         <h3 className={`text-lg font-semibold mb-4 ${colors.isDark ? 'text-white' : 'text-slate-800'}`}>添加 AI 配置</h3>
         <div className="space-y-3 mb-5">
           <label className={`block text-sm mb-2 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>选择类型</label>
-          <div className="flex gap-2">
+          <div className="grid grid-cols-3 gap-2">
             {PROVIDERS.map(p => (
               <button
                 key={p}
                 onClick={() => onSelectType(p)}
-                className={`flex-1 px-2 py-2 text-sm rounded-lg transition-all whitespace-nowrap ${
+                className={`px-2 py-2 text-sm rounded-lg transition-all whitespace-nowrap ${
                   selectedType === p
                     ? 'bg-gradient-to-br from-[var(--accent)] to-[var(--accent-2)] text-white'
                     : (colors.isDark ? 'fin-panel border fin-divider text-slate-400 hover:text-white' : 'fin-panel border fin-divider text-slate-500 hover:text-slate-800')
@@ -820,7 +894,9 @@ const ProviderListItem: React.FC<ProviderListItemProps> = ({
                 <span className="text-xs px-1.5 py-0.5 bg-accent/20 text-accent-2 rounded">默认</span>
               )}
             </div>
-            <p className={`text-xs ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>{config.modelName}</p>
+            <p className={`text-xs ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>
+              {config.provider === 'router' ? `${(config.routeConfigIds || []).length} 个成员` : config.modelName}
+            </p>
           </div>
         </div>
         <div className="flex items-center gap-1" onClick={e => e.stopPropagation()}>
@@ -863,16 +939,18 @@ const ProviderListItem: React.FC<ProviderListItemProps> = ({
 // ========== Provider 编辑视图 ==========
 interface ProviderEditViewProps {
   config: AIConfig;
+  allConfigs: AIConfig[];
   onBack: () => void;
   onChange: (config: AIConfig) => void;
   onDelete: () => void;
 }
 
 const ProviderEditView: React.FC<ProviderEditViewProps> = ({
-  config, onBack, onChange, onDelete
+  config, allConfigs, onBack, onChange, onDelete
 }) => {
   const { colors } = useTheme();
   const isVertexAI = config.provider === 'vertexai';
+  const isRouter = config.provider === 'router';
   const [testing, setTesting] = useState(false);
   const [testResult, setTestResult] = useState<{ success: boolean; error?: string } | null>(null);
 
@@ -954,10 +1032,16 @@ const ProviderEditView: React.FC<ProviderEditViewProps> = ({
       <div className="space-y-4">
         <FormField label="配置名称" value={config.name} onChange={v => onChange({ ...config, name: v })} />
 
-        {!isVertexAI && (
+        {isRouter && (
+          <RouterConfigPanel config={config} allConfigs={allConfigs} onChange={onChange} />
+        )}
+
+        {!isVertexAI && !isRouter && (
           <>
             <FormField label="Base URL" value={config.baseUrl} onChange={v => onChange({ ...config, baseUrl: v })} />
-            <FormField label="API Key" value={config.apiKey} onChange={v => onChange({ ...config, apiKey: v })} type="password" />
+            {config.provider !== 'ollama' && (
+              <FormField label="API Key" value={config.apiKey} onChange={v => onChange({ ...config, apiKey: v })} type="password" />
+            )}
           </>
         )}
 
@@ -1002,8 +1086,18 @@ const ProviderEditView: React.FC<ProviderEditViewProps> = ({
           </>
         )}
 
+        {!isRouter && (
+        <>
         <FormField label="模型名称" value={config.modelName} onChange={v => onChange({ ...config, modelName: v })} />
 
+        {config.provider === 'ollama' && (
+          <OllamaModelPanel
+            baseUrl={config.baseUrl}
+            modelName={config.modelName}
+            onSelect={v => onChange({ ...config, modelName: v })}
+          />
+        )}
+
         {/* 温度配置 */}
         <div>
           <label className={`block text-sm mb-1.5 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>
@@ -1043,7 +1137,374 @@ const ProviderEditView: React.FC<ProviderEditViewProps> = ({
           <p className={`text-xs mt-1 ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>建议值：2048-8192，最大取决于模型,设置为0时表示不传递这个参数</p>
         </div>
 
+        {/* 客户端限流 */}
+        <div>
+          <label className={`block text-sm mb-1.5 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>客户端限流</label>
+          <div className="grid grid-cols-3 gap-2">
+            {([
+              { key: 'maxConcurrency', label: '最大并发', max: 64 },
+              { key: 'requestsPerMinute', label: '每分钟请求', max: 10000 },
+              { key: 'tokensPerMinute', label: '每分钟 Token', max: 10000000 },
+            ] as const).map(field => (
+              <div key={field.key}>
+                <input
+                  type="number"
+                  min="0"
+                  max={field.max}
+                  value={config[field.key] || 0}
+                  onChange={e => {
+                    const val = parseInt(e.target.value);
+                    onChange({ ...config, [field.key]: isNaN(val) ? 0 : val });
+                  }}
+                  className={`w-full fin-input rounded-lg px-3 py-2 text-sm ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+                  placeholder="0"
+                />
+                <span className={`block text-xs mt-1 ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>{field.label}</span>
+              </div>
+            ))}
+          </div>
+          <p className={`text-xs mt-1 ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>同一配置的所有请求共享额度，超出时排队等待；0 表示不限制</p>
+        </div>
+
+        {/* 推理设置 */}
+        <ReasoningEditor value={config.reasoning} onChange={reasoning => onChange({ ...config, reasoning })} />
+        </>
+        )}
+
+      </div>
+    </div>
+  );
+};
+
+// ========== 推理设置 ==========
+const REASONING_EFFORTS = [
+  { value: 'low', label: '低' },
+  { value: 'medium', label: '中' },
+  { value: 'high', label: '高' },
+];
+
+// inheritable 为 true 时可选择沿用 AI 配置（value 为空）
+const ReasoningEditor: React.FC<{
+  value?: ReasoningConfig;
+  onChange: (value?: ReasoningConfig) => void;
+  inheritable?: boolean;
+}> = ({ value, onChange, inheritable }) => {
+  const { colors } = useTheme();
+  const current: ReasoningConfig = value || { enabled: false, budgetTokens: 0, effort: 'medium' };
+  const mode = !value ? (inheritable ? 'inherit' : 'off') : value.enabled ? 'on' : 'off';
+
+  const setMode = (next: string) => {
+    if (next === 'inherit') {
+      onChange(undefined);
+    } else {
+      onChange({ ...current, enabled: next === 'on' });
+    }
+  };
+
+  return (
+    <div>
+      <label className={`block text-sm mb-1.5 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>推理 / 扩展思考</label>
+      <div className="grid grid-cols-3 gap-2">
+        <select
+          value={mode}
+          onChange={e => setMode(e.target.value)}
+          className={`w-full fin-input rounded-lg px-3 py-2 text-sm ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+        >
+          {inheritable && <option value="inherit">沿用 AI 配置</option>}
+          <option value="off">关闭</option>
+          <option value="on">开启</option>
+        </select>
+        {mode === 'on' && (
+          <>
+            <select
+              value={current.effort || 'medium'}
+              onChange={e => onChange({ ...current, effort: e.target.value })}
+              disabled={current.budgetTokens > 0}
+              className={`w-full fin-input rounded-lg px-3 py-2 text-sm disabled:opacity-50 ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+            >
+              {REASONING_EFFORTS.map(e => (
+                <option key={e.value} value={e.value}>强度：{e.label}</option>
+              ))}
+            </select>
+            <input
+              type="number"
+              min="0"
+              max="128000"
+              step="1024"
+              value={current.budgetTokens || 0}
+              onChange={e => {
+                const val = parseInt(e.target.value);
+                onChange({ ...current, budgetTokens: isNaN(val) || val < 0 ? 0 : val });
+              }}
+              className={`w-full fin-input rounded-lg px-3 py-2 text-sm ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+              placeholder="预算 Token"
+            />
+          </>
+        )}
       </div>
+      <p className={`text-xs mt-1 ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>
+        预算 Token 大于 0 时优先生效（Anthropic 最低 1024），否则按强度；思考过程在会议中折叠展示，不计入发言
+      </p>
+    </div>
+  );
+};
+
+// ========== LLM 响应缓存面板 ==========
+const LLMCachePanel: React.FC<{ config: LLMCacheConfig; onChange: (config: LLMCacheConfig) => void }> = ({ config, onChange }) => {
+  const { colors } = useTheme();
+  const [stats, setStats] = useState<{ hits: number; misses: number; hitRate: number; entries: number; sizeBytes: number } | null>(null);
+
+  const refreshStats = useCallback(() => {
+    getLLMCacheStats().then(setStats).catch(() => setStats(null));
+  }, []);
+
+  useEffect(() => {
+    refreshStats();
+  }, [refreshStats]);
+
+  const handleClear = async () => {
+    await clearLLMCache();
+    refreshStats();
+  };
+
+  const setTTL = (key: 'tradingTtlMinutes' | 'closedTtlMinutes', value: string) => {
+    const val = parseInt(value);
+    onChange({ ...config, [key]: isNaN(val) || val <= 0 ? 1 : val });
+  };
+
+  return (
+    <div className="space-y-2">
+      <div className="flex items-center justify-between gap-3">
+        <div>
+          <div className={`text-sm font-medium ${colors.isDark ? 'text-white' : 'text-slate-800'}`}>LLM 响应缓存</div>
+          <div className={`text-xs mt-1 ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>相同问题、相同行情下复用专家回复，会议中可单次跳过</div>
+        </div>
+        <ToggleSwitch checked={config.enabled} onChange={v => onChange({ ...config, enabled: v })} />
+      </div>
+      {config.enabled && (
+        <div className="grid grid-cols-2 gap-2">
+          <label className={`text-xs ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>
+            交易时段有效期（分钟）
+            <input
+              type="number"
+              min="1"
+              value={config.tradingTtlMinutes}
+              onChange={e => setTTL('tradingTtlMinutes', e.target.value)}
+              className={`w-full fin-input rounded-lg px-2 py-1 text-sm mt-1 ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+            />
+          </label>
+          <label className={`text-xs ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>
+            收盘后有效期（分钟）
+            <input
+              type="number"
+              min="1"
+              value={config.closedTtlMinutes}
+              onChange={e => setTTL('closedTtlMinutes', e.target.value)}
+              className={`w-full fin-input rounded-lg px-2 py-1 text-sm mt-1 ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+            />
+          </label>
+        </div>
+      )}
+      {stats && (
+        <div className={`flex items-center justify-between text-xs ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>
+          <span>
+            命中 {stats.hits} / 未命中 {stats.misses}（{(stats.hitRate * 100).toFixed(0)}%）· {stats.entries} 条 · {(stats.sizeBytes / 1024).toFixed(0)} KB
+          </span>
+          <button onClick={handleClear} className="text-red-400 hover:text-red-300">清空</button>
+        </div>
+      )}
+    </div>
+  );
+};
+
+// ========== 路由配置面板 ==========
+const RouterConfigPanel: React.FC<{ config: AIConfig; allConfigs: AIConfig[]; onChange: (config: AIConfig) => void }> = ({
+  config, allConfigs, onChange
+}) => {
+  const { colors } = useTheme();
+  const memberIds = config.routeConfigIds || [];
+  const fallbackOn = config.routeFallbackOn || [];
+  const candidates = allConfigs.filter(c => c.provider !== 'router' && !memberIds.includes(c.id));
+  const labelClass = `block text-sm mb-1.5 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`;
+  const hintClass = `text-xs mt-1 ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`;
+
+  const setMembers = (ids: string[]) => onChange({ ...config, routeConfigIds: ids });
+  const moveMember = (index: number, delta: number) => {
+    const target = index + delta;
+    if (target < 0 || target >= memberIds.length) return;
+    const ids = [...memberIds];
+    [ids[index], ids[target]] = [ids[target], ids[index]];
+    setMembers(ids);
+  };
+  const toggleFallback = (value: string) => {
+    const next = fallbackOn.includes(value) ? fallbackOn.filter(v => v !== value) : [...fallbackOn, value];
+    onChange({ ...config, routeFallbackOn: next });
+  };
+
+  return (
+    <>
+      <div>
+        <label className={labelClass}>成员配置（按优先级）</label>
+        <div className="space-y-1.5">
+          {memberIds.map((id, index) => {
+            const member = allConfigs.find(c => c.id === id);
+            return (
+              <div key={id} className="flex items-center gap-2 fin-panel border fin-divider rounded-lg px-3 py-1.5">
+                <span className={`text-xs w-4 ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>{index + 1}</span>
+                <span className={`flex-1 text-sm truncate ${member ? (colors.isDark ? 'text-white' : 'text-slate-800') : 'text-red-400'}`}>
+                  {member ? `${member.name} · ${member.modelName}` : `${id}（已删除）`}
+                </span>
+                <button onClick={() => moveMember(index, -1)} disabled={index === 0} className="text-xs px-1 disabled:opacity-30" title="上移">↑</button>
+                <button onClick={() => moveMember(index, 1)} disabled={index === memberIds.length - 1} className="text-xs px-1 disabled:opacity-30" title="下移">↓</button>
+                <button onClick={() => setMembers(memberIds.filter(m => m !== id))} className="p-1 text-slate-400 hover:text-red-400" title="移除">
+                  <Trash2 className="h-3.5 w-3.5" />
+                </button>
+              </div>
+            );
+          })}
+        </div>
+        {candidates.length > 0 && (
+          <select
+            value=""
+            onChange={e => e.target.value && setMembers([...memberIds, e.target.value])}
+            className={`w-full fin-input rounded-lg px-3 py-2 text-sm mt-2 ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+          >
+            <option value="">+ 添加成员</option>
+            {candidates.map(c => (
+              <option key={c.id} value={c.id}>{c.name} · {c.modelName}</option>
+            ))}
+          </select>
+        )}
+        <p className={hintClass}>请求失败时按顺序切换到下一个成员；成员自身的限流额度同样生效，额度不足的成员会延后尝试</p>
+      </div>
+
+      <div>
+        <label className={labelClass}>选择策略</label>
+        <select
+          value={config.routeStrategy || 'ordered'}
+          onChange={e => onChange({ ...config, routeStrategy: e.target.value })}
+          className={`w-full fin-input rounded-lg px-3 py-2 text-sm ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+        >
+          <option value="ordered">按顺序优先</option>
+          <option value="latency">按首包延迟优先</option>
+        </select>
+      </div>
+
+      <div>
+        <label className={labelClass}>触发回退的错误</label>
+        <div className="flex flex-wrap gap-2">
+          {ROUTE_FALLBACK_OPTIONS.map(option => (
+            <button
+              key={option.value}
+              onClick={() => toggleFallback(option.value)}
+              className={`px-2.5 py-1 text-xs rounded-lg border transition-colors ${
+                fallbackOn.includes(option.value)
+                  ? 'border-accent/50 bg-accent/10 text-accent-2'
+                  : (colors.isDark ? 'fin-divider text-slate-400' : 'fin-divider text-slate-500')
+              }`}
+            >
+              {option.label}
+            </button>
+          ))}
+        </div>
+        <p className={hintClass}>不选择时以上类别均会触发回退；已输出内容后的失败不会切换</p>
+      </div>
+    </>
+  );
+};
+
+// ========== Ollama 本地模型面板 ==========
+const OllamaModelPanel: React.FC<{ baseUrl: string; modelName: string; onSelect: (v: string) => void }> = ({
+  baseUrl, modelName, onSelect
+}) => {
+  const { colors } = useTheme();
+  const [models, setModels] = useState<string[]>([]);
+  const [loading, setLoading] = useState(false);
+  const [pullStatus, setPullStatus] = useState<{ status: string; percent: number; done: boolean; error?: string } | null>(null);
+
+  const refresh = useCallback(async () => {
+    setLoading(true);
+    try {
+      const list = await listOllamaModels(baseUrl);
+      setModels((list || []).map(m => m.name));
+    } finally {
+      setLoading(false);
+    }
+  }, [baseUrl]);
+
+  useEffect(() => {
+    refresh();
+  }, [refresh]);
+
+  useEffect(() => {
+    return onOllamaPull(status => {
+      if (status.model !== modelName) return;
+      setPullStatus({ status: status.status, percent: status.percent, done: status.done, error: status.error });
+      if (status.done && !status.error) refresh();
+    });
+  }, [modelName, refresh]);
+
+  const handlePull = async () => {
+    setPullStatus({ status: 'pending', percent: 0, done: false });
+    const result = await pullOllamaModel(baseUrl, modelName);
+    if (result !== 'success') {
+      setPullStatus({ status: 'error', percent: 0, done: true, error: result });
+    }
+  };
+
+  const pulled = models.some(m => m === modelName || m === `${modelName}:latest`);
+  const pulling = pullStatus !== null && !pullStatus.done;
+
+  return (
+    <div className="space-y-2">
+      <div className="flex items-center justify-between">
+        <label className={`text-sm ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>本地模型</label>
+        <div className="flex items-center gap-2">
+          <button
+            onClick={refresh}
+            disabled={loading}
+            className={`p-1.5 rounded-lg transition-colors disabled:opacity-50 ${colors.isDark ? 'hover:bg-slate-700/60 text-slate-400' : 'hover:bg-slate-200/60 text-slate-500'}`}
+          >
+            <RefreshCw className={`h-3.5 w-3.5 ${loading ? 'animate-spin' : ''}`} />
+          </button>
+          {!pulled && modelName && (
+            <button
+              onClick={handlePull}
+              disabled={pulling}
+              className={`flex items-center gap-1 px-2 py-1 text-xs rounded-lg disabled:opacity-50 ${colors.isDark ? 'bg-slate-700 hover:bg-slate-600 text-slate-300' : 'bg-slate-200 hover:bg-slate-300 text-slate-600'}`}
+            >
+              <Download className="h-3 w-3" />
+              拉取 {modelName}
+            </button>
+          )}
+        </div>
+      </div>
+      {models.length > 0 ? (
+        <div className="flex flex-wrap gap-1.5">
+          {models.map(name => (
+            <button
+              key={name}
+              onClick={() => onSelect(name)}
+              className={`px-2 py-1 text-xs rounded-md transition-colors ${name === modelName ? 'bg-accent/20 text-accent-2' : colors.isDark ? 'bg-slate-700/60 text-slate-300 hover:bg-slate-700' : 'bg-slate-200/60 text-slate-600 hover:bg-slate-200'}`}
+            >
+              {name}
+            </button>
+          ))}
+        </div>
+      ) : (
+        <p className={`text-xs ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>
+          {loading ? '加载中...' : '未检测到本地模型，请确认 Ollama 已启动'}
+        </p>
+      )}
+      {pullStatus && (
+        <p className={`text-xs ${pullStatus.error ? 'text-red-400' : colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>
+          {pullStatus.error
+            ? `拉取失败：${pullStatus.error}`
+            : pullStatus.done
+              ? '拉取完成'
+              : `${pullStatus.status}${pullStatus.percent > 0 ? ` ${pullStatus.percent.toFixed(1)}%` : ''}`}
+        </p>
+      )}
     </div>
   );
 };
@@ -1069,9 +1530,11 @@ interface IntentSettingsProps {
   moderatorAiId: string;
   agentSelectionStyle: AgentSelectionStyle;
   enableSecondReview: boolean;
+  autoBriefing: boolean;
   onModeratorAiIdChange: (id: string) => void;
   onAgentSelectionStyleChange: (style: AgentSelectionStyle) => void;
   onEnableSecondReviewChange: (enabled: boolean) => void;
+  onAutoBriefingChange: (enabled: boolean) => void;
 }
 
 const IntentSettings: React.FC<IntentSettingsProps> = ({
@@ -1079,9 +1542,11 @@ const IntentSettings: React.FC<IntentSettingsProps> = ({
   moderatorAiId,
   agentSelectionStyle,
   enableSecondReview,
+  autoBriefing,
   onModeratorAiIdChange,
   onAgentSelectionStyleChange,
   onEnableSecondReviewChange,
+  onAutoBriefingChange,
 }) => {
   const { colors } = useTheme();
   const selectedConfig = configs.find(c => c.id === moderatorAiId);
@@ -1150,6 +1615,14 @@ const IntentSettings: React.FC<IntentSettingsProps> = ({
           <ToggleSwitch checked={enableSecondReview} onChange={onEnableSecondReviewChange} />
         </div>
 
+        <div className="mt-4 flex items-center justify-between gap-3">
+          <div>
+            <div className={`text-sm font-medium ${colors.isDark ? 'text-white' : 'text-slate-800'}`}>财报前瞻自动会议</div>
+            <div className={`text-xs mt-1 ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>自选股财报披露前自动召开前瞻会议，会消耗 AI 额度；该股有会议进行中时顺延</div>
+          </div>
+          <ToggleSwitch checked={autoBriefing} onChange={onAutoBriefingChange} />
+        </div>
+
         {/* 当前选择的配置信息 */}
         {(selectedConfig || defaultConfig) && (
           <div className="mt-4 pt-4 border-t fin-divider">
@@ -1205,6 +1678,7 @@ const getDefaultBaseUrl = (provider: string): string => {
     case 'openai': return 'https://api.openai.com/v1';
     case 'gemini': return 'https://generativelanguage.googleapis.com';
     case 'anthropic': return 'https://api.anthropic.com';
+    case 'ollama': return 'http://localhost:11434';
     default: return '';
   }
 };
@@ -1215,6 +1689,7 @@ const getDefaultModel = (provider: string): string => {
     case 'gemini': return 'gemini-2.5-flash';
     case 'vertexai': return 'gemini-2.5-flash';
     case 'anthropic': return 'claude-sonnet-4-20250514';
+    case 'ollama': return 'qwen3:8b';
     default: return '';
   }
 };
@@ -1479,6 +1954,94 @@ const MCPListItem: React.FC<{
 };
 
 // ========== MCP 编辑表单 ==========
+// 键值对编辑（每行一项），保留原始输入文本，解析结果为空时回传 undefined
+interface KeyValueFieldProps {
+  label: string;
+  separator: string;
+  placeholder: string;
+  value?: Record<string, string>;
+  onChange: (value: Record<string, string> | undefined) => void;
+}
+
+const KeyValueField: React.FC<KeyValueFieldProps> = ({ label, separator, placeholder, value, onChange }) => {
+  const { colors } = useTheme();
+  const [text, setText] = useState(() =>
+    Object.entries(value || {}).map(([k, v]) => `${k}${separator === ':' ? ': ' : separator}${v}`).join('\n')
+  );
+
+  const handleText = (next: string) => {
+    setText(next);
+    const result: Record<string, string> = {};
+    for (const line of next.split('\n')) {
+      const idx = line.indexOf(separator);
+      if (idx <= 0) continue;
+      const key = line.slice(0, idx).trim();
+      if (key) result[key] = line.slice(idx + 1).trim();
+    }
+    onChange(Object.keys(result).length > 0 ? result : undefined);
+  };
+
+  return (
+    <div>
+      <label className={`block text-sm mb-1.5 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>{label}</label>
+      <textarea
+        value={text}
+        onChange={e => handleText(e.target.value)}
+        rows={3}
+        placeholder={placeholder}
+        className={`w-full fin-input rounded-lg px-3 py-2 text-sm resize-none font-mono ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+      />
+    </div>
+  );
+};
+
+// MCP HTTP 鉴权编辑
+interface MCPAuthEditorProps {
+  value?: MCPAuthConfig;
+  onChange: (value: MCPAuthConfig | undefined) => void;
+}
+
+const MCPAuthEditor: React.FC<MCPAuthEditorProps> = ({ value, onChange }) => {
+  const { colors } = useTheme();
+  const auth: MCPAuthConfig = value || { type: '' };
+  const update = (patch: Partial<MCPAuthConfig>) => onChange({ ...auth, ...patch });
+
+  return (
+    <>
+      <div>
+        <label className={`block text-sm mb-1.5 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>鉴权方式</label>
+        <select
+          value={auth.type}
+          onChange={e => {
+            const type = e.target.value as MCPAuthType;
+            onChange(type ? { ...auth, type } : undefined);
+          }}
+          className={`w-full fin-input rounded-lg px-3 py-2 text-sm ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+        >
+          <option value="">无</option>
+          <option value="bearer">Bearer Token</option>
+          <option value="client_credentials">OAuth 客户端凭据</option>
+        </select>
+      </div>
+      {auth.type === 'bearer' && (
+        <FormField label="Token (可用 ${VAR})" type="password" value={auth.token || ''} onChange={v => update({ token: v })} />
+      )}
+      {auth.type === 'client_credentials' && (
+        <>
+          <FormField label="Token URL" value={auth.tokenUrl || ''} onChange={v => update({ tokenUrl: v })} />
+          <FormField label="Client ID" value={auth.clientId || ''} onChange={v => update({ clientId: v })} />
+          <FormField label="Client Secret" type="password" value={auth.clientSecret || ''} onChange={v => update({ clientSecret: v })} />
+          <FormField
+            label="Scopes (逗号分隔)"
+            value={(auth.scopes || []).join(', ')}
+            onChange={v => update({ scopes: v.split(',').map(s => s.trim()).filter(Boolean) })}
+          />
+        </>
+      )}
+    </>
+  );
+};
+
 interface MCPEditFormProps {
   server: MCPServerConfig;
   status?: MCPServerStatus;
@@ -1552,11 +2115,47 @@ const MCPEditForm: React.FC<MCPEditFormProps> = ({ server, status, tools, onBack
             value={edited.args.join(', ')}
             onChange={v => handleChange('args', v.split(',').map(s => s.trim()).filter(Boolean))}
           />
+          <FormField label="工作目录 (可选)" value={edited.workingDir || ''} onChange={v => handleChange('workingDir', v)} />
         </>
       ) : (
-        <FormField label="端点 URL" value={edited.endpoint} onChange={v => handleChange('endpoint', v)} />
+        <>
+          <FormField label="端点 URL" value={edited.endpoint} onChange={v => handleChange('endpoint', v)} />
+          <KeyValueField
+            label="请求头 (每行 Name: Value)"
+            separator=":"
+            placeholder={'X-Api-Key: ${API_KEY}'}
+            value={edited.headers}
+            onChange={v => handleChange('headers', v)}
+          />
+          <MCPAuthEditor value={edited.auth} onChange={v => handleChange('auth', v)} />
+        </>
       )}
 
+      {/* 环境变量：命令行传输注入子进程，HTTP/SSE 供请求头与鉴权中的 ${VAR} 引用 */}
+      <KeyValueField
+        label="环境变量 (每行 KEY=VALUE)"
+        separator="="
+        placeholder="API_KEY=sk-..."
+        value={edited.env}
+        onChange={v => handleChange('env', v)}
+      />
+
+      {/* 超时 */}
+      <div className="grid grid-cols-2 gap-3">
+        <FormField
+          label="连接超时 (秒, 0=默认10)"
+          type="number"
+          value={String(edited.connectTimeoutSeconds || 0)}
+          onChange={v => handleChange('connectTimeoutSeconds', Math.max(0, parseInt(v) || 0))}
+        />
+        <FormField
+          label="调用超时 (秒, 0=默认60)"
+          type="number"
+          value={String(edited.callTimeoutSeconds || 0)}
+          onChange={v => handleChange('callTimeoutSeconds', Math.max(0, parseInt(v) || 0))}
+        />
+      </div>
+
       {/* 启用状态 */}
       <div className="flex items-center justify-between pt-2">
         <span className={`text-sm ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>启用此服务</span>
@@ -2687,6 +3286,15 @@ const StrategyAgentEdit: React.FC<StrategyAgentEditProps> = ({
     handleChange('mcpServers', newServers);
   };
 
+  const toggleMCPResource = (resource: MCPResourceInfo) => {
+    const current = editedAgent.mcpResources || [];
+    const exists = current.some(r => r.serverId === resource.serverId && r.uri === resource.uri);
+    const next: MCPResourceRef[] = exists
+      ? current.filter(r => !(r.serverId === resource.serverId && r.uri === resource.uri))
+      : [...current, { serverId: resource.serverId, uri: resource.uri, name: resource.name }];
+    handleChange('mcpResources', next);
+  };
+
   const selectedToolsCount = (editedAgent.tools || []).length;
   const selectedMCPCount = (editedAgent.mcpServers || []).length;
 
@@ -2713,6 +3321,7 @@ const StrategyAgentEdit: React.FC<StrategyAgentEditProps> = ({
         <AgentBasicConfig
           agent={editedAgent}
           aiConfigs={aiConfigs}
+          mcpServers={mcpServers}
           onChange={handleChange}
         />
       )}
@@ -2725,6 +3334,7 @@ const StrategyAgentEdit: React.FC<StrategyAgentEditProps> = ({
           mcpServers={mcpServers}
           onToggleTool={toggleTool}
           onToggleMCPServer={toggleMCPServer}
+          onToggleMCPResource={toggleMCPResource}
         />
       )}
     </div>
@@ -2827,12 +3437,14 @@ const AgentEditTabs: React.FC<AgentEditTabsProps> = ({
 interface AgentBasicConfigProps {
   agent: StrategyAgent;
   aiConfigs: AIConfig[];
+  mcpServers: MCPServerConfig[];
   onChange: <K extends keyof StrategyAgent>(field: K, value: StrategyAgent[K]) => void;
 }
 
-const AgentBasicConfig: React.FC<AgentBasicConfigProps> = ({ agent, aiConfigs, onChange }) => {
+const AgentBasicConfig: React.FC<AgentBasicConfigProps> = ({ agent, aiConfigs, mcpServers, onChange }) => {
   const { colors } = useTheme();
   const [enhancing, setEnhancing] = useState(false);
+  const [showPromptImport, setShowPromptImport] = useState(false);
 
   const handleEnhance = async () => {
     if (!agent.instruction?.trim()) return;
@@ -2876,28 +3488,68 @@ const AgentBasicConfig: React.FC<AgentBasicConfigProps> = ({ agent, aiConfigs, o
         <p className={`text-xs mt-1 ${colors.isDark ? 'text-slate-500' : 'text-slate-500'}`}>为该专家指定专用的 AI 模型，留空则使用系统默认配置</p>
       </div>
 
+      {/* 推理设置 */}
+      <ReasoningEditor value={agent.reasoning} onChange={v => onChange('reasoning', v)} inheritable />
+
+      {/* 图像输入 */}
+      <div className="flex items-center justify-between">
+        <div>
+          <label className={`block text-sm ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>K线图输入</label>
+          <p className={`text-xs mt-1 ${colors.isDark ? 'text-slate-500' : 'text-slate-500'}`}>模型支持图像输入时开启，发言时随指令附带日K线图（含成交量与已启用指标）</p>
+        </div>
+        <label className="relative inline-flex items-center cursor-pointer">
+          <input
+            type="checkbox"
+            checked={!!agent.vision}
+            onChange={e => onChange('vision', e.target.checked)}
+            className="sr-only peer"
+          />
+          <div className={`w-11 h-6 peer-focus:outline-none rounded-full peer peer-checked:after:translate-x-full peer-checked:after:border-white after:content-[''] after:absolute after:top-[2px] after:left-[2px] after:bg-white after:rounded-full after:h-5 after:w-5 after:transition-all peer-checked:bg-accent ${colors.isDark ? 'bg-slate-700' : 'bg-slate-400'}`}></div>
+        </label>
+      </div>
+
       {/* 系统指令 */}
       <div>
         <div className="flex items-center justify-between mb-1.5">
           <label className={`block text-sm ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>系统指令 (Prompt)</label>
-          <button
-            onClick={handleEnhance}
-            disabled={enhancing || !agent.instruction?.trim()}
-            className="flex items-center gap-1.5 px-2 py-1 text-xs bg-gradient-to-br from-[var(--accent)] to-[var(--accent-2)] text-white rounded-lg disabled:opacity-50 hover:opacity-90 transition-opacity"
-          >
-            {enhancing ? (
-              <>
-                <Loader2 className="h-3 w-3 animate-spin" />
-                增强中...
-              </>
-            ) : (
-              <>
-                <Sparkles className="h-3 w-3" />
-                AI 增强
-              </>
+          <div className="flex items-center gap-2">
+            {mcpServers.length > 0 && (
+              <button
+                onClick={() => setShowPromptImport(v => !v)}
+                className={`flex items-center gap-1.5 px-2 py-1 text-xs rounded-lg transition-colors ${colors.isDark ? 'bg-slate-700 hover:bg-slate-600 text-slate-300' : 'bg-slate-200 hover:bg-slate-300 text-slate-600'}`}
+              >
+                <Plug className="h-3 w-3" />
+                导入 MCP 提示词
+              </button>
             )}
-          </button>
+            <button
+              onClick={handleEnhance}
+              disabled={enhancing || !agent.instruction?.trim()}
+              className="flex items-center gap-1.5 px-2 py-1 text-xs bg-gradient-to-br from-[var(--accent)] to-[var(--accent-2)] text-white rounded-lg disabled:opacity-50 hover:opacity-90 transition-opacity"
+            >
+              {enhancing ? (
+                <>
+                  <Loader2 className="h-3 w-3 animate-spin" />
+                  增强中...
+                </>
+              ) : (
+                <>
+                  <Sparkles className="h-3 w-3" />
+                  AI 增强
+                </>
+              )}
+            </button>
+          </div>
         </div>
+        {showPromptImport && (
+          <MCPPromptImporter
+            mcpServers={mcpServers}
+            onImport={text => {
+              onChange('instruction', text);
+              setShowPromptImport(false);
+            }}
+          />
+        )}
         <textarea
           value={agent.instruction || ""}
           onChange={e => onChange("instruction", e.target.value)}
@@ -2910,6 +3562,107 @@ const AgentBasicConfig: React.FC<AgentBasicConfigProps> = ({ agent, aiConfigs, o
   );
 };
 
+// MCP 提示词导入（渲染后作为专家指令）
+const MCPPromptImporter: React.FC<{ mcpServers: MCPServerConfig[]; onImport: (text: string) => void }> = ({ mcpServers, onImport }) => {
+  const { colors } = useTheme();
+  const [serverId, setServerId] = useState(mcpServers[0]?.id || '');
+  const [prompts, setPrompts] = useState<MCPPromptInfo[]>([]);
+  const [promptName, setPromptName] = useState('');
+  const [args, setArgs] = useState<Record<string, string>>({});
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState('');
+
+  useEffect(() => {
+    if (!serverId) return;
+    setLoading(true);
+    setError('');
+    setPromptName('');
+    getMCPServerPrompts(serverId)
+      .then(list => {
+        setPrompts(list);
+        if (list.length === 0) setError('该服务器未提供提示词');
+      })
+      .catch(() => setError('获取提示词失败'))
+      .finally(() => setLoading(false));
+  }, [serverId]);
+
+  const selected = prompts.find(p => p.name === promptName);
+  const missingRequired = !!selected?.arguments.some(a => a.required && !args[a.name]?.trim());
+
+  const handleImport = async () => {
+    if (!selected) return;
+    setLoading(true);
+    setError('');
+    try {
+      const result = await getMCPPrompt(serverId, selected.name, args);
+      if (result.error) {
+        setError(result.error);
+      } else if (!result.text.trim()) {
+        setError('提示词内容为空');
+      } else {
+        onImport(result.text);
+      }
+    } catch (e) {
+      setError('渲染提示词失败');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  return (
+    <div className={`mb-2 p-3 rounded-lg border space-y-2 ${colors.isDark ? 'border-slate-700 bg-slate-800/40' : 'border-slate-300 bg-slate-100/60'}`}>
+      <div className="flex gap-2">
+        <select
+          value={serverId}
+          onChange={e => setServerId(e.target.value)}
+          className={`flex-1 fin-input rounded-lg px-3 py-1.5 text-sm ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+        >
+          {mcpServers.map(server => (
+            <option key={server.id} value={server.id}>{server.name}</option>
+          ))}
+        </select>
+        <select
+          value={promptName}
+          onChange={e => {
+            setPromptName(e.target.value);
+            setArgs({});
+          }}
+          disabled={loading || prompts.length === 0}
+          className={`flex-1 fin-input rounded-lg px-3 py-1.5 text-sm ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+        >
+          <option value="">选择提示词</option>
+          {prompts.map(p => (
+            <option key={p.name} value={p.name}>{p.name}</option>
+          ))}
+        </select>
+      </div>
+      {selected?.description && (
+        <p className={`text-xs ${colors.isDark ? 'text-slate-500' : 'text-slate-500'}`}>{selected.description}</p>
+      )}
+      {selected?.arguments.map(arg => (
+        <input
+          key={arg.name}
+          value={args[arg.name] || ''}
+          onChange={e => setArgs({ ...args, [arg.name]: e.target.value })}
+          placeholder={`${arg.name}${arg.required ? ' *' : ''}${arg.description ? ` - ${arg.description}` : ''}`}
+          className={`w-full fin-input rounded-lg px-3 py-1.5 text-sm ${colors.isDark ? 'text-white' : 'text-slate-800'}`}
+        />
+      ))}
+      <div className="flex items-center justify-between">
+        <span className="text-xs text-red-400">{error}</span>
+        <button
+          onClick={handleImport}
+          disabled={loading || !selected || missingRequired}
+          className="flex items-center gap-1.5 px-2 py-1 text-xs bg-accent text-white rounded-lg disabled:opacity-50 hover:opacity-90 transition-opacity"
+        >
+          {loading ? <Loader2 className="h-3 w-3 animate-spin" /> : <Download className="h-3 w-3" />}
+          替换当前指令
+        </button>
+      </div>
+    </div>
+  );
+};
+
 // 专家工具配置
 interface AgentToolsConfigProps {
   agent: StrategyAgent;
@@ -2917,10 +3670,11 @@ interface AgentToolsConfigProps {
   mcpServers: MCPServerConfig[];
   onToggleTool: (toolName: string) => void;
   onToggleMCPServer: (serverId: string) => void;
+  onToggleMCPResource: (resource: MCPResourceInfo) => void;
 }
 
 const AgentToolsConfig: React.FC<AgentToolsConfigProps> = ({
-  agent, availableTools, mcpServers, onToggleTool, onToggleMCPServer
+  agent, availableTools, mcpServers, onToggleTool, onToggleMCPServer, onToggleMCPResource
 }) => {
   const { colors } = useTheme();
   const selectedTools = agent.tools || [];
@@ -3003,6 +3757,94 @@ const AgentToolsConfig: React.FC<AgentToolsConfigProps> = ({
           </div>
         </div>
       )}
+
+      {/* MCP 资源 */}
+      {mcpServers.length > 0 && (
+        <AgentMCPResources agent={agent} mcpServers={mcpServers} onToggle={onToggleMCPResource} />
+      )}
+    </div>
+  );
+};
+
+// 专家附加的 MCP 资源，内容在发言时注入上下文
+const AgentMCPResources: React.FC<{
+  agent: StrategyAgent;
+  mcpServers: MCPServerConfig[];
+  onToggle: (resource: MCPResourceInfo) => void;
+}> = ({ agent, mcpServers, onToggle }) => {
+  const { colors } = useTheme();
+  const [resources, setResources] = useState<MCPResourceInfo[]>([]);
+  const [loading, setLoading] = useState(false);
+  const [loaded, setLoaded] = useState(false);
+  const attached = agent.mcpResources || [];
+
+  const loadResources = async () => {
+    setLoading(true);
+    try {
+      const lists = await Promise.all(mcpServers.map(server => getMCPServerResources(server.id).catch(() => [])));
+      setResources(lists.flat());
+      setLoaded(true);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  // 已附加但当前未加载到的资源也要展示，便于取消
+  const items: MCPResourceInfo[] = [...resources];
+  attached.forEach(ref => {
+    if (!items.some(r => r.serverId === ref.serverId && r.uri === ref.uri)) {
+      const server = mcpServers.find(s => s.id === ref.serverId);
+      items.push({ uri: ref.uri, name: ref.name, description: '', mimeType: '', serverId: ref.serverId, serverName: server?.name || ref.serverId });
+    }
+  });
+
+  return (
+    <div className="space-y-2">
+      <div className="flex items-center justify-between mb-2">
+        <label className={`text-sm flex items-center gap-1.5 ${colors.isDark ? 'text-slate-400' : 'text-slate-500'}`}>
+          <FileText className="h-4 w-4" />
+          MCP 资源
+          <span className={`text-xs ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>({attached.length})</span>
+        </label>
+        <button
+          onClick={loadResources}
+          disabled={loading}
+          className={`flex items-center gap-1 px-2 py-1 text-xs rounded-lg transition-colors disabled:opacity-50 ${colors.isDark ? 'bg-slate-700 hover:bg-slate-600 text-slate-300' : 'bg-slate-200 hover:bg-slate-300 text-slate-600'}`}
+        >
+          {loading ? <Loader2 className="h-3 w-3 animate-spin" /> : <RefreshCw className="h-3 w-3" />}
+          {loaded ? '刷新' : '加载资源'}
+        </button>
+      </div>
+      <p className={`text-xs ${colors.isDark ? 'text-slate-500' : 'text-slate-500'}`}>勾选的资源内容会作为参考资料附加到专家上下文（过长内容自动截断）</p>
+      {loaded && items.length === 0 && (
+        <p className={`text-xs ${colors.isDark ? 'text-slate-500' : 'text-slate-400'}`}>已启用的 MCP 服务器未提供资源</p>
+      )}
+      <div className="grid grid-cols-1 gap-2 max-h-48 overflow-y-auto fin-scrollbar">
+        {items.map(resource => {
+          const isSelected = attached.some(r => r.serverId === resource.serverId && r.uri === resource.uri);
+          return (
+            <div
+              key={`${resource.serverId}:${resource.uri}`}
+              onClick={() => onToggle(resource)}
+              className={`flex items-center gap-3 p-3 rounded-lg border cursor-pointer transition-all ${
+                isSelected
+                  ? "border-purple-500/50 bg-purple-500/10"
+                  : (colors.isDark ? "border-slate-700 hover:border-slate-600 hover:bg-slate-800/40" : "border-slate-300 hover:border-slate-400 hover:bg-slate-100/40")
+              }`}
+            >
+              <div className={`w-5 h-5 rounded flex items-center justify-center shrink-0 ${
+                isSelected ? "bg-purple-500 text-white" : (colors.isDark ? "bg-slate-700 border border-slate-600" : "bg-slate-200 border border-slate-300")
+              }`}>
+                {isSelected && <Check className="h-3 w-3" />}
+              </div>
+              <div className="flex-1 min-w-0">
+                <div className={`text-sm font-medium truncate ${colors.isDark ? 'text-white' : 'text-slate-800'}`}>{resource.name || resource.uri}</div>
+                <div className={`text-xs truncate ${colors.isDark ? 'text-slate-500' : 'text-slate-500'}`}>{resource.serverName} · {resource.description || resource.uri}</div>
+              </div>
+            </div>
+          );
+        })}
+      </div>
     </div>
   );
 };
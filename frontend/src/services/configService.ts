@@ -1,6 +1,7 @@
 // 配置服务 - 调用后端API
-import { GetConfig, UpdateConfig, GetAvailableTools, TestAIConnection } from '@wailsjs/go/main/App';
-import type { models } from '@wailsjs/go/models';
+import { GetConfig, UpdateConfig, GetAvailableTools, TestAIConnection, ListOllamaModels, PullOllamaModel, GetLLMCacheStats, ClearLLMCache } from '@wailsjs/go/main/App';
+import { EventsOn } from '@wailsjs/runtime/runtime';
+import type { models, ollama, llmcache } from '@wailsjs/go/models';
 
 export type AppConfig = models.AppConfig;
 
@@ -27,3 +28,28 @@ export const getAvailableTools = async (): Promise<ToolInfo[]> => {
 export const testAIConnection = async (config: models.AIConfig): Promise<string> => {
   return await TestAIConnection(config);
 };
+
+// 获取 Ollama 本地模型列表
+export const listOllamaModels = async (baseUrl: string): Promise<ollama.ModelInfo[]> => {
+  return await ListOllamaModels(baseUrl);
+};
+
+// 后台拉取 Ollama 模型
+export const pullOllamaModel = async (baseUrl: string, modelName: string): Promise<string> => {
+  return await PullOllamaModel(baseUrl, modelName);
+};
+
+// 订阅 Ollama 模型拉取进度，返回取消订阅函数
+export const onOllamaPull = (callback: (status: ollama.PullStatus) => void): (() => void) => {
+  return EventsOn('ollama:pull', callback);
+};
+
+// 获取 LLM 响应缓存命中统计
+export const getLLMCacheStats = async (): Promise<llmcache.Stats> => {
+  return await GetLLMCacheStats();
+};
+
+// 清空 LLM 响应缓存
+export const clearLLMCache = async (): Promise<string> => {
+  return await ClearLLMCache();
+};
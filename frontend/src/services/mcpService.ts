@@ -1,7 +1,7 @@
-import { models } from '../../wailsjs/go/models';
-import { GetMCPServers, AddMCPServer, UpdateMCPServer, DeleteMCPServer, GetMCPStatus, TestMCPConnection, GetMCPServerTools } from '../../wailsjs/go/main/App';
+import { GetMCPServers, AddMCPServer, UpdateMCPServer, DeleteMCPServer, GetMCPStatus, TestMCPConnection, GetMCPServerTools, GetMCPServerResources, ReadMCPResource, GetMCPServerPrompts, GetMCPPrompt } from '../../wailsjs/go/main/App';
+import type { MCPServerConfig } from '../types';
 
-export type MCPServerConfig = models.MCPServerConfig;
+export type { MCPServerConfig, MCPAuthConfig, MCPAuthType } from '../types';
 
 // MCP 服务器状态
 export interface MCPServerStatus {
@@ -18,8 +18,40 @@ export interface MCPToolInfo {
   serverName: string;
 }
 
+// MCP 资源信息
+export interface MCPResourceInfo {
+  uri: string;
+  name: string;
+  description: string;
+  mimeType: string;
+  serverId: string;
+  serverName: string;
+}
+
+// MCP 提示词参数
+export interface MCPPromptArgument {
+  name: string;
+  description: string;
+  required: boolean;
+}
+
+// MCP 提示词信息
+export interface MCPPromptInfo {
+  name: string;
+  description: string;
+  arguments: MCPPromptArgument[];
+  serverId: string;
+  serverName: string;
+}
+
+// 读取资源或渲染提示词的结果
+export interface MCPTextResult {
+  text: string;
+  error?: string;
+}
+
 export async function getMCPServers(): Promise<MCPServerConfig[]> {
-  return await GetMCPServers();
+  return (await GetMCPServers()) as MCPServerConfig[];
 }
 
 export async function addMCPServer(server: MCPServerConfig): Promise<string> {
@@ -48,3 +80,23 @@ export async function testMCPConnection(serverID: string): Promise<MCPServerStat
 export async function getMCPServerTools(serverID: string): Promise<MCPToolInfo[]> {
   return await GetMCPServerTools(serverID);
 }
+
+// 获取指定 MCP 服务器的资源列表
+export async function getMCPServerResources(serverID: string): Promise<MCPResourceInfo[]> {
+  return await GetMCPServerResources(serverID);
+}
+
+// 读取指定 MCP 资源内容
+export async function readMCPResource(serverID: string, uri: string): Promise<MCPTextResult> {
+  return await ReadMCPResource(serverID, uri);
+}
+
+// 获取指定 MCP 服务器的提示词列表
+export async function getMCPServerPrompts(serverID: string): Promise<MCPPromptInfo[]> {
+  return await GetMCPServerPrompts(serverID);
+}
+
+// 按参数渲染 MCP 提示词
+export async function getMCPPrompt(serverID: string, name: string, args: Record<string, string>): Promise<MCPTextResult> {
+  return await GetMCPPrompt(serverID, name, args);
+}
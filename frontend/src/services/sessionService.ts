@@ -33,6 +33,7 @@ export interface MeetingMessageRequest {
   mentionIds: string[];
   replyToId: string;
   replyContent: string;
+  noCache: boolean; // 跳过 LLM 响应缓存
 }
 
 // 获取或创建Session
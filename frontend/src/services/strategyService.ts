@@ -1,5 +1,19 @@
 import { GetStrategies, GetActiveStrategyID, SetActiveStrategy, AddStrategy, UpdateStrategy, DeleteStrategy, GenerateStrategy, EnhancePrompt, GetAgentConfigs, AddAgentConfig, UpdateAgentConfig, DeleteAgentConfig } from '../../wailsjs/go/main/App';
 
+// 推理/扩展思考设置，budgetTokens > 0 时优先于 effort
+export interface ReasoningConfig {
+  enabled: boolean;
+  budgetTokens: number;
+  effort: string; // low / medium / high
+}
+
+// 专家附加的 MCP 资源引用
+export interface MCPResourceRef {
+  serverId: string;
+  uri: string;
+  name: string;
+}
+
 // 策略专属专家配置
 export interface StrategyAgent {
   id: string;
@@ -12,6 +26,9 @@ export interface StrategyAgent {
   mcpServers: string[];
   enabled: boolean;
   aiConfigId: string;
+  reasoning?: ReasoningConfig; // 为空则沿用 AI 配置
+  vision?: boolean; // 发言时附带K线图
+  mcpResources?: MCPResourceRef[]; // 附加到上下文的 MCP 资源
 }
 
 export interface Strategy {
@@ -104,6 +121,9 @@ export interface AgentConfig {
   mcpServers: string[];
   enabled: boolean;
   aiConfigId: string;
+  reasoning?: ReasoningConfig; // 为空则沿用 AI 配置
+  vision?: boolean; // 发言时附带K线图
+  mcpResources?: MCPResourceRef[]; // 附加到上下文的 MCP 资源
 }
 
 // 获取所有已启用的Agent配置
@@ -101,6 +101,25 @@ export interface MCPServerConfig {
   args: string[];
   toolFilter: string[];
   enabled: boolean;
+  env?: Record<string, string>;      // 命令行环境变量
+  workingDir?: string;               // 命令行工作目录
+  headers?: Record<string, string>;  // HTTP/SSE 请求头，可用 ${VAR} 引用环境变量
+  auth?: MCPAuthConfig;
+  connectTimeoutSeconds?: number;    // 0 使用默认 10 秒
+  callTimeoutSeconds?: number;       // 0 使用默认 60 秒
+}
+
+// MCP 鉴权方式
+export type MCPAuthType = '' | 'bearer' | 'client_credentials';
+
+// MCP HTTP 鉴权配置
+export interface MCPAuthConfig {
+  type: MCPAuthType;
+  token?: string;
+  tokenUrl?: string;
+  clientId?: string;
+  clientSecret?: string;
+  scopes?: string[];
 }
 
 // 大盘指数数据
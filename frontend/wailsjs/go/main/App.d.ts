@@ -6,6 +6,8 @@ import {main} from '../models';
 import {hottrend} from '../models';
 import {tools} from '../models';
 import {mcp} from '../models';
+import {llmcache} from '../models';
+import {ollama} from '../models';
 
 export function AddAgentConfig(arg1:models.AgentConfig):Promise<string>;
 
@@ -15,24 +17,38 @@ export function AddStrategy(arg1:models.Strategy):Promise<string>;
 
 export function AddToWatchlist(arg1:models.Stock):Promise<string>;
 
+export function BackfillLhbSeats(arg1:number):Promise<string>;
+
 export function CancelInterruptedMeeting(arg1:string):Promise<boolean>;
 
 export function CancelMeeting(arg1:string):Promise<boolean>;
 
+export function CheckAnnouncementsNow():Promise<Array<models.AnnouncementEvent>>;
+
 export function CheckForUpdate():Promise<services.UpdateInfo>;
 
+export function ClearLLMCache():Promise<string>;
+
 export function ClearSessionMessages(arg1:string):Promise<string>;
 
+export function CompareEarnings(arg1:string):Promise<models.EarningsComparison>;
+
 export function DeleteAgentConfig(arg1:string):Promise<string>;
 
 export function DeleteMCPServer(arg1:string):Promise<string>;
 
+export function DeleteMarketReview(arg1:string):Promise<string>;
+
+export function DeleteStockScreen(arg1:string):Promise<string>;
+
 export function DeleteStrategy(arg1:string):Promise<string>;
 
 export function DoUpdate():Promise<string>;
 
 export function EnhancePrompt(arg1:main.EnhancePromptRequest):Promise<main.EnhancePromptResponse>;
 
+export function GenerateMarketReview(arg1:boolean):Promise<models.MarketReview>;
+
 export function GenerateStrategy(arg1:main.GenerateStrategyRequest):Promise<main.GenerateStrategyResponse>;
 
 export function GetActiveStrategyID():Promise<string>;
@@ -41,52 +57,110 @@ export function GetAgentConfigs():Promise<Array<models.AgentConfig>>;
 
 export function GetAllHotTrends():Promise<Array<hottrend.HotTrendResult>>;
 
+export function GetAnalystConsensus(arg1:string,arg2:number):Promise<models.AnalystConsensus>;
+
+export function GetAnalystConsensusHistory(arg1:string):Promise<Array<models.ConsensusSnapshot>>;
+
+export function GetAnnouncementEvents(arg1:string,arg2:number):Promise<Array<models.AnnouncementEvent>>;
+
 export function GetAvailableTools():Promise<Array<tools.ToolInfo>>;
 
+export function GetBoardBreadth(arg1:string):Promise<models.BoardBreadth>;
+
 export function GetBoardFundFlow(arg1:string,arg2:number,arg3:number):Promise<models.BoardFundFlowList>;
 
 export function GetBoardLeaders(arg1:string,arg2:number):Promise<models.BoardLeaderList>;
 
+export function GetBoardSession(arg1:string,arg2:string):Promise<models.StockSession>;
+
+export function GetCompareSession(arg1:Array<string>):Promise<models.StockSession>;
+
 export function GetConfig():Promise<models.AppConfig>;
 
 export function GetCurrentVersion():Promise<string>;
 
+export function GetEarningsCalendar(arg1:number):Promise<Array<models.EarningsCalendarItem>>;
+
+export function GetEarningsComparisons(arg1:string):Promise<Array<models.EarningsComparison>>;
+
 export function GetF10Overview(arg1:string):Promise<models.F10Overview>;
 
 export function GetF10Valuation(arg1:string):Promise<models.StockValuation>;
 
+export function GetFinancialAnalysis(arg1:string):Promise<models.FinancialAnalysis>;
+
 export function GetHotTrend(arg1:string):Promise<hottrend.HotTrendResult>;
 
 export function GetHotTrendPlatforms():Promise<Array<hottrend.PlatformInfo>>;
 
+export function GetIntrinsicValue(arg1:string,arg2:models.ValuationParams):Promise<models.IntrinsicValuation>;
+
 export function GetKLineData(arg1:string,arg2:string,arg3:number):Promise<Array<models.KLineData>>;
 
+export function GetLLMCacheStats():Promise<llmcache.Stats>;
+
+export function GetLhbSeatProfile(arg1:string):Promise<models.SeatProfile>;
+
+export function GetLhbSeats(arg1:string,arg2:string,arg3:number):Promise<Array<models.SeatProfile>>;
+
+export function GetLhbStockSeats(arg1:string,arg2:string):Promise<models.StockSeatAnalysis>;
+
 export function GetLongHuBangDetail(arg1:string,arg2:string):Promise<Array<models.LongHuBangDetail>>;
 
 export function GetLongHuBangList(arg1:number,arg2:number,arg3:string):Promise<services.LongHuBangListResult>;
 
+export function GetMCPPrompt(arg1:string,arg2:string,arg3:Record<string, string>):Promise<mcp.TextResult>;
+
+export function GetMCPServerPrompts(arg1:string):Promise<Array<mcp.PromptInfo>>;
+
+export function GetMCPServerResources(arg1:string):Promise<Array<mcp.ResourceInfo>>;
+
 export function GetMCPServerTools(arg1:string):Promise<Array<mcp.ToolInfo>>;
 
 export function GetMCPServers():Promise<Array<models.MCPServerConfig>>;
 
 export function GetMCPStatus():Promise<Array<mcp.ServerStatus>>;
 
+export function GetMarginTrading(arg1:string,arg2:number):Promise<models.MarginTrading>;
+
 export function GetMarketIndices():Promise<Array<models.MarketIndex>>;
 
+export function GetMarketReview(arg1:string):Promise<models.MarketReview>;
+
+export function GetMarketReviews():Promise<Array<models.MarketReviewSummary>>;
+
+export function GetMarketSentiment():Promise<models.MarketSentiment>;
+
+export function GetMarketSentimentHistory(arg1:number):Promise<Array<models.MarketSentiment>>;
+
 export function GetMarketStatus():Promise<services.MarketStatus>;
 
+export function GetNorthboundHoldings(arg1:string,arg2:number):Promise<models.NorthboundHoldings>;
+
+export function GetOllamaPullStatus(arg1:string):Promise<ollama.PullStatus>;
+
 export function GetOpenClawStatus():Promise<Record<string, any>>;
 
 export function GetOrCreateSession(arg1:string,arg2:string):Promise<models.StockSession>;
 
 export function GetOrderBook(arg1:string):Promise<models.OrderBook>;
 
+export function GetPeerComparison(arg1:models.PeerRequest):Promise<models.PeerAnalysis>;
+
+export function GetRiskRadar():Promise<models.RiskRadar>;
+
+export function GetScreenerFields():Promise<Array<models.ScreenField>>;
+
 export function GetSessionMessages(arg1:string):Promise<Array<models.ChatMessage>>;
 
 export function GetStockMoves(arg1:string,arg2:number,arg3:number):Promise<models.StockMoveList>;
 
 export function GetStockRealTimeData(arg1:Array<string>):Promise<Array<models.Stock>>;
 
+export function GetStockScreenResult(arg1:string):Promise<models.ScreenResult>;
+
+export function GetStockScreens():Promise<Array<models.StockScreen>>;
+
 export function GetStrategies():Promise<Array<models.Strategy>>;
 
 export function GetTelegraphList():Promise<Array<services.Telegraph>>;
@@ -99,10 +173,18 @@ export function GetWatchlist():Promise<Array<models.Stock>>;
 
 export function Greet(arg1:string):Promise<string>;
 
+export function ListOllamaModels(arg1:string):Promise<Array<ollama.ModelInfo>>;
+
 export function NotifyFrontendReady():Promise<void>;
 
 export function OpenURL(arg1:string):Promise<void>;
 
+export function PullOllamaModel(arg1:string,arg2:string):Promise<string>;
+
+export function ReadMCPResource(arg1:string,arg2:string):Promise<mcp.TextResult>;
+
+export function RefreshRiskRadar():Promise<models.RiskRadar>;
+
 export function RemoveFromWatchlist(arg1:string):Promise<string>;
 
 export function RestartApp():Promise<string>;
@@ -111,8 +193,18 @@ export function RetryAgent(arg1:string,arg2:string,arg3:string):Promise<models.C
 
 export function RetryAgentAndContinue(arg1:string):Promise<Array<models.ChatMessage>>;
 
+export function RunScreenExpression(arg1:string,arg2:string,arg3:boolean,arg4:number):Promise<models.ScreenResult>;
+
+export function RunStockScreen(arg1:string):Promise<models.ScreenResult>;
+
+export function SaveStockScreen(arg1:models.StockScreen):Promise<string>;
+
 export function SearchStocks(arg1:string):Promise<Array<services.StockSearchResult>>;
 
+export function SendBoardMeetingMessage(arg1:main.BoardMeetingRequest):Promise<Array<models.ChatMessage>>;
+
+export function SendCompareMeetingMessage(arg1:main.CompareMeetingRequest):Promise<Array<models.ChatMessage>>;
+
 export function SendMeetingMessage(arg1:main.MeetingMessageRequest):Promise<Array<models.ChatMessage>>;
 
 export function SetActiveStrategy(arg1:string):Promise<string>;
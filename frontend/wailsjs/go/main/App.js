@@ -18,6 +18,10 @@ export function AddToWatchlist(arg1) {
   return window['go']['main']['App']['AddToWatchlist'](arg1);
 }
 
+export function BackfillLhbSeats(arg1) {
+  return window['go']['main']['App']['BackfillLhbSeats'](arg1);
+}
+
 export function CancelInterruptedMeeting(arg1) {
   return window['go']['main']['App']['CancelInterruptedMeeting'](arg1);
 }
@@ -26,14 +30,26 @@ export function CancelMeeting(arg1) {
   return window['go']['main']['App']['CancelMeeting'](arg1);
 }
 
+export function CheckAnnouncementsNow() {
+  return window['go']['main']['App']['CheckAnnouncementsNow']();
+}
+
 export function CheckForUpdate() {
   return window['go']['main']['App']['CheckForUpdate']();
 }
 
+export function ClearLLMCache() {
+  return window['go']['main']['App']['ClearLLMCache']();
+}
+
 export function ClearSessionMessages(arg1) {
   return window['go']['main']['App']['ClearSessionMessages'](arg1);
 }
 
+export function CompareEarnings(arg1) {
+  return window['go']['main']['App']['CompareEarnings'](arg1);
+}
+
 export function DeleteAgentConfig(arg1) {
   return window['go']['main']['App']['DeleteAgentConfig'](arg1);
 }
@@ -42,6 +58,14 @@ export function DeleteMCPServer(arg1) {
   return window['go']['main']['App']['DeleteMCPServer'](arg1);
 }
 
+export function DeleteMarketReview(arg1) {
+  return window['go']['main']['App']['DeleteMarketReview'](arg1);
+}
+
+export function DeleteStockScreen(arg1) {
+  return window['go']['main']['App']['DeleteStockScreen'](arg1);
+}
+
 export function DeleteStrategy(arg1) {
   return window['go']['main']['App']['DeleteStrategy'](arg1);
 }
@@ -54,6 +78,10 @@ export function EnhancePrompt(arg1) {
   return window['go']['main']['App']['EnhancePrompt'](arg1);
 }
 
+export function GenerateMarketReview(arg1) {
+  return window['go']['main']['App']['GenerateMarketReview'](arg1);
+}
+
 export function GenerateStrategy(arg1) {
   return window['go']['main']['App']['GenerateStrategy'](arg1);
 }
@@ -70,10 +98,26 @@ export function GetAllHotTrends() {
   return window['go']['main']['App']['GetAllHotTrends']();
 }
 
+export function GetAnalystConsensus(arg1, arg2) {
+  return window['go']['main']['App']['GetAnalystConsensus'](arg1, arg2);
+}
+
+export function GetAnalystConsensusHistory(arg1) {
+  return window['go']['main']['App']['GetAnalystConsensusHistory'](arg1);
+}
+
+export function GetAnnouncementEvents(arg1, arg2) {
+  return window['go']['main']['App']['GetAnnouncementEvents'](arg1, arg2);
+}
+
 export function GetAvailableTools() {
   return window['go']['main']['App']['GetAvailableTools']();
 }
 
+export function GetBoardBreadth(arg1) {
+  return window['go']['main']['App']['GetBoardBreadth'](arg1);
+}
+
 export function GetBoardFundFlow(arg1, arg2, arg3) {
   return window['go']['main']['App']['GetBoardFundFlow'](arg1, arg2, arg3);
 }
@@ -82,6 +126,14 @@ export function GetBoardLeaders(arg1, arg2) {
   return window['go']['main']['App']['GetBoardLeaders'](arg1, arg2);
 }
 
+export function GetBoardSession(arg1, arg2) {
+  return window['go']['main']['App']['GetBoardSession'](arg1, arg2);
+}
+
+export function GetCompareSession(arg1) {
+  return window['go']['main']['App']['GetCompareSession'](arg1);
+}
+
 export function GetConfig() {
   return window['go']['main']['App']['GetConfig']();
 }
@@ -90,6 +142,14 @@ export function GetCurrentVersion() {
   return window['go']['main']['App']['GetCurrentVersion']();
 }
 
+export function GetEarningsCalendar(arg1) {
+  return window['go']['main']['App']['GetEarningsCalendar'](arg1);
+}
+
+export function GetEarningsComparisons(arg1) {
+  return window['go']['main']['App']['GetEarningsComparisons'](arg1);
+}
+
 export function GetF10Overview(arg1) {
   return window['go']['main']['App']['GetF10Overview'](arg1);
 }
@@ -98,6 +158,10 @@ export function GetF10Valuation(arg1) {
   return window['go']['main']['App']['GetF10Valuation'](arg1);
 }
 
+export function GetFinancialAnalysis(arg1) {
+  return window['go']['main']['App']['GetFinancialAnalysis'](arg1);
+}
+
 export function GetHotTrend(arg1) {
   return window['go']['main']['App']['GetHotTrend'](arg1);
 }
@@ -106,10 +170,30 @@ export function GetHotTrendPlatforms() {
   return window['go']['main']['App']['GetHotTrendPlatforms']();
 }
 
+export function GetIntrinsicValue(arg1, arg2) {
+  return window['go']['main']['App']['GetIntrinsicValue'](arg1, arg2);
+}
+
 export function GetKLineData(arg1, arg2, arg3) {
   return window['go']['main']['App']['GetKLineData'](arg1, arg2, arg3);
 }
 
+export function GetLLMCacheStats() {
+  return window['go']['main']['App']['GetLLMCacheStats']();
+}
+
+export function GetLhbSeatProfile(arg1) {
+  return window['go']['main']['App']['GetLhbSeatProfile'](arg1);
+}
+
+export function GetLhbSeats(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetLhbSeats'](arg1, arg2, arg3);
+}
+
+export function GetLhbStockSeats(arg1, arg2) {
+  return window['go']['main']['App']['GetLhbStockSeats'](arg1, arg2);
+}
+
 export function GetLongHuBangDetail(arg1, arg2) {
   return window['go']['main']['App']['GetLongHuBangDetail'](arg1, arg2);
 }
@@ -118,6 +202,18 @@ export function GetLongHuBangList(arg1, arg2, arg3) {
   return window['go']['main']['App']['GetLongHuBangList'](arg1, arg2, arg3);
 }
 
+export function GetMCPPrompt(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetMCPPrompt'](arg1, arg2, arg3);
+}
+
+export function GetMCPServerPrompts(arg1) {
+  return window['go']['main']['App']['GetMCPServerPrompts'](arg1);
+}
+
+export function GetMCPServerResources(arg1) {
+  return window['go']['main']['App']['GetMCPServerResources'](arg1);
+}
+
 export function GetMCPServerTools(arg1) {
   return window['go']['main']['App']['GetMCPServerTools'](arg1);
 }
@@ -130,14 +226,42 @@ export function GetMCPStatus() {
   return window['go']['main']['App']['GetMCPStatus']();
 }
 
+export function GetMarginTrading(arg1, arg2) {
+  return window['go']['main']['App']['GetMarginTrading'](arg1, arg2);
+}
+
 export function GetMarketIndices() {
   return window['go']['main']['App']['GetMarketIndices']();
 }
 
+export function GetMarketReview(arg1) {
+  return window['go']['main']['App']['GetMarketReview'](arg1);
+}
+
+export function GetMarketReviews() {
+  return window['go']['main']['App']['GetMarketReviews']();
+}
+
+export function GetMarketSentiment() {
+  return window['go']['main']['App']['GetMarketSentiment']();
+}
+
+export function GetMarketSentimentHistory(arg1) {
+  return window['go']['main']['App']['GetMarketSentimentHistory'](arg1);
+}
+
 export function GetMarketStatus() {
   return window['go']['main']['App']['GetMarketStatus']();
 }
 
+export function GetNorthboundHoldings(arg1, arg2) {
+  return window['go']['main']['App']['GetNorthboundHoldings'](arg1, arg2);
+}
+
+export function GetOllamaPullStatus(arg1) {
+  return window['go']['main']['App']['GetOllamaPullStatus'](arg1);
+}
+
 export function GetOpenClawStatus() {
   return window['go']['main']['App']['GetOpenClawStatus']();
 }
@@ -150,6 +274,18 @@ export function GetOrderBook(arg1) {
   return window['go']['main']['App']['GetOrderBook'](arg1);
 }
 
+export function GetPeerComparison(arg1) {
+  return window['go']['main']['App']['GetPeerComparison'](arg1);
+}
+
+export function GetRiskRadar() {
+  return window['go']['main']['App']['GetRiskRadar']();
+}
+
+export function GetScreenerFields() {
+  return window['go']['main']['App']['GetScreenerFields']();
+}
+
 export function GetSessionMessages(arg1) {
   return window['go']['main']['App']['GetSessionMessages'](arg1);
 }
@@ -162,6 +298,14 @@ export function GetStockRealTimeData(arg1) {
   return window['go']['main']['App']['GetStockRealTimeData'](arg1);
 }
 
+export function GetStockScreenResult(arg1) {
+  return window['go']['main']['App']['GetStockScreenResult'](arg1);
+}
+
+export function GetStockScreens() {
+  return window['go']['main']['App']['GetStockScreens']();
+}
+
 export function GetStrategies() {
   return window['go']['main']['App']['GetStrategies']();
 }
@@ -186,6 +330,10 @@ export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
 
+export function ListOllamaModels(arg1) {
+  return window['go']['main']['App']['ListOllamaModels'](arg1);
+}
+
 export function NotifyFrontendReady() {
   return window['go']['main']['App']['NotifyFrontendReady']();
 }
@@ -194,6 +342,18 @@ export function OpenURL(arg1) {
   return window['go']['main']['App']['OpenURL'](arg1);
 }
 
+export function PullOllamaModel(arg1, arg2) {
+  return window['go']['main']['App']['PullOllamaModel'](arg1, arg2);
+}
+
+export function ReadMCPResource(arg1, arg2) {
+  return window['go']['main']['App']['ReadMCPResource'](arg1, arg2);
+}
+
+export function RefreshRiskRadar() {
+  return window['go']['main']['App']['RefreshRiskRadar']();
+}
+
 export function RemoveFromWatchlist(arg1) {
   return window['go']['main']['App']['RemoveFromWatchlist'](arg1);
 }
@@ -210,10 +370,30 @@ export function RetryAgentAndContinue(arg1) {
   return window['go']['main']['App']['RetryAgentAndContinue'](arg1);
 }
 
+export function RunScreenExpression(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['RunScreenExpression'](arg1, arg2, arg3, arg4);
+}
+
+export function RunStockScreen(arg1) {
+  return window['go']['main']['App']['RunStockScreen'](arg1);
+}
+
+export function SaveStockScreen(arg1) {
+  return window['go']['main']['App']['SaveStockScreen'](arg1);
+}
+
 export function SearchStocks(arg1) {
   return window['go']['main']['App']['SearchStocks'](arg1);
 }
 
+export function SendBoardMeetingMessage(arg1) {
+  return window['go']['main']['App']['SendBoardMeetingMessage'](arg1);
+}
+
+export function SendCompareMeetingMessage(arg1) {
+  return window['go']['main']['App']['SendCompareMeetingMessage'](arg1);
+}
+
 export function SendMeetingMessage(arg1) {
   return window['go']['main']['App']['SendMeetingMessage'](arg1);
 }
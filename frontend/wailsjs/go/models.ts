@@ -83,6 +83,37 @@ export namespace hottrend {
 	}
 
 }
+export namespace llmcache {
+	
+	export class Stats {
+	    enabled: boolean;
+	    hits: number;
+	    misses: number;
+	    stores: number;
+	    bypassed: number;
+	    hitRate: number;
+	    entries: number;
+	    sizeBytes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Stats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.hits = source["hits"];
+	        this.misses = source["misses"];
+	        this.stores = source["stores"];
+	        this.bypassed = source["bypassed"];
+	        this.hitRate = source["hitRate"];
+	        this.entries = source["entries"];
+	        this.sizeBytes = source["sizeBytes"];
+	    }
+	}
+
+}
+
 export namespace main {
 	
 	export class EnhancePromptRequest {
@@ -171,6 +202,7 @@ export namespace main {
 	    mentionIds: string[];
 	    replyToId: string;
 	    replyContent: string;
+	    noCache: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new MeetingMessageRequest(source);
@@ -183,6 +215,41 @@ export namespace main {
 	        this.mentionIds = source["mentionIds"];
 	        this.replyToId = source["replyToId"];
 	        this.replyContent = source["replyContent"];
+	        this.noCache = source["noCache"];
+	    }
+	}
+	export class CompareMeetingRequest {
+	    stockCodes: string[];
+	    content: string;
+	    noCache: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CompareMeetingRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stockCodes = source["stockCodes"];
+	        this.content = source["content"];
+	        this.noCache = source["noCache"];
+	    }
+	}
+	export class BoardMeetingRequest {
+	    boardCode: string;
+	    boardName: string;
+	    content: string;
+	    noCache: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new BoardMeetingRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.boardCode = source["boardCode"];
+	        this.boardName = source["boardName"];
+	        this.content = source["content"];
+	        this.noCache = source["noCache"];
 	    }
 	}
 
@@ -224,6 +291,97 @@ export namespace mcp {
 	        this.serverName = source["serverName"];
 	    }
 	}
+	export class ResourceInfo {
+	    uri: string;
+	    name: string;
+	    description: string;
+	    mimeType: string;
+	    serverId: string;
+	    serverName: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ResourceInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.uri = source["uri"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.mimeType = source["mimeType"];
+	        this.serverId = source["serverId"];
+	        this.serverName = source["serverName"];
+	    }
+	}
+	export class PromptArgument {
+	    name: string;
+	    description: string;
+	    required: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptArgument(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.required = source["required"];
+	    }
+	}
+	export class PromptInfo {
+	    name: string;
+	    description: string;
+	    arguments: PromptArgument[];
+	    serverId: string;
+	    serverName: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.arguments = this.convertValues(source["arguments"], PromptArgument);
+	        this.serverId = source["serverId"];
+	        this.serverName = source["serverName"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class TextResult {
+	    text: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TextResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.error = source["error"];
+	    }
+	}
 
 }
 
@@ -246,6 +404,13 @@ export namespace models {
 	    project: string;
 	    location: string;
 	    credentialsJson: string;
+	    maxConcurrency: number;
+	    requestsPerMinute: number;
+	    tokensPerMinute: number;
+	    routeConfigIds: string[];
+	    routeStrategy: string;
+	    routeFallbackOn: string[];
+	    reasoning?: ReasoningConfig;
 	
 	    static createFrom(source: any = {}) {
 	        return new AIConfig(source);
@@ -269,8 +434,34 @@ export namespace models {
 	        this.project = source["project"];
 	        this.location = source["location"];
 	        this.credentialsJson = source["credentialsJson"];
+	        this.maxConcurrency = source["maxConcurrency"];
+	        this.requestsPerMinute = source["requestsPerMinute"];
+	        this.tokensPerMinute = source["tokensPerMinute"];
+	        this.routeConfigIds = source["routeConfigIds"];
+	        this.routeStrategy = source["routeStrategy"];
+	        this.routeFallbackOn = source["routeFallbackOn"];
+	        this.reasoning = this.convertValues(source["reasoning"], ReasoningConfig);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
+	
 	export class AgentConfig {
 	    id: string;
 	    name: string;
@@ -282,6 +473,9 @@ export namespace models {
 	    mcpServers: string[];
 	    enabled: boolean;
 	    aiConfigId: string;
+	    reasoning?: ReasoningConfig;
+	    vision?: boolean;
+	    mcpResources?: MCPResourceRef[];
 	
 	    static createFrom(source: any = {}) {
 	        return new AgentConfig(source);
@@ -299,8 +493,30 @@ export namespace models {
 	        this.mcpServers = source["mcpServers"];
 	        this.enabled = source["enabled"];
 	        this.aiConfigId = source["aiConfigId"];
+	        this.reasoning = this.convertValues(source["reasoning"], ReasoningConfig);
+	        this.vision = source["vision"];
+	        this.mcpResources = this.convertValues(source["mcpResources"], MCPResourceRef);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
+	
 	export class KDJConfig {
 	    enabled: boolean;
 	    period: number;
@@ -507,6 +723,28 @@ export namespace models {
 	        this.compressThreshold = source["compressThreshold"];
 	    }
 	}
+	export class MCPAuthConfig {
+	    type: string;
+	    token?: string;
+	    tokenUrl?: string;
+	    clientId?: string;
+	    clientSecret?: string;
+	    scopes?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPAuthConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.token = source["token"];
+	        this.tokenUrl = source["tokenUrl"];
+	        this.clientId = source["clientId"];
+	        this.clientSecret = source["clientSecret"];
+	        this.scopes = source["scopes"];
+	    }
+	}
 	export class MCPServerConfig {
 	    id: string;
 	    name: string;
@@ -516,6 +754,12 @@ export namespace models {
 	    args: string[];
 	    toolFilter: string[];
 	    enabled: boolean;
+	    env?: Record<string, string>;
+	    workingDir?: string;
+	    headers?: Record<string, string>;
+	    auth?: MCPAuthConfig;
+	    connectTimeoutSeconds?: number;
+	    callTimeoutSeconds?: number;
 	
 	    static createFrom(source: any = {}) {
 	        return new MCPServerConfig(source);
@@ -531,7 +775,31 @@ export namespace models {
 	        this.args = source["args"];
 	        this.toolFilter = source["toolFilter"];
 	        this.enabled = source["enabled"];
+	        this.env = source["env"];
+	        this.workingDir = source["workingDir"];
+	        this.headers = source["headers"];
+	        this.auth = this.convertValues(source["auth"], MCPAuthConfig);
+	        this.connectTimeoutSeconds = source["connectTimeoutSeconds"];
+	        this.callTimeoutSeconds = source["callTimeoutSeconds"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class AppConfig {
 	    theme: string;
@@ -550,6 +818,9 @@ export namespace models {
 	    layout: LayoutConfig;
 	    openClaw: OpenClawConfig;
 	    indicators: IndicatorConfig;
+	    announcementWatch: AnnouncementWatchConfig;
+	    earningsCalendar: EarningsCalendarConfig;
+	    llmCache: LLMCacheConfig;
 	
 	    static createFrom(source: any = {}) {
 	        return new AppConfig(source);
@@ -573,6 +844,9 @@ export namespace models {
 	        this.layout = this.convertValues(source["layout"], LayoutConfig);
 	        this.openClaw = this.convertValues(source["openClaw"], OpenClawConfig);
 	        this.indicators = this.convertValues(source["indicators"], IndicatorConfig);
+	        this.announcementWatch = this.convertValues(source["announcementWatch"], AnnouncementWatchConfig);
+	        this.earningsCalendar = this.convertValues(source["earningsCalendar"], EarningsCalendarConfig);
+	        this.llmCache = this.convertValues(source["llmCache"], LLMCacheConfig);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1624,6 +1898,8 @@ export namespace models {
 	    id: string;
 	    stockCode: string;
 	    stockName: string;
+	    compareCodes?: string[];
+	    boardCode?: string;
 	    messages: ChatMessage[];
 	    position?: StockPosition;
 	    createdAt: number;
@@ -1638,6 +1914,8 @@ export namespace models {
 	        this.id = source["id"];
 	        this.stockCode = source["stockCode"];
 	        this.stockName = source["stockName"];
+	        this.compareCodes = source["compareCodes"];
+	        this.boardCode = source["boardCode"];
 	        this.messages = this.convertValues(source["messages"], ChatMessage);
 	        this.position = this.convertValues(source["position"], StockPosition);
 	        this.createdAt = source["createdAt"];
@@ -1674,6 +1952,9 @@ export namespace models {
 	    mcpServers: string[];
 	    enabled: boolean;
 	    aiConfigId: string;
+	    reasoning?: ReasoningConfig;
+	    vision?: boolean;
+	    mcpResources?: MCPResourceRef[];
 	
 	    static createFrom(source: any = {}) {
 	        return new StrategyAgent(source);
@@ -1691,8 +1972,30 @@ export namespace models {
 	        this.mcpServers = source["mcpServers"];
 	        this.enabled = source["enabled"];
 	        this.aiConfigId = source["aiConfigId"];
+	        this.reasoning = this.convertValues(source["reasoning"], ReasoningConfig);
+	        this.vision = source["vision"];
+	        this.mcpResources = this.convertValues(source["mcpResources"], MCPResourceRef);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
+	
 	export class Strategy {
 	    id: string;
 	    name: string;
@@ -1739,6 +2042,1832 @@ export namespace models {
 		    return a;
 		}
 	}
+	
+	export class ScreenField {
+	    name: string;
+	    label: string;
+	    source: string;
+	    description?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScreenField(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.label = source["label"];
+	        this.source = source["source"];
+	        this.description = source["description"];
+	    }
+	}
+	export class ScreenMatch {
+	    code: string;
+	    name: string;
+	    industry?: string;
+	    metrics: Record<string, number>;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScreenMatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.name = source["name"];
+	        this.industry = source["industry"];
+	        this.metrics = source["metrics"];
+	    }
+	}
+	export class ScreenResult {
+	    screenId?: string;
+	    expression: string;
+	    runAt: number;
+	    universe: number;
+	    candidates: number;
+	    matched: number;
+	    items: ScreenMatch[];
+	    errors?: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScreenResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.screenId = source["screenId"];
+	        this.expression = source["expression"];
+	        this.runAt = source["runAt"];
+	        this.universe = source["universe"];
+	        this.candidates = source["candidates"];
+	        this.matched = source["matched"];
+	        this.items = this.convertValues(source["items"], ScreenMatch);
+	        this.errors = source["errors"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class StockScreen {
+	    id: string;
+	    name: string;
+	    description?: string;
+	    expression: string;
+	    sortBy?: string;
+	    sortDesc?: boolean;
+	    limit?: number;
+	    schedule?: number;
+	    tradingOnly?: boolean;
+	    enabled: boolean;
+	    lastRunAt?: number;
+	    createdAt: number;
+	    updatedAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StockScreen(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.expression = source["expression"];
+	        this.sortBy = source["sortBy"];
+	        this.sortDesc = source["sortDesc"];
+	        this.limit = source["limit"];
+	        this.schedule = source["schedule"];
+	        this.tradingOnly = source["tradingOnly"];
+	        this.enabled = source["enabled"];
+	        this.lastRunAt = source["lastRunAt"];
+	        this.createdAt = source["createdAt"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	}
+	export class BoardBreadth {
+	    boardCode: string;
+	    total: number;
+	    up: number;
+	    down: number;
+	    flat: number;
+	    limitUp: number;
+	    limitDown: number;
+	    upRatio: number;
+	    avgChange: number;
+	    medianChange: number;
+	    updateTime?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BoardBreadth(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.boardCode = source["boardCode"];
+	        this.total = source["total"];
+	        this.up = source["up"];
+	        this.down = source["down"];
+	        this.flat = source["flat"];
+	        this.limitUp = source["limitUp"];
+	        this.limitDown = source["limitDown"];
+	        this.upRatio = source["upRatio"];
+	        this.avgChange = source["avgChange"];
+	        this.medianChange = source["medianChange"];
+	        this.updateTime = source["updateTime"];
+	    }
+	}
+	export class ReviewStock {
+	    code: string;
+	    name: string;
+	    price: number;
+	    changePercent: number;
+	    turnoverRate: number;
+	    amount: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReviewStock(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.name = source["name"];
+	        this.price = source["price"];
+	        this.changePercent = source["changePercent"];
+	        this.turnoverRate = source["turnoverRate"];
+	        this.amount = source["amount"];
+	    }
+	}
+	export class ReviewNews {
+	    time: string;
+	    content: string;
+	    url?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReviewNews(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = source["time"];
+	        this.content = source["content"];
+	        this.url = source["url"];
+	    }
+	}
+	export class ReviewHotTopic {
+	    platform: string;
+	    title: string;
+	    rank: number;
+	    url?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReviewHotTopic(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.platform = source["platform"];
+	        this.title = source["title"];
+	        this.rank = source["rank"];
+	        this.url = source["url"];
+	    }
+	}
+	export class MarketReview {
+	    date: string;
+	    generatedAt: number;
+	    indices: MarketIndex[];
+	    limitUpCount: number;
+	    limitDownCount: number;
+	    limitUpStocks: ReviewStock[];
+	    limitDownStocks: ReviewStock[];
+	    industryInflow: BoardFundFlowItem[];
+	    conceptInflow: BoardFundFlowItem[];
+	    longHuBang: LongHuBangItem[];
+	    longHuBangDate?: string;
+	    news: ReviewNews[];
+	    hotTopics: ReviewHotTopic[];
+	    narrative?: string;
+	    errors?: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new MarketReview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.generatedAt = source["generatedAt"];
+	        this.indices = this.convertValues(source["indices"], MarketIndex);
+	        this.limitUpCount = source["limitUpCount"];
+	        this.limitDownCount = source["limitDownCount"];
+	        this.limitUpStocks = this.convertValues(source["limitUpStocks"], ReviewStock);
+	        this.limitDownStocks = this.convertValues(source["limitDownStocks"], ReviewStock);
+	        this.industryInflow = this.convertValues(source["industryInflow"], BoardFundFlowItem);
+	        this.conceptInflow = this.convertValues(source["conceptInflow"], BoardFundFlowItem);
+	        this.longHuBang = this.convertValues(source["longHuBang"], LongHuBangItem);
+	        this.longHuBangDate = source["longHuBangDate"];
+	        this.news = this.convertValues(source["news"], ReviewNews);
+	        this.hotTopics = this.convertValues(source["hotTopics"], ReviewHotTopic);
+	        this.narrative = source["narrative"];
+	        this.errors = source["errors"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class MarketReviewSummary {
+	    date: string;
+	    generatedAt: number;
+	    limitUpCount: number;
+	    limitDownCount: number;
+	    hasNarrative: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new MarketReviewSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.generatedAt = source["generatedAt"];
+	        this.limitUpCount = source["limitUpCount"];
+	        this.limitDownCount = source["limitDownCount"];
+	        this.hasNarrative = source["hasNarrative"];
+	    }
+	}
+	export class LadderStock {
+	    code: string;
+	    name: string;
+	    industry?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LadderStock(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.name = source["name"];
+	        this.industry = source["industry"];
+	    }
+	}
+	export class BoardLadderLevel {
+	    height: number;
+	    count: number;
+	    stocks?: LadderStock[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BoardLadderLevel(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.height = source["height"];
+	        this.count = source["count"];
+	        this.stocks = this.convertValues(source["stocks"], LadderStock);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class MarketSentiment {
+	    date: string;
+	    updateTime: string;
+	    total: number;
+	    up: number;
+	    down: number;
+	    flat: number;
+	    upRatio: number;
+	    limitUp: number;
+	    limitDown: number;
+	    failedLimitUp: number;
+	    failedRatio: number;
+	    maxHeight: number;
+	    ladder?: BoardLadderLevel[];
+	    newHigh: number;
+	    newLow: number;
+	    turnover: number;
+	    turnoverAvg20: number;
+	    turnoverRatio: number;
+	    errors?: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new MarketSentiment(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.updateTime = source["updateTime"];
+	        this.total = source["total"];
+	        this.up = source["up"];
+	        this.down = source["down"];
+	        this.flat = source["flat"];
+	        this.upRatio = source["upRatio"];
+	        this.limitUp = source["limitUp"];
+	        this.limitDown = source["limitDown"];
+	        this.failedLimitUp = source["failedLimitUp"];
+	        this.failedRatio = source["failedRatio"];
+	        this.maxHeight = source["maxHeight"];
+	        this.ladder = this.convertValues(source["ladder"], BoardLadderLevel);
+	        this.newHigh = source["newHigh"];
+	        this.newLow = source["newLow"];
+	        this.turnover = source["turnover"];
+	        this.turnoverAvg20 = source["turnoverAvg20"];
+	        this.turnoverRatio = source["turnoverRatio"];
+	        this.errors = source["errors"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ConsensusYear {
+	    year: number;
+	    eps: number;
+	    epsMedian: number;
+	    epsHigh: number;
+	    epsLow: number;
+	    pe?: number;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConsensusYear(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.year = source["year"];
+	        this.eps = source["eps"];
+	        this.epsMedian = source["epsMedian"];
+	        this.epsHigh = source["epsHigh"];
+	        this.epsLow = source["epsLow"];
+	        this.pe = source["pe"];
+	        this.count = source["count"];
+	    }
+	}
+	export class RatingCount {
+	    rating: string;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RatingCount(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.rating = source["rating"];
+	        this.count = source["count"];
+	    }
+	}
+	export class BrokerRatingChange {
+	    broker: string;
+	    date: string;
+	    from?: string;
+	    to: string;
+	    direction: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BrokerRatingChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.broker = source["broker"];
+	        this.date = source["date"];
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.direction = source["direction"];
+	    }
+	}
+	export class EPSRevision {
+	    year: number;
+	    up: number;
+	    down: number;
+	    unchanged: number;
+	    avgChangePct: number;
+	    consensusPrior: number;
+	    consensusNow: number;
+	    momentumPct: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new EPSRevision(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.year = source["year"];
+	        this.up = source["up"];
+	        this.down = source["down"];
+	        this.unchanged = source["unchanged"];
+	        this.avgChangePct = source["avgChangePct"];
+	        this.consensusPrior = source["consensusPrior"];
+	        this.consensusNow = source["consensusNow"];
+	        this.momentumPct = source["momentumPct"];
+	    }
+	}
+	export class AnalystConsensus {
+	    code: string;
+	    name?: string;
+	    date: string;
+	    windowDays: number;
+	    reportCount: number;
+	    brokerCount: number;
+	    years: ConsensusYear[];
+	    ratings: RatingCount[];
+	    priorRatings: RatingCount[];
+	    ratingChanges: BrokerRatingChange[];
+	    upgrades: number;
+	    downgrades: number;
+	    revision?: EPSRevision;
+	    revisionDays: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AnalystConsensus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.name = source["name"];
+	        this.date = source["date"];
+	        this.windowDays = source["windowDays"];
+	        this.reportCount = source["reportCount"];
+	        this.brokerCount = source["brokerCount"];
+	        this.years = this.convertValues(source["years"], ConsensusYear);
+	        this.ratings = this.convertValues(source["ratings"], RatingCount);
+	        this.priorRatings = this.convertValues(source["priorRatings"], RatingCount);
+	        this.ratingChanges = this.convertValues(source["ratingChanges"], BrokerRatingChange);
+	        this.upgrades = source["upgrades"];
+	        this.downgrades = source["downgrades"];
+	        this.revision = this.convertValues(source["revision"], EPSRevision);
+	        this.revisionDays = source["revisionDays"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ConsensusSnapshot {
+	    date: string;
+	    reportCount: number;
+	    brokerCount: number;
+	    years: ConsensusYear[];
+	    ratings: RatingCount[];
+	    upgrades: number;
+	    downgrades: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConsensusSnapshot(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.reportCount = source["reportCount"];
+	        this.brokerCount = source["brokerCount"];
+	        this.years = this.convertValues(source["years"], ConsensusYear);
+	        this.ratings = this.convertValues(source["ratings"], RatingCount);
+	        this.upgrades = source["upgrades"];
+	        this.downgrades = source["downgrades"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class AnnouncementWatchConfig {
+	    enabled: boolean;
+	    intervalMinutes: number;
+	    useLlm: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new AnnouncementWatchConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.intervalMinutes = source["intervalMinutes"];
+	        this.useLlm = source["useLlm"];
+	    }
+	}
+	export class AnnouncementEvent {
+	    id: string;
+	    stockCode: string;
+	    stockName: string;
+	    title: string;
+	    noticeDate: string;
+	    category: string;
+	    categoryName: string;
+	    severity: string;
+	    source: string;
+	    url: string;
+	    detectedAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AnnouncementEvent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.stockCode = source["stockCode"];
+	        this.stockName = source["stockName"];
+	        this.title = source["title"];
+	        this.noticeDate = source["noticeDate"];
+	        this.category = source["category"];
+	        this.categoryName = source["categoryName"];
+	        this.severity = source["severity"];
+	        this.source = source["source"];
+	        this.url = source["url"];
+	        this.detectedAt = source["detectedAt"];
+	    }
+	}
+	export class EarningsCalendarConfig {
+	    autoBriefing: boolean;
+	    briefingDaysBefore: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new EarningsCalendarConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.autoBriefing = source["autoBriefing"];
+	        this.briefingDaysBefore = source["briefingDaysBefore"];
+	    }
+	}
+	export class EarningsCalendarItem {
+	    stockCode: string;
+	    stockName: string;
+	    reportDate: string;
+	    reportType?: string;
+	    appointDate: string;
+	    actualDate?: string;
+	    daysLeft: number;
+	    status: string;
+	    briefed: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new EarningsCalendarItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stockCode = source["stockCode"];
+	        this.stockName = source["stockName"];
+	        this.reportDate = source["reportDate"];
+	        this.reportType = source["reportType"];
+	        this.appointDate = source["appointDate"];
+	        this.actualDate = source["actualDate"];
+	        this.daysLeft = source["daysLeft"];
+	        this.status = source["status"];
+	        this.briefed = source["briefed"];
+	    }
+	}
+	export class EarningsComparison {
+	    stockCode: string;
+	    stockName: string;
+	    reportDate: string;
+	    actualDate?: string;
+	    actualSource: string;
+	    netProfit: number;
+	    netProfitYoY?: number;
+	    revenue?: number;
+	    eps?: number;
+	    forecastLower?: number;
+	    forecastUpper?: number;
+	    forecastType?: string;
+	    forecastVerdict?: string;
+	    consensusEps?: number;
+	    consensusCount?: number;
+	    epsSurprisePct?: number;
+	    consensusVerdict?: string;
+	    summary: string;
+	    createdAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new EarningsComparison(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stockCode = source["stockCode"];
+	        this.stockName = source["stockName"];
+	        this.reportDate = source["reportDate"];
+	        this.actualDate = source["actualDate"];
+	        this.actualSource = source["actualSource"];
+	        this.netProfit = source["netProfit"];
+	        this.netProfitYoY = source["netProfitYoY"];
+	        this.revenue = source["revenue"];
+	        this.eps = source["eps"];
+	        this.forecastLower = source["forecastLower"];
+	        this.forecastUpper = source["forecastUpper"];
+	        this.forecastType = source["forecastType"];
+	        this.forecastVerdict = source["forecastVerdict"];
+	        this.consensusEps = source["consensusEps"];
+	        this.consensusCount = source["consensusCount"];
+	        this.epsSurprisePct = source["epsSurprisePct"];
+	        this.consensusVerdict = source["consensusVerdict"];
+	        this.summary = source["summary"];
+	        this.createdAt = source["createdAt"];
+	    }
+	}
+	export class RiskSignal {
+	    type: string;
+	    level: string;
+	    score: number;
+	    title: string;
+	    detail?: string;
+	    date?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RiskSignal(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.level = source["level"];
+	        this.score = source["score"];
+	        this.title = source["title"];
+	        this.detail = source["detail"];
+	        this.date = source["date"];
+	    }
+	}
+	export class StockRiskProfile {
+	    stockCode: string;
+	    stockName: string;
+	    inWatchlist: boolean;
+	    held: boolean;
+	    score: number;
+	    level: string;
+	    signals: RiskSignal[];
+	    errors?: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new StockRiskProfile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stockCode = source["stockCode"];
+	        this.stockName = source["stockName"];
+	        this.inWatchlist = source["inWatchlist"];
+	        this.held = source["held"];
+	        this.score = source["score"];
+	        this.level = source["level"];
+	        this.signals = this.convertValues(source["signals"], RiskSignal);
+	        this.errors = source["errors"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class RiskRadar {
+	    updateTime: number;
+	    stocks: StockRiskProfile[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RiskRadar(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.updateTime = source["updateTime"];
+	        this.stocks = this.convertValues(source["stocks"], StockRiskProfile);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class SeatStockStat {
+	    code: string;
+	    name: string;
+	    count: number;
+	    netAmt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SeatStockStat(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.name = source["name"];
+	        this.count = source["count"];
+	        this.netAmt = source["netAmt"];
+	    }
+	}
+	export class SeatProfile {
+	    operName: string;
+	    seatType: string;
+	    alias?: string;
+	    appearances: number;
+	    buyCount: number;
+	    sellCount: number;
+	    totalBuy: number;
+	    totalSell: number;
+	    netAmt: number;
+	    d1Samples: number;
+	    d1WinRate: number;
+	    d1AvgChange: number;
+	    d5Samples: number;
+	    d5WinRate: number;
+	    d5AvgChange: number;
+	    holdSamples: number;
+	    holdDays: number;
+	    topStocks: SeatStockStat[];
+	    firstDate: string;
+	    lastDate: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SeatProfile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.operName = source["operName"];
+	        this.seatType = source["seatType"];
+	        this.alias = source["alias"];
+	        this.appearances = source["appearances"];
+	        this.buyCount = source["buyCount"];
+	        this.sellCount = source["sellCount"];
+	        this.totalBuy = source["totalBuy"];
+	        this.totalSell = source["totalSell"];
+	        this.netAmt = source["netAmt"];
+	        this.d1Samples = source["d1Samples"];
+	        this.d1WinRate = source["d1WinRate"];
+	        this.d1AvgChange = source["d1AvgChange"];
+	        this.d5Samples = source["d5Samples"];
+	        this.d5WinRate = source["d5WinRate"];
+	        this.d5AvgChange = source["d5AvgChange"];
+	        this.holdSamples = source["holdSamples"];
+	        this.holdDays = source["holdDays"];
+	        this.topStocks = this.convertValues(source["topStocks"], SeatStockStat);
+	        this.firstDate = source["firstDate"];
+	        this.lastDate = source["lastDate"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class StockSeatActivity {
+	    operName: string;
+	    seatType: string;
+	    alias?: string;
+	    direction: string;
+	    buyAmt: number;
+	    sellAmt: number;
+	    netAmt: number;
+	    profile?: SeatProfile;
+	
+	    static createFrom(source: any = {}) {
+	        return new StockSeatActivity(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.operName = source["operName"];
+	        this.seatType = source["seatType"];
+	        this.alias = source["alias"];
+	        this.direction = source["direction"];
+	        this.buyAmt = source["buyAmt"];
+	        this.sellAmt = source["sellAmt"];
+	        this.netAmt = source["netAmt"];
+	        this.profile = this.convertValues(source["profile"], SeatProfile);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class StockSeatAnalysis {
+	    code: string;
+	    name: string;
+	    tradeDate: string;
+	    reason?: string;
+	    buyers: StockSeatActivity[];
+	    sellers: StockSeatActivity[];
+	
+	    static createFrom(source: any = {}) {
+	        return new StockSeatAnalysis(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.name = source["name"];
+	        this.tradeDate = source["tradeDate"];
+	        this.reason = source["reason"];
+	        this.buyers = this.convertValues(source["buyers"], StockSeatActivity);
+	        this.sellers = this.convertValues(source["sellers"], StockSeatActivity);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class NorthboundDay {
+	    tradeDate: string;
+	    closePrice: number;
+	    changePercent: number;
+	    holdShares: number;
+	    holdMarketCap: number;
+	    freeSharesRatio: number;
+	    totalSharesRatio: number;
+	    shareChange: number;
+	    marketCapChange: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new NorthboundDay(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tradeDate = source["tradeDate"];
+	        this.closePrice = source["closePrice"];
+	        this.changePercent = source["changePercent"];
+	        this.holdShares = source["holdShares"];
+	        this.holdMarketCap = source["holdMarketCap"];
+	        this.freeSharesRatio = source["freeSharesRatio"];
+	        this.totalSharesRatio = source["totalSharesRatio"];
+	        this.shareChange = source["shareChange"];
+	        this.marketCapChange = source["marketCapChange"];
+	    }
+	}
+	export class NorthboundHoldings {
+	    code: string;
+	    name: string;
+	    items: NorthboundDay[];
+	    change5d?: number;
+	    change20d?: number;
+	    stale?: boolean;
+	    updatedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new NorthboundHoldings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.name = source["name"];
+	        this.items = this.convertValues(source["items"], NorthboundDay);
+	        this.change5d = source["change5d"];
+	        this.change20d = source["change20d"];
+	        this.stale = source["stale"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class MarginDay {
+	    tradeDate: string;
+	    closePrice: number;
+	    changePercent: number;
+	    finBalance: number;
+	    finBuy: number;
+	    finRepay: number;
+	    finNetBuy: number;
+	    secBalance: number;
+	    secVolume: number;
+	    secSell: number;
+	    secRepay: number;
+	    totalBalance: number;
+	    finBalanceRatio: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MarginDay(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.tradeDate = source["tradeDate"];
+	        this.closePrice = source["closePrice"];
+	        this.changePercent = source["changePercent"];
+	        this.finBalance = source["finBalance"];
+	        this.finBuy = source["finBuy"];
+	        this.finRepay = source["finRepay"];
+	        this.finNetBuy = source["finNetBuy"];
+	        this.secBalance = source["secBalance"];
+	        this.secVolume = source["secVolume"];
+	        this.secSell = source["secSell"];
+	        this.secRepay = source["secRepay"];
+	        this.totalBalance = source["totalBalance"];
+	        this.finBalanceRatio = source["finBalanceRatio"];
+	    }
+	}
+	export class MarginTrading {
+	    code: string;
+	    name: string;
+	    items: MarginDay[];
+	    finChange5d: number;
+	    finChange20d: number;
+	    updatedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MarginTrading(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.name = source["name"];
+	        this.items = this.convertValues(source["items"], MarginDay);
+	        this.finChange5d = source["finChange5d"];
+	        this.finChange20d = source["finChange20d"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class FinancialPeriod {
+	    reportDate: string;
+	    reportType: string;
+	    revenue: number;
+	    netProfit: number;
+	    deductNetProfit?: number;
+	    operatingCashFlow: number;
+	    freeCashFlow: number;
+	    grossMargin?: number;
+	    operatingMargin?: number;
+	    netMargin?: number;
+	    roe?: number;
+	    roa?: number;
+	    assetTurnover?: number;
+	    equityMultiplier?: number;
+	    cashConversion?: number;
+	    cashToRevenue?: number;
+	    receivableDays?: number;
+	    inventoryDays?: number;
+	    debtRatio?: number;
+	    currentRatio?: number;
+	    goodwillRatio?: number;
+	    revenueYoY?: number;
+	    netProfitYoY?: number;
+	    quarterRevenue?: number;
+	    quarterNetProfit?: number;
+	    quarterRevenueQoQ?: number;
+	    quarterProfitQoQ?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FinancialPeriod(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.reportDate = source["reportDate"];
+	        this.reportType = source["reportType"];
+	        this.revenue = source["revenue"];
+	        this.netProfit = source["netProfit"];
+	        this.deductNetProfit = source["deductNetProfit"];
+	        this.operatingCashFlow = source["operatingCashFlow"];
+	        this.freeCashFlow = source["freeCashFlow"];
+	        this.grossMargin = source["grossMargin"];
+	        this.operatingMargin = source["operatingMargin"];
+	        this.netMargin = source["netMargin"];
+	        this.roe = source["roe"];
+	        this.roa = source["roa"];
+	        this.assetTurnover = source["assetTurnover"];
+	        this.equityMultiplier = source["equityMultiplier"];
+	        this.cashConversion = source["cashConversion"];
+	        this.cashToRevenue = source["cashToRevenue"];
+	        this.receivableDays = source["receivableDays"];
+	        this.inventoryDays = source["inventoryDays"];
+	        this.debtRatio = source["debtRatio"];
+	        this.currentRatio = source["currentRatio"];
+	        this.goodwillRatio = source["goodwillRatio"];
+	        this.revenueYoY = source["revenueYoY"];
+	        this.netProfitYoY = source["netProfitYoY"];
+	        this.quarterRevenue = source["quarterRevenue"];
+	        this.quarterNetProfit = source["quarterNetProfit"];
+	        this.quarterRevenueQoQ = source["quarterRevenueQoQ"];
+	        this.quarterProfitQoQ = source["quarterProfitQoQ"];
+	    }
+	}
+	export class FinancialTTM {
+	    baseDate: string;
+	    revenue: number;
+	    netProfit: number;
+	    deductNetProfit?: number;
+	    operatingCashFlow: number;
+	    netMargin?: number;
+	    roe?: number;
+	    cashConversion?: number;
+	    revenueYoY?: number;
+	    netProfitYoY?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FinancialTTM(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.baseDate = source["baseDate"];
+	        this.revenue = source["revenue"];
+	        this.netProfit = source["netProfit"];
+	        this.deductNetProfit = source["deductNetProfit"];
+	        this.operatingCashFlow = source["operatingCashFlow"];
+	        this.netMargin = source["netMargin"];
+	        this.roe = source["roe"];
+	        this.cashConversion = source["cashConversion"];
+	        this.revenueYoY = source["revenueYoY"];
+	        this.netProfitYoY = source["netProfitYoY"];
+	    }
+	}
+	export class FinancialRedFlag {
+	    type: string;
+	    level: string;
+	    reportDate: string;
+	    message: string;
+	    value: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FinancialRedFlag(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.level = source["level"];
+	        this.reportDate = source["reportDate"];
+	        this.message = source["message"];
+	        this.value = source["value"];
+	    }
+	}
+	export class FinancialAnalysis {
+	    code: string;
+	    periods: FinancialPeriod[];
+	    ttm?: FinancialTTM;
+	    redFlags: FinancialRedFlag[];
+	    updatedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FinancialAnalysis(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.periods = this.convertValues(source["periods"], FinancialPeriod);
+	        this.ttm = this.convertValues(source["ttm"], FinancialTTM);
+	        this.redFlags = this.convertValues(source["redFlags"], FinancialRedFlag);
+	        this.updatedAt = source["updatedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ValuationParams {
+	    wacc?: number;
+	    stage1Growth?: number;
+	    stage1Years?: number;
+	    terminalGrowth?: number;
+	    requiredReturn?: number;
+	    dividendGrowth?: number;
+	    range?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ValuationParams(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.wacc = source["wacc"];
+	        this.stage1Growth = source["stage1Growth"];
+	        this.stage1Years = source["stage1Years"];
+	        this.terminalGrowth = source["terminalGrowth"];
+	        this.requiredReturn = source["requiredReturn"];
+	        this.dividendGrowth = source["dividendGrowth"];
+	        this.range = source["range"];
+	    }
+	}
+	export class SensitivityTable {
+	    rowLabel: string;
+	    colLabel: string;
+	    rows: number[];
+	    cols: number[];
+	    values: number[][];
+	
+	    static createFrom(source: any = {}) {
+	        return new SensitivityTable(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.rowLabel = source["rowLabel"];
+	        this.colLabel = source["colLabel"];
+	        this.rows = source["rows"];
+	        this.cols = source["cols"];
+	        this.values = source["values"];
+	    }
+	}
+	export class DCFValuation {
+	    baseFcf: number;
+	    wacc: number;
+	    stage1Growth: number;
+	    stage1Years: number;
+	    terminalGrowth: number;
+	    stagePv: number;
+	    terminalPv: number;
+	    netCash: number;
+	    equityValue: number;
+	    perShare: number;
+	    upside: number;
+	    sensitivity?: SensitivityTable;
+	
+	    static createFrom(source: any = {}) {
+	        return new DCFValuation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.baseFcf = source["baseFcf"];
+	        this.wacc = source["wacc"];
+	        this.stage1Growth = source["stage1Growth"];
+	        this.stage1Years = source["stage1Years"];
+	        this.terminalGrowth = source["terminalGrowth"];
+	        this.stagePv = source["stagePv"];
+	        this.terminalPv = source["terminalPv"];
+	        this.netCash = source["netCash"];
+	        this.equityValue = source["equityValue"];
+	        this.perShare = source["perShare"];
+	        this.upside = source["upside"];
+	        this.sensitivity = this.convertValues(source["sensitivity"], SensitivityTable);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class DDMValuation {
+	    dividendPerShare: number;
+	    dividendYield: number;
+	    requiredReturn: number;
+	    dividendGrowth: number;
+	    perShare: number;
+	    upside: number;
+	    sensitivity?: SensitivityTable;
+	
+	    static createFrom(source: any = {}) {
+	        return new DDMValuation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.dividendPerShare = source["dividendPerShare"];
+	        this.dividendYield = source["dividendYield"];
+	        this.requiredReturn = source["requiredReturn"];
+	        this.dividendGrowth = source["dividendGrowth"];
+	        this.perShare = source["perShare"];
+	        this.upside = source["upside"];
+	        this.sensitivity = this.convertValues(source["sensitivity"], SensitivityTable);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class BandPoint {
+	    percentile: number;
+	    multiple: number;
+	    price: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BandPoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.percentile = source["percentile"];
+	        this.multiple = source["multiple"];
+	        this.price = source["price"];
+	    }
+	}
+	export class BandValuation {
+	    metric: string;
+	    current: number;
+	    currentPercentile: number;
+	    base: number;
+	    samples: number;
+	    range?: string;
+	    points: BandPoint[];
+	    fairValue: number;
+	    upside: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BandValuation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.metric = source["metric"];
+	        this.current = source["current"];
+	        this.currentPercentile = source["currentPercentile"];
+	        this.base = source["base"];
+	        this.samples = source["samples"];
+	        this.range = source["range"];
+	        this.points = this.convertValues(source["points"], BandPoint);
+	        this.fairValue = source["fairValue"];
+	        this.upside = source["upside"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class IntrinsicValuation {
+	    code: string;
+	    price: number;
+	    shares: number;
+	    dcf?: DCFValuation;
+	    ddm?: DDMValuation;
+	    peBand?: BandValuation;
+	    pbBand?: BandValuation;
+	    fairValueLow?: number;
+	    fairValueMid?: number;
+	    fairValueHigh?: number;
+	    notes?: string[];
+	    errors?: Record<string, string>;
+	    updatedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new IntrinsicValuation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.price = source["price"];
+	        this.shares = source["shares"];
+	        this.dcf = this.convertValues(source["dcf"], DCFValuation);
+	        this.ddm = this.convertValues(source["ddm"], DDMValuation);
+	        this.peBand = this.convertValues(source["peBand"], BandValuation);
+	        this.pbBand = this.convertValues(source["pbBand"], BandValuation);
+	        this.fairValueLow = source["fairValueLow"];
+	        this.fairValueMid = source["fairValueMid"];
+	        this.fairValueHigh = source["fairValueHigh"];
+	        this.notes = source["notes"];
+	        this.errors = source["errors"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class PeerMetric {
+	    name: string;
+	    label: string;
+	    group: string;
+	    higherBetter: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PeerMetric(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.label = source["label"];
+	        this.group = source["group"];
+	        this.higherBetter = source["higherBetter"];
+	    }
+	}
+	export class PeerRow {
+	    code: string;
+	    name: string;
+	    isTarget?: boolean;
+	    metrics: Record<string, number>;
+	    percentiles?: Record<string, number>;
+	    groupScores?: Record<string, number>;
+	
+	    static createFrom(source: any = {}) {
+	        return new PeerRow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.name = source["name"];
+	        this.isTarget = source["isTarget"];
+	        this.metrics = source["metrics"];
+	        this.percentiles = source["percentiles"];
+	        this.groupScores = source["groupScores"];
+	    }
+	}
+	export class PeerRank {
+	    metric: string;
+	    label: string;
+	    group: string;
+	    value: number;
+	    median: number;
+	    rank: number;
+	    count: number;
+	    percentile: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PeerRank(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.metric = source["metric"];
+	        this.label = source["label"];
+	        this.group = source["group"];
+	        this.value = source["value"];
+	        this.median = source["median"];
+	        this.rank = source["rank"];
+	        this.count = source["count"];
+	        this.percentile = source["percentile"];
+	    }
+	}
+	export class PeerAnalysis {
+	    code: string;
+	    name: string;
+	    source: string;
+	    sourceName?: string;
+	    metrics: PeerMetric[];
+	    rows: PeerRow[];
+	    ranks?: PeerRank[];
+	    groupScores?: Record<string, number>;
+	    errors?: Record<string, string>;
+	    updatedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PeerAnalysis(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.name = source["name"];
+	        this.source = source["source"];
+	        this.sourceName = source["sourceName"];
+	        this.metrics = this.convertValues(source["metrics"], PeerMetric);
+	        this.rows = this.convertValues(source["rows"], PeerRow);
+	        this.ranks = this.convertValues(source["ranks"], PeerRank);
+	        this.groupScores = source["groupScores"];
+	        this.errors = source["errors"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class PeerRequest {
+	    code: string;
+	    source?: string;
+	    theme?: string;
+	    peers?: string[];
+	    limit?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PeerRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.source = source["source"];
+	        this.theme = source["theme"];
+	        this.peers = source["peers"];
+	        this.limit = source["limit"];
+	    }
+	}
+	export class LLMCacheConfig {
+	    enabled: boolean;
+	    tradingTtlMinutes: number;
+	    closedTtlMinutes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LLMCacheConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.tradingTtlMinutes = source["tradingTtlMinutes"];
+	        this.closedTtlMinutes = source["closedTtlMinutes"];
+	    }
+	}
+	export class ReasoningConfig {
+	    enabled: boolean;
+	    budgetTokens: number;
+	    effort: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReasoningConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.budgetTokens = source["budgetTokens"];
+	        this.effort = source["effort"];
+	    }
+	}
+	export class MCPResourceRef {
+	    serverId: string;
+	    uri: string;
+	    name: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MCPResourceRef(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.serverId = source["serverId"];
+	        this.uri = source["uri"];
+	        this.name = source["name"];
+	    }
+	}
+
+}
+
+export namespace ollama {
+	
+	export class ModelDetails {
+	    family: string;
+	    parameter_size: string;
+	    quantization_level: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelDetails(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.family = source["family"];
+	        this.parameter_size = source["parameter_size"];
+	        this.quantization_level = source["quantization_level"];
+	    }
+	}
+	export class ModelInfo {
+	    name: string;
+	    model: string;
+	    modified_at: string;
+	    size: number;
+	    digest: string;
+	    details: ModelDetails;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.model = source["model"];
+	        this.modified_at = source["modified_at"];
+	        this.size = source["size"];
+	        this.digest = source["digest"];
+	        this.details = this.convertValues(source["details"], ModelDetails);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class PullStatus {
+	    model: string;
+	    status: string;
+	    total: number;
+	    completed: number;
+	    percent: number;
+	    done: boolean;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PullStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.model = source["model"];
+	        this.status = source["status"];
+	        this.total = source["total"];
+	        this.completed = source["completed"];
+	        this.percent = source["percent"];
+	        this.done = source["done"];
+	        this.error = source["error"];
+	    }
+	}
 
 }
 
@@ -1,10 +1,13 @@
 package anthropic
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/run-bigpig/jcp/internal/adk/structured"
 	"github.com/run-bigpig/jcp/internal/logger"
 	"google.golang.org/adk/model"
 	"google.golang.org/genai"
@@ -60,17 +63,19 @@ func toAnthropicRequest(req *model.LLMRequest, modelName string, noSystemRole bo
 	}
 
 	// 非官方 API 或不支持 system role：降级为第一条 user message
+	// 系统指令在工具调用循环中保持不变，末尾设置缓存断点以复用前缀
 	if systemText != "" {
+		systemBlock := ContentBlock{Type: "text", Text: systemText, CacheControl: ephemeralCache()}
 		if !noSystemRole {
-			ar.System = systemText
+			ar.System = []ContentBlock{systemBlock}
 		} else {
 			systemMsg := Message{
 				Role:    "user",
-				Content: []ContentBlock{{Type: "text", Text: systemText}},
+				Content: []ContentBlock{systemBlock},
 			}
 			// 如果第一条也是 user，合并避免连续 user
 			if len(msgs) > 0 && msgs[0].Role == "user" {
-				msgs[0].Content = append([]ContentBlock{{Type: "text", Text: systemText}}, msgs[0].Content...)
+				msgs[0].Content = append([]ContentBlock{systemBlock}, msgs[0].Content...)
 			} else {
 				msgs = append([]Message{systemMsg}, msgs...)
 			}
@@ -104,11 +109,70 @@ func toAnthropicRequest(req *model.LLMRequest, modelName string, noSystemRole bo
 		if len(req.Config.StopSequences) > 0 {
 			ar.StopSequences = req.Config.StopSequences
 		}
+		// 强制工具调用与扩展思考互斥，结构化输出时不开启思考
+		if schema := structured.JSONSchema(req.Config); schema != nil {
+			if err := applyStructuredOutput(ar, schema); err != nil {
+				return nil, err
+			}
+		} else {
+			applyThinking(ar, req.Config.ThinkingConfig)
+		}
 	}
 
 	return ar, nil
 }
 
+// applyStructuredOutput 通过强制调用专用工具实现结构化输出，工具入参即为结果 JSON
+func applyStructuredOutput(ar *MessagesRequest, schema map[string]any) error {
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return fmt.Errorf("marshal response schema: %w", err)
+	}
+	ar.Tools = append(ar.Tools, Tool{
+		Name:        structured.OutputName,
+		Description: "以结构化 JSON 提交最终结果",
+		InputSchema: raw,
+	})
+	ar.ToolChoice = &ToolChoice{Type: "tool", Name: structured.OutputName}
+	return nil
+}
+
+// Anthropic 扩展思考预算
+const (
+	minThinkingBudget     = 1024
+	defaultThinkingBudget = 8192
+	thinkingAnswerTokens  = 4096 // 预算占满 max_tokens 时为正文保留的 token 数
+)
+
+// thinkingBudget 将 ThinkingConfig 转换为思考预算，未指定预算时按强度估算
+func thinkingBudget(tc *genai.ThinkingConfig) int {
+	if tc.ThinkingBudget != nil && *tc.ThinkingBudget > 0 {
+		return max(int(*tc.ThinkingBudget), minThinkingBudget)
+	}
+	switch tc.ThinkingLevel {
+	case genai.ThinkingLevelMinimal, genai.ThinkingLevelLow:
+		return 2048
+	case genai.ThinkingLevelHigh:
+		return 16384
+	default:
+		return defaultThinkingBudget
+	}
+}
+
+// applyThinking 开启扩展思考；思考模式下不允许调整 temperature / top_p
+func applyThinking(ar *MessagesRequest, tc *genai.ThinkingConfig) {
+	if tc == nil || (tc.ThinkingBudget != nil && *tc.ThinkingBudget == 0) {
+		return
+	}
+	budget := thinkingBudget(tc)
+	ar.Thinking = &ThinkingParam{Type: "enabled", BudgetTokens: budget}
+	if ar.MaxTokens <= budget {
+		ar.MaxTokens = budget + thinkingAnswerTokens
+	}
+	ar.Temperature = nil
+	ar.TopP = nil
+}
+
 // toAnthropicMessages 将 genai.Content 列表转换为 Anthropic messages
 func toAnthropicMessages(contents []*genai.Content) ([]Message, error) {
 	var msgs []Message
@@ -126,8 +190,15 @@ func toAnthropicMessages(contents []*genai.Content) ([]Message, error) {
 		var blocks []ContentBlock
 
 		for _, part := range content.Parts {
-			// 跳过 thought parts（不回传给 API）
+			// 带签名的 thinking 块需在工具调用轮次中原样回传，其余 thought parts 跳过
 			if part.Thought {
+				if role == "assistant" && len(part.ThoughtSignature) > 0 {
+					blocks = append(blocks, ContentBlock{
+						Type:      "thinking",
+						Thinking:  part.Text,
+						Signature: string(part.ThoughtSignature),
+					})
+				}
 				continue
 			}
 
@@ -139,6 +210,18 @@ func toAnthropicMessages(contents []*genai.Content) ([]Message, error) {
 				})
 			}
 
+			// 内联图片 → image（仅 user 消息）
+			if role == "user" && part.InlineData != nil && strings.HasPrefix(part.InlineData.MIMEType, "image/") {
+				blocks = append(blocks, ContentBlock{
+					Type: "image",
+					Source: &ImageSource{
+						Type:      "base64",
+						MediaType: part.InlineData.MIMEType,
+						Data:      base64.StdEncoding.EncodeToString(part.InlineData.Data),
+					},
+				})
+			}
+
 			// 函数调用 → tool_use
 			if part.FunctionCall != nil {
 				inputJSON, err := json.Marshal(part.FunctionCall.Args)
@@ -213,6 +296,11 @@ func convertTools(genaiTools []*genai.Tool) ([]Tool, error) {
 			})
 		}
 	}
+	// 按名称排序保证前缀稳定，并在最后一个工具上设置缓存断点
+	sort.SliceStable(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	if len(tools) > 0 {
+		tools[len(tools)-1].CacheControl = ephemeralCache()
+	}
 	return tools, nil
 }
 
@@ -293,9 +381,16 @@ func convertAnthropicResponse(resp *MessagesResponse) (*model.LLMResponse, error
 			}
 		case "thinking":
 			if block.Thinking != "" {
-				content.Parts = append(content.Parts, &genai.Part{Text: block.Thinking, Thought: true})
+				content.Parts = append(content.Parts, &genai.Part{
+					Text: block.Thinking, Thought: true, ThoughtSignature: []byte(block.Signature),
+				})
 			}
 		case "tool_use":
+			// 结构化输出工具的入参作为文本结果返回
+			if block.Name == structured.OutputName {
+				content.Parts = append(content.Parts, &genai.Part{Text: string(block.Input)})
+				continue
+			}
 			args := make(map[string]any)
 			if len(block.Input) > 0 {
 				if err := json.Unmarshal(block.Input, &args); err != nil {
@@ -313,23 +408,62 @@ func convertAnthropicResponse(resp *MessagesResponse) (*model.LLMResponse, error
 	}
 
 	return &model.LLMResponse{
-		Content:       content,
-		UsageMetadata: convertUsage(&resp.Usage),
-		FinishReason:  convertStopReason(resp.StopReason),
-		TurnComplete:  true,
+		Content:        content,
+		UsageMetadata:  convertUsage(&resp.Usage),
+		CustomMetadata: cacheUsageMetadata(&resp.Usage),
+		FinishReason:   convertStopReason(resp.StopReason),
+		TurnComplete:   true,
 	}, nil
 }
 
-// convertUsage 转换 token 用量
+// 响应 CustomMetadata 中的提示缓存用量键
+const (
+	MetadataCacheCreationTokens = "cache_creation_input_tokens"
+	MetadataCacheReadTokens     = "cache_read_input_tokens"
+)
+
+// convertUsage 转换 token 用量，提示 token 数包含缓存命中与写入部分
 func convertUsage(u *Usage) *genai.GenerateContentResponseUsageMetadata {
 	if u == nil {
 		return nil
 	}
+	prompt := u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
 	return &genai.GenerateContentResponseUsageMetadata{
-		PromptTokenCount:     int32(u.InputTokens),
-		CandidatesTokenCount: int32(u.OutputTokens),
-		TotalTokenCount:      int32(u.InputTokens + u.OutputTokens),
+		PromptTokenCount:        int32(prompt),
+		CachedContentTokenCount: int32(u.CacheReadInputTokens),
+		CandidatesTokenCount:    int32(u.OutputTokens),
+		TotalTokenCount:         int32(prompt + u.OutputTokens),
+	}
+}
+
+// cacheUsageMetadata 提取提示缓存读写 token 数，未使用缓存时返回 nil
+func cacheUsageMetadata(u *Usage) map[string]any {
+	if u == nil || (u.CacheCreationInputTokens == 0 && u.CacheReadInputTokens == 0) {
+		return nil
+	}
+	return map[string]any{
+		MetadataCacheCreationTokens: u.CacheCreationInputTokens,
+		MetadataCacheReadTokens:     u.CacheReadInputTokens,
+	}
+}
+
+// mergeUsage 合并流式用量：message_start 携带输入用量，message_delta 携带累计输出用量
+func mergeUsage(base, delta *Usage) *Usage {
+	if base == nil {
+		return delta
+	}
+	merged := *base
+	merged.OutputTokens = delta.OutputTokens
+	if delta.InputTokens > 0 {
+		merged.InputTokens = delta.InputTokens
+	}
+	if delta.CacheCreationInputTokens > 0 {
+		merged.CacheCreationInputTokens = delta.CacheCreationInputTokens
+	}
+	if delta.CacheReadInputTokens > 0 {
+		merged.CacheReadInputTokens = delta.CacheReadInputTokens
 	}
+	return &merged
 }
 
 // convertStopReason 转换停止原因
@@ -14,6 +14,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/run-bigpig/jcp/internal/adk/structured"
 	"github.com/run-bigpig/jcp/internal/logger"
 	"google.golang.org/adk/model"
 	"google.golang.org/genai"
@@ -167,6 +168,7 @@ type blockState struct {
 	toolName  string
 	text      string
 	thinking  string
+	signature string
 	toolArgs  string
 }
 
@@ -262,7 +264,7 @@ func (m *AnthropicModel) handleSSEEvent(
 		}
 		*stopReason = ev.Delta.StopReason
 		if ev.Usage != nil {
-			*usage = ev.Usage
+			*usage = mergeUsage(*usage, ev.Usage)
 		}
 
 	case "message_stop":
@@ -322,6 +324,9 @@ func (m *AnthropicModel) handleDelta(
 			return errStopIteration
 		}
 
+	case "signature_delta":
+		bs.signature += ev.Delta.Signature
+
 	case "input_json_delta":
 		bs.toolArgs += ev.Delta.PartialJSON
 	}
@@ -349,7 +354,7 @@ func (m *AnthropicModel) emitFinalResponse(
 		case "thinking":
 			if bs.thinking != "" {
 				aggregated.Parts = append(aggregated.Parts, &genai.Part{
-					Text: bs.thinking, Thought: true,
+					Text: bs.thinking, Thought: true, ThoughtSignature: []byte(bs.signature),
 				})
 			}
 		case "text":
@@ -359,6 +364,11 @@ func (m *AnthropicModel) emitFinalResponse(
 				})
 			}
 		case "tool_use":
+			// 结构化输出工具的入参作为文本结果返回
+			if bs.toolName == structured.OutputName {
+				aggregated.Parts = append(aggregated.Parts, &genai.Part{Text: bs.toolArgs})
+				continue
+			}
 			args := make(map[string]any)
 			if bs.toolArgs != "" {
 				if err := json.Unmarshal([]byte(bs.toolArgs), &args); err != nil {
@@ -376,11 +386,12 @@ func (m *AnthropicModel) emitFinalResponse(
 	}
 
 	finalResp := &model.LLMResponse{
-		Content:       aggregated,
-		UsageMetadata: convertUsage(usage),
-		FinishReason:  convertStopReason(stopReason),
-		Partial:       false,
-		TurnComplete:  true,
+		Content:        aggregated,
+		UsageMetadata:  convertUsage(usage),
+		CustomMetadata: cacheUsageMetadata(usage),
+		FinishReason:   convertStopReason(stopReason),
+		Partial:        false,
+		TurnComplete:   true,
 	}
 	yield(finalResp, nil)
 }
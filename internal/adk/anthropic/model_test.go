@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/run-bigpig/jcp/internal/adk/structured"
 	"google.golang.org/adk/model"
 	"google.golang.org/genai"
 )
@@ -38,8 +39,8 @@ func TestToAnthropicRequest_Basic(t *testing.T) {
 	if ar.MaxTokens != 1024 {
 		t.Errorf("max_tokens = %d, want 1024", ar.MaxTokens)
 	}
-	if ar.System != "You are helpful." {
-		t.Errorf("system = %q, want %q", ar.System, "You are helpful.")
+	if len(ar.System) != 1 || ar.System[0].Text != "You are helpful." {
+		t.Errorf("system = %+v, want %q", ar.System, "You are helpful.")
 	}
 	if ar.Temperature == nil {
 		t.Error("temperature is nil")
@@ -167,6 +168,26 @@ func TestToToolResultContent_StringAndObject(t *testing.T) {
 	}
 }
 
+func TestToAnthropicMessages_Image(t *testing.T) {
+	contents := []*genai.Content{{Role: "user", Parts: []*genai.Part{
+		{Text: "看图"},
+		genai.NewPartFromBytes([]byte{0x89, 'P', 'N', 'G'}, "image/png"),
+	}}}
+
+	msgs, err := toAnthropicMessages(contents)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	blocks := msgs[0].Content
+	if len(blocks) != 2 || blocks[1].Type != "image" || blocks[1].Source.MediaType != "image/png" || blocks[1].Source.Data != "iVBORw==" {
+		t.Fatalf("unexpected blocks: %+v", blocks)
+	}
+	data, _ := json.Marshal(blocks[1])
+	if string(data) != `{"type":"image","source":{"type":"base64","media_type":"image/png","data":"iVBORw=="}}` {
+		t.Errorf("image block json = %s", data)
+	}
+}
+
 func TestConvertAnthropicResponse_TextAndToolUse(t *testing.T) {
 	resp := &MessagesResponse{
 		ID:         "msg_123",
@@ -249,7 +270,164 @@ func TestToAnthropicMessages_MergeConsecutiveRoles(t *testing.T) {
 	}
 }
 
+func TestToAnthropicRequest_CacheBreakpoints(t *testing.T) {
+	schema := map[string]any{"type": "object"}
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{
+			{Role: "user", Parts: []*genai.Part{{Text: "hello"}}},
+		},
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: &genai.Content{Parts: []*genai.Part{{Text: "You are helpful."}}},
+			Tools: []*genai.Tool{{FunctionDeclarations: []*genai.FunctionDeclaration{
+				{Name: "get_weather", ParametersJsonSchema: schema},
+				{Name: "get_stock", ParametersJsonSchema: schema},
+			}}},
+		},
+	}
+
+	ar, err := toAnthropicRequest(req, "claude-sonnet-4", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ar.System[0].CacheControl == nil {
+		t.Error("system block should carry cache_control")
+	}
+	if ar.Tools[0].Name != "get_stock" || ar.Tools[0].CacheControl != nil || ar.Tools[1].CacheControl == nil {
+		t.Errorf("tools should be sorted with breakpoint on the last one: %+v", ar.Tools)
+	}
+
+	body, err := json.Marshal(ar)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if n := strings.Count(string(body), `"cache_control":{"type":"ephemeral"}`); n != 2 {
+		t.Errorf("got %d cache_control markers, want 2: %s", n, body)
+	}
+
+	// 降级为 user message 时断点跟随系统指令块
+	ar, err = toAnthropicRequest(req, "claude-sonnet-4", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ar.System) != 0 || ar.Messages[0].Content[0].CacheControl == nil || ar.Messages[0].Content[1].CacheControl != nil {
+		t.Errorf("injected system block should carry cache_control: %+v", ar.Messages[0].Content)
+	}
+}
+
+func TestConvertUsage_CacheTokens(t *testing.T) {
+	u := &Usage{InputTokens: 10, OutputTokens: 20, CacheCreationInputTokens: 100, CacheReadInputTokens: 2000}
+	meta := convertUsage(u)
+	if meta.PromptTokenCount != 2110 || meta.CachedContentTokenCount != 2000 || meta.TotalTokenCount != 2130 {
+		t.Errorf("unexpected usage metadata: %+v", meta)
+	}
+	custom := cacheUsageMetadata(u)
+	if custom[MetadataCacheCreationTokens] != 100 || custom[MetadataCacheReadTokens] != 2000 {
+		t.Errorf("unexpected custom metadata: %+v", custom)
+	}
+	if cacheUsageMetadata(&Usage{InputTokens: 10}) != nil {
+		t.Error("no cache usage should yield nil metadata")
+	}
+
+	// 流式：message_delta 只带输出用量时保留 message_start 的输入与缓存用量
+	merged := mergeUsage(u, &Usage{OutputTokens: 42})
+	if merged.InputTokens != 10 || merged.CacheReadInputTokens != 2000 || merged.OutputTokens != 42 {
+		t.Errorf("unexpected merged usage: %+v", merged)
+	}
+}
+
+func TestToAnthropicRequest_Thinking(t *testing.T) {
+	temp := float32(0.7)
+	budget := int32(2000)
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{
+			{Role: "user", Parts: []*genai.Part{{Text: "hello"}}},
+			{Role: "model", Parts: []*genai.Part{
+				{Text: "let me think", Thought: true, ThoughtSignature: []byte("sig")},
+				{Text: "unsigned", Thought: true},
+				{FunctionCall: &genai.FunctionCall{ID: "tu_1", Name: "get_stock", Args: map[string]any{}}},
+			}},
+		},
+		Config: &genai.GenerateContentConfig{
+			MaxOutputTokens: 1024,
+			Temperature:     &temp,
+			ThinkingConfig:  &genai.ThinkingConfig{ThinkingBudget: &budget},
+		},
+	}
+
+	ar, err := toAnthropicRequest(req, "claude-sonnet-4", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ar.Thinking == nil || ar.Thinking.BudgetTokens != 2000 {
+		t.Fatalf("thinking = %+v, want budget 2000", ar.Thinking)
+	}
+	if ar.MaxTokens <= ar.Thinking.BudgetTokens || ar.Temperature != nil {
+		t.Errorf("max_tokens should exceed budget and temperature be dropped: max=%d temp=%v", ar.MaxTokens, ar.Temperature)
+	}
+
+	// 带签名的思考块回传，且位于 tool_use 之前
+	blocks := ar.Messages[1].Content
+	if len(blocks) != 2 || blocks[0].Type != "thinking" || blocks[0].Signature != "sig" || blocks[1].Type != "tool_use" {
+		t.Errorf("unexpected assistant blocks: %+v", blocks)
+	}
+
+	// 仅指定强度时按强度换算预算
+	req.Config.ThinkingConfig = &genai.ThinkingConfig{ThinkingLevel: genai.ThinkingLevelHigh}
+	if ar, _ = toAnthropicRequest(req, "claude-sonnet-4", false); ar.Thinking == nil || ar.Thinking.BudgetTokens != 16384 {
+		t.Errorf("high level thinking = %+v", ar.Thinking)
+	}
+
+	// 预算为 0 表示关闭
+	zero := int32(0)
+	req.Config.ThinkingConfig = &genai.ThinkingConfig{ThinkingBudget: &zero}
+	if ar, _ = toAnthropicRequest(req, "claude-sonnet-4", false); ar.Thinking != nil || ar.Temperature == nil {
+		t.Errorf("zero budget should disable thinking: %+v", ar.Thinking)
+	}
+}
+
 // 集成测试：需要设置环境变量 ANTHROPIC_TEST_URL 和 ANTHROPIC_TEST_KEY
+func TestToAnthropicRequest_StructuredOutput(t *testing.T) {
+	budget := int32(2000)
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{{Role: "user", Parts: []*genai.Part{{Text: "hello"}}}},
+		Config: &genai.GenerateContentConfig{
+			ResponseMIMEType: "application/json",
+			ResponseSchema: &genai.Schema{
+				Type:       genai.TypeObject,
+				Properties: map[string]*genai.Schema{"topic": {Type: genai.TypeString}},
+				Required:   []string{"topic"},
+			},
+			ThinkingConfig: &genai.ThinkingConfig{ThinkingBudget: &budget},
+		},
+	}
+
+	ar, err := toAnthropicRequest(req, "claude-sonnet-4", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ar.ToolChoice == nil || ar.ToolChoice.Type != "tool" || ar.ToolChoice.Name != structured.OutputName {
+		t.Fatalf("tool_choice = %+v", ar.ToolChoice)
+	}
+	if len(ar.Tools) != 1 || !strings.Contains(string(ar.Tools[0].InputSchema), `"additionalProperties":false`) {
+		t.Errorf("unexpected tools: %+v", ar.Tools)
+	}
+	if ar.Thinking != nil {
+		t.Error("thinking should be disabled when tool choice is forced")
+	}
+
+	// 结构化输出工具的入参转为文本结果
+	llmResp, err := convertAnthropicResponse(&MessagesResponse{
+		StopReason: "tool_use",
+		Content:    []ContentBlock{{Type: "tool_use", ID: "tu_1", Name: structured.OutputName, Input: json.RawMessage(`{"topic":"x"}`)}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if parts := llmResp.Content.Parts; len(parts) != 1 || parts[0].FunctionCall != nil || parts[0].Text != `{"topic":"x"}` {
+		t.Errorf("unexpected parts: %+v", parts)
+	}
+}
+
 func TestIntegration_NonStreaming(t *testing.T) {
 	baseURL := os.Getenv("ANTHROPIC_TEST_URL")
 	apiKey := os.Getenv("ANTHROPIC_TEST_KEY")
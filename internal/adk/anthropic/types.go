@@ -6,13 +6,27 @@ import "encoding/json"
 type MessagesRequest struct {
 	Model       string    `json:"model"`
 	Messages    []Message `json:"messages"`
-	System      string    `json:"system,omitempty"`
+	System      []ContentBlock `json:"system,omitempty"` // 使用内容块以便附加 cache_control
 	MaxTokens   int       `json:"max_tokens"`
 	Temperature *float64  `json:"temperature,omitempty"`
 	TopP        *float64  `json:"top_p,omitempty"`
 	Stream      bool      `json:"stream,omitempty"`
 	Tools       []Tool    `json:"tools,omitempty"`
 	StopSequences []string `json:"stop_sequences,omitempty"`
+	Thinking    *ThinkingParam `json:"thinking,omitempty"`
+	ToolChoice  *ToolChoice    `json:"tool_choice,omitempty"`
+}
+
+// ToolChoice 工具选择策略，type 为 tool 时强制调用指定工具
+type ToolChoice struct {
+	Type string `json:"type"` // auto / any / tool
+	Name string `json:"name,omitempty"`
+}
+
+// ThinkingParam 扩展思考配置，budget_tokens 须 >= 1024 且小于 max_tokens
+type ThinkingParam struct {
+	Type         string `json:"type"` // enabled
+	BudgetTokens int    `json:"budget_tokens"`
 }
 
 // Message 消息
@@ -30,7 +44,11 @@ type ContentBlock struct {
 	Text string `json:"text,omitempty"`
 
 	// thinking
-	Thinking string `json:"thinking,omitempty"`
+	Thinking  string `json:"thinking,omitempty"`
+	Signature string `json:"signature,omitempty"` // 工具调用轮次中需原样回传
+
+	// image
+	Source *ImageSource `json:"source,omitempty"`
 
 	// tool_use
 	ID    string          `json:"id,omitempty"`
@@ -41,6 +59,26 @@ type ContentBlock struct {
 	ToolUseID  string          `json:"tool_use_id,omitempty"`
 	RawContent json.RawMessage `json:"-"` // 自定义序列化，不走默认 tag
 	IsError    bool            `json:"is_error,omitempty"`
+
+	// 提示缓存断点，仅 text 块使用
+	CacheControl *CacheControl `json:"cache_control,omitempty"`
+}
+
+// ImageSource 图片来源（base64 内联）
+type ImageSource struct {
+	Type      string `json:"type"` // base64
+	MediaType string `json:"media_type"`
+	Data      string `json:"data"`
+}
+
+// CacheControl 提示缓存断点
+type CacheControl struct {
+	Type string `json:"type"` // ephemeral
+}
+
+// ephemeralCache 默认的 5 分钟缓存断点
+func ephemeralCache() *CacheControl {
+	return &CacheControl{Type: "ephemeral"}
 }
 
 // MarshalJSON 按 Type 输出对应字段，避免多余字段导致 Anthropic 拒绝
@@ -48,14 +86,21 @@ func (b ContentBlock) MarshalJSON() ([]byte, error) {
 	switch b.Type {
 	case "text":
 		return json.Marshal(struct {
-			Type string `json:"type"`
-			Text string `json:"text"`
-		}{b.Type, b.Text})
+			Type         string        `json:"type"`
+			Text         string        `json:"text"`
+			CacheControl *CacheControl `json:"cache_control,omitempty"`
+		}{b.Type, b.Text, b.CacheControl})
 	case "thinking":
 		return json.Marshal(struct {
-			Type     string `json:"type"`
-			Thinking string `json:"thinking"`
-		}{b.Type, b.Thinking})
+			Type      string `json:"type"`
+			Thinking  string `json:"thinking"`
+			Signature string `json:"signature,omitempty"`
+		}{b.Type, b.Thinking, b.Signature})
+	case "image":
+		return json.Marshal(struct {
+			Type   string       `json:"type"`
+			Source *ImageSource `json:"source"`
+		}{b.Type, b.Source})
 	case "tool_use":
 		return json.Marshal(struct {
 			Type  string          `json:"type"`
@@ -82,6 +127,8 @@ type Tool struct {
 	Name        string          `json:"name"`
 	Description string          `json:"description,omitempty"`
 	InputSchema json.RawMessage `json:"input_schema"`
+
+	CacheControl *CacheControl `json:"cache_control,omitempty"`
 }
 
 // ---- 响应类型 ----
@@ -99,9 +146,12 @@ type MessagesResponse struct {
 }
 
 // Usage token 用量
+// InputTokens 不含缓存命中与缓存写入部分
 type Usage struct {
-	InputTokens  int `json:"input_tokens"`
-	OutputTokens int `json:"output_tokens"`
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens,omitempty"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens,omitempty"`
 }
 
 // ---- SSE 事件类型 ----
@@ -128,9 +178,10 @@ type SSEContentBlockDelta struct {
 
 // Delta 增量内容
 type Delta struct {
-	Type     string          `json:"type"` // text_delta / input_json_delta / thinking_delta
+	Type     string          `json:"type"` // text_delta / input_json_delta / thinking_delta / signature_delta
 	Text     string          `json:"text,omitempty"`
 	Thinking string          `json:"thinking,omitempty"`
+	Signature string         `json:"signature,omitempty"`
 	PartialJSON string       `json:"partial_json,omitempty"`
 }
 
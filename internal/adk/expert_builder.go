@@ -2,6 +2,8 @@ package adk
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -42,7 +44,23 @@ func NewExpertAgentBuilderFull(llm model.LLM, aiConfig *models.AIConfig, registr
 // BuildAgentWithContext 根据配置构建 LLM Agent（支持引用上下文）
 func (b *ExpertAgentBuilder) BuildAgentWithContext(config *models.AgentConfig, stock *models.Stock, query string, replyContent string, coreContext string, position *models.StockPosition) (agent.Agent, error) {
 	instruction := b.buildInstructionWithContext(config, stock, query, replyContent, coreContext, position)
+	return b.newAgent(config, instruction)
+}
+
+// BuildCompareAgent 构建多股对比会议中的专家 Agent
+func (b *ExpertAgentBuilder) BuildCompareAgent(config *models.AgentConfig, stocks []models.Stock, query string, previousContext string, coreContext string) (agent.Agent, error) {
+	instruction := b.buildCompareInstruction(config, stocks, query, previousContext, coreContext)
+	return b.newAgent(config, instruction)
+}
+
+// BuildBoardAgent 构建板块会议中的专家 Agent
+func (b *ExpertAgentBuilder) BuildBoardAgent(config *models.AgentConfig, board *models.BoardFundFlowItem, query string, previousContext string, coreContext string) (agent.Agent, error) {
+	instruction := b.buildBoardInstruction(config, board, query, previousContext, coreContext)
+	return b.newAgent(config, instruction)
+}
 
+// newAgent 按专家配置和指令创建 LLM Agent
+func (b *ExpertAgentBuilder) newAgent(config *models.AgentConfig, instruction string) (agent.Agent, error) {
 	// 获取 Agent 配置的工具
 	var agentTools []tool.Tool
 	if b.toolRegistry != nil && len(config.Tools) > 0 {
@@ -72,6 +90,12 @@ func (b *ExpertAgentBuilder) BuildAgentWithContext(config *models.AgentConfig, s
 			generateConfig.MaxOutputTokens = int32(b.aiConfig.MaxTokens)
 		}
 	}
+	if thinking := b.thinkingConfig(config); thinking != nil {
+		if generateConfig == nil {
+			generateConfig = &genai.GenerateContentConfig{}
+		}
+		generateConfig.ThinkingConfig = thinking
+	}
 
 	return llmagent.New(llmagent.Config{
 		Name:                  config.ID,
@@ -84,8 +108,115 @@ func (b *ExpertAgentBuilder) BuildAgentWithContext(config *models.AgentConfig, s
 	})
 }
 
+// thinkingConfig 解析推理设置（专家配置优先于 AI 配置），未开启时返回 nil
+func (b *ExpertAgentBuilder) thinkingConfig(config *models.AgentConfig) *genai.ThinkingConfig {
+	reasoning := config.Reasoning
+	if reasoning == nil && b.aiConfig != nil {
+		reasoning = b.aiConfig.Reasoning
+	}
+	if reasoning == nil || !reasoning.Enabled {
+		return nil
+	}
+
+	// Gemini 不允许同时设置预算与强度，这里只设其一，其余供应商在适配层互相换算
+	tc := &genai.ThinkingConfig{IncludeThoughts: true}
+	if reasoning.BudgetTokens > 0 {
+		budget := int32(reasoning.BudgetTokens)
+		tc.ThinkingBudget = &budget
+		return tc
+	}
+	// 仅 Gemini 3 及以上支持 ThinkingLevel，其余按强度换算为预算
+	if b.supportsThinkingLevel() {
+		switch reasoning.Effort {
+		case models.ReasoningEffortLow:
+			tc.ThinkingLevel = genai.ThinkingLevelLow
+		case models.ReasoningEffortHigh:
+			tc.ThinkingLevel = genai.ThinkingLevelHigh
+		default:
+			tc.ThinkingLevel = genai.ThinkingLevelMedium
+		}
+		return tc
+	}
+	var budget int32
+	switch reasoning.Effort {
+	case models.ReasoningEffortLow:
+		budget = 2048
+	case models.ReasoningEffortHigh:
+		budget = 16384
+	default:
+		budget = 8192
+	}
+	tc.ThinkingBudget = &budget
+	return tc
+}
+
+var geminiVersionPattern = regexp.MustCompile(`gemini-(\d+)`)
+
+// supportsThinkingLevel 当前模型是否接受 ThinkingLevel（Gemini 3 起），路由配置按成员未知处理
+func (b *ExpertAgentBuilder) supportsThinkingLevel() bool {
+	if b.aiConfig == nil {
+		return false
+	}
+	if b.aiConfig.Provider != models.AIProviderGemini && b.aiConfig.Provider != models.AIProviderVertexAI {
+		return false
+	}
+	m := geminiVersionPattern.FindStringSubmatch(strings.ToLower(b.aiConfig.ModelName))
+	if m == nil {
+		return false
+	}
+	major, _ := strconv.Atoi(m[1])
+	return major >= 3
+}
+
 // buildInstructionWithContext 构建 Agent 指令（支持引用上下文）
 func (b *ExpertAgentBuilder) buildInstructionWithContext(config *models.AgentConfig, stock *models.Stock, query string, replyContent string, coreContext string, position *models.StockPosition) string {
+	prompt := b.buildInstructionPreamble(config) + fmt.Sprintf(`股票: %s (%s)
+当前价格: %.2f
+涨跌幅: %.2f%%
+`, stock.Symbol, stock.Name, stock.Price, stock.ChangePercent)
+
+	// 如果有持仓信息，加入上下文
+	if position != nil && position.Shares > 0 {
+		marketValue := float64(position.Shares) * stock.Price
+		costAmount := float64(position.Shares) * position.CostPrice
+		profitLoss := marketValue - costAmount
+		profitPercent := 0.0
+		if costAmount > 0 {
+			profitPercent = (profitLoss / costAmount) * 100
+		}
+		prompt += fmt.Sprintf(`
+用户持仓: %d股，成本价 %.2f
+持仓市值: %.2f，盈亏: %.2f (%.2f%%)
+`, position.Shares, position.CostPrice, marketValue, profitLoss, profitPercent)
+	}
+
+	if strings.TrimSpace(coreContext) != "" {
+		prompt += fmt.Sprintf(`
+【核心数据包】
+%s
+`, coreContext)
+	}
+
+	// 如果有引用内容，加入上下文
+	if replyContent != "" {
+		prompt += fmt.Sprintf(`--- 引用的观点 ---
+%s
+---
+
+你的分析任务: %s
+
+请结合以上引用的观点，发表你的专业看法。可以赞同、补充或反驳。回复控制在150字以内。`, replyContent, query)
+	} else {
+		prompt += fmt.Sprintf(`你的分析任务: %s
+
+请用简洁专业的语言回答，控制在150字以内。`, query)
+	}
+
+	return prompt
+}
+
+// buildInstructionPreamble 构建指令公共部分（角色、工具、时间与盘中状态）
+func (b *ExpertAgentBuilder) buildInstructionPreamble(config *models.AgentConfig) string {
 	baseInstruction := config.Instruction
 	if baseInstruction == "" {
 		baseInstruction = fmt.Sprintf("你是一位%s，名字是%s。", config.Role, config.Name)
@@ -93,6 +224,7 @@ func (b *ExpertAgentBuilder) buildInstructionWithContext(config *models.AgentCon
 
 	// 构建可用工具说明
 	toolsDescription := b.buildToolsDescription(config)
+	resourcesDescription := b.buildResourcesDescription(config)
 
 	// 获取当前时间和盘中状态
 	now := time.Now()
@@ -117,11 +249,9 @@ func (b *ExpertAgentBuilder) buildInstructionWithContext(config *models.AgentCon
 		marketStatus = "午间休市"
 	}
 
-	prompt := fmt.Sprintf(`%s
-%s
-当前时间: %s
-市场状态: %s
-
+	// 静态部分（角色、工具、调用规范）在前，时间等易变信息在后，便于命中提示前缀缓存
+	return fmt.Sprintf(`%s
+%s%s
 ## 工具调用规范
 当你需要调用工具时，必须通过系统提供的标准 function call 机制进行调用。
 **重要：需要调用工具时，不要在工具调用前输出任何思考过程或分析文字，直接发起工具调用。工具返回结果后，再基于结果组织你的回答。**
@@ -133,49 +263,55 @@ func (b *ExpertAgentBuilder) buildInstructionWithContext(config *models.AgentCon
 - 任何类似 <xxx:tool_call> 格式的标签
 直接使用 API 提供的 tool_calls 功能，不要在文本中模拟工具调用。
 
-股票: %s (%s)
-当前价格: %.2f
-涨跌幅: %.2f%%
-`, baseInstruction, toolsDescription, timeStr, marketStatus, stock.Symbol, stock.Name, stock.Price, stock.ChangePercent)
+当前时间: %s
+市场状态: %s
 
-	// 如果有持仓信息，加入上下文
-	if position != nil && position.Shares > 0 {
-		marketValue := float64(position.Shares) * stock.Price
-		costAmount := float64(position.Shares) * position.CostPrice
-		profitLoss := marketValue - costAmount
-		profitPercent := 0.0
-		if costAmount > 0 {
-			profitPercent = (profitLoss / costAmount) * 100
-		}
-		prompt += fmt.Sprintf(`
-用户持仓: %d股，成本价 %.2f
-持仓市值: %.2f，盈亏: %.2f (%.2f%%)
-`, position.Shares, position.CostPrice, marketValue, profitLoss, profitPercent)
-	}
+`, baseInstruction, toolsDescription, resourcesDescription, timeStr, marketStatus)
+}
 
-	if strings.TrimSpace(coreContext) != "" {
-		prompt += fmt.Sprintf(`
-【核心数据包】
-%s
-`, coreContext)
+// buildResourcesDescription 构建专家附加的 MCP 参考资料
+func (b *ExpertAgentBuilder) buildResourcesDescription(config *models.AgentConfig) string {
+	if b.mcpManager == nil || len(config.MCPResources) == 0 {
+		return ""
 	}
+	content := b.mcpManager.BuildResourceContext(config.MCPResources)
+	if content == "" {
+		return ""
+	}
+	return fmt.Sprintf("\n## 参考资料\n以下资料由用户附加，分析时可引用：\n%s\n", content)
+}
 
-	// 如果有引用内容，加入上下文
-	if replyContent != "" {
-		prompt += fmt.Sprintf(`--- 引用的观点 ---
-%s
----
-
-你的分析任务: %s
+// buildCompareInstruction 构建多股对比指令
+func (b *ExpertAgentBuilder) buildCompareInstruction(config *models.AgentConfig, stocks []models.Stock, query string, previousContext string, coreContext string) string {
+	var subject strings.Builder
+	subject.WriteString("对比标的:\n")
+	for _, stock := range stocks {
+		fmt.Fprintf(&subject, "- %s (%s)，现价 %.2f，涨跌幅 %.2f%%\n", stock.Symbol, stock.Name, stock.Price, stock.ChangePercent)
+	}
+	return b.buildTopicInstruction(config, subject.String(), query, previousContext, coreContext,
+		"请只从你负责的维度对以上股票逐一比较，给出该维度下的排序（由优到劣）及关键依据，不要展开其他维度，控制在250字以内。")
+}
 
-请结合以上引用的观点，发表你的专业看法。可以赞同、补充或反驳。回复控制在150字以内。`, replyContent, query)
-	} else {
-		prompt += fmt.Sprintf(`你的分析任务: %s
+// buildBoardInstruction 构建板块会议指令
+func (b *ExpertAgentBuilder) buildBoardInstruction(config *models.AgentConfig, board *models.BoardFundFlowItem, query string, previousContext string, coreContext string) string {
+	subject := fmt.Sprintf("板块: %s (%s)\n板块指数: %.2f\n涨跌幅: %.2f%%\n", board.Name, board.Code, board.Price, board.ChangePercent)
+	return b.buildTopicInstruction(config, subject, query, previousContext, coreContext,
+		"请围绕板块整体（资金轮动、龙头辨识度、涨跌扩散度、持续性）给出判断，必要时点名具体成分股，控制在200字以内。")
+}
 
-请用简洁专业的语言回答，控制在150字以内。`, query)
+// buildTopicInstruction 构建非单股主题（多股对比、板块）的专家指令
+func (b *ExpertAgentBuilder) buildTopicInstruction(config *models.AgentConfig, subject string, query string, previousContext string, coreContext string, requirement string) string {
+	var sb strings.Builder
+	sb.WriteString(b.buildInstructionPreamble(config))
+	sb.WriteString(subject)
+	if strings.TrimSpace(coreContext) != "" {
+		fmt.Fprintf(&sb, "\n【核心数据包】\n%s\n", coreContext)
 	}
-
-	return prompt
+	if strings.TrimSpace(previousContext) != "" {
+		fmt.Fprintf(&sb, "--- 其他专家已发表的观点 ---\n%s\n---\n\n", previousContext)
+	}
+	fmt.Fprintf(&sb, "你的分析任务: %s\n\n%s", query, requirement)
+	return sb.String()
 }
 
 // buildToolsDescription 构建可用工具说明
@@ -0,0 +1,50 @@
+package adk
+
+import (
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/genai"
+)
+
+func TestThinkingConfig(t *testing.T) {
+	agent := func(effort string, budget int) *models.AgentConfig {
+		return &models.AgentConfig{Reasoning: &models.ReasoningConfig{Enabled: true, Effort: effort, BudgetTokens: budget}}
+	}
+	builder := func(provider models.AIProvider, modelName string) *ExpertAgentBuilder {
+		return &ExpertAgentBuilder{aiConfig: &models.AIConfig{Provider: provider, ModelName: modelName}}
+	}
+
+	// Gemini 3 使用 ThinkingLevel
+	tc := builder(models.AIProviderGemini, "gemini-3-pro-preview").thinkingConfig(agent(models.ReasoningEffortHigh, 0))
+	if tc.ThinkingLevel != genai.ThinkingLevelHigh || tc.ThinkingBudget != nil {
+		t.Errorf("gemini-3 high = %+v", tc)
+	}
+
+	// Gemini 2.5 及其他供应商按强度换算预算
+	cases := []struct {
+		b      *ExpertAgentBuilder
+		effort string
+		want   int32
+	}{
+		{builder(models.AIProviderGemini, "gemini-2.5-flash"), models.ReasoningEffortLow, 2048},
+		{builder(models.AIProviderVertexAI, "gemini-2.5-pro"), "", 8192},
+		{builder(models.AIProviderOpenAI, "o4-mini"), models.ReasoningEffortHigh, 16384},
+		{builder(models.AIProviderRouter, "gemini-3-pro-preview"), models.ReasoningEffortMedium, 8192},
+	}
+	for _, c := range cases {
+		tc := c.b.thinkingConfig(agent(c.effort, 0))
+		if tc.ThinkingLevel != "" || tc.ThinkingBudget == nil || *tc.ThinkingBudget != c.want {
+			t.Errorf("%s/%s effort=%q: %+v", c.b.aiConfig.Provider, c.b.aiConfig.ModelName, c.effort, tc)
+		}
+	}
+
+	// 显式预算优先，未开启时不设置
+	if tc := builder(models.AIProviderGemini, "gemini-3-pro").thinkingConfig(agent(models.ReasoningEffortHigh, 1000)); tc.ThinkingLevel != "" || *tc.ThinkingBudget != 1000 {
+		t.Errorf("explicit budget = %+v", tc)
+	}
+	if tc := builder(models.AIProviderGemini, "gemini-2.5-flash").thinkingConfig(&models.AgentConfig{}); tc != nil {
+		t.Errorf("disabled = %+v", tc)
+	}
+}
@@ -0,0 +1,419 @@
+package llmcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"iter"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+var log = logger.New("LLMCache")
+
+// DefaultTTL 未设置 TTL 函数时的缓存有效期
+const DefaultTTL = 10 * time.Minute
+
+// Stats 缓存命中统计
+type Stats struct {
+	Enabled   bool    `json:"enabled"`
+	Hits      int64   `json:"hits"`
+	Misses    int64   `json:"misses"`
+	Stores    int64   `json:"stores"`
+	Bypassed  int64   `json:"bypassed"`
+	HitRate   float64 `json:"hitRate"`   // 命中率（0-1）
+	Entries   int     `json:"entries"`   // 磁盘上未过期的条目数
+	SizeBytes int64   `json:"sizeBytes"` // 磁盘占用
+}
+
+// entry 磁盘缓存条目
+type entry struct {
+	Scope     string               `json:"scope"`
+	CreatedAt time.Time            `json:"createdAt"`
+	ExpiresAt time.Time            `json:"expiresAt"`
+	Responses []*model.LLMResponse `json:"responses"`
+}
+
+// Cache 基于磁盘的 LLM 响应缓存
+type Cache struct {
+	dir     string
+	enabled atomic.Bool
+
+	mu  sync.RWMutex
+	ttl func() time.Duration
+
+	hits, misses, stores, bypassed atomic.Int64
+}
+
+// New 创建缓存，dir 不存在时自动创建，并清理已过期条目
+func New(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	c := &Cache{dir: dir}
+	c.Prune()
+	return c, nil
+}
+
+// SetEnabled 开关缓存，关闭后读写均跳过
+func (c *Cache) SetEnabled(enabled bool) {
+	c.enabled.Store(enabled)
+}
+
+// Enabled 是否启用
+func (c *Cache) Enabled() bool {
+	return c != nil && c.enabled.Load()
+}
+
+// SetTTLFunc 设置写入时的有效期计算函数（如按盘中/盘后返回不同时长）
+func (c *Cache) SetTTLFunc(ttl func() time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = ttl
+}
+
+func (c *Cache) currentTTL() time.Duration {
+	c.mu.RLock()
+	fn := c.ttl
+	c.mu.RUnlock()
+	if fn == nil {
+		return DefaultTTL
+	}
+	return fn()
+}
+
+// Wrap 用缓存装饰模型，scope 区分不同的 AI 配置与模型
+func (c *Cache) Wrap(llm model.LLM, scope string) model.LLM {
+	return &cachedModel{LLM: llm, cache: c, scope: scope}
+}
+
+// Stats 返回命中统计与磁盘占用
+func (c *Cache) Stats() Stats {
+	s := Stats{
+		Enabled:  c.Enabled(),
+		Hits:     c.hits.Load(),
+		Misses:   c.misses.Load(),
+		Stores:   c.stores.Load(),
+		Bypassed: c.bypassed.Load(),
+	}
+	if total := s.Hits + s.Misses; total > 0 {
+		s.HitRate = float64(s.Hits) / float64(total)
+	}
+	c.walk(func(path string, info os.FileInfo) {
+		s.Entries++
+		s.SizeBytes += info.Size()
+	})
+	return s
+}
+
+// Clear 删除全部缓存条目并重置统计
+func (c *Cache) Clear() error {
+	var firstErr error
+	c.walk(func(path string, _ os.FileInfo) {
+		if err := os.Remove(path); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	})
+	c.hits.Store(0)
+	c.misses.Store(0)
+	c.stores.Store(0)
+	c.bypassed.Store(0)
+	return firstErr
+}
+
+// Prune 删除已过期或损坏的条目
+func (c *Cache) Prune() {
+	now := time.Now()
+	removed := 0
+	c.walk(func(path string, _ os.FileInfo) {
+		e, err := readEntry(path)
+		if err != nil || now.After(e.ExpiresAt) {
+			os.Remove(path)
+			removed++
+		}
+	})
+	if removed > 0 {
+		log.Info("清理过期缓存 %d 条", removed)
+	}
+}
+
+func (c *Cache) walk(fn func(path string, info os.FileInfo)) {
+	files, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".json" {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		fn(filepath.Join(c.dir, f.Name()), info)
+	}
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+func readEntry(path string) (*entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// get 读取未过期条目
+func (c *Cache) get(key string) []*model.LLMResponse {
+	path := c.path(key)
+	e, err := readEntry(path)
+	if err != nil {
+		return nil
+	}
+	if time.Now().After(e.ExpiresAt) || len(e.Responses) == 0 {
+		os.Remove(path)
+		return nil
+	}
+	return e.Responses
+}
+
+// put 写入条目，先写临时文件再重命名，避免并发读到半截内容
+func (c *Cache) put(key, scope string, responses []*model.LLMResponse) {
+	ttl := c.currentTTL()
+	if ttl <= 0 {
+		return
+	}
+	now := time.Now()
+	data, err := json.Marshal(entry{Scope: scope, CreatedAt: now, ExpiresAt: now.Add(ttl), Responses: responses})
+	if err != nil {
+		log.Warn("序列化缓存失败: %v", err)
+		return
+	}
+	tmp := c.path(key) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		log.Warn("写入缓存失败: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, c.path(key)); err != nil {
+		os.Remove(tmp)
+		log.Warn("写入缓存失败: %v", err)
+		return
+	}
+	c.stores.Add(1)
+}
+
+type bypassKey struct{}
+
+// WithBypass 标记 ctx 下的模型调用跳过缓存（不读不写）
+func WithBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassKey{}, true)
+}
+
+// IsBypassed 是否跳过缓存
+func IsBypassed(ctx context.Context) bool {
+	v, _ := ctx.Value(bypassKey{}).(bool)
+	return v
+}
+
+// cachedModel 带响应缓存的模型装饰器
+type cachedModel struct {
+	model.LLM
+	cache *Cache
+	scope string
+}
+
+// GenerateContent 命中时回放缓存；未命中时透传并在完整成功后写入
+func (m *cachedModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	if !m.cache.Enabled() {
+		return m.LLM.GenerateContent(ctx, req, stream)
+	}
+	if IsBypassed(ctx) {
+		m.cache.bypassed.Add(1)
+		return m.LLM.GenerateContent(ctx, req, stream)
+	}
+
+	key := Key(m.scope, req)
+	if cached := m.cache.get(key); cached != nil {
+		m.cache.hits.Add(1)
+		log.Debug("缓存命中 [%s] %s", m.scope, key[:12])
+		return replay(cached, stream)
+	}
+	m.cache.misses.Add(1)
+
+	return func(yield func(*model.LLMResponse, error) bool) {
+		var finals []*model.LLMResponse
+		for resp, err := range m.LLM.GenerateContent(ctx, req, stream) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if resp != nil && !resp.Partial && resp.ErrorCode == "" {
+				finals = append(finals, resp)
+			}
+			if !yield(resp, nil) {
+				return
+			}
+		}
+		if hasContent(finals) {
+			m.cache.put(key, m.scope, finals)
+		}
+	}
+}
+
+// replay 回放缓存的最终响应，流式模式下先补一条文本分片供前端展示
+func replay(responses []*model.LLMResponse, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		for _, resp := range responses {
+			if stream && resp.Content != nil {
+				var parts []*genai.Part
+				for _, p := range resp.Content.Parts {
+					if p != nil && p.Text != "" {
+						parts = append(parts, &genai.Part{Text: p.Text, Thought: p.Thought})
+					}
+				}
+				if len(parts) > 0 {
+					partial := &model.LLMResponse{Content: &genai.Content{Role: resp.Content.Role, Parts: parts}, Partial: true}
+					if !yield(partial, nil) {
+						return
+					}
+				}
+			}
+			if !yield(resp, nil) {
+				return
+			}
+		}
+	}
+}
+
+func hasContent(responses []*model.LLMResponse) bool {
+	for _, r := range responses {
+		if r.Content != nil && len(r.Content.Parts) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// timestampPattern 指令与上下文中的时间戳精确到秒，归一化到日期以便同一市场状态下复用
+var timestampPattern = regexp.MustCompile(`(\d{4}-\d{2}-\d{2})[ T]\d{2}:\d{2}(:\d{2})?`)
+
+func normalizeText(s string) string {
+	return strings.TrimSpace(timestampPattern.ReplaceAllString(s, "$1"))
+}
+
+type keyPart struct {
+	Text     string `json:"t,omitempty"`
+	Call     string `json:"c,omitempty"`
+	Args     any    `json:"a,omitempty"`
+	Response any    `json:"r,omitempty"`
+	Data     string `json:"d,omitempty"`
+}
+
+type keyContent struct {
+	Role  string    `json:"role"`
+	Parts []keyPart `json:"parts"`
+}
+
+type keyRequest struct {
+	Scope       string       `json:"scope"`
+	Instruction string       `json:"instruction"`
+	Tools       []string     `json:"tools,omitempty"`
+	Temperature *float32     `json:"temperature,omitempty"`
+	MaxTokens   int32        `json:"maxTokens,omitempty"`
+	Schema      any          `json:"schema,omitempty"`
+	MIMEType    string       `json:"mimeType,omitempty"`
+	Thinking    any          `json:"thinking,omitempty"`
+	Contents    []keyContent `json:"contents"`
+}
+
+// Key 计算请求的缓存键：忽略思考内容、调用 ID 与时间戳秒级差异
+func Key(scope string, req *model.LLMRequest) string {
+	k := keyRequest{Scope: scope}
+	if cfg := req.Config; cfg != nil {
+		if cfg.SystemInstruction != nil {
+			k.Instruction = normalizeText(joinText(cfg.SystemInstruction.Parts))
+		}
+		for _, tool := range cfg.Tools {
+			if tool == nil {
+				continue
+			}
+			for _, fd := range tool.FunctionDeclarations {
+				k.Tools = append(k.Tools, fd.Name)
+			}
+		}
+		sort.Strings(k.Tools)
+		k.Temperature = cfg.Temperature
+		k.MaxTokens = cfg.MaxOutputTokens
+		if cfg.ResponseJsonSchema != nil {
+			k.Schema = cfg.ResponseJsonSchema
+		} else if cfg.ResponseSchema != nil {
+			k.Schema = cfg.ResponseSchema
+		}
+		k.MIMEType = cfg.ResponseMIMEType
+		if cfg.ThinkingConfig != nil {
+			k.Thinking = cfg.ThinkingConfig
+		}
+	}
+	for _, c := range req.Contents {
+		if c == nil {
+			continue
+		}
+		kc := keyContent{Role: c.Role}
+		for _, p := range c.Parts {
+			if p == nil || p.Thought {
+				continue
+			}
+			var kp keyPart
+			switch {
+			case p.FunctionCall != nil:
+				kp.Call, kp.Args = p.FunctionCall.Name, p.FunctionCall.Args
+			case p.FunctionResponse != nil:
+				kp.Call, kp.Response = p.FunctionResponse.Name, p.FunctionResponse.Response
+			case p.InlineData != nil:
+				sum := sha256.Sum256(p.InlineData.Data)
+				kp.Data = p.InlineData.MIMEType + ":" + hex.EncodeToString(sum[:])
+			case p.Text != "":
+				kp.Text = normalizeText(p.Text)
+			default:
+				continue
+			}
+			kc.Parts = append(kc.Parts, kp)
+		}
+		if len(kc.Parts) > 0 {
+			k.Contents = append(k.Contents, kc)
+		}
+	}
+
+	data, _ := json.Marshal(k)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func joinText(parts []*genai.Part) string {
+	var sb strings.Builder
+	for _, p := range parts {
+		if p != nil && p.Text != "" {
+			sb.WriteString(p.Text)
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
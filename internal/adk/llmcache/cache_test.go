@@ -0,0 +1,173 @@
+package llmcache
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+	"time"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+type countingLLM struct {
+	calls int
+	err   error
+}
+
+func (m *countingLLM) Name() string { return "fake" }
+
+func (m *countingLLM) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		m.calls++
+		if m.err != nil {
+			yield(nil, m.err)
+			return
+		}
+		if stream && !yield(&model.LLMResponse{Content: genai.NewContentFromText("看", "model"), Partial: true}, nil) {
+			return
+		}
+		yield(&model.LLMResponse{Content: genai.NewContentFromText("看多", "model"), TurnComplete: true}, nil)
+	}
+}
+
+func newRequest(instruction, query string) *model.LLMRequest {
+	return &model.LLMRequest{
+		Contents: []*genai.Content{genai.NewContentFromText(query, "user")},
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: genai.NewContentFromText(instruction, "user"),
+		},
+	}
+}
+
+func collect(t *testing.T, llm model.LLM, ctx context.Context, req *model.LLMRequest, stream bool) (partials int, final string, err error) {
+	t.Helper()
+	for resp, e := range llm.GenerateContent(ctx, req, stream) {
+		if e != nil {
+			return partials, final, e
+		}
+		if resp.Partial {
+			partials++
+			continue
+		}
+		final = resp.Content.Parts[0].Text
+	}
+	return
+}
+
+func TestKey_Normalization(t *testing.T) {
+	a := newRequest("当前时间: 2026-10-19 10:31:02\n盘中", "茅台怎么看")
+	b := newRequest("当前时间: 2026-10-19 10:45:59\n盘中", "茅台怎么看")
+	if Key("s", a) != Key("s", b) {
+		t.Fatal("timestamps within the same day should share a key")
+	}
+	c := newRequest("当前时间: 2026-10-20 10:31:02\n盘中", "茅台怎么看")
+	if Key("s", a) == Key("s", c) {
+		t.Fatal("different days should not share a key")
+	}
+	if Key("s", a) == Key("other", a) {
+		t.Fatal("scope should be part of the key")
+	}
+
+	// 工具调用 ID 与思考内容不影响键，工具结果影响
+	withTool := func(id, thought string, price float64) *model.LLMRequest {
+		req := newRequest("x", "q")
+		req.Contents = append(req.Contents,
+			&genai.Content{Role: "model", Parts: []*genai.Part{
+				{Text: thought, Thought: true},
+				{FunctionCall: &genai.FunctionCall{ID: id, Name: "get_stock_realtime", Args: map[string]any{"code": "600519"}}},
+			}},
+			&genai.Content{Role: "user", Parts: []*genai.Part{
+				{FunctionResponse: &genai.FunctionResponse{ID: id, Name: "get_stock_realtime", Response: map[string]any{"price": price}}},
+			}},
+		)
+		return req
+	}
+	if Key("s", withTool("call_0", "想想", 1500)) != Key("s", withTool("call_9", "再想想", 1500)) {
+		t.Fatal("call IDs and thoughts should be ignored")
+	}
+	if Key("s", withTool("call_0", "", 1500)) == Key("s", withTool("call_0", "", 1501)) {
+		t.Fatal("tool results should change the key")
+	}
+
+	// 推理设置与 JSON 模式影响键
+	withConfig := func(thinking *genai.ThinkingConfig, mime string) *model.LLMRequest {
+		req := newRequest("x", "q")
+		req.Config.ThinkingConfig = thinking
+		req.Config.ResponseMIMEType = mime
+		return req
+	}
+	budget := int32(8192)
+	if Key("s", withConfig(nil, "")) == Key("s", withConfig(&genai.ThinkingConfig{ThinkingBudget: &budget}, "")) {
+		t.Fatal("thinking config should change the key")
+	}
+	if Key("s", withConfig(nil, "")) == Key("s", withConfig(nil, "application/json")) {
+		t.Fatal("response MIME type should change the key")
+	}
+}
+
+func TestCachedModel_HitMissBypass(t *testing.T) {
+	cache, err := New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	inner := &countingLLM{}
+	llm := cache.Wrap(inner, "cfg")
+	req := newRequest("instr", "茅台怎么看")
+	ctx := context.Background()
+
+	// 未启用时直接透传
+	collect(t, llm, ctx, req, false)
+	if inner.calls != 1 || cache.Stats().Misses != 0 {
+		t.Fatalf("disabled cache should pass through: calls=%d stats=%+v", inner.calls, cache.Stats())
+	}
+
+	cache.SetEnabled(true)
+	if _, final, _ := collect(t, llm, ctx, req, true); final != "看多" {
+		t.Fatalf("miss final = %q", final)
+	}
+	partials, final, _ := collect(t, llm, ctx, req, true)
+	if inner.calls != 2 || final != "看多" || partials != 1 {
+		t.Fatalf("hit should replay without calling model: calls=%d final=%q partials=%d", inner.calls, final, partials)
+	}
+
+	collect(t, llm, WithBypass(ctx), req, false)
+	if inner.calls != 3 {
+		t.Fatalf("bypass should call the model, calls=%d", inner.calls)
+	}
+
+	s := cache.Stats()
+	if s.Hits != 1 || s.Misses != 1 || s.Stores != 1 || s.Bypassed != 1 || s.Entries != 1 || s.HitRate != 0.5 {
+		t.Fatalf("unexpected stats: %+v", s)
+	}
+
+	if err := cache.Clear(); err != nil || cache.Stats().Entries != 0 {
+		t.Fatalf("clear failed: %v %+v", err, cache.Stats())
+	}
+}
+
+func TestCachedModel_ErrorsAndExpiry(t *testing.T) {
+	cache, _ := New(t.TempDir())
+	cache.SetEnabled(true)
+	ctx := context.Background()
+	req := newRequest("instr", "q")
+
+	failing := &countingLLM{err: errors.New("HTTP 500")}
+	if _, _, err := collect(t, cache.Wrap(failing, "cfg"), ctx, req, false); err == nil {
+		t.Fatal("expected error")
+	}
+	if cache.Stats().Stores != 0 {
+		t.Fatal("errors must not be cached")
+	}
+
+	// TTL 非正时不写入缓存，每次都调用模型
+	cache.SetTTLFunc(func() time.Duration { return -time.Second })
+	inner := &countingLLM{}
+	llm := cache.Wrap(inner, "cfg")
+	collect(t, llm, ctx, req, false)
+	collect(t, llm, ctx, req, false)
+	if inner.calls != 2 {
+		t.Fatalf("non-positive TTL should disable storing, calls=%d", inner.calls)
+	}
+}
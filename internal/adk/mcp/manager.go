@@ -4,14 +4,11 @@ package mcp
 
 import (
 	"context"
-	"os/exec"
 	"sync"
-	"time"
 
 	"github.com/run-bigpig/jcp/internal/logger"
 	"github.com/run-bigpig/jcp/internal/models"
 
-	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/mcptoolset"
 )
@@ -40,6 +37,8 @@ type Manager struct {
 	mu       sync.RWMutex
 	configs  map[string]*models.MCPServerConfig
 	toolsets map[string]tool.Toolset // 缓存已创建的 toolset
+	// 缓存已读取的资源内容
+	resources resourceCache
 }
 
 // NewManager 创建 MCP 管理器（需要调用 Initialize 绑定 context）
@@ -81,6 +80,7 @@ func (m *Manager) LoadConfigs(configs []models.MCPServerConfig) error {
 	// 清空旧配置和缓存
 	m.configs = make(map[string]*models.MCPServerConfig)
 	m.toolsets = make(map[string]tool.Toolset)
+	m.resources.clear()
 
 	for i := range configs {
 		cfg := &configs[i]
@@ -106,24 +106,6 @@ func (m *Manager) LoadConfigs(configs []models.MCPServerConfig) error {
 	return nil
 }
 
-// createTransport 根据配置创建 MCP 传输层
-func createTransport(cfg *models.MCPServerConfig) mcp.Transport {
-	switch cfg.TransportType {
-	case models.MCPTransportSSE:
-		log.Warn("创建 SSE 传输 [%s]: %s (已废弃)", cfg.Name, cfg.Endpoint)
-		return &mcp.SSEClientTransport{Endpoint: cfg.Endpoint}
-	case models.MCPTransportCommand:
-		log.Info("创建 Command 传输 [%s]: %s %v", cfg.Name, cfg.Command, cfg.Args)
-		return &mcp.CommandTransport{Command: exec.Command(cfg.Command, cfg.Args...)}
-	default:
-		log.Info("创建 StreamableHTTP 传输 [%s]: %s", cfg.Name, cfg.Endpoint)
-		return &mcp.StreamableClientTransport{
-			Endpoint:   cfg.Endpoint,
-			MaxRetries: 3,
-		}
-	}
-}
-
 // CreateToolset 为指定配置创建 mcptoolset（直接使用 adk-go 官方实现）
 func (m *Manager) CreateToolset(cfg *models.MCPServerConfig) (tool.Toolset, error) {
 	return m.createToolsetLocked(cfg)
@@ -131,8 +113,14 @@ func (m *Manager) CreateToolset(cfg *models.MCPServerConfig) (tool.Toolset, erro
 
 // createToolsetLocked 内部方法，创建 toolset（调用方需持有锁）
 func (m *Manager) createToolsetLocked(cfg *models.MCPServerConfig) (tool.Toolset, error) {
+	transport, err := createTransport(cfg)
+	if err != nil {
+		log.Error("创建 MCP 传输失败 [%s]: %v", cfg.Name, err)
+		return nil, err
+	}
 	ts, err := mcptoolset.New(mcptoolset.Config{
-		Transport: createTransport(cfg),
+		Client:    newClient(cfg),
+		Transport: transport,
 	})
 	if err != nil {
 		log.Error("创建 mcptoolset 失败 [%s]: %v", cfg.Name, err)
@@ -222,17 +210,20 @@ func (m *Manager) TestConnection(serverID string) *ServerStatus {
 		return &ServerStatus{ID: serverID, Connected: false, Error: "服务器未配置"}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	transport, err := createTransport(cfg)
+	if err != nil {
+		return &ServerStatus{ID: serverID, Connected: false, Error: err.Error()}
+	}
 
-	impl := &mcp.Implementation{Name: cfg.Name, Version: "1.0.0"}
-	client := mcp.NewClient(impl, nil)
-	_, err := client.Connect(ctx, createTransport(cfg), nil)
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout(cfg))
+	defer cancel()
 
+	session, err := newClient(cfg).Connect(ctx, transport, nil)
 	if err != nil {
 		log.Error("测试连接失败 [%s]: %v", cfg.Name, err)
 		return &ServerStatus{ID: serverID, Connected: false, Error: err.Error()}
 	}
+	session.Close()
 	log.Info("测试连接成功: %s", cfg.Name)
 	return &ServerStatus{ID: serverID, Connected: true}
 }
@@ -247,12 +238,15 @@ func (m *Manager) GetServerTools(serverID string) ([]ToolInfo, error) {
 		return nil, nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	transport, err := createTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*connectTimeout(cfg))
 	defer cancel()
 
-	impl := &mcp.Implementation{Name: cfg.Name, Version: "1.0.0"}
-	client := mcp.NewClient(impl, nil)
-	session, err := client.Connect(ctx, createTransport(cfg), nil)
+	session, err := newClient(cfg).Connect(ctx, transport, nil)
 	if err != nil {
 		return nil, err
 	}
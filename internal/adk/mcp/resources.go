@@ -0,0 +1,313 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// 资源注入上下文的限制
+const (
+	resourceCacheTTL     = 5 * time.Minute
+	resourceFetchTimeout = 5 * time.Second // 构建专家时读取资源的上限，避免服务不可用时阻塞会议
+	maxResourceRunes     = 4000
+	maxResourceContext   = 12000
+)
+
+// ResourceInfo MCP 资源信息
+type ResourceInfo struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	MIMEType    string `json:"mimeType"`
+	ServerID    string `json:"serverId"`
+	ServerName  string `json:"serverName"`
+}
+
+// PromptArgument MCP 提示词参数
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+}
+
+// PromptInfo MCP 提示词信息
+type PromptInfo struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Arguments   []PromptArgument `json:"arguments"`
+	ServerID    string           `json:"serverId"`
+	ServerName  string           `json:"serverName"`
+}
+
+// TextResult 读取资源或渲染提示词的结果
+type TextResult struct {
+	Text  string `json:"text"`
+	Error string `json:"error,omitempty"`
+}
+
+// cachedText 带过期时间的资源内容缓存，读取失败也缓存以免反复等待超时
+type cachedText struct {
+	text    string
+	err     error
+	expires time.Time
+}
+
+// resourceCache 资源内容缓存，避免每次构建专家都重新读取
+type resourceCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedText
+}
+
+func (c *resourceCache) get(key string) (cachedText, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || time.Now().After(e.expires) {
+		return cachedText{}, false
+	}
+	return e, true
+}
+
+func (c *resourceCache) set(key, text string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]cachedText)
+	}
+	c.entries[key] = cachedText{text: text, err: err, expires: time.Now().Add(resourceCacheTTL)}
+}
+
+func (c *resourceCache) clear() {
+	c.mu.Lock()
+	c.entries = nil
+	c.mu.Unlock()
+}
+
+// withSession 连接指定服务器并执行 fn，结束后关闭会话；timeout 为 0 时使用连接超时的两倍
+func (m *Manager) withSession(serverID string, timeout time.Duration, fn func(ctx context.Context, cfg *models.MCPServerConfig, session *mcp.ClientSession) error) error {
+	m.mu.RLock()
+	cfg, ok := m.configs[serverID]
+	m.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("服务器未配置: %s", serverID)
+	}
+
+	transport, err := createTransport(cfg)
+	if err != nil {
+		return err
+	}
+
+	if timeout <= 0 {
+		timeout = 2 * connectTimeout(cfg)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	session, err := newClient(cfg).Connect(ctx, transport, nil)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	return fn(ctx, cfg, session)
+}
+
+// GetServerResources 获取指定 MCP 服务器的资源列表
+func (m *Manager) GetServerResources(serverID string) ([]ResourceInfo, error) {
+	var resources []ResourceInfo
+	err := m.withSession(serverID, 0, func(ctx context.Context, cfg *models.MCPServerConfig, session *mcp.ClientSession) error {
+		if caps := session.InitializeResult().Capabilities; caps == nil || caps.Resources == nil {
+			return nil
+		}
+		for r, err := range session.Resources(ctx, nil) {
+			if err != nil {
+				return err
+			}
+			name := r.Title
+			if name == "" {
+				name = r.Name
+			}
+			resources = append(resources, ResourceInfo{
+				URI:         r.URI,
+				Name:        name,
+				Description: r.Description,
+				MIMEType:    r.MIMEType,
+				ServerID:    serverID,
+				ServerName:  cfg.Name,
+			})
+		}
+		return nil
+	})
+	return resources, err
+}
+
+// ReadResource 读取资源的文本内容（不走缓存），二进制内容以占位说明代替
+func (m *Manager) ReadResource(serverID, uri string) (string, error) {
+	return m.fetchResource(serverID, uri, 0)
+}
+
+// cachedResource 优先使用缓存（含失败记录），未命中时以短超时读取
+func (m *Manager) cachedResource(serverID, uri string) (string, error) {
+	if e, ok := m.resources.get(resourceKey(serverID, uri)); ok {
+		return e.text, e.err
+	}
+	return m.fetchResource(serverID, uri, resourceFetchTimeout)
+}
+
+// fetchResource 读取资源并写入缓存，失败结果同样缓存
+func (m *Manager) fetchResource(serverID, uri string, timeout time.Duration) (string, error) {
+	var text string
+	err := m.withSession(serverID, timeout, func(ctx context.Context, _ *models.MCPServerConfig, session *mcp.ClientSession) error {
+		res, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
+		if err != nil {
+			return err
+		}
+		text = resourceText(res.Contents)
+		return nil
+	})
+	m.resources.set(resourceKey(serverID, uri), text, err)
+	return text, err
+}
+
+func resourceKey(serverID, uri string) string {
+	return serverID + "\x00" + uri
+}
+
+// GetServerPrompts 获取指定 MCP 服务器的提示词列表
+func (m *Manager) GetServerPrompts(serverID string) ([]PromptInfo, error) {
+	var prompts []PromptInfo
+	err := m.withSession(serverID, 0, func(ctx context.Context, cfg *models.MCPServerConfig, session *mcp.ClientSession) error {
+		if caps := session.InitializeResult().Capabilities; caps == nil || caps.Prompts == nil {
+			return nil
+		}
+		for p, err := range session.Prompts(ctx, nil) {
+			if err != nil {
+				return err
+			}
+			info := PromptInfo{
+				Name:        p.Name,
+				Description: p.Description,
+				Arguments:   []PromptArgument{},
+				ServerID:    serverID,
+				ServerName:  cfg.Name,
+			}
+			for _, arg := range p.Arguments {
+				info.Arguments = append(info.Arguments, PromptArgument{
+					Name:        arg.Name,
+					Description: arg.Description,
+					Required:    arg.Required,
+				})
+			}
+			prompts = append(prompts, info)
+		}
+		return nil
+	})
+	return prompts, err
+}
+
+// GetPrompt 按参数渲染提示词，返回拼接后的文本
+func (m *Manager) GetPrompt(serverID, name string, args map[string]string) (string, error) {
+	var text string
+	err := m.withSession(serverID, 0, func(ctx context.Context, _ *models.MCPServerConfig, session *mcp.ClientSession) error {
+		res, err := session.GetPrompt(ctx, &mcp.GetPromptParams{Name: name, Arguments: args})
+		if err != nil {
+			return err
+		}
+		text = promptText(res.Messages)
+		return nil
+	})
+	return text, err
+}
+
+// BuildResourceContext 并行读取专家附加的资源并拼接为上下文，单个资源与总长度均截断
+func (m *Manager) BuildResourceContext(refs []models.MCPResourceRef) string {
+	if len(refs) == 0 {
+		return ""
+	}
+	texts := make([]string, len(refs))
+	var wg sync.WaitGroup
+	for i, ref := range refs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			text, err := m.cachedResource(ref.ServerID, ref.URI)
+			if err != nil {
+				log.Warn("读取 MCP 资源失败 [%s %s]: %v", ref.ServerID, ref.URI, err)
+				return
+			}
+			texts[i] = text
+		}()
+	}
+	wg.Wait()
+
+	var sb strings.Builder
+	remaining := maxResourceContext
+	for i, ref := range refs {
+		if remaining <= 0 {
+			break
+		}
+		text := truncateRunes(strings.TrimSpace(texts[i]), min(maxResourceRunes, remaining))
+		if text == "" {
+			continue
+		}
+		remaining -= len([]rune(text))
+		title := ref.Name
+		if title == "" {
+			title = ref.URI
+		}
+		fmt.Fprintf(&sb, "### %s\n%s\n\n", title, text)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// resourceText 拼接资源内容中的文本部分
+func resourceText(contents []*mcp.ResourceContents) string {
+	var parts []string
+	for _, c := range contents {
+		if c == nil {
+			continue
+		}
+		if c.Blob != nil && c.Text == "" {
+			parts = append(parts, fmt.Sprintf("[二进制资源 %s，%s，%d 字节]", c.URI, c.MIMEType, len(c.Blob)))
+			continue
+		}
+		parts = append(parts, c.Text)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// promptText 拼接提示词消息中的文本与内嵌资源
+func promptText(messages []*mcp.PromptMessage) string {
+	var parts []string
+	for _, msg := range messages {
+		if msg == nil {
+			continue
+		}
+		switch c := msg.Content.(type) {
+		case *mcp.TextContent:
+			parts = append(parts, c.Text)
+		case *mcp.EmbeddedResource:
+			if c.Resource != nil {
+				parts = append(parts, resourceText([]*mcp.ResourceContents{c.Resource}))
+			}
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// truncateRunes 按字符数截断并标注
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "\n…（内容已截断）"
+}
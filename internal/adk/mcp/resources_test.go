@@ -0,0 +1,57 @@
+package mcp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestResourceAndPromptText(t *testing.T) {
+	got := resourceText([]*mcp.ResourceContents{
+		{URI: "file:///a.md", Text: "hello"},
+		{URI: "file:///b.png", MIMEType: "image/png", Blob: []byte{1, 2, 3}},
+	})
+	if !strings.HasPrefix(got, "hello\n\n") || !strings.Contains(got, "image/png，3 字节") {
+		t.Errorf("resourceText = %q", got)
+	}
+
+	got = promptText([]*mcp.PromptMessage{
+		{Role: "user", Content: &mcp.TextContent{Text: "你是分析师"}},
+		{Role: "user", Content: &mcp.ImageContent{MIMEType: "image/png"}},
+		{Role: "assistant", Content: &mcp.EmbeddedResource{Resource: &mcp.ResourceContents{URI: "x://r", Text: "附录"}}},
+	})
+	if got != "你是分析师\n\n附录" {
+		t.Errorf("promptText = %q", got)
+	}
+}
+
+func TestBuildResourceContext(t *testing.T) {
+	m := NewManager()
+	m.resources.set(resourceKey("s1", "doc://short"), "  简短资料  ", nil)
+	m.resources.set(resourceKey("s1", "doc://long"), strings.Repeat("长", maxResourceRunes+10), nil)
+	m.resources.set(resourceKey("s1", "doc://down"), "", errors.New("connection refused"))
+
+	got := m.BuildResourceContext([]models.MCPResourceRef{
+		{ServerID: "s1", URI: "doc://short", Name: "简报"},
+		{ServerID: "s1", URI: "doc://down", Name: "离线资料"},
+		{ServerID: "s1", URI: "doc://long"},
+		{ServerID: "missing", URI: "doc://none"},
+	})
+	if !strings.HasPrefix(got, "### 简报\n简短资料\n\n### doc://long\n") {
+		t.Errorf("unexpected header: %q", got[:min(len(got), 60)])
+	}
+	if !strings.HasSuffix(got, "（内容已截断）") || strings.Contains(got, "doc://none") || strings.Contains(got, "离线资料") {
+		t.Errorf("long resource not truncated or failed one included")
+	}
+	// 失败结果被缓存，不会重复连接
+	if e, ok := m.resources.get(resourceKey("missing", "doc://none")); !ok || e.err == nil {
+		t.Errorf("failed read should be cached as negative entry: %+v ok=%v", e, ok)
+	}
+	if m.BuildResourceContext(nil) != "" {
+		t.Error("expected empty context for no refs")
+	}
+}
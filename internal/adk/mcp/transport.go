@@ -0,0 +1,188 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// 默认超时
+const (
+	defaultConnectTimeout = 10 * time.Second
+	defaultCallTimeout    = 60 * time.Second
+)
+
+// connectTimeout 连接（含 initialize 与工具列表）超时
+func connectTimeout(cfg *models.MCPServerConfig) time.Duration {
+	if cfg.ConnectTimeoutSeconds > 0 {
+		return time.Duration(cfg.ConnectTimeoutSeconds) * time.Second
+	}
+	return defaultConnectTimeout
+}
+
+// callTimeout 单次工具调用超时
+func callTimeout(cfg *models.MCPServerConfig) time.Duration {
+	if cfg.CallTimeoutSeconds > 0 {
+		return time.Duration(cfg.CallTimeoutSeconds) * time.Second
+	}
+	return defaultCallTimeout
+}
+
+// newClient 创建 MCP 客户端，按请求方法施加超时：tools/call 用调用超时，其余用连接超时
+func newClient(cfg *models.MCPServerConfig) *mcp.Client {
+	client := mcp.NewClient(&mcp.Implementation{Name: cfg.Name, Version: "1.0.0"}, nil)
+	client.AddSendingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			timeout := connectTimeout(cfg)
+			if method == "tools/call" {
+				timeout = callTimeout(cfg)
+			}
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return next(ctx, method, req)
+		}
+	})
+	return client
+}
+
+// createTransport 根据配置创建 MCP 传输层
+func createTransport(cfg *models.MCPServerConfig) (mcp.Transport, error) {
+	switch cfg.TransportType {
+	case models.MCPTransportCommand:
+		log.Info("创建 Command 传输 [%s]: %s %v", cfg.Name, cfg.Command, cfg.Args)
+		if cfg.Command == "" {
+			return nil, errors.New("未配置命令")
+		}
+		return &mcp.CommandTransport{Command: buildCommand(cfg)}, nil
+	case models.MCPTransportSSE:
+		log.Warn("创建 SSE 传输 [%s]: %s (已废弃)", cfg.Name, cfg.Endpoint)
+		client, err := httpClient(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.SSEClientTransport{Endpoint: cfg.Endpoint, HTTPClient: client}, nil
+	default:
+		log.Info("创建 StreamableHTTP 传输 [%s]: %s", cfg.Name, cfg.Endpoint)
+		client, err := httpClient(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.StreamableClientTransport{
+			Endpoint:   cfg.Endpoint,
+			HTTPClient: client,
+			MaxRetries: 3,
+		}, nil
+	}
+}
+
+// buildCommand 构建子进程命令，Env 追加在系统环境之后（同名覆盖）
+func buildCommand(cfg *models.MCPServerConfig) *exec.Cmd {
+	cmd := exec.Command(cfg.Command, cfg.Args...)
+	cmd.Dir = cfg.WorkingDir
+	if len(cfg.Env) > 0 {
+		keys := make([]string, 0, len(cfg.Env))
+		for k := range cfg.Env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		cmd.Env = os.Environ()
+		for _, k := range keys {
+			cmd.Env = append(cmd.Env, k+"="+expandEnv(cfg.Env[k], nil))
+		}
+	}
+	return cmd
+}
+
+// httpClient 构建带自定义请求头与鉴权的 HTTP 客户端
+// 不设置整体超时：StreamableHTTP/SSE 依赖长连接接收服务端消息，超时由 newClient 按请求控制
+func httpClient(cfg *models.MCPServerConfig) (*http.Client, error) {
+	headers := make(map[string]string, len(cfg.Headers)+1)
+	for k, v := range cfg.Headers {
+		headers[k] = expandEnv(v, cfg.Env)
+	}
+
+	var tokenSource oauth2.TokenSource
+	if auth := cfg.Auth; auth != nil {
+		switch auth.Type {
+		case models.MCPAuthNone:
+		case models.MCPAuthBearer:
+			token := expandEnv(auth.Token, cfg.Env)
+			if token == "" {
+				return nil, errors.New("Bearer 鉴权未配置 Token")
+			}
+			headers["Authorization"] = "Bearer " + token
+		case models.MCPAuthClientCredentials:
+			if auth.TokenURL == "" || auth.ClientID == "" {
+				return nil, errors.New("OAuth 客户端凭据鉴权需要 Token URL 与 Client ID")
+			}
+			cc := &clientcredentials.Config{
+				ClientID:     expandEnv(auth.ClientID, cfg.Env),
+				ClientSecret: expandEnv(auth.ClientSecret, cfg.Env),
+				TokenURL:     expandEnv(auth.TokenURL, cfg.Env),
+				Scopes:       auth.Scopes,
+			}
+			// 获取令牌的请求设置超时，避免授权服务器无响应时阻塞连接
+			tokenCtx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: proxyTransport{}, Timeout: connectTimeout(cfg)})
+			tokenSource = cc.TokenSource(tokenCtx)
+		default:
+			return nil, fmt.Errorf("不支持的鉴权方式: %s", auth.Type)
+		}
+	}
+
+	var rt http.RoundTripper = &headerTransport{base: proxyTransport{}, headers: headers}
+	if tokenSource != nil {
+		rt = &oauth2.Transport{Source: tokenSource, Base: rt}
+	}
+	return &http.Client{Transport: rt}, nil
+}
+
+// proxyTransport 每次请求取代理管理器当前的共享 Transport，使缓存的 MCP 连接也能跟随代理设置变更
+// 不用 GetTransport：它每次返回克隆，无法复用连接
+type proxyTransport struct{}
+
+func (proxyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return proxy.GetManager().GetClient().Transport.RoundTrip(req)
+}
+
+// headerTransport 为每个请求附加固定请求头
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(t.headers) == 0 {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	return t.base.RoundTrip(req)
+}
+
+var envRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnv 展开 ${VAR} 引用，优先取 env，其次取系统环境变量
+func expandEnv(value string, env map[string]string) string {
+	return envRefPattern.ReplaceAllStringFunc(value, func(ref string) string {
+		name := ref[2 : len(ref)-1]
+		if v, ok := env[name]; ok {
+			return v
+		}
+		return os.Getenv(name)
+	})
+}
@@ -0,0 +1,112 @@
+package mcp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("JCP_MCP_TEST_SYS", "sys")
+	env := map[string]string{"KEY": "local"}
+	got := expandEnv("a=${KEY},b=${JCP_MCP_TEST_SYS},c=${MISSING_JCP_VAR},d=$KEY", env)
+	if want := "a=local,b=sys,c=,d=$KEY"; got != want {
+		t.Errorf("expandEnv = %q, want %q", got, want)
+	}
+}
+
+func TestHTTPClient_HeadersAndBearer(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer srv.Close()
+
+	client, err := httpClient(&models.MCPServerConfig{
+		Env:     map[string]string{"API_KEY": "secret"},
+		Headers: map[string]string{"X-Api-Key": "${API_KEY}"},
+		Auth:    &models.MCPAuthConfig{Type: models.MCPAuthBearer, Token: "tok-${API_KEY}"},
+	})
+	if err != nil {
+		t.Fatalf("httpClient: %v", err)
+	}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	resp.Body.Close()
+	if got.Get("X-Api-Key") != "secret" || got.Get("Authorization") != "Bearer tok-secret" {
+		t.Errorf("headers = %v", got)
+	}
+}
+
+func TestHTTPClient_ClientCredentials(t *testing.T) {
+	tokenCalls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			tokenCalls++
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"cc-token","token_type":"bearer","expires_in":3600}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer cc-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := httpClient(&models.MCPServerConfig{Auth: &models.MCPAuthConfig{
+		Type: models.MCPAuthClientCredentials, TokenURL: srv.URL + "/token", ClientID: "id", ClientSecret: "s",
+	}})
+	if err != nil {
+		t.Fatalf("httpClient: %v", err)
+	}
+	for range 2 {
+		resp, err := client.Get(srv.URL + "/mcp")
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("status = %d", resp.StatusCode)
+		}
+	}
+	if tokenCalls != 1 {
+		t.Errorf("token requested %d times, want 1 (cached)", tokenCalls)
+	}
+}
+
+func TestCreateTransport_Errors(t *testing.T) {
+	cases := []models.MCPServerConfig{
+		{TransportType: models.MCPTransportCommand},
+		{TransportType: models.MCPTransportHTTP, Auth: &models.MCPAuthConfig{Type: models.MCPAuthBearer}},
+		{TransportType: models.MCPTransportSSE, Auth: &models.MCPAuthConfig{Type: models.MCPAuthClientCredentials}},
+		{TransportType: models.MCPTransportHTTP, Auth: &models.MCPAuthConfig{Type: "basic"}},
+	}
+	for i := range cases {
+		if _, err := createTransport(&cases[i]); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
+
+func TestBuildCommandAndTimeouts(t *testing.T) {
+	cfg := &models.MCPServerConfig{
+		Command:            "node",
+		Args:               []string{"server.js"},
+		WorkingDir:         "/srv/mcp",
+		Env:                map[string]string{"TOKEN": "abc"},
+		CallTimeoutSeconds: 5,
+	}
+	cmd := buildCommand(cfg)
+	if cmd.Dir != "/srv/mcp" || !slices.Contains(cmd.Env, "TOKEN=abc") {
+		t.Errorf("cmd dir=%q env has TOKEN=%v", cmd.Dir, slices.Contains(cmd.Env, "TOKEN=abc"))
+	}
+	if connectTimeout(cfg) != defaultConnectTimeout || callTimeout(cfg) != 5*time.Second {
+		t.Errorf("timeouts = %v / %v", connectTimeout(cfg), callTimeout(cfg))
+	}
+}
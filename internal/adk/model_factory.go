@@ -9,12 +9,15 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"cloud.google.com/go/auth"
 	"cloud.google.com/go/auth/credentials"
 	"cloud.google.com/go/auth/httptransport"
 	"github.com/run-bigpig/jcp/internal/adk/anthropic"
+	"github.com/run-bigpig/jcp/internal/adk/llmcache"
+	"github.com/run-bigpig/jcp/internal/adk/ollama"
 	"github.com/run-bigpig/jcp/internal/adk/openai"
 	"github.com/run-bigpig/jcp/internal/models"
 	"github.com/run-bigpig/jcp/internal/pkg/proxy"
@@ -49,7 +52,49 @@ func NewModelFactory() *ModelFactory {
 }
 
 // CreateModel 根据 AI 配置创建对应的模型
+// 路由配置创建 RouterModel；设置了并发、RPM 或 TPM 限制的普通配置包装为限流模型；
+// 启用响应缓存时最外层再包装缓存，命中时不占用配额
 func (f *ModelFactory) CreateModel(ctx context.Context, config *models.AIConfig) (model.LLM, error) {
+	var llm model.LLM
+	var err error
+	if config.Provider == models.AIProviderRouter {
+		llm, err = f.createRouterModel(ctx, config)
+	} else {
+		llm, err = f.createProviderModel(ctx, config)
+		if limits := limitsOf(config); err == nil && limits.enabled() {
+			llm = &quotaModel{LLM: llm, limits: limits, quota: sharedQuota}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cache := getResponseCache(); cache != nil {
+		scope := config.ID + "|" + config.ModelName + "|" + strings.Join(config.RouteConfigIDs, ",")
+		llm = cache.Wrap(llm, scope)
+	}
+	return llm, nil
+}
+
+var (
+	responseCacheMu sync.RWMutex
+	responseCache   *llmcache.Cache
+)
+
+// SetResponseCache 注册全局 LLM 响应缓存，之后创建的模型均经过缓存装饰
+func SetResponseCache(cache *llmcache.Cache) {
+	responseCacheMu.Lock()
+	defer responseCacheMu.Unlock()
+	responseCache = cache
+}
+
+func getResponseCache() *llmcache.Cache {
+	responseCacheMu.RLock()
+	defer responseCacheMu.RUnlock()
+	return responseCache
+}
+
+// createProviderModel 按 provider 创建底层模型
+func (f *ModelFactory) createProviderModel(ctx context.Context, config *models.AIConfig) (model.LLM, error) {
 	switch config.Provider {
 	case models.AIProviderGemini:
 		return f.createGeminiModel(ctx, config)
@@ -62,11 +107,35 @@ func (f *ModelFactory) CreateModel(ctx context.Context, config *models.AIConfig)
 		return f.createOpenAIModel(config)
 	case models.AIProviderAnthropic:
 		return f.createAnthropicModel(config)
+	case models.AIProviderOllama:
+		return f.createOllamaModel(config)
 	default:
 		return nil, fmt.Errorf("unsupported provider: %s", config.Provider)
 	}
 }
 
+// createRouterModel 解析路由成员并创建 RouterModel，跳过缺失、嵌套路由与创建失败的成员
+func (f *ModelFactory) createRouterModel(ctx context.Context, config *models.AIConfig) (model.LLM, error) {
+	var members []*routeMember
+	for _, id := range config.RouteConfigIDs {
+		member := lookupAIConfig(id)
+		if member == nil || member.ID == config.ID || member.Provider == models.AIProviderRouter {
+			log.Warn("路由 [%s] 成员 %s 不存在或不可嵌套，已跳过", config.Name, id)
+			continue
+		}
+		llm, err := f.createProviderModel(ctx, member)
+		if err != nil {
+			log.Warn("路由 [%s] 成员 [%s] 创建失败，已跳过: %v", config.Name, member.Name, err)
+			continue
+		}
+		members = append(members, &routeMember{id: member.ID, name: member.Name, limits: limitsOf(member), llm: llm})
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("路由 [%s] 没有可用的成员配置", config.Name)
+	}
+	return newRouterModel(config.Name, config.RouteStrategy, config.RouteFallbackOn, members, sharedRouteState, sharedQuota), nil
+}
+
 // createGeminiModel 创建 Gemini 模型
 func (f *ModelFactory) createGeminiModel(ctx context.Context, config *models.AIConfig) (model.LLM, error) {
 	clientConfig := &genai.ClientConfig{
@@ -165,6 +234,12 @@ func (f *ModelFactory) createAnthropicModel(config *models.AIConfig) (model.LLM,
 	return anthropic.NewAnthropicModel(config.ModelName, config.APIKey, baseURL, httpClient, config.NoSystemRole), nil
 }
 
+// createOllamaModel 创建 Ollama 本地模型，回环地址由代理管理器自动直连
+func (f *ModelFactory) createOllamaModel(config *models.AIConfig) (model.LLM, error) {
+	httpClient := &http.Client{Transport: proxy.GetManager().GetTransport()}
+	return ollama.NewOllamaModel(config.ModelName, config.BaseURL, httpClient, config.NoSystemRole), nil
+}
+
 // createOpenAIResponsesModel 创建使用 Responses API 的 OpenAI 模型
 func (f *ModelFactory) createOpenAIResponsesModel(config *models.AIConfig) (model.LLM, error) {
 	baseURL := normalizeOpenAIBaseURL(config.BaseURL)
@@ -179,6 +254,10 @@ func (f *ModelFactory) createOpenAIResponsesModel(config *models.AIConfig) (mode
 // TestConnection 测试 AI 配置的连通性
 // 通过发送一个最小请求来验证 API Key、Base URL、模型名称是否正确
 func (f *ModelFactory) TestConnection(ctx context.Context, config *models.AIConfig) error {
+	if config.Provider == models.AIProviderRouter {
+		return f.testRouterConnection(ctx, config)
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
 
@@ -191,11 +270,34 @@ func (f *ModelFactory) TestConnection(ctx context.Context, config *models.AIConf
 		return f.testVertexAIConnection(ctx, config)
 	case models.AIProviderAnthropic:
 		return f.testAnthropicConnection(ctx, config)
+	case models.AIProviderOllama:
+		return f.testOllamaConnection(ctx, config)
 	default:
 		return fmt.Errorf("不支持的 provider: %s", config.Provider)
 	}
 }
 
+// testRouterConnection 逐个测试路由成员，任一成员可用即视为成功
+func (f *ModelFactory) testRouterConnection(ctx context.Context, config *models.AIConfig) error {
+	if len(config.RouteConfigIDs) == 0 {
+		return fmt.Errorf("路由未配置成员")
+	}
+	var failures []string
+	for _, id := range config.RouteConfigIDs {
+		member := lookupAIConfig(id)
+		if member == nil || member.Provider == models.AIProviderRouter {
+			failures = append(failures, fmt.Sprintf("%s: 配置不存在或不可嵌套", id))
+			continue
+		}
+		if err := f.TestConnection(ctx, member); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", member.Name, err))
+			continue
+		}
+		return nil
+	}
+	return fmt.Errorf("所有路由成员均不可用: %s", strings.Join(failures, "; "))
+}
+
 // systemRoleProbeKeyword 探测暗号，不可能在正常对话中自然出现
 const systemRoleProbeKeyword = "SYS_PROBE_7X3K"
 
@@ -209,7 +311,7 @@ func (f *ModelFactory) DetectSystemRoleSupport(ctx context.Context, config *mode
 	case models.AIProviderAnthropic:
 		return f.detectAnthropicSystemRole(ctx, config)
 	default:
-		return false // Gemini/VertexAI 原生支持
+		return false // Gemini/VertexAI/Ollama 原生支持
 	}
 }
 
@@ -449,6 +551,34 @@ func (f *ModelFactory) testAnthropicConnection(ctx context.Context, config *mode
 	return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(respBody))
 }
 
+// testOllamaConnection 测试 Ollama 连通性，并确认模型已拉取到本地
+// 本地模型首次加载耗时较长，这里不发送生成请求
+func (f *ModelFactory) testOllamaConnection(ctx context.Context, config *models.AIConfig) error {
+	httpClient := &http.Client{Transport: proxy.GetManager().GetTransport()}
+	list, err := ollama.ListModels(ctx, httpClient, config.BaseURL)
+	if err != nil {
+		return err
+	}
+	if !ollama.HasModel(list, config.ModelName) {
+		return fmt.Errorf("模型 %s 尚未拉取到本地，请先拉取", config.ModelName)
+	}
+	return nil
+}
+
+// ListOllamaModels 获取 Ollama 本地模型列表
+func (f *ModelFactory) ListOllamaModels(ctx context.Context, baseURL string) ([]ollama.ModelInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	httpClient := &http.Client{Transport: proxy.GetManager().GetTransport()}
+	return ollama.ListModels(ctx, httpClient, baseURL)
+}
+
+// PullOllamaModel 拉取 Ollama 模型，进度通过回调返回
+func (f *ModelFactory) PullOllamaModel(ctx context.Context, baseURL, name string, onProgress func(ollama.PullProgress)) error {
+	httpClient := &http.Client{Transport: proxy.GetManager().GetTransport()}
+	return ollama.PullModel(ctx, httpClient, baseURL, name, onProgress)
+}
+
 // testViaGenerate 通过 GenerateContent 发送最小请求测试连通性
 func (f *ModelFactory) testViaGenerate(ctx context.Context, llm model.LLM) error {
 	req := &model.LLMRequest{
@@ -0,0 +1,127 @@
+package ollama
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// ListModels 获取本地已下载的模型列表（/api/tags）
+func ListModels(ctx context.Context, httpClient *http.Client, baseURL string) ([]ModelInfo, error) {
+	endpoint, err := url.JoinPath(NormalizeBaseURL(baseURL), "api", "tags")
+	if err != nil {
+		return nil, fmt.Errorf("无效 BaseURL: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("请求创建失败: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("连接失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4*1024*1024))
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tags struct {
+		Models []ModelInfo `json:"models"`
+	}
+	if err := json.Unmarshal(body, &tags); err != nil {
+		return nil, fmt.Errorf("解析模型列表失败: %w", err)
+	}
+	return tags.Models, nil
+}
+
+// HasModel 判断模型是否已在本地，未写 tag 时按 latest 匹配
+func HasModel(list []ModelInfo, name string) bool {
+	candidates := []string{name}
+	if !containsTag(name) {
+		candidates = append(candidates, name+":latest")
+	}
+	for _, item := range list {
+		for _, c := range candidates {
+			if item.Name == c || item.Model == c {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsTag(name string) bool {
+	for i := len(name) - 1; i >= 0; i-- {
+		switch name[i] {
+		case ':':
+			return true
+		case '/':
+			return false
+		}
+	}
+	return false
+}
+
+// PullModel 拉取模型（/api/pull），通过 onProgress 回调流式进度，拉取完成返回 nil
+func PullModel(ctx context.Context, httpClient *http.Client, baseURL, name string, onProgress func(PullProgress)) error {
+	endpoint, err := url.JoinPath(NormalizeBaseURL(baseURL), "api", "pull")
+	if err != nil {
+		return fmt.Errorf("无效 BaseURL: %w", err)
+	}
+	jsonBody, err := json.Marshal(map[string]any{"model": name, "stream": true})
+	if err != nil {
+		return fmt.Errorf("请求构造失败: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(jsonBody))
+	if err != nil {
+		return fmt.Errorf("请求创建失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("连接失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var lastStatus string
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var progress PullProgress
+		if err := json.Unmarshal(line, &progress); err != nil {
+			continue
+		}
+		if progress.Error != "" {
+			return fmt.Errorf("拉取失败: %s", progress.Error)
+		}
+		lastStatus = progress.Status
+		if onProgress != nil {
+			onProgress(progress)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("读取拉取进度失败: %w", err)
+	}
+	if lastStatus != "success" {
+		return fmt.Errorf("拉取未完成，最后状态: %s", lastStatus)
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+package ollama
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/adk/structured"
+	"github.com/run-bigpig/jcp/internal/adk/thinkparser"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// toOllamaRequest 将 ADK LLMRequest 转换为 Ollama /api/chat 请求
+func toOllamaRequest(req *model.LLMRequest, modelName string, noSystemRole bool) (*ChatRequest, error) {
+	cr := &ChatRequest{Model: modelName}
+
+	msgs, err := toOllamaMessages(req.Contents)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Config != nil && req.Config.SystemInstruction != nil {
+		if systemText := extractTextFromContent(req.Config.SystemInstruction); systemText != "" {
+			role := "system"
+			if noSystemRole {
+				role = "user"
+			}
+			msgs = append([]Message{{Role: role, Content: systemText}}, msgs...)
+		}
+	}
+	cr.Messages = msgs
+
+	if req.Config == nil {
+		return cr, nil
+	}
+
+	if len(req.Config.Tools) > 0 {
+		tools, err := convertTools(req.Config.Tools)
+		if err != nil {
+			return nil, err
+		}
+		cr.Tools = tools
+	}
+
+	opts := &Options{}
+	if req.Config.Temperature != nil {
+		t := float64(*req.Config.Temperature)
+		opts.Temperature = &t
+	}
+	if req.Config.TopP != nil {
+		p := float64(*req.Config.TopP)
+		opts.TopP = &p
+	}
+	if req.Config.MaxOutputTokens > 0 {
+		opts.NumPredict = int(req.Config.MaxOutputTokens)
+	}
+	if len(req.Config.StopSequences) > 0 {
+		opts.Stop = req.Config.StopSequences
+	}
+	if opts.Temperature != nil || opts.TopP != nil || opts.NumPredict > 0 || len(opts.Stop) > 0 {
+		cr.Options = opts
+	}
+
+	// 结构化输出：有 schema 时约束为 schema，否则仅要求 JSON
+	if schema := structured.JSONSchema(req.Config); schema != nil {
+		cr.Format = schema
+	} else if req.Config.ResponseMIMEType == "application/json" {
+		cr.Format = "json"
+	}
+
+	// Ollama 仅支持开关思考，预算为 0 表示关闭
+	if tc := req.Config.ThinkingConfig; tc != nil {
+		think := tc.ThinkingBudget == nil || *tc.ThinkingBudget != 0
+		cr.Think = &think
+	}
+
+	return cr, nil
+}
+
+// extractTextFromContent 提取 genai.Content 中的纯文本
+func extractTextFromContent(content *genai.Content) string {
+	if content == nil {
+		return ""
+	}
+	var texts []string
+	for _, part := range content.Parts {
+		if part.Text != "" && !part.Thought {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// toOllamaMessages 将 genai.Content 列表转换为 Ollama messages
+// 函数响应按 Ollama 约定拆为独立的 tool 消息
+func toOllamaMessages(contents []*genai.Content) ([]Message, error) {
+	var msgs []Message
+
+	for _, content := range contents {
+		if content == nil {
+			continue
+		}
+
+		role := "user"
+		if content.Role == genai.RoleModel {
+			role = "assistant"
+		}
+
+		msg := Message{Role: role}
+		var texts []string
+		var toolMsgs []Message
+
+		for _, part := range content.Parts {
+			// 跳过 thought parts（不回传给模型）
+			if part.Thought {
+				continue
+			}
+			if part.Text != "" {
+				texts = append(texts, part.Text)
+			}
+			if part.InlineData != nil && strings.HasPrefix(part.InlineData.MIMEType, "image/") {
+				msg.Images = append(msg.Images, base64.StdEncoding.EncodeToString(part.InlineData.Data))
+			}
+			if part.FunctionCall != nil {
+				args := part.FunctionCall.Args
+				if args == nil {
+					args = map[string]any{}
+				}
+				msg.ToolCalls = append(msg.ToolCalls, ToolCall{
+					Function: ToolCallFunction{Name: part.FunctionCall.Name, Arguments: args},
+				})
+			}
+			if part.FunctionResponse != nil {
+				result, err := toToolResultContent(part.FunctionResponse.Response)
+				if err != nil {
+					return nil, fmt.Errorf("marshal function response: %w", err)
+				}
+				toolMsgs = append(toolMsgs, Message{
+					Role:     "tool",
+					Content:  result,
+					ToolName: part.FunctionResponse.Name,
+				})
+			}
+		}
+
+		msg.Content = strings.Join(texts, "\n")
+		if msg.Content != "" || len(msg.Images) > 0 || len(msg.ToolCalls) > 0 {
+			msgs = append(msgs, msg)
+		}
+		msgs = append(msgs, toolMsgs...)
+	}
+
+	return msgs, nil
+}
+
+// toToolResultContent 将函数返回值统一转换为字符串
+func toToolResultContent(resp any) (string, error) {
+	if s, ok := resp.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(resp)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// convertTools 将 genai.Tool 转换为 Ollama Tool
+func convertTools(genaiTools []*genai.Tool) ([]Tool, error) {
+	var tools []Tool
+	for _, gt := range genaiTools {
+		if gt == nil {
+			continue
+		}
+		for _, fd := range gt.FunctionDeclarations {
+			var schema any = fd.ParametersJsonSchema
+			if fd.ParametersJsonSchema == nil {
+				if fd.Parameters == nil {
+					return nil, fmt.Errorf("parameters is nil for tool %s", fd.Name)
+				}
+				schema = fd.Parameters
+			}
+			schemaJSON, err := json.Marshal(schema)
+			if err != nil {
+				return nil, fmt.Errorf("marshal tool schema: %w", err)
+			}
+			tools = append(tools, Tool{
+				Type: "function",
+				Function: ToolFunction{
+					Name:        fd.Name,
+					Description: fd.Description,
+					Parameters:  schemaJSON,
+				},
+			})
+		}
+	}
+	return tools, nil
+}
+
+// appendTextParts 追加文本，未开启 think 参数的模型会在正文中输出 <think> 标签
+func appendTextParts(parts []*genai.Part, thinking, text string) []*genai.Part {
+	if thinking != "" {
+		parts = append(parts, &genai.Part{Text: thinking, Thought: true})
+	}
+	for _, seg := range thinkparser.Split(text) {
+		parts = append(parts, &genai.Part{Text: seg.Text, Thought: seg.Thought})
+	}
+	return parts
+}
+
+// toFunctionCallParts 转换工具调用，Ollama 不返回调用 ID，按序号生成
+func toFunctionCallParts(calls []ToolCall, offset int) []*genai.Part {
+	parts := make([]*genai.Part, 0, len(calls))
+	for i, call := range calls {
+		args := call.Function.Arguments
+		if args == nil {
+			args = map[string]any{}
+		}
+		parts = append(parts, &genai.Part{
+			FunctionCall: &genai.FunctionCall{
+				ID:   fmt.Sprintf("call_%d", offset+i),
+				Name: call.Function.Name,
+				Args: args,
+			},
+		})
+	}
+	return parts
+}
+
+// convertChatResponse 将非流式响应转换为 ADK LLMResponse
+func convertChatResponse(resp *ChatResponse) *model.LLMResponse {
+	content := &genai.Content{Role: genai.RoleModel}
+	content.Parts = appendTextParts(content.Parts, resp.Message.Thinking, resp.Message.Content)
+	content.Parts = append(content.Parts, toFunctionCallParts(resp.Message.ToolCalls, 0)...)
+
+	return &model.LLMResponse{
+		Content:       content,
+		UsageMetadata: convertUsage(resp),
+		FinishReason:  convertDoneReason(resp.DoneReason),
+		TurnComplete:  true,
+	}
+}
+
+// convertUsage 转换 token 用量
+func convertUsage(resp *ChatResponse) *genai.GenerateContentResponseUsageMetadata {
+	if resp == nil || (resp.PromptEvalCount == 0 && resp.EvalCount == 0) {
+		return nil
+	}
+	return &genai.GenerateContentResponseUsageMetadata{
+		PromptTokenCount:     int32(resp.PromptEvalCount),
+		CandidatesTokenCount: int32(resp.EvalCount),
+		TotalTokenCount:      int32(resp.PromptEvalCount + resp.EvalCount),
+	}
+}
+
+// convertDoneReason 转换结束原因
+func convertDoneReason(reason string) genai.FinishReason {
+	switch reason {
+	case "stop", "":
+		return genai.FinishReasonStop
+	case "length":
+		return genai.FinishReasonMaxTokens
+	default:
+		return genai.FinishReasonUnspecified
+	}
+}
@@ -0,0 +1,224 @@
+package ollama
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/adk/thinkparser"
+	"github.com/run-bigpig/jcp/internal/logger"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+var modelLog = logger.New("ollama:model")
+
+// DefaultBaseURL Ollama 默认本地地址
+const DefaultBaseURL = "http://localhost:11434"
+
+// 确保实现 model.LLM 接口
+var _ model.LLM = &OllamaModel{}
+
+// OllamaModel Ollama 原生 /api/chat 模型
+type OllamaModel struct {
+	httpClient   *http.Client
+	baseURL      string
+	modelName    string
+	noSystemRole bool
+}
+
+// NormalizeBaseURL 规范化 Ollama BaseURL，兼容填写 /v1 或 /api 后缀的地址
+func NormalizeBaseURL(baseURL string) string {
+	baseURL = strings.TrimSpace(strings.TrimRight(baseURL, "/"))
+	if baseURL == "" {
+		return DefaultBaseURL
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/v1")
+	baseURL = strings.TrimSuffix(baseURL, "/api")
+	return baseURL
+}
+
+// NewOllamaModel 创建 Ollama 模型
+func NewOllamaModel(modelName, baseURL string, httpClient *http.Client, noSystemRole bool) *OllamaModel {
+	return &OllamaModel{
+		httpClient:   httpClient,
+		baseURL:      NormalizeBaseURL(baseURL),
+		modelName:    modelName,
+		noSystemRole: noSystemRole,
+	}
+}
+
+// Name 返回模型名称
+func (m *OllamaModel) Name() string {
+	return m.modelName
+}
+
+// GenerateContent 实现 model.LLM 接口
+func (m *OllamaModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		cr, err := toOllamaRequest(req, m.modelName, m.noSystemRole)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		cr.Stream = stream
+
+		resp, err := m.doRequest(ctx, cr)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer resp.Body.Close()
+
+		if stream {
+			processStream(resp.Body, yield)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 10*1024*1024))
+		if err != nil {
+			yield(nil, fmt.Errorf("read response: %w", err))
+			return
+		}
+		var chatResp ChatResponse
+		if err := json.Unmarshal(body, &chatResp); err != nil {
+			yield(nil, fmt.Errorf("unmarshal response: %w", err))
+			return
+		}
+		if chatResp.Error != "" {
+			yield(nil, fmt.Errorf("Ollama error: %s", chatResp.Error))
+			return
+		}
+		yield(convertChatResponse(&chatResp), nil)
+	}
+}
+
+// doRequest 发送 HTTP 请求到 Ollama /api/chat
+func (m *OllamaModel) doRequest(ctx context.Context, cr *ChatRequest) (*http.Response, error) {
+	jsonBody, err := json.Marshal(cr)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	endpoint, err := url.JoinPath(m.baseURL, "api", "chat")
+	if err != nil {
+		return nil, fmt.Errorf("build endpoint: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		resp.Body.Close()
+		modelLog.Error("API 响应异常: status=%d, body=%s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	return resp, nil
+}
+
+// processStream 处理 NDJSON 流：逐块输出文本/思考增量，结束时输出聚合响应
+func processStream(body io.Reader, yield func(*model.LLMResponse, error) bool) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 1024*1024), 1024*1024)
+
+	thinkParser := thinkparser.NewStreamParser()
+	var text, thinking strings.Builder
+	var toolCalls []ToolCall
+	var last ChatResponse
+
+	emitPartial := func(seg thinkparser.Segment) bool {
+		if seg.Text == "" {
+			return true
+		}
+		if seg.Thought {
+			thinking.WriteString(seg.Text)
+		} else {
+			text.WriteString(seg.Text)
+		}
+		return yield(&model.LLMResponse{
+			Content: &genai.Content{
+				Role:  genai.RoleModel,
+				Parts: []*genai.Part{{Text: seg.Text, Thought: seg.Thought}},
+			},
+			Partial: true,
+		}, nil)
+	}
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var chunk ChatResponse
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			modelLog.Warn("解析流式响应失败: %v", err)
+			continue
+		}
+		if chunk.Error != "" {
+			yield(nil, fmt.Errorf("Ollama error: %s", chunk.Error))
+			return
+		}
+
+		if !emitPartial(thinkparser.Segment{Text: chunk.Message.Thinking, Thought: true}) {
+			return
+		}
+		for _, seg := range thinkParser.Feed(chunk.Message.Content) {
+			if !emitPartial(seg) {
+				return
+			}
+		}
+		toolCalls = append(toolCalls, chunk.Message.ToolCalls...)
+
+		if chunk.Done {
+			last = chunk
+			break
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		if !errors.Is(err, context.Canceled) {
+			yield(nil, fmt.Errorf("流读取错误: %w", err))
+		}
+		return
+	}
+
+	for _, seg := range thinkParser.Flush() {
+		if !emitPartial(seg) {
+			return
+		}
+	}
+
+	aggregated := &genai.Content{Role: genai.RoleModel}
+	if thinking.Len() > 0 {
+		aggregated.Parts = append(aggregated.Parts, &genai.Part{Text: thinking.String(), Thought: true})
+	}
+	if text.Len() > 0 {
+		aggregated.Parts = append(aggregated.Parts, &genai.Part{Text: text.String()})
+	}
+	aggregated.Parts = append(aggregated.Parts, toFunctionCallParts(toolCalls, 0)...)
+
+	yield(&model.LLMResponse{
+		Content:       aggregated,
+		UsageMetadata: convertUsage(&last),
+		FinishReason:  convertDoneReason(last.DoneReason),
+		TurnComplete:  true,
+	}, nil)
+}
@@ -0,0 +1,135 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+func TestToOllamaRequest_ToolRoundTrip(t *testing.T) {
+	temp := float32(0.3)
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{
+			{Role: "user", Parts: []*genai.Part{{Text: "茅台现价？"}}},
+			{Role: "model", Parts: []*genai.Part{
+				{Text: "思考中", Thought: true},
+				{FunctionCall: &genai.FunctionCall{ID: "call_0", Name: "get_stock_realtime", Args: map[string]any{"code": "600519"}}},
+			}},
+			{Role: "user", Parts: []*genai.Part{
+				{FunctionResponse: &genai.FunctionResponse{ID: "call_0", Name: "get_stock_realtime", Response: map[string]any{"price": 1500}}},
+			}},
+		},
+		Config: &genai.GenerateContentConfig{
+			Temperature:       &temp,
+			MaxOutputTokens:   256,
+			SystemInstruction: &genai.Content{Parts: []*genai.Part{{Text: "你是分析师"}}},
+			Tools: []*genai.Tool{{FunctionDeclarations: []*genai.FunctionDeclaration{{
+				Name:                 "get_stock_realtime",
+				Description:          "行情",
+				ParametersJsonSchema: map[string]any{"type": "object"},
+			}}}},
+		},
+	}
+
+	cr, err := toOllamaRequest(req, "qwen3:8b", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cr.Messages) != 4 {
+		t.Fatalf("messages = %+v", cr.Messages)
+	}
+	if cr.Messages[0].Role != "system" || cr.Messages[0].Content != "你是分析师" {
+		t.Fatalf("system message = %+v", cr.Messages[0])
+	}
+	assistant := cr.Messages[2]
+	if assistant.Role != "assistant" || assistant.Content != "" || len(assistant.ToolCalls) != 1 || assistant.ToolCalls[0].Function.Arguments["code"] != "600519" {
+		t.Fatalf("assistant message = %+v", assistant)
+	}
+	tool := cr.Messages[3]
+	if tool.Role != "tool" || tool.ToolName != "get_stock_realtime" || tool.Content != `{"price":1500}` {
+		t.Fatalf("tool message = %+v", tool)
+	}
+	if cr.Options == nil || cr.Options.NumPredict != 256 || cr.Options.Temperature == nil {
+		t.Fatalf("options = %+v", cr.Options)
+	}
+	if len(cr.Tools) != 1 || cr.Tools[0].Type != "function" || string(cr.Tools[0].Function.Parameters) != `{"type":"object"}` {
+		t.Fatalf("tools = %+v", cr.Tools)
+	}
+
+	cr, _ = toOllamaRequest(req, "qwen3:8b", true)
+	if cr.Messages[0].Role != "user" {
+		t.Fatalf("noSystemRole should downgrade system to user, got %q", cr.Messages[0].Role)
+	}
+}
+
+func TestGenerateContent_Stream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var body ChatRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if !body.Stream || body.Model != "qwen3:8b" {
+			t.Errorf("unexpected request: %+v", body)
+		}
+		lines := []string{
+			`{"message":{"role":"assistant","content":"<thi"},"done":false}`,
+			`{"message":{"role":"assistant","content":"nk>想一想</think>结论"},"done":false}`,
+			`{"message":{"role":"assistant","content":"","tool_calls":[{"function":{"name":"get_kline_data","arguments":{"code":"600519"}}}]},"done":false}`,
+			`{"message":{"role":"assistant","content":""},"done":true,"done_reason":"stop","prompt_eval_count":10,"eval_count":5}`,
+		}
+		w.Write([]byte(strings.Join(lines, "\n") + "\n"))
+	}))
+	defer server.Close()
+
+	m := NewOllamaModel("qwen3:8b", server.URL+"/v1/", server.Client(), false)
+	req := &model.LLMRequest{Contents: []*genai.Content{{Role: "user", Parts: []*genai.Part{{Text: "hi"}}}}}
+
+	var partials []*genai.Part
+	var final *model.LLMResponse
+	for resp, err := range m.GenerateContent(context.Background(), req, true) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Partial {
+			partials = append(partials, resp.Content.Parts...)
+			continue
+		}
+		final = resp
+	}
+
+	if len(partials) != 2 || !partials[0].Thought || partials[0].Text != "想一想" || partials[1].Text != "结论" {
+		t.Fatalf("unexpected partials: %+v", partials)
+	}
+	if final == nil || len(final.Content.Parts) != 3 {
+		t.Fatalf("unexpected final: %+v", final)
+	}
+	call := final.Content.Parts[2].FunctionCall
+	if call == nil || call.Name != "get_kline_data" || call.ID != "call_0" || call.Args["code"] != "600519" {
+		t.Fatalf("unexpected function call: %+v", call)
+	}
+	if final.UsageMetadata == nil || final.UsageMetadata.TotalTokenCount != 15 {
+		t.Fatalf("unexpected usage: %+v", final.UsageMetadata)
+	}
+}
+
+func TestHasModel(t *testing.T) {
+	list := []ModelInfo{{Name: "qwen3:8b"}, {Name: "llama3.2:latest"}, {Name: "registry.local:5000/team/model:q4"}}
+	for name, want := range map[string]bool{
+		"qwen3:8b":                          true,
+		"qwen3":                             false,
+		"llama3.2":                          true,
+		"registry.local:5000/team/model:q4": true,
+		"registry.local:5000/team/model":    false,
+	} {
+		if got := HasModel(list, name); got != want {
+			t.Errorf("HasModel(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package ollama
+
+import "encoding/json"
+
+// ChatRequest Ollama /api/chat 请求
+type ChatRequest struct {
+	Model    string    `json:"model"`
+	Messages []Message `json:"messages"`
+	Tools    []Tool    `json:"tools,omitempty"`
+	Stream   bool      `json:"stream"`
+	Options  *Options  `json:"options,omitempty"`
+	Think    *bool     `json:"think,omitempty"`  // 思考模型开关，为空时使用模型默认行为
+	Format   any       `json:"format,omitempty"` // "json" 或 JSON Schema
+}
+
+// Options 采样参数
+type Options struct {
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
+	NumPredict  int      `json:"num_predict,omitempty"`
+	Stop        []string `json:"stop,omitempty"`
+}
+
+// Message 消息
+type Message struct {
+	Role      string     `json:"role"` // system / user / assistant / tool
+	Content   string     `json:"content"`
+	Thinking  string     `json:"thinking,omitempty"`
+	Images    []string   `json:"images,omitempty"` // base64 图片
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	ToolName  string     `json:"tool_name,omitempty"` // role=tool 时对应的函数名
+}
+
+// ToolCall 工具调用
+type ToolCall struct {
+	Function ToolCallFunction `json:"function"`
+}
+
+// ToolCallFunction 工具调用的函数与参数
+type ToolCallFunction struct {
+	Index     int            `json:"index,omitempty"`
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+// Tool 工具定义
+type Tool struct {
+	Type     string       `json:"type"` // function
+	Function ToolFunction `json:"function"`
+}
+
+// ToolFunction 函数声明
+type ToolFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters"`
+}
+
+// ChatResponse /api/chat 响应，流式时每行一个
+type ChatResponse struct {
+	Model           string  `json:"model"`
+	CreatedAt       string  `json:"created_at"`
+	Message         Message `json:"message"`
+	Done            bool    `json:"done"`
+	DoneReason      string  `json:"done_reason,omitempty"`
+	PromptEvalCount int     `json:"prompt_eval_count,omitempty"`
+	EvalCount       int     `json:"eval_count,omitempty"`
+	Error           string  `json:"error,omitempty"`
+}
+
+// ModelInfo 本地模型信息（/api/tags）
+type ModelInfo struct {
+	Name       string       `json:"name"`
+	Model      string       `json:"model"`
+	ModifiedAt string       `json:"modified_at"`
+	Size       int64        `json:"size"`
+	Digest     string       `json:"digest"`
+	Details    ModelDetails `json:"details"`
+}
+
+// ModelDetails 模型细节
+type ModelDetails struct {
+	Family            string `json:"family"`
+	ParameterSize     string `json:"parameter_size"`
+	QuantizationLevel string `json:"quantization_level"`
+}
+
+// PullProgress 模型拉取进度（/api/pull 流式返回）
+type PullProgress struct {
+	Status    string `json:"status"`
+	Digest    string `json:"digest,omitempty"`
+	Total     int64  `json:"total,omitempty"`
+	Completed int64  `json:"completed,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// PullStatus 模型拉取状态汇总，供前端轮询或事件推送
+type PullStatus struct {
+	Model     string  `json:"model"`
+	Status    string  `json:"status"`
+	Total     int64   `json:"total"`
+	Completed int64   `json:"completed"`
+	Percent   float64 `json:"percent"`
+	Done      bool    `json:"done"`
+	Error     string  `json:"error,omitempty"`
+}
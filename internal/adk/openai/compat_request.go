@@ -8,6 +8,13 @@ import (
 )
 
 func buildCompatRetryRequest(req goopenai.ChatCompletionRequest, err error) (goopenai.ChatCompletionRequest, bool) {
+	// 兼容接口不支持 json_schema 时降级为 json_object，由调用方本地校验
+	if isJSONSchemaUnsupported(req, err) {
+		req.ResponseFormat = &goopenai.ChatCompletionResponseFormat{
+			Type: goopenai.ChatCompletionResponseFormatTypeJSONObject,
+		}
+		return req, true
+	}
 	if !isCompatRetryableError(err) {
 		return req, false
 	}
@@ -42,6 +49,14 @@ func buildCompatRetryRequest(req goopenai.ChatCompletionRequest, err error) (goo
 	return req, changed
 }
 
+func isJSONSchemaUnsupported(req goopenai.ChatCompletionRequest, err error) bool {
+	if err == nil || req.ResponseFormat == nil || req.ResponseFormat.Type != goopenai.ChatCompletionResponseFormatTypeJSONSchema {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "response_format") || strings.Contains(msg, "json_schema")
+}
+
 func isCompatRetryableError(err error) bool {
 	if err == nil {
 		return false
@@ -1,15 +1,19 @@
 package openai
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/sashabaranov/go-openai"
 	"google.golang.org/adk/model"
 	"google.golang.org/genai"
 
+	"github.com/run-bigpig/jcp/internal/adk/structured"
+	"github.com/run-bigpig/jcp/internal/adk/thinkparser"
 	"github.com/run-bigpig/jcp/internal/logger"
 )
 
@@ -188,14 +192,7 @@ func toOpenAIChatCompletionRequest(req *model.LLMRequest, modelName string, noSy
 
 	// 处理 thinking 配置
 	if req.Config != nil && req.Config.ThinkingConfig != nil {
-		switch req.Config.ThinkingConfig.ThinkingLevel {
-		case genai.ThinkingLevelLow:
-			openaiReq.ReasoningEffort = "low"
-		case genai.ThinkingLevelHigh:
-			openaiReq.ReasoningEffort = "high"
-		default:
-			openaiReq.ReasoningEffort = "medium"
-		}
+		openaiReq.ReasoningEffort = reasoningEffort(req.Config.ThinkingConfig)
 	}
 
 	// 处理工具
@@ -235,7 +232,13 @@ func toOpenAIChatCompletionRequest(req *model.LLMRequest, modelName string, noSy
 				injected := false
 				for i, msg := range openaiMessages {
 					if msg.Role == openai.ChatMessageRoleUser {
-						openaiMessages[i].Content = systemText + "\n\n" + msg.Content
+						if len(msg.MultiContent) > 0 {
+							// 多模态消息不能同时设置 Content，作为首个文本片段插入
+							parts := []openai.ChatMessagePart{{Type: openai.ChatMessagePartTypeText, Text: systemText}}
+							openaiMessages[i].MultiContent = append(parts, msg.MultiContent...)
+						} else {
+							openaiMessages[i].Content = systemText + "\n\n" + msg.Content
+						}
 						injected = true
 						break
 					}
@@ -258,8 +261,21 @@ func toOpenAIChatCompletionRequest(req *model.LLMRequest, modelName string, noSy
 			openaiReq.Messages = openaiMessages
 		}
 
-		// 处理 JSON 模式
-		if req.Config.ResponseMIMEType == "application/json" {
+		// 处理 JSON 模式：有 schema 时使用 json_schema，否则降级为 json_object
+		if schema := structured.JSONSchema(req.Config); schema != nil {
+			raw, err := json.Marshal(schema)
+			if err != nil {
+				return openai.ChatCompletionRequest{}, fmt.Errorf("marshal response schema: %w", err)
+			}
+			openaiReq.ResponseFormat = &openai.ChatCompletionResponseFormat{
+				Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+				JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+					Name:   structured.OutputName,
+					Schema: json.RawMessage(raw),
+					Strict: structured.Strict(schema),
+				},
+			}
+		} else if req.Config.ResponseMIMEType == "application/json" {
 			openaiReq.ResponseFormat = &openai.ChatCompletionResponseFormat{
 				Type: openai.ChatCompletionResponseFormatTypeJSONObject,
 			}
@@ -269,6 +285,31 @@ func toOpenAIChatCompletionRequest(req *model.LLMRequest, modelName string, noSy
 	return openaiReq, nil
 }
 
+// reasoningEffort 将 ThinkingConfig 转换为 reasoning effort，未指定强度时按思考预算估算
+func reasoningEffort(tc *genai.ThinkingConfig) string {
+	switch tc.ThinkingLevel {
+	case genai.ThinkingLevelMinimal, genai.ThinkingLevelLow:
+		return "low"
+	case genai.ThinkingLevelMedium:
+		return "medium"
+	case genai.ThinkingLevelHigh:
+		return "high"
+	}
+	if tc.ThinkingBudget == nil || *tc.ThinkingBudget < 0 {
+		return "medium"
+	}
+	switch budget := *tc.ThinkingBudget; {
+	case budget == 0:
+		return "" // 关闭思考，交由模型默认行为
+	case budget <= 4096:
+		return "low"
+	case budget <= 12288:
+		return "medium"
+	default:
+		return "high"
+	}
+}
+
 // toOpenAIChatCompletionMessage 将 genai.Content 转换为 OpenAI 消息
 // 关键：处理 thinking 模型的 reasoning_content
 func toOpenAIChatCompletionMessage(content *genai.Content) ([]openai.ChatCompletionMessage, error) {
@@ -305,8 +346,18 @@ func toOpenAIChatCompletionMessage(content *genai.Content) ([]openai.ChatComplet
 	var textContent string
 	var reasoningContent string
 	var toolCalls []openai.ToolCall
+	var imageParts []openai.ChatMessagePart
 
 	for _, part := range parts {
+		// 处理图片（仅用户消息支持多模态内容）
+		if url := imageDataURL(part); url != "" && openaiMsg.Role == openai.ChatMessageRoleUser {
+			imageParts = append(imageParts, openai.ChatMessagePart{
+				Type:     openai.ChatMessagePartTypeImageURL,
+				ImageURL: &openai.ChatMessageImageURL{URL: url, Detail: openai.ImageURLDetailAuto},
+			})
+			continue
+		}
+
 		// 处理 thinking/reasoning 内容
 		if part.Thought && part.Text != "" {
 			reasoningContent += part.Text
@@ -336,8 +387,15 @@ func toOpenAIChatCompletionMessage(content *genai.Content) ([]openai.ChatComplet
 		}
 	}
 
-	// 设置消息内容
-	if textContent != "" {
+	// 设置消息内容，带图片时改用多段内容（Content 与 MultiContent 不能同时设置）
+	if len(imageParts) > 0 {
+		if textContent != "" {
+			openaiMsg.MultiContent = append(openaiMsg.MultiContent, openai.ChatMessagePart{
+				Type: openai.ChatMessagePartTypeText, Text: textContent,
+			})
+		}
+		openaiMsg.MultiContent = append(openaiMsg.MultiContent, imageParts...)
+	} else if textContent != "" {
 		openaiMsg.Content = textContent
 	}
 
@@ -353,6 +411,14 @@ func toOpenAIChatCompletionMessage(content *genai.Content) ([]openai.ChatComplet
 	return append(toolRespMessages, openaiMsg), nil
 }
 
+// imageDataURL 将内联图片转换为 data URL，非图片返回空串
+func imageDataURL(part *genai.Part) string {
+	if part.InlineData == nil || !strings.HasPrefix(part.InlineData.MIMEType, "image/") {
+		return ""
+	}
+	return "data:" + part.InlineData.MIMEType + ";base64," + base64.StdEncoding.EncodeToString(part.InlineData.Data)
+}
+
 // convertRoleToOpenAI 转换角色
 func convertRoleToOpenAI(role string) string {
 	switch role {
@@ -416,6 +482,10 @@ func convertTools(genaiTools []*genai.Tool) ([]openai.Tool, error) {
 		}
 	}
 
+	// 按名称排序，保证请求前缀稳定以命中自动前缀缓存
+	sort.SliceStable(openaiTools, func(i, j int) bool {
+		return openaiTools[i].Function.Name < openaiTools[j].Function.Name
+	})
 	return openaiTools, nil
 }
 
@@ -443,7 +513,7 @@ func convertChatCompletionResponse(resp *openai.ChatCompletionResponse) (*model.
 	if choice.Message.Content != "" {
 		vendorCalls, cleanedText := parseVendorToolCalls(choice.Message.Content)
 		// 解析 <think> 标签并映射到 Thought
-		for _, seg := range splitThinkTaggedText(cleanedText) {
+		for _, seg := range thinkparser.Split(cleanedText) {
 			content.Parts = append(content.Parts, &genai.Part{
 				Text:    seg.Text,
 				Thought: seg.Thought,
@@ -488,11 +558,7 @@ func convertChatCompletionResponse(resp *openai.ChatCompletionResponse) (*model.
 	// 处理 usage
 	var usageMetadata *genai.GenerateContentResponseUsageMetadata
 	if resp.Usage.TotalTokens > 0 {
-		usageMetadata = &genai.GenerateContentResponseUsageMetadata{
-			PromptTokenCount:     int32(resp.Usage.PromptTokens),
-			CandidatesTokenCount: int32(resp.Usage.CompletionTokens),
-			TotalTokenCount:      int32(resp.Usage.TotalTokens),
-		}
+		usageMetadata = convertChatUsage(&resp.Usage)
 	}
 
 	return &model.LLMResponse{
@@ -503,6 +569,19 @@ func convertChatCompletionResponse(resp *openai.ChatCompletionResponse) (*model.
 	}, nil
 }
 
+// convertChatUsage 转换 token 用量，缓存命中的提示 token 记入 CachedContentTokenCount
+func convertChatUsage(u *openai.Usage) *genai.GenerateContentResponseUsageMetadata {
+	meta := &genai.GenerateContentResponseUsageMetadata{
+		PromptTokenCount:     int32(u.PromptTokens),
+		CandidatesTokenCount: int32(u.CompletionTokens),
+		TotalTokenCount:      int32(u.TotalTokens),
+	}
+	if u.PromptTokensDetails != nil {
+		meta.CachedContentTokenCount = int32(u.PromptTokensDetails.CachedTokens)
+	}
+	return meta
+}
+
 // convertFinishReason 转换结束原因
 func convertFinishReason(reason string) genai.FinishReason {
 	switch reason {
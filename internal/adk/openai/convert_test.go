@@ -0,0 +1,58 @@
+package openai
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// visionRequest 带系统指令和 K 线图的请求
+func visionRequest() *model.LLMRequest {
+	return &model.LLMRequest{
+		Contents: []*genai.Content{{
+			Role: "user",
+			Parts: []*genai.Part{
+				{Text: "怎么看"},
+				{InlineData: &genai.Blob{MIMEType: "image/png", Data: []byte{0x89, 'P', 'N', 'G'}}},
+			},
+		}},
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: genai.NewContentFromText("你是技术分析师", "system"),
+		},
+	}
+}
+
+func TestToOpenAIChatCompletionRequest_NoSystemRoleWithImage(t *testing.T) {
+	req, err := toOpenAIChatCompletionRequest(visionRequest(), "gpt-4o", true, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := json.Marshal(req); err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	msg := req.Messages[0]
+	if msg.Content != "" || len(msg.MultiContent) != 3 {
+		t.Fatalf("message = %+v", msg)
+	}
+	if msg.MultiContent[0].Text != "你是技术分析师" || msg.MultiContent[1].Text != "怎么看" || msg.MultiContent[2].Type != openai.ChatMessagePartTypeImageURL {
+		t.Errorf("parts = %+v", msg.MultiContent)
+	}
+}
+
+func TestToResponsesRequest_NoSystemRoleWithImage(t *testing.T) {
+	req, err := toResponsesRequest(visionRequest(), "gpt-4o", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := req.Input.([]ResponsesInputItem)
+	parts, ok := items[0].Content.([]ResponsesInputContent)
+	if !ok || len(parts) != 3 {
+		t.Fatalf("content = %#v", items[0].Content)
+	}
+	if parts[0].Type != "input_text" || parts[0].Text != "你是技术分析师" || parts[1].Text != "怎么看" || parts[2].Type != "input_image" {
+		t.Errorf("parts = %+v", parts)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"google.golang.org/adk/model"
 	"google.golang.org/genai"
 
+	"github.com/run-bigpig/jcp/internal/adk/thinkparser"
 	"github.com/run-bigpig/jcp/internal/logger"
 )
 
@@ -128,9 +129,9 @@ func (o *OpenAIModel) processStream(stream *openai.ChatCompletionStream, yield f
 	toolCalls := newChatStreamToolCallAggregator()
 	var textContent string
 	var thoughtContent string
-	thinkParser := newThinkTagStreamParser()
+	thinkParser := thinkparser.NewStreamParser()
 
-	emitPartial := func(seg thinkSegment) bool {
+	emitPartial := func(seg thinkparser.Segment) bool {
 		if seg.Text == "" {
 			return true
 		}
@@ -171,7 +172,7 @@ func (o *OpenAIModel) processStream(stream *openai.ChatCompletionStream, yield f
 
 		// 官方 reasoning_content -> Thought
 		if choice.Delta.ReasoningContent != "" {
-			if !emitPartial(thinkSegment{
+			if !emitPartial(thinkparser.Segment{
 				Text:    choice.Delta.ReasoningContent,
 				Thought: true,
 			}) {
@@ -196,11 +197,7 @@ func (o *OpenAIModel) processStream(stream *openai.ChatCompletionStream, yield f
 		}
 
 		if chunk.Usage != nil {
-			usageMetadata = &genai.GenerateContentResponseUsageMetadata{
-				PromptTokenCount:     int32(chunk.Usage.PromptTokens),
-				CandidatesTokenCount: int32(chunk.Usage.CompletionTokens),
-				TotalTokenCount:      int32(chunk.Usage.TotalTokens),
-			}
+			usageMetadata = convertChatUsage(chunk.Usage)
 		}
 	}
 
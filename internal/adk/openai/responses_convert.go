@@ -3,9 +3,13 @@ package openai
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	"google.golang.org/adk/model"
 	"google.golang.org/genai"
+
+	"github.com/run-bigpig/jcp/internal/adk/structured"
+	"github.com/run-bigpig/jcp/internal/adk/thinkparser"
 )
 
 // toResponsesRequest 将 ADK 请求转换为 Responses API 请求
@@ -33,9 +37,14 @@ func toResponsesRequest(req *model.LLMRequest, modelName string, noSystemRole bo
 			injected := false
 			for i, item := range inputItems {
 				if item.Role == "user" {
-					if s, ok := item.Content.(string); ok {
-						inputItems[i].Content = systemText + "\n\n" + s
-					} else {
+					switch c := item.Content.(type) {
+					case string:
+						inputItems[i].Content = systemText + "\n\n" + c
+					case []ResponsesInputContent:
+						// 多模态输入保留原有文本与图片，系统指令作为首个文本片段
+						parts := []ResponsesInputContent{{Type: "input_text", Text: systemText}}
+						inputItems[i].Content = append(parts, c...)
+					default:
 						inputItems[i].Content = systemText
 					}
 					injected = true
@@ -56,16 +65,9 @@ func toResponsesRequest(req *model.LLMRequest, modelName string, noSystemRole bo
 
 	// 处理 thinking/reasoning 配置
 	if req.Config.ThinkingConfig != nil {
-		reasoning := &ResponsesReasoning{}
-		switch req.Config.ThinkingConfig.ThinkingLevel {
-		case genai.ThinkingLevelLow:
-			reasoning.Effort = "low"
-		case genai.ThinkingLevelHigh:
-			reasoning.Effort = "high"
-		default:
-			reasoning.Effort = "medium"
+		if effort := reasoningEffort(req.Config.ThinkingConfig); effort != "" {
+			apiReq.Reasoning = &ResponsesReasoning{Effort: effort}
 		}
-		apiReq.Reasoning = reasoning
 	}
 
 	// 转换工具定义
@@ -92,6 +94,18 @@ func toResponsesRequest(req *model.LLMRequest, modelName string, noSystemRole bo
 		apiReq.Stop = req.Config.StopSequences
 	}
 
+	// 处理 JSON 模式：有 schema 时使用 json_schema，否则 json_object
+	if schema := structured.JSONSchema(req.Config); schema != nil {
+		apiReq.Text = &ResponsesText{Format: ResponsesTextFormat{
+			Type:   "json_schema",
+			Name:   structured.OutputName,
+			Schema: schema,
+			Strict: structured.Strict(schema),
+		}}
+	} else if req.Config.ResponseMIMEType == "application/json" {
+		apiReq.Text = &ResponsesText{Format: ResponsesTextFormat{Type: "json_object"}}
+	}
+
 	return apiReq, nil
 }
 
@@ -131,12 +145,17 @@ func toResponsesInputItem(content *genai.Content) ([]ResponsesInputItem, error)
 
 	// 收集文本、reasoning、函数调用
 	var textContent string
+	var imageURLs []string
 	var toolCallItems []ResponsesInputItem
 
 	for _, part := range content.Parts {
 		if part.FunctionResponse != nil {
 			continue // 已处理
 		}
+		if url := imageDataURL(part); url != "" && content.Role != genai.RoleModel {
+			imageURLs = append(imageURLs, url)
+			continue
+		}
 		if part.Text != "" && !part.Thought {
 			textContent += part.Text
 		}
@@ -156,7 +175,20 @@ func toResponsesInputItem(content *genai.Content) ([]ResponsesInputItem, error)
 
 	// 构建普通消息
 	role := convertRoleForResponses(content.Role)
-	if textContent != "" {
+	if len(imageURLs) > 0 {
+		// 带图片时使用多段内容
+		var parts []ResponsesInputContent
+		if textContent != "" {
+			parts = append(parts, ResponsesInputContent{Type: "input_text", Text: textContent})
+		}
+		for _, url := range imageURLs {
+			parts = append(parts, ResponsesInputContent{Type: "input_image", ImageURL: url})
+		}
+		items = append(items, ResponsesInputItem{
+			Role:    role,
+			Content: parts,
+		})
+	} else if textContent != "" {
 		items = append(items, ResponsesInputItem{
 			Role:    role,
 			Content: textContent,
@@ -207,6 +239,8 @@ func convertResponsesTools(genaiTools []*genai.Tool) ([]ResponsesTool, error) {
 			})
 		}
 	}
+	// 按名称排序，保证请求前缀稳定以命中自动前缀缓存
+	sort.SliceStable(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
 	return tools, nil
 }
 
@@ -317,7 +351,7 @@ func convertResponsesResponse(resp *CreateResponseResponse) (*model.LLMResponse,
 				case "output_text":
 					// 解析第三方特殊工具调用标记
 					vendorCalls, cleanedText := parseVendorToolCalls(part.Text)
-					for _, seg := range splitThinkTaggedText(cleanedText) {
+					for _, seg := range thinkparser.Split(cleanedText) {
 						content.Parts = append(content.Parts, &genai.Part{
 							Text:    seg.Text,
 							Thought: seg.Thought,
@@ -351,19 +385,26 @@ func convertResponsesResponse(resp *CreateResponseResponse) (*model.LLMResponse,
 	}
 
 	// 处理 usage
-	var usageMetadata *genai.GenerateContentResponseUsageMetadata
-	if resp.Usage != nil {
-		usageMetadata = &genai.GenerateContentResponseUsageMetadata{
-			PromptTokenCount:     int32(resp.Usage.InputTokens),
-			CandidatesTokenCount: int32(resp.Usage.OutputTokens),
-			TotalTokenCount:      int32(resp.Usage.TotalTokens),
-		}
-	}
-
 	return &model.LLMResponse{
 		Content:       content,
-		UsageMetadata: usageMetadata,
+		UsageMetadata: convertResponsesUsage(resp.Usage),
 		FinishReason:  genai.FinishReasonStop,
 		TurnComplete:  true,
 	}, nil
 }
+
+// convertResponsesUsage 转换 token 用量，缓存命中的输入 token 记入 CachedContentTokenCount
+func convertResponsesUsage(u *ResponsesUsage) *genai.GenerateContentResponseUsageMetadata {
+	if u == nil {
+		return nil
+	}
+	meta := &genai.GenerateContentResponseUsageMetadata{
+		PromptTokenCount:     int32(u.InputTokens),
+		CandidatesTokenCount: int32(u.OutputTokens),
+		TotalTokenCount:      int32(u.TotalTokens),
+	}
+	if u.InputTokensDetails != nil {
+		meta.CachedContentTokenCount = int32(u.InputTokensDetails.CachedTokens)
+	}
+	return meta
+}
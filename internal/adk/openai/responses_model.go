@@ -14,6 +14,7 @@ import (
 	"google.golang.org/adk/model"
 	"google.golang.org/genai"
 
+	"github.com/run-bigpig/jcp/internal/adk/thinkparser"
 	"github.com/run-bigpig/jcp/internal/logger"
 )
 
@@ -176,7 +177,7 @@ func (r *ResponsesModel) processResponsesStream(body io.Reader, yield func(*mode
 	var toolCallOrder []string
 	var usageMetadata *genai.GenerateContentResponseUsageMetadata
 	var currentEventType string
-	thinkParser := newThinkTagStreamParser()
+	thinkParser := thinkparser.NewStreamParser()
 
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -276,7 +277,7 @@ type responsesToolCallBuilder struct {
 // handleTextDelta 处理文本增量事件
 func (r *ResponsesModel) handleTextDelta(
 	data string,
-	thinkParser *thinkTagStreamParser,
+	thinkParser *thinkparser.StreamParser,
 	textContent *string,
 	thoughtContent *string,
 	yield func(*model.LLMResponse, error) bool,
@@ -290,7 +291,7 @@ func (r *ResponsesModel) handleTextDelta(
 }
 
 func (r *ResponsesModel) emitTextSegments(
-	segments []thinkSegment,
+	segments []thinkparser.Segment,
 	textContent *string,
 	thoughtContent *string,
 	yield func(*model.LLMResponse, error) bool,
@@ -380,10 +381,6 @@ func (r *ResponsesModel) handleCompleted(data string, usageMetadata **genai.Gene
 		return
 	}
 	if completed.Response.Usage != nil {
-		*usageMetadata = &genai.GenerateContentResponseUsageMetadata{
-			PromptTokenCount:     int32(completed.Response.Usage.InputTokens),
-			CandidatesTokenCount: int32(completed.Response.Usage.OutputTokens),
-			TotalTokenCount:      int32(completed.Response.Usage.TotalTokens),
-		}
+		*usageMetadata = convertResponsesUsage(completed.Response.Usage)
 	}
 }
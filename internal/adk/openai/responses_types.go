@@ -15,6 +15,20 @@ type CreateResponseRequest struct {
 	Stop               []string            `json:"stop,omitempty"`
 	Reasoning          *ResponsesReasoning `json:"reasoning,omitempty"`
 	PreviousResponseID string              `json:"previous_response_id,omitempty"` // 多轮对话关联
+	Text               *ResponsesText      `json:"text,omitempty"`
+}
+
+// ResponsesText 文本输出配置
+type ResponsesText struct {
+	Format ResponsesTextFormat `json:"format"`
+}
+
+// ResponsesTextFormat 输出格式：text / json_object / json_schema
+type ResponsesTextFormat struct {
+	Type   string         `json:"type"`
+	Name   string         `json:"name,omitempty"`
+	Schema map[string]any `json:"schema,omitempty"`
+	Strict bool           `json:"strict,omitempty"`
 }
 
 // ResponsesInputItem input 数组中的一条消息
@@ -31,6 +45,13 @@ type ResponsesInputItem struct {
 	Arguments string `json:"arguments,omitempty"`
 }
 
+// ResponsesInputContent 多段 input 内容（input_text / input_image）
+type ResponsesInputContent struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	ImageURL string `json:"image_url,omitempty"` // data URL 或远程地址
+}
+
 // ResponsesTool Responses API 工具定义（扁平化，name 在顶层）
 type ResponsesTool struct {
 	Type        string `json:"type"`                  // "function"
@@ -82,9 +103,15 @@ type ResponsesContentPart struct {
 
 // ResponsesUsage 用量信息
 type ResponsesUsage struct {
-	InputTokens  int `json:"input_tokens"`
-	OutputTokens int `json:"output_tokens"`
-	TotalTokens  int `json:"total_tokens"`
+	InputTokens        int                    `json:"input_tokens"`
+	OutputTokens       int                    `json:"output_tokens"`
+	TotalTokens        int                    `json:"total_tokens"`
+	InputTokensDetails *ResponsesInputDetails `json:"input_tokens_details,omitempty"`
+}
+
+// ResponsesInputDetails 输入用量明细
+type ResponsesInputDetails struct {
+	CachedTokens int `json:"cached_tokens"` // 命中前缀缓存的 token 数
 }
 
 // ===== 流式 SSE 事件类型 =====
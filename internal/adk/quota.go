@@ -0,0 +1,338 @@
+package adk
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// quotaWindow 请求数与 token 数的统计窗口
+const quotaWindow = time.Minute
+
+// 配额等待原因
+const (
+	QuotaReasonConcurrency = "concurrency"
+	QuotaReasonRPM         = "rpm"
+	QuotaReasonTPM         = "tpm"
+)
+
+// QuotaEvent 配额排队事件，Waiting=false 表示已获得配额
+type QuotaEvent struct {
+	ConfigID   string `json:"configId"`
+	ConfigName string `json:"configName"`
+	Waiting    bool   `json:"waiting"`
+	Reason     string `json:"reason"`
+	WaitMs     int64  `json:"waitMs"` // 预计等待时长，并发排队时为 0
+}
+
+type quotaNotifierKey struct{}
+
+// WithQuotaNotifier 在 ctx 上挂载配额排队通知，模型调用排队时回调
+func WithQuotaNotifier(ctx context.Context, notify func(QuotaEvent)) context.Context {
+	return context.WithValue(ctx, quotaNotifierKey{}, notify)
+}
+
+func quotaNotifierFrom(ctx context.Context) func(QuotaEvent) {
+	if notify, ok := ctx.Value(quotaNotifierKey{}).(func(QuotaEvent)); ok && notify != nil {
+		return notify
+	}
+	return func(QuotaEvent) {}
+}
+
+// quotaLimits 单个 AI 配置的客户端限流参数
+type quotaLimits struct {
+	id          string
+	name        string
+	concurrency int
+	rpm         int
+	tpm         int
+}
+
+func limitsOf(config *models.AIConfig) quotaLimits {
+	return quotaLimits{
+		id:          config.ID,
+		name:        config.Name,
+		concurrency: config.MaxConcurrency,
+		rpm:         config.RequestsPerMinute,
+		tpm:         config.TokensPerMinute,
+	}
+}
+
+func (l quotaLimits) enabled() bool {
+	return l.concurrency > 0 || l.rpm > 0 || l.tpm > 0
+}
+
+// tokenUse 窗口内的一次 token 占用，请求结束后按实际用量修正
+type tokenUse struct {
+	at time.Time
+	n  int
+}
+
+// quotaBucket 单个配置的并发槽位与滑动窗口
+type quotaBucket struct {
+	slots chan struct{} // nil 表示不限并发
+
+	mu       sync.Mutex
+	rpm      int
+	tpm      int
+	requests []time.Time
+	tokens   []*tokenUse
+}
+
+// reserve 尝试占用一次请求额度，失败时返回需等待的时长与原因
+func (b *quotaBucket) reserve(now time.Time, est int) (*tokenUse, time.Duration, string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cutoff := now.Add(-quotaWindow)
+	for len(b.requests) > 0 && !b.requests[0].After(cutoff) {
+		b.requests = b.requests[1:]
+	}
+	for len(b.tokens) > 0 && !b.tokens[0].at.After(cutoff) {
+		b.tokens = b.tokens[1:]
+	}
+
+	if b.rpm > 0 && len(b.requests) >= b.rpm {
+		return nil, b.requests[len(b.requests)-b.rpm].Add(quotaWindow).Sub(now), QuotaReasonRPM
+	}
+	if b.tpm > 0 {
+		used := 0
+		for _, u := range b.tokens {
+			used += u.n
+		}
+		// 窗口为空时总是放行，避免单个超大请求永久阻塞
+		if used > 0 && used+est > b.tpm {
+			need, freed := used+est-b.tpm, 0
+			for i, u := range b.tokens {
+				freed += u.n
+				if freed >= need || i == len(b.tokens)-1 {
+					return nil, u.at.Add(quotaWindow).Sub(now), QuotaReasonTPM
+				}
+			}
+		}
+	}
+
+	b.requests = append(b.requests, now)
+	use := &tokenUse{at: now, n: est}
+	if b.tpm > 0 {
+		b.tokens = append(b.tokens, use)
+	}
+	return use, 0, ""
+}
+
+func (b *quotaBucket) settle(use *tokenUse, actual int) {
+	if use == nil || actual <= 0 {
+		return
+	}
+	b.mu.Lock()
+	use.n = actual
+	b.mu.Unlock()
+}
+
+// quotaLease 一次已获得的配额，调用结束后释放
+type quotaLease struct {
+	bucket *quotaBucket
+	use    *tokenUse
+	slot   bool
+	done   bool
+}
+
+// release 释放并发槽位，并用实际 token 用量修正窗口统计
+func (l *quotaLease) release(actualTokens int) {
+	if l == nil || l.done {
+		return
+	}
+	l.done = true
+	l.bucket.settle(l.use, actualTokens)
+	if l.slot {
+		<-l.bucket.slots
+	}
+}
+
+// quotaLimiter 按 AI 配置 ID 共享的客户端限流器
+type quotaLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*quotaBucket
+}
+
+var sharedQuota = newQuotaLimiter()
+
+func newQuotaLimiter() *quotaLimiter {
+	return &quotaLimiter{buckets: make(map[string]*quotaBucket)}
+}
+
+// bucket 获取配置对应的桶，并发上限变化时重建槽位，已持有的租约仍归还到旧槽位
+func (q *quotaLimiter) bucket(lim quotaLimits) *quotaBucket {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	b, ok := q.buckets[lim.id]
+	if !ok || cap(b.slots) != lim.concurrency {
+		nb := &quotaBucket{}
+		if lim.concurrency > 0 {
+			nb.slots = make(chan struct{}, lim.concurrency)
+		}
+		if ok {
+			nb.requests, nb.tokens = b.requests, b.tokens
+		}
+		b = nb
+		q.buckets[lim.id] = b
+	}
+	b.mu.Lock()
+	b.rpm, b.tpm = lim.rpm, lim.tpm
+	b.mu.Unlock()
+	return b
+}
+
+// tryAcquire 非阻塞获取配额，任一限制不满足时返回 nil
+func (q *quotaLimiter) tryAcquire(lim quotaLimits, est int) *quotaLease {
+	b := q.bucket(lim)
+	lease := &quotaLease{bucket: b}
+	if b.slots != nil {
+		select {
+		case b.slots <- struct{}{}:
+			lease.slot = true
+		default:
+			return nil
+		}
+	}
+	use, _, _ := b.reserve(time.Now(), est)
+	if use == nil {
+		lease.release(0)
+		return nil
+	}
+	lease.use = use
+	return lease
+}
+
+// acquire 阻塞获取配额，排队期间通过 ctx 上的通知回调上报等待原因
+func (q *quotaLimiter) acquire(ctx context.Context, lim quotaLimits, est int) (*quotaLease, error) {
+	b := q.bucket(lim)
+	lease := &quotaLease{bucket: b}
+	notify := quotaNotifierFrom(ctx)
+	lastReason := ""
+	announce := func(reason string, wait time.Duration) {
+		if reason == lastReason {
+			return
+		}
+		lastReason = reason
+		log.Info("配置 [%s] 等待配额（%s），预计 %v", lim.name, reason, wait.Round(time.Second))
+		notify(QuotaEvent{ConfigID: lim.id, ConfigName: lim.name, Waiting: true, Reason: reason, WaitMs: wait.Milliseconds()})
+	}
+
+	if b.slots != nil {
+		select {
+		case b.slots <- struct{}{}:
+		default:
+			announce(QuotaReasonConcurrency, 0)
+			select {
+			case b.slots <- struct{}{}:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		lease.slot = true
+	}
+
+	for {
+		use, wait, reason := b.reserve(time.Now(), est)
+		if use != nil {
+			lease.use = use
+			break
+		}
+		if wait < 10*time.Millisecond {
+			wait = 10 * time.Millisecond
+		}
+		announce(reason, wait)
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			lease.release(0)
+			return nil, ctx.Err()
+		}
+	}
+
+	if lastReason != "" {
+		notify(QuotaEvent{ConfigID: lim.id, ConfigName: lim.name, Waiting: false, Reason: lastReason})
+	}
+	return lease, nil
+}
+
+// estimateRequestTokens 粗略估算请求的输入 token 数（约两个字符一个 token）
+func estimateRequestTokens(req *model.LLMRequest) int {
+	if req == nil {
+		return 0
+	}
+	chars := 0
+	count := func(parts []*genai.Part) {
+		for _, p := range parts {
+			if p == nil {
+				continue
+			}
+			chars += utf8.RuneCountInString(p.Text)
+			if p.FunctionCall != nil {
+				if data, err := json.Marshal(p.FunctionCall.Args); err == nil {
+					chars += utf8.RuneCount(data)
+				}
+			}
+			if p.FunctionResponse != nil {
+				if data, err := json.Marshal(p.FunctionResponse.Response); err == nil {
+					chars += utf8.RuneCount(data)
+				}
+			}
+		}
+	}
+	for _, c := range req.Contents {
+		if c != nil {
+			count(c.Parts)
+		}
+	}
+	if req.Config != nil && req.Config.SystemInstruction != nil {
+		count(req.Config.SystemInstruction.Parts)
+	}
+	return chars/2 + 1
+}
+
+// responseTokens 返回响应中的 token 总用量，没有用量信息时返回 0
+func responseTokens(resp *model.LLMResponse) int {
+	if resp == nil || resp.UsageMetadata == nil {
+		return 0
+	}
+	return int(resp.UsageMetadata.TotalTokenCount)
+}
+
+// quotaModel 为单个配置施加并发、RPM、TPM 限制的模型包装
+type quotaModel struct {
+	model.LLM
+	limits quotaLimits
+	quota  *quotaLimiter
+}
+
+// GenerateContent 获取配额后调用底层模型，迭代结束时释放并按实际用量结算
+func (m *quotaModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		lease, err := m.quota.acquire(ctx, m.limits, estimateRequestTokens(req))
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		used := 0
+		defer func() { lease.release(used) }()
+		for resp, err := range m.LLM.GenerateContent(ctx, req, stream) {
+			if n := responseTokens(resp); n > used {
+				used = n
+			}
+			if !yield(resp, err) || err != nil {
+				return
+			}
+		}
+	}
+}
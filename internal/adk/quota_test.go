@@ -0,0 +1,87 @@
+package adk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQuotaBucket_RPM(t *testing.T) {
+	b := &quotaBucket{rpm: 2}
+	now := time.Now()
+	if use, _, _ := b.reserve(now, 0); use == nil {
+		t.Fatal("first request should pass")
+	}
+	if use, _, _ := b.reserve(now.Add(10*time.Second), 0); use == nil {
+		t.Fatal("second request should pass")
+	}
+	use, wait, reason := b.reserve(now.Add(20*time.Second), 0)
+	if use != nil || reason != QuotaReasonRPM || wait != 40*time.Second {
+		t.Fatalf("third request: use=%v wait=%v reason=%q", use, wait, reason)
+	}
+	if use, _, _ := b.reserve(now.Add(61*time.Second), 0); use == nil {
+		t.Fatal("request should pass after the window slides")
+	}
+}
+
+func TestQuotaBucket_TPM(t *testing.T) {
+	b := &quotaBucket{tpm: 1000}
+	now := time.Now()
+	first, _, _ := b.reserve(now, 300)
+	if first == nil {
+		t.Fatal("first request should pass")
+	}
+	// 实际用量高于估算，按实际结算
+	b.settle(first, 900)
+	if use, wait, reason := b.reserve(now.Add(5*time.Second), 300); use != nil || reason != QuotaReasonTPM || wait != 55*time.Second {
+		t.Fatalf("over budget: use=%v wait=%v reason=%q", use, wait, reason)
+	}
+	if use, _, _ := b.reserve(now.Add(5*time.Second), 100); use == nil {
+		t.Fatal("request within remaining budget should pass")
+	}
+
+	// 窗口为空时，超过 TPM 的单个请求也放行
+	empty := &quotaBucket{tpm: 100}
+	if use, _, _ := empty.reserve(now, 500); use == nil {
+		t.Fatal("oversized request should pass on an empty window")
+	}
+}
+
+func TestQuotaLimiter_AcquireNotifies(t *testing.T) {
+	q := newQuotaLimiter()
+	lim := quotaLimits{id: "kimi", name: "Kimi", concurrency: 1}
+	held := q.tryAcquire(lim, 0)
+	if held == nil {
+		t.Fatal("slot should be free")
+	}
+	if q.tryAcquire(lim, 0) != nil {
+		t.Fatal("second tryAcquire should fail")
+	}
+
+	events := make(chan QuotaEvent, 4)
+	ctx := WithQuotaNotifier(context.Background(), func(e QuotaEvent) { events <- e })
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		held.release(0)
+	}()
+	lease, err := q.acquire(ctx, lim, 0)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	lease.release(0)
+
+	wait, ready := <-events, <-events
+	if !wait.Waiting || wait.Reason != QuotaReasonConcurrency || wait.ConfigName != "Kimi" || ready.Waiting {
+		t.Fatalf("unexpected events: %+v %+v", wait, ready)
+	}
+
+	// 排队期间取消
+	held = q.tryAcquire(lim, 0)
+	defer held.release(0)
+	cctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := q.acquire(cctx, lim, 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error, got %v", err)
+	}
+}
@@ -0,0 +1,286 @@
+package adk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	go_openai "github.com/sashabaranov/go-openai"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+const (
+	// routeCooldown 成员被限流后的冷却时间，冷却期内排到队尾
+	routeCooldown = 30 * time.Second
+	// routeLatencyAlpha 首包延迟 EWMA 平滑系数
+	routeLatencyAlpha = 0.3
+)
+
+var (
+	aiConfigLookupMu sync.RWMutex
+	aiConfigLookup   func(id string) *models.AIConfig
+)
+
+// SetAIConfigLookup 注册按 ID 查找 AI 配置的函数，路由据此解析成员配置
+func SetAIConfigLookup(lookup func(id string) *models.AIConfig) {
+	aiConfigLookupMu.Lock()
+	defer aiConfigLookupMu.Unlock()
+	aiConfigLookup = lookup
+}
+
+func lookupAIConfig(id string) *models.AIConfig {
+	aiConfigLookupMu.RLock()
+	defer aiConfigLookupMu.RUnlock()
+	if aiConfigLookup == nil {
+		return nil
+	}
+	return aiConfigLookup(id)
+}
+
+// routeState 跨模型实例共享的运行时状态：首包延迟、限流冷却
+type routeState struct {
+	mu        sync.Mutex
+	latency   map[string]time.Duration
+	coolUntil map[string]time.Time
+}
+
+var sharedRouteState = newRouteState()
+
+func newRouteState() *routeState {
+	return &routeState{
+		latency:   make(map[string]time.Duration),
+		coolUntil: make(map[string]time.Time),
+	}
+}
+
+func (s *routeState) observeLatency(id string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if prev, ok := s.latency[id]; ok {
+		d = time.Duration(float64(prev)*(1-routeLatencyAlpha) + float64(d)*routeLatencyAlpha)
+	}
+	s.latency[id] = d
+}
+
+func (s *routeState) coolDown(id string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.coolUntil[id] = time.Now().Add(d)
+}
+
+// order 按策略排列成员：延迟策略按 EWMA 升序（无记录的优先试探），冷却中的排到队尾
+func (s *routeState) order(members []*routeMember, strategy string) []*routeMember {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	ordered := append([]*routeMember(nil), members...)
+	if strategy == models.RouteStrategyLatency {
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return s.latency[ordered[i].id] < s.latency[ordered[j].id]
+		})
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return !now.Before(s.coolUntil[ordered[i].id]) && now.Before(s.coolUntil[ordered[j].id])
+	})
+	return ordered
+}
+
+// routeMember 路由成员
+type routeMember struct {
+	id     string
+	name   string
+	limits quotaLimits
+	llm    model.LLM
+}
+
+// RouterModel 在多个 AI 配置间按错误类别自动回退的模型
+type RouterModel struct {
+	name       string
+	strategy   string
+	fallbackOn map[string]bool
+	members    []*routeMember
+	state      *routeState
+	quota      *quotaLimiter
+}
+
+var _ model.LLM = &RouterModel{}
+
+// newRouterModel 创建路由模型，fallbackOn 为空时所有可回退类别均触发回退
+func newRouterModel(name, strategy string, fallbackOn []string, members []*routeMember, state *routeState, quota *quotaLimiter) *RouterModel {
+	classes := make(map[string]bool)
+	for _, c := range fallbackOn {
+		classes[c] = true
+	}
+	if len(classes) == 0 {
+		for _, c := range []string{models.RouteErrRateLimit, models.RouteErrServer, models.RouteErrTimeout, models.RouteErrContextLength} {
+			classes[c] = true
+		}
+	}
+	return &RouterModel{name: name, strategy: strategy, fallbackOn: classes, members: members, state: state, quota: quota}
+}
+
+// Name 返回路由名称
+func (r *RouterModel) Name() string {
+	return "router:" + r.name
+}
+
+// GenerateContent 依次尝试成员：配额不足的成员延后尝试；尚未输出内容且错误类别可回退时切换下一个
+func (r *RouterModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		ordered := r.state.order(r.members, r.strategy)
+		est := estimateRequestTokens(req)
+		var deferred []*routeMember
+		var lastErr error
+
+		// attempt 返回 true 表示已结束（成功、不可回退的失败或调用方停止）
+		attempt := func(m *routeMember, lease *quotaLease, last bool) bool {
+			used := 0
+			defer func() { lease.release(used) }()
+			started := time.Now()
+			emitted := false
+			var failErr error
+			for resp, err := range m.llm.GenerateContent(ctx, req, stream) {
+				if err != nil {
+					failErr = err
+					break
+				}
+				if n := responseTokens(resp); n > used {
+					used = n
+				}
+				if !emitted {
+					r.state.observeLatency(m.id, time.Since(started))
+					emitted = true
+				}
+				if !yield(resp, nil) {
+					return true
+				}
+			}
+			if failErr == nil {
+				return true
+			}
+
+			class := classifyLLMError(failErr)
+			if class == models.RouteErrRateLimit {
+				r.state.coolDown(m.id, routeCooldown)
+			}
+			if emitted || ctx.Err() != nil || !r.fallbackOn[class] || last {
+				yield(nil, failErr)
+				return true
+			}
+			log.Warn("路由 [%s] 成员 [%s] 失败（%s），切换下一个: %v", r.name, m.name, class, failErr)
+			lastErr = failErr
+			return false
+		}
+
+		for i, m := range ordered {
+			lease := r.quota.tryAcquire(m.limits, est)
+			if lease == nil {
+				deferred = append(deferred, m)
+				continue
+			}
+			last := i == len(ordered)-1 && len(deferred) == 0
+			if attempt(m, lease, last) {
+				return
+			}
+		}
+
+		// 有配额的成员均已失败或没有可用配额，排队等待其余成员
+		for i, m := range deferred {
+			lease, err := r.quota.acquire(ctx, m.limits, est)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if attempt(m, lease, i == len(deferred)-1) {
+				return
+			}
+		}
+
+		if lastErr == nil {
+			lastErr = fmt.Errorf("路由 [%s] 没有可用的成员", r.name)
+		}
+		yield(nil, lastErr)
+	}
+}
+
+var httpStatusPattern = regexp.MustCompile(`(?i)(?:HTTP|status code:?)\s*(\d{3})`)
+
+// llmErrorStatus 提取错误中的 HTTP 状态码，无法识别时返回 0
+func llmErrorStatus(err error) int {
+	var oaiAPIErr *go_openai.APIError
+	if errors.As(err, &oaiAPIErr) && oaiAPIErr.HTTPStatusCode > 0 {
+		return oaiAPIErr.HTTPStatusCode
+	}
+	var oaiReqErr *go_openai.RequestError
+	if errors.As(err, &oaiReqErr) && oaiReqErr.HTTPStatusCode > 0 {
+		return oaiReqErr.HTTPStatusCode
+	}
+	var genaiErr genai.APIError
+	if errors.As(err, &genaiErr) && genaiErr.Code > 0 {
+		return genaiErr.Code
+	}
+	var genaiPtrErr *genai.APIError
+	if errors.As(err, &genaiPtrErr) && genaiPtrErr.Code > 0 {
+		return genaiPtrErr.Code
+	}
+	if m := httpStatusPattern.FindStringSubmatch(err.Error()); m != nil {
+		code, _ := strconv.Atoi(m[1])
+		return code
+	}
+	return 0
+}
+
+var contextLengthHints = []string{
+	"context_length_exceeded", "maximum context length", "context length", "context window",
+	"prompt is too long", "too many tokens", "input is too long", "上下文长度",
+}
+
+// classifyLLMError 将模型调用错误归类为路由回退类别，无法归类返回 other
+func classifyLLMError(err error) string {
+	if err == nil {
+		return ""
+	}
+	msg := strings.ToLower(err.Error())
+	var oaiAPIErr *go_openai.APIError
+	if errors.As(err, &oaiAPIErr) && oaiAPIErr.Code != nil {
+		msg += " " + strings.ToLower(fmt.Sprint(oaiAPIErr.Code))
+	}
+	for _, hint := range contextLengthHints {
+		if strings.Contains(msg, hint) {
+			return models.RouteErrContextLength
+		}
+	}
+
+	status := llmErrorStatus(err)
+	switch {
+	case status == 429:
+		return models.RouteErrRateLimit
+	case status == 408 || status == 504:
+		return models.RouteErrTimeout
+	case status >= 500:
+		return models.RouteErrServer
+	}
+	if strings.Contains(msg, "rate limit") || strings.Contains(msg, "too many requests") || strings.Contains(msg, "resource_exhausted") {
+		return models.RouteErrRateLimit
+	}
+	if strings.Contains(msg, "overloaded") {
+		return models.RouteErrServer
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) ||
+		strings.Contains(msg, "timeout") || strings.Contains(msg, "timed out") {
+		return models.RouteErrTimeout
+	}
+	return "other"
+}
@@ -0,0 +1,153 @@
+package adk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	go_openai "github.com/sashabaranov/go-openai"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+// fakeLLM 按预设返回文本或错误，记录调用次数
+type fakeLLM struct {
+	name  string
+	text  string
+	err   error
+	calls int
+}
+
+func (f *fakeLLM) Name() string { return f.name }
+
+func (f *fakeLLM) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		f.calls++
+		if f.text != "" {
+			if !yield(&model.LLMResponse{Content: genai.NewContentFromText(f.text, "model")}, nil) {
+				return
+			}
+		}
+		if f.err != nil {
+			yield(nil, f.err)
+		}
+	}
+}
+
+func collectRouter(r *RouterModel) (string, error) {
+	var text string
+	for resp, err := range r.GenerateContent(context.Background(), &model.LLMRequest{}, false) {
+		if err != nil {
+			return text, err
+		}
+		text += resp.Content.Parts[0].Text
+	}
+	return text, nil
+}
+
+func TestClassifyLLMError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&go_openai.APIError{HTTPStatusCode: 429, Message: "slow down"}, models.RouteErrRateLimit},
+		{fmt.Errorf("wrap: %w", &go_openai.RequestError{HTTPStatusCode: 502}), models.RouteErrServer},
+		{genai.APIError{Code: 503, Message: "unavailable"}, models.RouteErrServer},
+		{errors.New("HTTP 500: internal error"), models.RouteErrServer},
+		{errors.New("anthropic: overloaded_error"), models.RouteErrServer},
+		{errors.New("This model's maximum context length is 8192 tokens"), models.RouteErrContextLength},
+		{&go_openai.APIError{HTTPStatusCode: 400, Code: "context_length_exceeded"}, models.RouteErrContextLength},
+		{fmt.Errorf("request: %w", context.DeadlineExceeded), models.RouteErrTimeout},
+		{errors.New("read tcp: i/o timeout"), models.RouteErrTimeout},
+		{&go_openai.APIError{HTTPStatusCode: 401, Message: "invalid api key"}, "other"},
+	}
+	for _, tt := range tests {
+		if got := classifyLLMError(tt.err); got != tt.want {
+			t.Errorf("classifyLLMError(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRouterModel_Fallback(t *testing.T) {
+	primary := &fakeLLM{name: "a", err: &go_openai.APIError{HTTPStatusCode: 429}}
+	backup := &fakeLLM{name: "b", text: "ok"}
+	state := newRouteState()
+	r := newRouterModel("r", models.RouteStrategyOrdered, nil, []*routeMember{
+		{id: "a", name: "a", llm: primary},
+		{id: "b", name: "b", llm: backup},
+	}, state, newQuotaLimiter())
+
+	text, err := collectRouter(r)
+	if err != nil || text != "ok" {
+		t.Fatalf("got %q, %v", text, err)
+	}
+
+	// 被限流的成员进入冷却，下一次请求直接命中备用成员
+	if _, err := collectRouter(r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if primary.calls != 1 || backup.calls != 2 {
+		t.Fatalf("calls = %d/%d, want 1/2", primary.calls, backup.calls)
+	}
+}
+
+func TestRouterModel_NoFallback(t *testing.T) {
+	authErr := &go_openai.APIError{HTTPStatusCode: 401}
+	backup := &fakeLLM{name: "b", text: "ok"}
+	r := newRouterModel("r", models.RouteStrategyOrdered, nil, []*routeMember{
+		{id: "a", llm: &fakeLLM{err: authErr}},
+		{id: "b", llm: backup},
+	}, newRouteState(), newQuotaLimiter())
+	if _, err := collectRouter(r); !errors.Is(err, authErr) || backup.calls != 0 {
+		t.Fatalf("non-retryable error should not fall back: %v, calls=%d", err, backup.calls)
+	}
+
+	// 仅配置了 server 类别时，超时不回退
+	r = newRouterModel("r", models.RouteStrategyOrdered, []string{models.RouteErrServer}, []*routeMember{
+		{id: "a", llm: &fakeLLM{err: context.DeadlineExceeded}},
+		{id: "b", llm: backup},
+	}, newRouteState(), newQuotaLimiter())
+	if _, err := collectRouter(r); err == nil || backup.calls != 0 {
+		t.Fatalf("timeout should not fall back: %v, calls=%d", err, backup.calls)
+	}
+
+	// 已输出部分内容后失败，不再切换
+	r = newRouterModel("r", models.RouteStrategyOrdered, nil, []*routeMember{
+		{id: "a", llm: &fakeLLM{text: "half", err: errors.New("HTTP 502")}},
+		{id: "b", llm: backup},
+	}, newRouteState(), newQuotaLimiter())
+	if text, err := collectRouter(r); err == nil || text != "half" || backup.calls != 0 {
+		t.Fatalf("mid-stream failure should not fall back: %q, %v", text, err)
+	}
+}
+
+func TestRouterModel_LatencyAndConcurrency(t *testing.T) {
+	state := newRouteState()
+	quota := newQuotaLimiter()
+	slow := &fakeLLM{name: "slow", text: "slow"}
+	fast := &fakeLLM{name: "fast", text: "fast"}
+	state.observeLatency("slow", 2*time.Second)
+	state.observeLatency("fast", 200*time.Millisecond)
+
+	r := newRouterModel("r", models.RouteStrategyLatency, nil, []*routeMember{
+		{id: "slow", llm: slow},
+		{id: "fast", llm: fast, limits: quotaLimits{id: "fast", concurrency: 1}},
+	}, state, quota)
+	if text, _ := collectRouter(r); text != "fast" {
+		t.Fatalf("latency strategy picked %q", text)
+	}
+
+	// fast 并发已满时，改用空闲的 slow
+	lease := quota.tryAcquire(quotaLimits{id: "fast", concurrency: 1}, 0)
+	if lease == nil {
+		t.Fatal("slot should be free")
+	}
+	defer lease.release(0)
+	if text, _ := collectRouter(r); text != "slow" {
+		t.Fatalf("busy member should be skipped, got %q", text)
+	}
+}
@@ -0,0 +1,257 @@
+package structured
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+// JSONSchema 返回请求中的结构化输出 JSON Schema，未开启结构化输出时返回 nil
+// 优先使用 ResponseJsonSchema，否则由 ResponseSchema 转换
+func JSONSchema(cfg *genai.GenerateContentConfig) map[string]any {
+	if cfg == nil {
+		return nil
+	}
+	if cfg.ResponseJsonSchema != nil {
+		if m, ok := cfg.ResponseJsonSchema.(map[string]any); ok {
+			return m
+		}
+		data, err := json.Marshal(cfg.ResponseJsonSchema)
+		if err != nil {
+			return nil
+		}
+		var m map[string]any
+		if json.Unmarshal(data, &m) != nil {
+			return nil
+		}
+		return m
+	}
+	if cfg.ResponseSchema != nil {
+		return FromSchema(cfg.ResponseSchema)
+	}
+	return nil
+}
+
+// FromSchema 将 genai.Schema 转换为标准 JSON Schema，带属性的对象禁止额外字段
+func FromSchema(s *genai.Schema) map[string]any {
+	if s == nil {
+		return nil
+	}
+	out := map[string]any{}
+	if s.Type != "" && s.Type != genai.TypeUnspecified {
+		typ := strings.ToLower(string(s.Type))
+		if s.Nullable != nil && *s.Nullable {
+			out["type"] = []any{typ, "null"}
+		} else {
+			out["type"] = typ
+		}
+	}
+	if s.Title != "" {
+		out["title"] = s.Title
+	}
+	if s.Description != "" {
+		out["description"] = s.Description
+	}
+	if s.Format != "" {
+		out["format"] = s.Format
+	}
+	if s.Pattern != "" {
+		out["pattern"] = s.Pattern
+	}
+	if len(s.Enum) > 0 {
+		enum := make([]any, len(s.Enum))
+		for i, e := range s.Enum {
+			enum[i] = e
+		}
+		out["enum"] = enum
+	}
+	if s.Items != nil {
+		out["items"] = FromSchema(s.Items)
+	}
+	if len(s.Properties) > 0 {
+		props := make(map[string]any, len(s.Properties))
+		for name, p := range s.Properties {
+			props[name] = FromSchema(p)
+		}
+		out["properties"] = props
+		out["additionalProperties"] = false
+	}
+	if len(s.Required) > 0 {
+		required := make([]any, len(s.Required))
+		for i, r := range s.Required {
+			required[i] = r
+		}
+		out["required"] = required
+	}
+	if len(s.AnyOf) > 0 {
+		anyOf := make([]any, len(s.AnyOf))
+		for i, a := range s.AnyOf {
+			anyOf[i] = FromSchema(a)
+		}
+		out["anyOf"] = anyOf
+	}
+	setInt := func(key string, v *int64) {
+		if v != nil {
+			out[key] = *v
+		}
+	}
+	setInt("minItems", s.MinItems)
+	setInt("maxItems", s.MaxItems)
+	setInt("minLength", s.MinLength)
+	setInt("maxLength", s.MaxLength)
+	if s.Minimum != nil {
+		out["minimum"] = *s.Minimum
+	}
+	if s.Maximum != nil {
+		out["maximum"] = *s.Maximum
+	}
+	return out
+}
+
+// Strict 判断 schema 是否满足 OpenAI strict 模式：所有对象的属性均为必填且禁止额外字段
+func Strict(schema map[string]any) bool {
+	if props, ok := schema["properties"].(map[string]any); ok {
+		if schema["additionalProperties"] != false {
+			return false
+		}
+		required := toStrings(schema["required"])
+		for name, p := range props {
+			if !slices.Contains(required, name) {
+				return false
+			}
+			if sub, ok := p.(map[string]any); ok && !Strict(sub) {
+				return false
+			}
+		}
+	}
+	if items, ok := schema["items"].(map[string]any); ok && !Strict(items) {
+		return false
+	}
+	return true
+}
+
+// Validate 按 JSON Schema 校验 json.Unmarshal 得到的值，支持常用关键字子集
+func Validate(schema map[string]any, v any) error {
+	return validate(schema, v, "$")
+}
+
+func validate(schema map[string]any, v any, path string) error {
+	if schema == nil {
+		return nil
+	}
+	if types := toStrings(schema["type"]); len(types) > 0 && !slices.ContainsFunc(types, func(t string) bool { return matchType(t, v) }) {
+		return fmt.Errorf("%s: 类型应为 %s", path, strings.Join(types, "/"))
+	}
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		if !slices.ContainsFunc(enum, func(e any) bool { return fmt.Sprint(e) == fmt.Sprint(v) }) {
+			return fmt.Errorf("%s: 取值 %v 不在可选范围 %v 内", path, v, enum)
+		}
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		for _, name := range toStrings(schema["required"]) {
+			if _, ok := val[name]; !ok {
+				return fmt.Errorf("%s: 缺少必填字段 %s", path, name)
+			}
+		}
+		props, _ := schema["properties"].(map[string]any)
+		for name, fieldValue := range val {
+			if sub, ok := props[name].(map[string]any); ok {
+				if err := validate(sub, fieldValue, path+"."+name); err != nil {
+					return err
+				}
+			}
+		}
+	case []any:
+		if n, ok := toFloat(schema["minItems"]); ok && float64(len(val)) < n {
+			return fmt.Errorf("%s: 至少需要 %d 项", path, int(n))
+		}
+		if n, ok := toFloat(schema["maxItems"]); ok && float64(len(val)) > n {
+			return fmt.Errorf("%s: 最多 %d 项", path, int(n))
+		}
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range val {
+				if err := validate(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case string:
+		if n, ok := toFloat(schema["minLength"]); ok && float64(len([]rune(val))) < n {
+			return fmt.Errorf("%s: 长度至少为 %d", path, int(n))
+		}
+	case float64:
+		if n, ok := toFloat(schema["minimum"]); ok && val < n {
+			return fmt.Errorf("%s: 不能小于 %v", path, n)
+		}
+		if n, ok := toFloat(schema["maximum"]); ok && val > n {
+			return fmt.Errorf("%s: 不能大于 %v", path, n)
+		}
+	}
+	return nil
+}
+
+// matchType 判断值是否符合 JSON Schema 类型
+func matchType(typ string, v any) bool {
+	switch typ {
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "null":
+		return v == nil
+	default:
+		return true
+	}
+}
+
+// toStrings 将 string 或 []any / []string 统一为字符串切片
+func toStrings(v any) []string {
+	switch t := v.(type) {
+	case string:
+		return []string{t}
+	case []string:
+		return t
+	case []any:
+		out := make([]string, 0, len(t))
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// toFloat 读取数值型关键字（map 字面量中可能为 int / int64 / float64）
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
@@ -0,0 +1,202 @@
+// Package structured 提供与供应商无关的结构化输出：请求携带 JSON Schema，
+// 由各适配器转换为原生能力（OpenAI json_schema、Anthropic 强制工具调用、Gemini ResponseSchema），
+// 并在本地校验输出，失败时自动修复一轮。
+package structured
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+var log = logger.New("structured")
+
+// OutputName 结构化输出在各家 API 中使用的名称（OpenAI schema 名、Anthropic 工具名）
+const OutputName = "structured_output"
+
+// maxRepairRounds 校验失败后的自动修复轮数
+const maxRepairRounds = 1
+
+// Generate 以结构化输出模式调用模型，并将结果解析到 out（指针）。
+// 输出无法解析、不符合 schema 或未通过 check 时，把错误反馈给模型修复一轮。
+// schema 根节点必须为对象（OpenAI 与 Anthropic 的要求）。
+func Generate(ctx context.Context, llm model.LLM, prompt string, schema *genai.Schema, out any, check func() error) error {
+	if schema == nil || schema.Type != genai.TypeObject {
+		return errors.New("structured output schema must be an object")
+	}
+	jsonSchema := FromSchema(schema)
+	contents := []*genai.Content{genai.NewContentFromText(prompt, genai.RoleUser)}
+
+	var lastErr error
+	for round := 0; round <= maxRepairRounds; round++ {
+		text, err := generateText(ctx, llm, contents, schema)
+		if err != nil {
+			return err
+		}
+		if lastErr = decode(text, jsonSchema, out, check); lastErr == nil {
+			return nil
+		}
+		log.Warn("结构化输出校验失败（第 %d 轮）: %v", round+1, lastErr)
+		if strings.TrimSpace(text) != "" {
+			contents = append(contents, genai.NewContentFromText(text, genai.RoleModel))
+		}
+		contents = append(contents, genai.NewContentFromText(repairPrompt(lastErr), genai.RoleUser))
+	}
+	return lastErr
+}
+
+// generateText 调用模型并拼接非思考文本
+func generateText(ctx context.Context, llm model.LLM, contents []*genai.Content, schema *genai.Schema) (string, error) {
+	req := &model.LLMRequest{
+		Contents: contents,
+		Config: &genai.GenerateContentConfig{
+			ResponseMIMEType: "application/json",
+			ResponseSchema:   schema,
+		},
+	}
+	var sb strings.Builder
+	for resp, err := range llm.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return "", err
+		}
+		if resp == nil || resp.Content == nil {
+			continue
+		}
+		for _, part := range resp.Content.Parts {
+			if !part.Thought && part.Text != "" {
+				sb.WriteString(part.Text)
+			}
+		}
+	}
+	return sb.String(), nil
+}
+
+// decode 提取 JSON、按 schema 校验后解析到 out，再执行业务校验
+func decode(text string, schema map[string]any, out any, check func() error) error {
+	jsonStr := ExtractJSON(text)
+	if jsonStr == "" {
+		return fmt.Errorf("输出中没有 JSON 对象: %s", truncate(text, 200))
+	}
+	var generic any
+	if err := json.Unmarshal([]byte(jsonStr), &generic); err != nil {
+		return fmt.Errorf("JSON 解析失败: %w", err)
+	}
+	if err := Validate(schema, generic); err != nil {
+		return err
+	}
+
+	// 修复轮复用 out，先清零避免残留上一轮字段
+	if rv := reflect.ValueOf(out); rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv.Elem().Set(reflect.Zero(rv.Elem().Type()))
+	}
+	if err := json.Unmarshal([]byte(jsonStr), out); err != nil {
+		return fmt.Errorf("JSON 解析失败: %w", err)
+	}
+	if check != nil {
+		return check()
+	}
+	return nil
+}
+
+// repairPrompt 构建修复轮提示
+func repairPrompt(err error) string {
+	return fmt.Sprintf("上面的输出未通过校验：%v\n请修正后重新输出，只输出符合要求的 JSON 对象，不要任何其他内容。", err)
+}
+
+// ExtractJSON 从文本中提取 JSON 对象（兼容代码块包裹与前后说明文字）
+func ExtractJSON(content string) string {
+	// 方法1: 尝试直接解析整个内容
+	content = strings.TrimSpace(content)
+	if strings.HasPrefix(content, "{") && strings.HasSuffix(content, "}") {
+		return content
+	}
+
+	// 方法2: 查找 ```json 代码块
+	if idx := strings.Index(content, "```json"); idx != -1 {
+		start := idx + 7
+		if end := strings.Index(content[start:], "```"); end != -1 {
+			return strings.TrimSpace(content[start : start+end])
+		}
+	}
+
+	// 方法3: 查找 ``` 代码块
+	if idx := strings.Index(content, "```"); idx != -1 {
+		start := idx + 3
+		// 跳过可能的语言标识
+		if newline := strings.Index(content[start:], "\n"); newline != -1 {
+			start += newline + 1
+		}
+		if end := strings.Index(content[start:], "```"); end != -1 {
+			extracted := strings.TrimSpace(content[start : start+end])
+			if strings.HasPrefix(extracted, "{") {
+				return extracted
+			}
+		}
+	}
+
+	// 方法4: 查找第一个完整的 JSON 对象（匹配括号）
+	start := strings.Index(content, "{")
+	if start == -1 {
+		return ""
+	}
+
+	depth := 0
+	inString := false
+	escape := false
+
+	for i := start; i < len(content); i++ {
+		c := content[i]
+
+		if escape {
+			escape = false
+			continue
+		}
+
+		if c == '\\' && inString {
+			escape = true
+			continue
+		}
+
+		if c == '"' {
+			inString = !inString
+			continue
+		}
+
+		if inString {
+			continue
+		}
+
+		if c == '{' {
+			depth++
+		} else if c == '}' {
+			depth--
+			if depth == 0 {
+				return content[start : i+1]
+			}
+		}
+	}
+
+	// 方法5: 回退到简单的首尾匹配
+	end := strings.LastIndex(content, "}")
+	if end > start {
+		return content[start : end+1]
+	}
+
+	return ""
+}
+
+// truncate 截断字符串用于错误信息
+func truncate(s string, maxLen int) string {
+	r := []rune(s)
+	if len(r) <= maxLen {
+		return s
+	}
+	return string(r[:maxLen]) + "..."
+}
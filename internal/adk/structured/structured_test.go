@@ -0,0 +1,131 @@
+package structured
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"strings"
+	"testing"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+var errUnknown = errors.New("unknown agent")
+
+type scriptedLLM struct {
+	replies  []string
+	requests []*model.LLMRequest
+}
+
+func (m *scriptedLLM) Name() string { return "fake" }
+
+func (m *scriptedLLM) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		reply := m.replies[len(m.requests)]
+		m.requests = append(m.requests, req)
+		yield(&model.LLMResponse{Content: genai.NewContentFromText(reply, genai.RoleModel), TurnComplete: true}, nil)
+	}
+}
+
+var testSchema = &genai.Schema{
+	Type: genai.TypeObject,
+	Properties: map[string]*genai.Schema{
+		"selected": {Type: genai.TypeArray, Items: &genai.Schema{Type: genai.TypeString}, MinItems: genai.Ptr[int64](1)},
+		"level":    {Type: genai.TypeString, Enum: []string{"low", "high"}},
+	},
+	Required: []string{"selected", "level"},
+}
+
+type testOutput struct {
+	Selected []string `json:"selected"`
+	Level    string   `json:"level"`
+}
+
+func TestFromSchema(t *testing.T) {
+	s := FromSchema(testSchema)
+	if s["type"] != "object" || s["additionalProperties"] != false {
+		t.Fatalf("unexpected root: %v", s)
+	}
+	selected := s["properties"].(map[string]any)["selected"].(map[string]any)
+	if selected["type"] != "array" || selected["minItems"] != int64(1) {
+		t.Errorf("unexpected selected: %v", selected)
+	}
+	if !Strict(s) {
+		t.Error("schema with all properties required should be strict")
+	}
+	if Strict(FromSchema(&genai.Schema{Type: genai.TypeObject, Properties: map[string]*genai.Schema{"a": {Type: genai.TypeString}}})) {
+		t.Error("schema with optional property should not be strict")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	schema := FromSchema(testSchema)
+	tests := []struct {
+		name    string
+		value   any
+		wantErr string
+	}{
+		{"ok", map[string]any{"selected": []any{"a"}, "level": "low"}, ""},
+		{"missing", map[string]any{"selected": []any{"a"}}, "缺少必填字段 level"},
+		{"empty array", map[string]any{"selected": []any{}, "level": "low"}, "$.selected: 至少需要 1 项"},
+		{"enum", map[string]any{"selected": []any{"a"}, "level": "mid"}, "$.level: 取值 mid"},
+		{"type", map[string]any{"selected": "a", "level": "low"}, "$.selected: 类型应为 array"},
+	}
+	for _, tt := range tests {
+		err := Validate(schema, tt.value)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: got %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestGenerate_RepairRound(t *testing.T) {
+	llm := &scriptedLLM{replies: []string{
+		`{"selected":[],"level":"low"}`,
+		"```json\n{\"selected\":[\"a\"],\"level\":\"high\"}\n```",
+	}}
+	var out testOutput
+	if err := Generate(context.Background(), llm, "选择", testSchema, &out, nil); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(out.Selected) != 1 || out.Selected[0] != "a" || out.Level != "high" {
+		t.Errorf("unexpected output: %+v", out)
+	}
+	if len(llm.requests) != 2 {
+		t.Fatalf("requests = %d, want 2", len(llm.requests))
+	}
+	if cfg := llm.requests[0].Config; cfg.ResponseMIMEType != "application/json" || cfg.ResponseSchema != testSchema {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	repair := llm.requests[1].Contents
+	if len(repair) != 3 || !strings.Contains(repair[2].Parts[0].Text, "至少需要 1 项") {
+		t.Errorf("repair round should feed back validation error, got %d contents", len(repair))
+	}
+}
+
+func TestGenerate_CheckFailsAfterRepair(t *testing.T) {
+	llm := &scriptedLLM{replies: []string{
+		`{"selected":["x"],"level":"low"}`,
+		`{"selected":["y"],"level":"low"}`,
+	}}
+	var out testOutput
+	check := func() error {
+		if out.Selected[0] != "a" {
+			return errUnknown
+		}
+		return nil
+	}
+	if err := Generate(context.Background(), llm, "选择", testSchema, &out, check); err != errUnknown {
+		t.Fatalf("err = %v, want %v", err, errUnknown)
+	}
+	if len(llm.requests) != 1+maxRepairRounds {
+		t.Errorf("requests = %d, want %d", len(llm.requests), 1+maxRepairRounds)
+	}
+}
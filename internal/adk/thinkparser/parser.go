@@ -1,4 +1,5 @@
-package openai
+// Package thinkparser 解析模型输出中的 <think>...</think> 思考标签
+package thinkparser
 
 import "strings"
 
@@ -7,43 +8,45 @@ const (
 	thinkCloseTag = "</think>"
 )
 
-type thinkSegment struct {
+// Segment is a parsed text chunk, Thought marks content inside <think> tags.
+type Segment struct {
 	Text    string
 	Thought bool
 }
 
-// splitThinkTaggedText parses a complete text and maps <think>...</think>
+// Split parses a complete text and maps <think>...</think>
 // content into Thought segments.
-func splitThinkTaggedText(text string) []thinkSegment {
+func Split(text string) []Segment {
 	if text == "" {
 		return nil
 	}
 
-	parser := newThinkTagStreamParser()
+	parser := NewStreamParser()
 	segments := parser.Feed(text)
 	segments = append(segments, parser.Flush()...)
-	return mergeThinkSegments(segments)
+	return mergeSegments(segments)
 }
 
-// thinkTagStreamParser incrementally parses <think>...</think> markers.
-type thinkTagStreamParser struct {
+// StreamParser incrementally parses <think>...</think> markers.
+type StreamParser struct {
 	buffer  string
 	inThink bool
 }
 
-func newThinkTagStreamParser() *thinkTagStreamParser {
-	return &thinkTagStreamParser{}
+// NewStreamParser creates an incremental <think> tag parser.
+func NewStreamParser() *StreamParser {
+	return &StreamParser{}
 }
 
 // Feed parses incremental chunks and returns deterministically parsed segments.
 // Potentially incomplete tag prefixes are kept in internal buffer.
-func (p *thinkTagStreamParser) Feed(chunk string) []thinkSegment {
+func (p *StreamParser) Feed(chunk string) []Segment {
 	if chunk == "" {
 		return nil
 	}
 
 	p.buffer += chunk
-	var segments []thinkSegment
+	var segments []Segment
 
 	for {
 		if p.buffer == "" {
@@ -54,7 +57,7 @@ func (p *thinkTagStreamParser) Feed(chunk string) []thinkSegment {
 			endIdx := indexFold(p.buffer, thinkCloseTag)
 			if endIdx >= 0 {
 				if endIdx > 0 {
-					segments = append(segments, thinkSegment{
+					segments = append(segments, Segment{
 						Text:    p.buffer[:endIdx],
 						Thought: true,
 					})
@@ -66,7 +69,7 @@ func (p *thinkTagStreamParser) Feed(chunk string) []thinkSegment {
 
 			emit, keep := splitKeepPossibleTagPrefix(p.buffer, thinkCloseTag)
 			if emit != "" {
-				segments = append(segments, thinkSegment{
+				segments = append(segments, Segment{
 					Text:    emit,
 					Thought: true,
 				})
@@ -78,7 +81,7 @@ func (p *thinkTagStreamParser) Feed(chunk string) []thinkSegment {
 		startIdx := indexFold(p.buffer, thinkOpenTag)
 		if startIdx >= 0 {
 			if startIdx > 0 {
-				segments = append(segments, thinkSegment{
+				segments = append(segments, Segment{
 					Text: p.buffer[:startIdx],
 				})
 			}
@@ -89,7 +92,7 @@ func (p *thinkTagStreamParser) Feed(chunk string) []thinkSegment {
 
 		emit, keep := splitKeepPossibleTagPrefix(p.buffer, thinkOpenTag)
 		if emit != "" {
-			segments = append(segments, thinkSegment{
+			segments = append(segments, Segment{
 				Text: emit,
 			})
 		}
@@ -97,16 +100,16 @@ func (p *thinkTagStreamParser) Feed(chunk string) []thinkSegment {
 		break
 	}
 
-	return mergeThinkSegments(segments)
+	return mergeSegments(segments)
 }
 
 // Flush flushes leftover buffered text, usually called at end-of-stream.
-func (p *thinkTagStreamParser) Flush() []thinkSegment {
+func (p *StreamParser) Flush() []Segment {
 	if p.buffer == "" {
 		return nil
 	}
 
-	segment := thinkSegment{
+	segment := Segment{
 		Text:    p.buffer,
 		Thought: p.inThink,
 	}
@@ -116,7 +119,7 @@ func (p *thinkTagStreamParser) Flush() []thinkSegment {
 	if segment.Text == "" {
 		return nil
 	}
-	return []thinkSegment{segment}
+	return []Segment{segment}
 }
 
 func splitKeepPossibleTagPrefix(text, tag string) (emit string, keep string) {
@@ -138,12 +141,12 @@ func splitKeepPossibleTagPrefix(text, tag string) (emit string, keep string) {
 	return text, ""
 }
 
-func mergeThinkSegments(segments []thinkSegment) []thinkSegment {
+func mergeSegments(segments []Segment) []Segment {
 	if len(segments) == 0 {
 		return nil
 	}
 
-	merged := make([]thinkSegment, 0, len(segments))
+	merged := make([]Segment, 0, len(segments))
 	for _, seg := range segments {
 		if seg.Text == "" {
 			continue
@@ -166,4 +169,3 @@ func indexFold(s, sep string) int {
 	}
 	return strings.Index(strings.ToLower(s), strings.ToLower(sep))
 }
-
@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/services"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// GetCapitalSeriesInput 北向/两融序列查询输入
+type GetCapitalSeriesInput struct {
+	Code string `json:"code" jsonschema:"股票代码，如 600519 或 sz000001"`
+	Days int    `json:"days,omitempty" jsonschema:"返回最近N个交易日，默认20，最大120"`
+}
+
+// GetNorthboundHoldingsOutput 北向持股输出
+type GetNorthboundHoldingsOutput struct {
+	Data   *models.NorthboundHoldings `json:"data,omitempty"`
+	Errors map[string]string          `json:"errors,omitempty"`
+}
+
+// GetMarginTradingOutput 融资融券输出
+type GetMarginTradingOutput struct {
+	Data   *models.MarginTrading `json:"data,omitempty"`
+	Errors map[string]string     `json:"errors,omitempty"`
+}
+
+// SetNorthboundService 设置北向资金服务并注册北向持股工具
+func (r *Registry) SetNorthboundService(northboundService *services.NorthboundService) {
+	r.northboundService = northboundService
+	r.registerTool("get_northbound_holdings", "获取个股北向资金（沪深港通）持股数量、持股市值、占流通股比及逐日增减持", r.createGetNorthboundHoldingsTool)
+}
+
+// SetMarginService 设置融资融券服务并注册两融工具
+func (r *Registry) SetMarginService(marginService *services.MarginService) {
+	r.marginService = marginService
+	r.registerTool("get_margin_trading", "获取个股融资融券余额时间序列：融资余额/买入/偿还/净买入、融券余额/余量", r.createGetMarginTradingTool)
+}
+
+func (r *Registry) createGetNorthboundHoldingsTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetCapitalSeriesInput) (GetNorthboundHoldingsOutput, error) {
+		fmt.Printf("[Tool:get_northbound_holdings] 调用开始, code=%s, days=%d\n", input.Code, input.Days)
+		if r.northboundService == nil {
+			return GetNorthboundHoldingsOutput{Errors: map[string]string{"service": "北向资金服务未初始化"}}, nil
+		}
+
+		data, err := r.northboundService.GetHoldings(input.Code, input.Days)
+		output := GetNorthboundHoldingsOutput{}
+		if len(data.Items) > 0 {
+			output.Data = &data
+		}
+		if err != nil {
+			fmt.Printf("[Tool:get_northbound_holdings] 错误: %v\n", err)
+			output.Errors = map[string]string{"northbound": err.Error()}
+		}
+		fmt.Printf("[Tool:get_northbound_holdings] 调用完成, items=%d\n", len(data.Items))
+		return output, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name: "get_northbound_holdings",
+		Description: "获取个股北向资金（沪深港通）持股序列，按日期倒序：持股数量(股)、持股市值(元)、占流通股/总股本比例(%)、" +
+			"较上一披露日的持股变化及折算金额，并给出近5/20个披露日的累计增减持股数。非沪深港通标的返回错误",
+	}, handler)
+}
+
+func (r *Registry) createGetMarginTradingTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetCapitalSeriesInput) (GetMarginTradingOutput, error) {
+		fmt.Printf("[Tool:get_margin_trading] 调用开始, code=%s, days=%d\n", input.Code, input.Days)
+		if r.marginService == nil {
+			return GetMarginTradingOutput{Errors: map[string]string{"service": "融资融券服务未初始化"}}, nil
+		}
+
+		data, err := r.marginService.GetMarginTrading(input.Code, input.Days)
+		output := GetMarginTradingOutput{}
+		if len(data.Items) > 0 {
+			output.Data = &data
+		}
+		if err != nil {
+			fmt.Printf("[Tool:get_margin_trading] 错误: %v\n", err)
+			output.Errors = map[string]string{"margin": err.Error()}
+		}
+		fmt.Printf("[Tool:get_margin_trading] 调用完成, items=%d\n", len(data.Items))
+		return output, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name: "get_margin_trading",
+		Description: "获取个股融资融券时间序列，按日期倒序：融资余额、融资买入额、融资偿还额、融资净买入、融券余额、融券余量/卖出量/偿还量、" +
+			"两融余额、融资余额占流通市值比(%)，金额单位为元，并给出近5/20日融资余额变化。非两融标的返回错误",
+	}, handler)
+}
@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/services"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// GetAnalystConsensusInput 一致预期输入
+type GetAnalystConsensusInput struct {
+	Code string `json:"code" jsonschema:"股票代码，如 sh600519 或 600519"`
+	Days int    `json:"days,omitempty" jsonschema:"统计窗口天数，默认180，最大730"`
+}
+
+// GetAnalystConsensusOutput 一致预期输出
+type GetAnalystConsensusOutput struct {
+	Data    *models.AnalystConsensus   `json:"data,omitempty"`
+	History []models.ConsensusSnapshot `json:"history,omitempty"`
+	Errors  map[string]string          `json:"errors,omitempty"`
+}
+
+// SetConsensusService 设置一致预期服务并注册一致预期工具
+func (r *Registry) SetConsensusService(consensusService *services.ConsensusService) {
+	r.consensusService = consensusService
+	r.registerTool("get_analyst_consensus", "汇总券商研报得到一致预期EPS/PE、评级分布与变动、EPS修正动量", r.createGetAnalystConsensusTool)
+}
+
+func (r *Registry) createGetAnalystConsensusTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetAnalystConsensusInput) (GetAnalystConsensusOutput, error) {
+		fmt.Printf("[Tool:get_analyst_consensus] 调用开始, code=%s, days=%d\n", input.Code, input.Days)
+		if r.consensusService == nil {
+			return GetAnalystConsensusOutput{Errors: map[string]string{"service": "一致预期服务未初始化"}}, nil
+		}
+		if input.Code == "" {
+			return GetAnalystConsensusOutput{Errors: map[string]string{"code": "请提供股票代码"}}, nil
+		}
+
+		data, err := r.consensusService.GetConsensus(input.Code, input.Days)
+		if err != nil {
+			fmt.Printf("[Tool:get_analyst_consensus] 错误: %v\n", err)
+			return GetAnalystConsensusOutput{Errors: map[string]string{"consensus": err.Error()}}, nil
+		}
+		output := GetAnalystConsensusOutput{Data: data}
+		// 历史快照只取最近若干条，便于观察一致预期趋势
+		history := r.consensusService.GetHistory(input.Code)
+		if len(history) > 10 {
+			history = history[len(history)-10:]
+		}
+		if len(history) > 1 {
+			output.History = history
+		}
+		fmt.Printf("[Tool:get_analyst_consensus] 调用完成, reports=%d, brokers=%d\n", data.ReportCount, data.BrokerCount)
+		return output, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name: "get_analyst_consensus",
+		Description: "获取个股分析师一致预期：按财年的一致预期EPS(均值/中位数/高低)与PE、各券商最新评级分布及回看起点时的分布、" +
+			"券商评级上调/下调明细、当年EPS修正动量(上调/下调券商数、一致预期变化%)，并附带本地历史快照",
+	}, handler)
+}
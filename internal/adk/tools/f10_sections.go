@@ -172,3 +172,15 @@ func (r *Registry) createF10BuybackTool() (tool.Tool, error) {
 		r.f10Service.GetStockBuybackByCode,
 	)
 }
+
+func (r *Registry) createFinancialAnalysisTool() (tool.Tool, error) {
+	return buildF10SectionTool[models.FinancialAnalysis](
+		r,
+		"get_financial_analysis",
+		"获取已计算好的财务分析结果（无需自行计算）：按报告期倒序的毛利率/营业利润率/净利率、ROE杜邦拆解（净利率×总资产周转率×权益乘数）、"+
+			"经营现金流/净利润、销售收现比、应收与存货周转天数、资产负债率、流动比率、商誉占净资产比，累计同比与单季环比，"+
+			"TTM营收/归母净利润/经营现金流/ROE，以及财务异常信号（有利润无现金、应收或存货增速远超营收、高商誉、高杠杆、依赖非经常性损益）。"+
+			"利润与现金流为年初至报告期末累计值，期间ROE/ROA未年化",
+		r.f10Service.GetFinancialAnalysisByCode,
+	)
+}
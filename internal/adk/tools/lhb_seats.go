@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/services"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// QueryLhbSeatsInput 龙虎榜席位查询输入
+type QueryLhbSeatsInput struct {
+	Code      string `json:"code,omitempty" jsonschema:"股票代码，查询该股上榜席位及各席位历史表现，如 600519 或 sz000001"`
+	TradeDate string `json:"trade_date,omitempty" jsonschema:"上榜日期 YYYY-MM-DD，为空时取最近一次上榜"`
+	Seat      string `json:"seat,omitempty" jsonschema:"营业部名称关键字或游资别称（如 炒股养家），查询该席位画像"`
+	SeatType  string `json:"seat_type,omitempty" jsonschema:"按席位类型列出活跃席位: institution(机构)/northbound(北向)/hot_money(游资)/branch(普通营业部)"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"列出席位时的数量，默认10，最大30"`
+}
+
+// QueryLhbSeatsOutput 龙虎榜席位查询输出
+type QueryLhbSeatsOutput struct {
+	Stock  *models.StockSeatAnalysis `json:"stock,omitempty"`
+	Seat   *models.SeatProfile       `json:"seat,omitempty"`
+	Seats  []models.SeatProfile      `json:"seats,omitempty"`
+	Errors map[string]string         `json:"errors,omitempty"`
+}
+
+// SetLhbSeatService 设置龙虎榜席位服务并注册席位查询工具
+func (r *Registry) SetLhbSeatService(lhbSeatService *services.LhbSeatService) {
+	r.lhbSeatService = lhbSeatService
+	r.registerTool("query_lhb_seats", "查询龙虎榜席位数据库：个股上榜席位及其历史胜率、席位画像、活跃游资/机构席位", r.createQueryLhbSeatsTool)
+}
+
+func (r *Registry) createQueryLhbSeatsTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input QueryLhbSeatsInput) (QueryLhbSeatsOutput, error) {
+		fmt.Printf("[Tool:query_lhb_seats] 调用开始, code=%s, date=%s, seat=%s, type=%s\n", input.Code, input.TradeDate, input.Seat, input.SeatType)
+		if r.lhbSeatService == nil {
+			return QueryLhbSeatsOutput{Errors: map[string]string{"service": "龙虎榜席位服务未初始化"}}, nil
+		}
+
+		output := QueryLhbSeatsOutput{}
+		errs := make(map[string]string)
+		if code := strings.TrimSpace(input.Code); code != "" {
+			analysis, err := r.lhbSeatService.AnalyzeStock(code, strings.TrimSpace(input.TradeDate))
+			if err != nil {
+				errs["stock"] = err.Error()
+			} else {
+				output.Stock = analysis
+			}
+		}
+		if seat := strings.TrimSpace(input.Seat); seat != "" {
+			profile, err := r.lhbSeatService.GetSeatProfile(seat)
+			if err != nil {
+				errs["seat"] = err.Error()
+			} else {
+				output.Seat = profile
+			}
+		}
+		if input.SeatType != "" || (input.Code == "" && input.Seat == "") {
+			limit := input.Limit
+			if limit <= 0 {
+				limit = 10
+			}
+			if limit > 30 {
+				limit = 30
+			}
+			output.Seats = r.lhbSeatService.ListSeats("", strings.TrimSpace(input.SeatType), limit)
+		}
+		if len(errs) > 0 {
+			output.Errors = errs
+		}
+		fmt.Printf("[Tool:query_lhb_seats] 调用完成, seats=%d, errors=%d\n", len(output.Seats), len(errs))
+		return output, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name: "query_lhb_seats",
+		Description: "查询本地累积的龙虎榜席位数据库。传 code 返回该股上榜的买方/卖方席位，以及每个席位的历史画像" +
+			"（上榜次数、净买入后次日/5日上涨概率与平均涨幅、典型持有天数、偏好个股、机构/北向/游资类型）；" +
+			"传 seat 查询单个营业部或游资别称的画像；传 seat_type 列出该类型最活跃的席位",
+	}, handler)
+}
@@ -0,0 +1,66 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/services"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// GetPeerComparisonInput 同业对比输入
+type GetPeerComparisonInput struct {
+	Code   string   `json:"code" jsonschema:"目标股票代码，如 600519 或 sz000001"`
+	Source string   `json:"source,omitempty" jsonschema:"同业来源: industry(同行业，默认)/concept(核心题材板块)/custom(自定义列表)"`
+	Theme  string   `json:"theme,omitempty" jsonschema:"source=concept时指定概念名称或板块代码(如BK0896)，留空取首个核心题材"`
+	Peers  []string `json:"peers,omitempty" jsonschema:"source=custom时的对比股票代码列表"`
+	Limit  int      `json:"limit,omitempty" jsonschema:"同业数量，默认10，最大20"`
+}
+
+// GetPeerComparisonOutput 同业对比输出
+type GetPeerComparisonOutput struct {
+	Data   *models.PeerAnalysis `json:"data,omitempty"`
+	Errors map[string]string    `json:"errors,omitempty"`
+}
+
+// SetPeerService 设置同业对比服务并注册同业对比工具
+func (r *Registry) SetPeerService(peerService *services.PeerService) {
+	r.peerService = peerService
+	r.registerTool("get_peer_comparison", "同业对比矩阵：按行业/概念/自定义同业比较估值、成长、盈利与资金流，给出目标股各指标百分位", r.createPeerComparisonTool)
+}
+
+func (r *Registry) createPeerComparisonTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetPeerComparisonInput) (GetPeerComparisonOutput, error) {
+		code := resolveStockCodeFromCandidates(ctx, input.Code)
+		fmt.Printf("[Tool:get_peer_comparison] 调用开始, rawCode=%s, resolvedCode=%s, source=%s\n", input.Code, code, input.Source)
+		if code == "" {
+			return GetPeerComparisonOutput{Errors: map[string]string{"code": "未提供股票代码"}}, nil
+		}
+		if r.peerService == nil {
+			return GetPeerComparisonOutput{Errors: map[string]string{"service": "同业对比服务未初始化"}}, nil
+		}
+
+		data, err := r.peerService.Analyze(models.PeerRequest{
+			Code:   code,
+			Source: input.Source,
+			Theme:  input.Theme,
+			Peers:  input.Peers,
+			Limit:  input.Limit,
+		})
+		if err != nil {
+			fmt.Printf("[Tool:get_peer_comparison] 错误: %v\n", err)
+			return GetPeerComparisonOutput{Errors: map[string]string{"service": err.Error()}}, nil
+		}
+		fmt.Printf("[Tool:get_peer_comparison] 调用完成, code=%s, peers=%d\n", code, len(data.Rows)-1)
+		return GetPeerComparisonOutput{Data: &data, Errors: data.Errors}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name: "get_peer_comparison",
+		Description: "构建同业对比矩阵（请直接引用计算结果）：同业可来自同行业、核心题材概念板块或自定义股票列表，" +
+			"逐只给出市盈率TTM/市净率、营收与净利同比、ROE/毛利率/净利率、主力净占比与5日主力净流入，" +
+			"并计算目标股在每项指标上的排名与百分位（0-100，越高越优，估值越低越优）及估值/成长/盈利/资金分组得分",
+	}, handler)
+}
@@ -24,6 +24,14 @@ type Registry struct {
 	f10Service            *services.F10Service
 	hotTrendService       *hottrend.HotTrendService
 	longHuBangService     *services.LongHuBangService
+	screenerService       *services.ScreenerService
+	sentimentService      *services.MarketSentimentService
+	consensusService      *services.ConsensusService
+	reportIndexService    *services.ReportIndexService
+	lhbSeatService        *services.LhbSeatService
+	northboundService     *services.NorthboundService
+	marginService         *services.MarginService
+	peerService           *services.PeerService
 	tools                 map[string]tool.Tool
 	toolInfos             map[string]ToolInfo // 工具信息映射
 }
@@ -108,6 +116,8 @@ func (r *Registry) registerAllTools() {
 	r.registerTool("get_f10_core_themes", "获取核心题材与所属板块数据，包含当前与历史题材", r.createF10CoreThemesTool)
 	r.registerTool("get_f10_industry_compare", "获取同行业估值与经营指标对比数据（PE/PB/PS/PCF/PEG与ROE、毛利率等）", r.createF10IndustryCompareTool)
 	r.registerTool("get_f10_main_indicators", "获取主要财务指标的年度与季度数据（核心指标、同比与环比）", r.createF10MainIndicatorsTool)
+	r.registerTool("get_financial_analysis", "获取由三表计算的财务分析：多期比率、杜邦拆解、TTM、同比环比与财务异常信号", r.createFinancialAnalysisTool)
+	r.registerTool("get_intrinsic_value", "计算内在价值：DCF、股利折现DDM、PE/PB历史分位估值带及敏感性表", r.createIntrinsicValueTool)
 
 	// 注册舆情热点工具
 	r.registerTool("get_hottrend", "获取全网舆情热点，支持微博、知乎、B站、百度、抖音、头条等平台的实时热搜榜单", r.createHotTrendTool)
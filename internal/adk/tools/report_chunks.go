@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/services"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// SearchReportChunksInput 研报片段检索输入
+type SearchReportChunksInput struct {
+	Query    string `json:"query" jsonschema:"检索问题或关键词，如 毛利率变化原因、产能规划、风险提示"`
+	Code     string `json:"code,omitempty" jsonschema:"股票代码，检索该股最近若干篇研报"`
+	InfoCode string `json:"infoCode,omitempty" jsonschema:"研报唯一标识码，提供时只检索该篇研报；请同时提供 code 以补全券商与日期"`
+	Reports  int    `json:"reports,omitempty" jsonschema:"按股票代码检索时使用的最近研报篇数，默认5，最大10"`
+	Limit    int    `json:"limit,omitempty" jsonschema:"返回片段数，默认6，最大15"`
+}
+
+// SearchReportChunksOutput 研报片段检索输出
+type SearchReportChunksOutput struct {
+	Hits   []models.ReportChunkHit `json:"hits"`
+	Errors map[string]string       `json:"errors,omitempty"`
+}
+
+// SetReportIndexService 设置研报索引服务并注册研报片段检索工具
+func (r *Registry) SetReportIndexService(reportIndexService *services.ReportIndexService) {
+	r.reportIndexService = reportIndexService
+	r.registerTool("search_report_chunks", "下载解析研报PDF并按问题检索相关段落，返回带券商/日期/页码引用的片段", r.createSearchReportChunksTool)
+}
+
+func (r *Registry) createSearchReportChunksTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input SearchReportChunksInput) (SearchReportChunksOutput, error) {
+		fmt.Printf("[Tool:search_report_chunks] 调用开始, query=%s, code=%s, infoCode=%s\n", input.Query, input.Code, input.InfoCode)
+		if r.reportIndexService == nil {
+			return SearchReportChunksOutput{Errors: map[string]string{"service": "研报索引服务未初始化"}}, nil
+		}
+
+		hits, errs := r.reportIndexService.Search(ctx, input.Code, input.InfoCode, input.Query, input.Reports, input.Limit)
+		output := SearchReportChunksOutput{Hits: hits}
+		if output.Hits == nil {
+			output.Hits = []models.ReportChunkHit{}
+		}
+		if len(errs) > 0 {
+			output.Errors = errs
+		}
+		fmt.Printf("[Tool:search_report_chunks] 调用完成, hits=%d, errors=%d\n", len(output.Hits), len(errs))
+		return output, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name: "search_report_chunks",
+		Description: "按问题检索券商研报PDF正文中最相关的段落，避免读取整篇研报。提供 code 时检索该股最近几篇研报，提供 infoCode 时只检索该篇；" +
+			"每个片段附 citation（券商 日期《标题》第N页），引用研报观点时请注明出处",
+	}, handler)
+}
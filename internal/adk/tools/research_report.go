@@ -68,10 +68,14 @@ type GetReportContentInput struct {
 
 // GetReportContentOutput 研报内容查询输出
 type GetReportContentOutput struct {
-	Content string `json:"content" jsonschema:"研报正文内容"`
-	PDFUrl  string `json:"pdfUrl" jsonschema:"PDF下载链接"`
+	Content   string `json:"content" jsonschema:"研报正文内容"`
+	PDFUrl    string `json:"pdfUrl" jsonschema:"PDF下载链接"`
+	Truncated bool   `json:"truncated,omitempty" jsonschema:"正文是否被截断"`
 }
 
+// reportContentMaxRunes 研报正文最大返回字数，超出部分应通过 search_report_chunks 按问题检索
+const reportContentMaxRunes = 3000
+
 // createReportContentTool 创建研报内容查询工具
 func (r *Registry) createReportContentTool() (tool.Tool, error) {
 	handler := func(ctx tool.Context, input GetReportContentInput) (GetReportContentOutput, error) {
@@ -90,14 +94,19 @@ func (r *Registry) createReportContentTool() (tool.Tool, error) {
 
 		fmt.Printf("[Tool:get_report_content] 调用完成, 内容长度=%d\n", len(result.Content))
 
-		return GetReportContentOutput{
+		output := GetReportContentOutput{
 			Content: result.Content,
 			PDFUrl:  result.PDFUrl,
-		}, nil
+		}
+		if runes := []rune(result.Content); len(runes) > reportContentMaxRunes {
+			output.Content = string(runes[:reportContentMaxRunes]) + "\n...（正文过长已截断，请使用 search_report_chunks 按问题检索相关段落）"
+			output.Truncated = true
+		}
+		return output, nil
 	}
 
 	return functiontool.New(functiontool.Config{
 		Name:        "get_report_content",
-		Description: "获取研报正文内容，需要先通过 get_research_report 获取研报列表中的 infoCode",
+		Description: "获取研报正文摘要内容（过长时截断），需要先通过 get_research_report 获取研报列表中的 infoCode；需要研报细节时优先使用 search_report_chunks",
 	}, handler)
 }
@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/services"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// ScreenStocksInput 条件选股输入
+type ScreenStocksInput struct {
+	Expression string `json:"expression,omitempty" jsonschema:"筛选表达式，如 pe < 20 and roe > 15 and main_net_5d > 0 and close > ma20；支持 and/or/not、括号、比较与四则运算"`
+	ScreenID   string `json:"screenId,omitempty" jsonschema:"已保存的选股方案ID，提供时忽略 expression"`
+	SortBy     string `json:"sortBy,omitempty" jsonschema:"排序字段，默认 amount(成交额)"`
+	SortDesc   bool   `json:"sortDesc,omitempty" jsonschema:"是否倒序排列"`
+	Limit      int    `json:"limit,omitempty" jsonschema:"返回数量，默认50，最大200"`
+}
+
+// ScreenStocksOutput 条件选股输出
+type ScreenStocksOutput struct {
+	Data   models.ScreenResult  `json:"data"`
+	Fields []models.ScreenField `json:"fields,omitempty"`
+	Errors map[string]string    `json:"errors,omitempty"`
+}
+
+// SetScreenerService 设置选股服务并注册选股工具
+func (r *Registry) SetScreenerService(screenerService *services.ScreenerService) {
+	r.screenerService = screenerService
+	r.registerTool("screen_stocks", "按条件表达式全市场选股（估值/财务/资金流/均线等），输出候选股列表", r.createScreenStocksTool)
+}
+
+func (r *Registry) createScreenStocksTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input ScreenStocksInput) (ScreenStocksOutput, error) {
+		fmt.Printf("[Tool:screen_stocks] 调用开始, expression=%s, screenId=%s\n", input.Expression, input.ScreenID)
+		if r.screenerService == nil {
+			return ScreenStocksOutput{Errors: map[string]string{"service": "选股服务未初始化"}}, nil
+		}
+
+		var (
+			data models.ScreenResult
+			err  error
+		)
+		if screenID := strings.TrimSpace(input.ScreenID); screenID != "" {
+			data, err = r.screenerService.RunScreen(ctx, screenID)
+		} else {
+			if strings.TrimSpace(input.Expression) == "" {
+				return ScreenStocksOutput{
+					Fields: r.screenerService.GetFields(),
+					Errors: map[string]string{"expression": "未提供筛选表达式，可用字段见 fields"},
+				}, nil
+			}
+			data, err = r.screenerService.Run(ctx, input.Expression, input.SortBy, input.SortDesc, input.Limit)
+		}
+		if err != nil {
+			fmt.Printf("[Tool:screen_stocks] 错误: %v\n", err)
+			return ScreenStocksOutput{
+				Data:   data,
+				Fields: r.screenerService.GetFields(),
+				Errors: map[string]string{"service": err.Error()},
+			}, nil
+		}
+		fmt.Printf("[Tool:screen_stocks] 调用完成, matched=%d, returned=%d\n", data.Matched, len(data.Items))
+		return ScreenStocksOutput{Data: data}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name: "screen_stocks",
+		Description: "按条件表达式全市场选股。可用字段：行情 price/change_pct/turnover/volume_ratio/amount(亿)/total_mv(亿)/float_mv(亿)/main_net(万)/main_net_ratio/change_60d/change_ytd；" +
+			"估值 pe(TTM)/pe_dynamic/pb；资金 main_net_5d/main_net_10d(万)；技术 close/ma5/ma10/ma20/ma60/ret_5d/ret_20d/high_20d/low_20d/vol_ratio_5d；" +
+			"财务 roe/gross_margin/net_margin/revenue_yoy/profit_yoy/debt_ratio/eps。百分比字段直接写数值，如 roe > 15",
+	}, handler)
+}
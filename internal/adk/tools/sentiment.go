@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/services"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// GetMarketSentimentInput 市场情绪输入
+type GetMarketSentimentInput struct {
+	Days int `json:"days,omitempty" jsonschema:"附带最近N个交易日的历史情绪序列，默认5，最大60，0表示使用默认值"`
+}
+
+// GetMarketSentimentOutput 市场情绪输出
+type GetMarketSentimentOutput struct {
+	Data    *models.MarketSentiment  `json:"data,omitempty"`
+	History []models.MarketSentiment `json:"history,omitempty"`
+	Errors  map[string]string        `json:"errors,omitempty"`
+}
+
+// SetSentimentService 设置市场情绪服务并注册情绪工具
+func (r *Registry) SetSentimentService(sentimentService *services.MarketSentimentService) {
+	r.sentimentService = sentimentService
+	r.registerTool("get_market_sentiment", "获取全市场宽度与情绪：涨跌家数、涨跌停、炸板率、连板梯队、新高新低、成交额与20日均值对比", r.createGetMarketSentimentTool)
+}
+
+func (r *Registry) createGetMarketSentimentTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetMarketSentimentInput) (GetMarketSentimentOutput, error) {
+		fmt.Printf("[Tool:get_market_sentiment] 调用开始, days=%d\n", input.Days)
+		if r.sentimentService == nil {
+			return GetMarketSentimentOutput{Errors: map[string]string{"service": "市场情绪服务未初始化"}}, nil
+		}
+
+		days := input.Days
+		if days <= 0 {
+			days = 5
+		}
+		if days > 60 {
+			days = 60
+		}
+
+		output := GetMarketSentimentOutput{History: r.sentimentService.GetHistory(days)}
+		data, err := r.sentimentService.GetSentiment()
+		if err != nil {
+			fmt.Printf("[Tool:get_market_sentiment] 错误: %v\n", err)
+			output.Errors = map[string]string{"sentiment": err.Error()}
+			return output, nil
+		}
+		output.Data = data
+		fmt.Printf("[Tool:get_market_sentiment] 调用完成, up=%d, down=%d, limitUp=%d\n", data.Up, data.Down, data.LimitUp)
+		return output, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name: "get_market_sentiment",
+		Description: "获取A股整体市场温度：上涨/下跌/平盘家数、涨停/跌停家数、炸板数与炸板率、连板梯队与最高连板高度、60日新高/新低家数、" +
+			"沪深两市成交额(亿)及其与前20日均值之比，并附带最近交易日的历史情绪序列",
+	}, handler)
+}
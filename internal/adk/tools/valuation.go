@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// GetIntrinsicValueInput 内在价值估算输入
+type GetIntrinsicValueInput struct {
+	Code           string  `json:"code" jsonschema:"股票代码，如 600519 或 sz000001"`
+	WACC           float64 `json:"wacc,omitempty" jsonschema:"DCF折现率(%)，默认9"`
+	Stage1Growth   float64 `json:"stage1_growth,omitempty" jsonschema:"DCF高速增长期自由现金流增速(%)，默认取TTM净利润增速并限制在-5~20"`
+	Stage1Years    int     `json:"stage1_years,omitempty" jsonschema:"DCF高速增长期年数，默认5，最大10"`
+	TerminalGrowth float64 `json:"terminal_growth,omitempty" jsonschema:"DCF永续增长率(%)，默认2.5"`
+	RequiredReturn float64 `json:"required_return,omitempty" jsonschema:"DDM要求回报率(%)，默认9"`
+	DividendGrowth float64 `json:"dividend_growth,omitempty" jsonschema:"DDM股息增长率(%)，默认3"`
+	Range          string  `json:"range,omitempty" jsonschema:"PE/PB估值带历史区间: 1y/3y/5y/10y，默认5y"`
+}
+
+// GetIntrinsicValueOutput 内在价值估算输出
+type GetIntrinsicValueOutput struct {
+	Data   *models.IntrinsicValuation `json:"data,omitempty"`
+	Errors map[string]string          `json:"errors,omitempty"`
+}
+
+func (r *Registry) createIntrinsicValueTool() (tool.Tool, error) {
+	handler := func(ctx tool.Context, input GetIntrinsicValueInput) (GetIntrinsicValueOutput, error) {
+		code := resolveStockCodeFromCandidates(ctx, input.Code)
+		fmt.Printf("[Tool:get_intrinsic_value] 调用开始, rawCode=%s, resolvedCode=%s, wacc=%.2f\n", input.Code, code, input.WACC)
+		if code == "" {
+			return GetIntrinsicValueOutput{Errors: map[string]string{"code": "未提供股票代码"}}, nil
+		}
+		if r.f10Service == nil {
+			return GetIntrinsicValueOutput{Errors: map[string]string{"service": "F10 服务未初始化"}}, nil
+		}
+
+		data, err := r.f10Service.GetIntrinsicValue(code, models.ValuationParams{
+			WACC:           input.WACC,
+			Stage1Growth:   input.Stage1Growth,
+			Stage1Years:    input.Stage1Years,
+			TerminalGrowth: input.TerminalGrowth,
+			RequiredReturn: input.RequiredReturn,
+			DividendGrowth: input.DividendGrowth,
+			Range:          input.Range,
+		})
+		if err != nil {
+			fmt.Printf("[Tool:get_intrinsic_value] 错误: %v\n", err)
+			return GetIntrinsicValueOutput{Errors: map[string]string{"service": err.Error()}}, nil
+		}
+		fmt.Printf("[Tool:get_intrinsic_value] 调用完成, code=%s, fairMid=%.2f\n", code, data.FairValueMid)
+		return GetIntrinsicValueOutput{Data: &data, Errors: data.Errors}, nil
+	}
+
+	return functiontool.New(functiontool.Config{
+		Name: "get_intrinsic_value",
+		Description: "计算个股内在价值（请直接引用计算结果，不要自行估算）：两阶段DCF（TTM自由现金流、可配置WACC/增速/永续增长率，含WACC×永续增长率敏感性表）、" +
+			"Gordon股利折现DDM（近12个月每股分红，含要求回报率×股息增长率敏感性表）、PE/PB历史分位估值带（10/25/50/75/90分位对应价格及当前分位），" +
+			"并汇总各模型合理价值区间与相对现价空间(%)。不适用的模型会在 notes 中说明原因",
+	}, handler)
+}
@@ -0,0 +1,159 @@
+package meeting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/run-bigpig/jcp/internal/adk"
+	"github.com/run-bigpig/jcp/internal/memory"
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/adk/agent"
+)
+
+// BoardRequest 板块会议请求
+type BoardRequest struct {
+	MemoryKey   string                   `json:"memoryKey"` // 板块记忆键（为空时不读写记忆）
+	Board       models.BoardFundFlowItem `json:"board"`
+	Query       string                   `json:"query"`
+	CoreContext string                   `json:"coreContext"` // 板块核心数据包（资金流、龙头、涨跌分布）
+	AllAgents   []models.AgentConfig     `json:"allAgents"`
+}
+
+// RunBoardMeetingWithCallback 板块会议
+// 以行业/概念板块为讨论对象，专家串行讨论轮动与龙头，小韭菜总结并写入板块记忆
+func (s *Service) RunBoardMeetingWithCallback(ctx context.Context, aiConfig *models.AIConfig, req BoardRequest, respCallback ResponseCallback, progressCallback ProgressCallback) ([]ChatResponse, error) {
+	if aiConfig == nil {
+		return nil, ErrNoAIConfig
+	}
+	if len(req.AllAgents) == 0 {
+		return nil, ErrNoAgents
+	}
+
+	meetingCtx, meetingCancel := context.WithTimeout(ctx, MeetingTimeout)
+	defer meetingCancel()
+
+	modelCtx, modelCancel := context.WithTimeout(meetingCtx, ModelCreationTimeout)
+	llm, err := s.modelFactory.CreateModel(modelCtx, aiConfig)
+	modelCancel()
+	if err != nil {
+		return nil, fmt.Errorf("create model error: %w", err)
+	}
+	moderator := s.createModerator(meetingCtx, llm)
+
+	// 加载板块记忆
+	var (
+		boardMemory   *memory.StockMemory
+		memoryContext string
+	)
+	if s.memoryManager != nil && req.MemoryKey != "" {
+		s.prepareMemoryLLM(meetingCtx, llm)
+		boardMemory, _ = s.memoryManager.GetOrCreate(req.MemoryKey, req.Board.Name)
+		memoryContext = s.memoryManager.BuildContext(boardMemory, req.Query)
+	}
+
+	log.Info("board: %s(%s), query: %s, agents: %d", req.Board.Name, req.Board.Code, req.Query, len(req.AllAgents))
+
+	emitProgress(progressCallback, ProgressEvent{
+		Type: "agent_start", AgentID: "moderator", AgentName: "小韭菜", Detail: "分析板块议题",
+	})
+	moderatorCtx, moderatorCancel := context.WithTimeout(meetingCtx, ModeratorTimeout)
+	decision, err := moderator.AnalyzeBoard(moderatorCtx, &req.Board, req.Query, req.AllAgents)
+	moderatorCancel()
+	emitProgress(progressCallback, ProgressEvent{
+		Type: "agent_done", AgentID: "moderator", AgentName: "小韭菜",
+	})
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: 小韭菜分析超时", ErrModeratorTimeout)
+		}
+		return nil, fmt.Errorf("moderator analyze board error: %w", err)
+	}
+
+	openingResp := ChatResponse{
+		AgentID:     "moderator",
+		AgentName:   "小韭菜",
+		Role:        "会议主持",
+		Content:     decision.Opening,
+		Round:       0,
+		MsgType:     "opening",
+		MeetingMode: MeetingModeBoard,
+	}
+	responses := []ChatResponse{openingResp}
+	if respCallback != nil {
+		respCallback(openingResp)
+	}
+
+	selectedAgents := s.filterAgentsOrdered(req.AllAgents, decision.Selected)
+	if len(selectedAgents) == 0 {
+		selectedAgents = s.fallbackAgents(req.AllAgents, 2)
+	}
+	assignments := make([]topicAssignment, 0, len(selectedAgents))
+	for _, agentCfg := range selectedAgents {
+		task := req.Query
+		if t, ok := decision.Tasks[agentCfg.ID]; ok && t != "" {
+			task = t
+		}
+		assignments = append(assignments, topicAssignment{Agent: agentCfg, Task: task})
+	}
+
+	factory := func(builder *adk.ExpertAgentBuilder, cfg *models.AgentConfig, task string, previousContext string) (agent.Agent, error) {
+		return builder.BuildBoardAgent(cfg, &req.Board, task, previousContext, req.CoreContext)
+	}
+	roundResponses, history, err := s.runTopicExperts(meetingCtx, aiConfig, assignments, MeetingModeBoard, memoryContext, factory, respCallback, progressCallback)
+	responses = append(responses, roundResponses...)
+	if err != nil {
+		return responses, err
+	}
+	if len(history) == 0 {
+		return responses, nil
+	}
+
+	emitProgress(progressCallback, ProgressEvent{
+		Type: "agent_start", AgentID: "moderator", AgentName: "小韭菜", Detail: "总结讨论",
+	})
+	summaryCtx, summaryCancel := context.WithTimeout(meetingCtx, ModeratorTimeout)
+	summary, err := moderator.SummarizeBoard(summaryCtx, &req.Board, req.Query, history)
+	summaryCancel()
+	emitProgress(progressCallback, ProgressEvent{
+		Type: "agent_done", AgentID: "moderator", AgentName: "小韭菜",
+	})
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			log.Warn("board summary timeout, returning partial results")
+		} else {
+			log.Error("board summary error: %v", err)
+		}
+		return responses, nil
+	}
+	if summary == "" {
+		return responses, nil
+	}
+
+	summaryResp := ChatResponse{
+		AgentID:     "moderator",
+		AgentName:   "小韭菜",
+		Role:        "会议主持",
+		Content:     summary,
+		Round:       summaryRound(history),
+		MsgType:     "summary",
+		MeetingMode: MeetingModeBoard,
+	}
+	responses = append(responses, summaryResp)
+	if respCallback != nil {
+		respCallback(summaryResp)
+	}
+
+	// 异步保存板块记忆
+	if s.memoryManager != nil && boardMemory != nil {
+		go func() {
+			bgCtx := context.Background()
+			keyPoints := s.extractKeyPointsFromHistory(bgCtx, history)
+			if err := s.memoryManager.AddRound(bgCtx, boardMemory, req.Query, summary, keyPoints); err != nil {
+				log.Error("save board memory error: %v", err)
+			}
+		}()
+	}
+	return responses, nil
+}
@@ -0,0 +1,220 @@
+package meeting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/run-bigpig/jcp/internal/adk"
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/adk/agent"
+)
+
+// 对比会议标的数量限制
+const (
+	MinCompareStocks = 2
+	MaxCompareStocks = 5
+)
+
+// ErrCompareStockCount 对比标的数量不合法
+var ErrCompareStockCount = fmt.Errorf("对比会议需要 %d-%d 只股票", MinCompareStocks, MaxCompareStocks)
+
+// CompareStock 对比会议中的单只股票
+type CompareStock struct {
+	StockCode   string                `json:"stockCode"`
+	Stock       models.Stock          `json:"stock"`
+	CoreContext string                `json:"coreContext"` // 该股票的核心数据包
+	Position    *models.StockPosition `json:"position"`    // 用户持仓信息
+}
+
+// CompareRequest 多股对比会议请求
+type CompareRequest struct {
+	Stocks    []CompareStock       `json:"stocks"`
+	Query     string               `json:"query"`
+	AllAgents []models.AgentConfig `json:"allAgents"`
+	// PeerMatrix 标的间的横向对比矩阵（估值/成长/盈利/资金百分位），可为空
+	PeerMatrix string `json:"peerMatrix,omitempty"`
+}
+
+// RunCompareMeetingWithCallback 多股对比会议
+// 小韭菜按维度分派专家，专家串行横向比较所有标的，最后输出排名对比表
+func (s *Service) RunCompareMeetingWithCallback(ctx context.Context, aiConfig *models.AIConfig, req CompareRequest, respCallback ResponseCallback, progressCallback ProgressCallback) ([]ChatResponse, error) {
+	if aiConfig == nil {
+		return nil, ErrNoAIConfig
+	}
+	if len(req.AllAgents) == 0 {
+		return nil, ErrNoAgents
+	}
+	if len(req.Stocks) < MinCompareStocks || len(req.Stocks) > MaxCompareStocks {
+		return nil, ErrCompareStockCount
+	}
+
+	meetingCtx, meetingCancel := context.WithTimeout(ctx, MeetingTimeout)
+	defer meetingCancel()
+
+	modelCtx, modelCancel := context.WithTimeout(meetingCtx, ModelCreationTimeout)
+	llm, err := s.modelFactory.CreateModel(modelCtx, aiConfig)
+	modelCancel()
+	if err != nil {
+		return nil, fmt.Errorf("create model error: %w", err)
+	}
+	moderator := s.createModerator(meetingCtx, llm)
+
+	stocks := make([]models.Stock, 0, len(req.Stocks))
+	for _, item := range req.Stocks {
+		stocks = append(stocks, item.Stock)
+	}
+	coreContext := buildCompareCoreContext(req.Stocks, req.PeerMatrix)
+
+	log.Info("compare: %d stocks, query: %s, agents: %d", len(stocks), req.Query, len(req.AllAgents))
+
+	// 第0轮：小韭菜拆解对比维度并分派专家
+	emitProgress(progressCallback, ProgressEvent{
+		Type: "agent_start", AgentID: "moderator", AgentName: "小韭菜", Detail: "拆解对比维度",
+	})
+	moderatorCtx, moderatorCancel := context.WithTimeout(meetingCtx, ModeratorTimeout)
+	decision, err := moderator.AnalyzeCompare(moderatorCtx, stocks, req.Query, req.AllAgents)
+	moderatorCancel()
+	emitProgress(progressCallback, ProgressEvent{
+		Type: "agent_done", AgentID: "moderator", AgentName: "小韭菜",
+	})
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: 小韭菜分析超时", ErrModeratorTimeout)
+		}
+		return nil, fmt.Errorf("moderator analyze compare error: %w", err)
+	}
+
+	openingResp := ChatResponse{
+		AgentID:     "moderator",
+		AgentName:   "小韭菜",
+		Role:        "会议主持",
+		Content:     decision.Opening,
+		Round:       0,
+		MsgType:     "opening",
+		MeetingMode: MeetingModeCompare,
+	}
+	responses := []ChatResponse{openingResp}
+	if respCallback != nil {
+		respCallback(openingResp)
+	}
+
+	dimensions, agents := s.resolveCompareDimensions(req.AllAgents, decision.Dimensions, req.Query)
+	if len(dimensions) == 0 {
+		return responses, nil
+	}
+
+	// 第1轮：各维度专家串行发言，后一个参考前面的内容
+	assignments := make([]topicAssignment, 0, len(dimensions))
+	for i, dim := range dimensions {
+		task := dim.Task
+		if dim.Name != "" {
+			task = fmt.Sprintf("【%s】%s", dim.Name, dim.Task)
+		}
+		assignments = append(assignments, topicAssignment{Agent: agents[i], Label: dim.Name, Task: task})
+	}
+	factory := func(builder *adk.ExpertAgentBuilder, cfg *models.AgentConfig, task string, previousContext string) (agent.Agent, error) {
+		return builder.BuildCompareAgent(cfg, stocks, task, previousContext, coreContext)
+	}
+	roundResponses, history, err := s.runTopicExperts(meetingCtx, aiConfig, assignments, MeetingModeCompare, "", factory, respCallback, progressCallback)
+	responses = append(responses, roundResponses...)
+	if err != nil {
+		return responses, err
+	}
+
+	if len(history) == 0 {
+		return responses, nil
+	}
+
+	// 最终轮：小韭菜输出排名对比表
+	emitProgress(progressCallback, ProgressEvent{
+		Type: "agent_start", AgentID: "moderator", AgentName: "小韭菜", Detail: "汇总对比结论",
+	})
+	summaryCtx, summaryCancel := context.WithTimeout(meetingCtx, ModeratorTimeout)
+	summary, err := moderator.SummarizeCompare(summaryCtx, stocks, req.Query, dimensions, history)
+	summaryCancel()
+	emitProgress(progressCallback, ProgressEvent{
+		Type: "agent_done", AgentID: "moderator", AgentName: "小韭菜",
+	})
+
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			log.Warn("compare summary timeout, returning partial results")
+		} else {
+			log.Error("compare summary error: %v", err)
+		}
+		return responses, nil
+	}
+
+	if summary != "" {
+		summaryResp := ChatResponse{
+			AgentID:     "moderator",
+			AgentName:   "小韭菜",
+			Role:        "会议主持",
+			Content:     summary,
+			Round:       summaryRound(history),
+			MsgType:     "summary",
+			MeetingMode: MeetingModeCompare,
+		}
+		responses = append(responses, summaryResp)
+		if respCallback != nil {
+			respCallback(summaryResp)
+		}
+	}
+	return responses, nil
+}
+
+// resolveCompareDimensions 将对比维度映射到可用专家，未分派时降级为默认专家
+func (s *Service) resolveCompareDimensions(all []models.AgentConfig, dimensions []CompareDimension, query string) ([]CompareDimension, []models.AgentConfig) {
+	agentMap := make(map[string]models.AgentConfig, len(all))
+	for _, a := range all {
+		agentMap[a.ID] = a
+	}
+
+	var (
+		resolved []CompareDimension
+		agents   []models.AgentConfig
+	)
+	for _, dim := range dimensions {
+		agent, ok := agentMap[dim.AgentID]
+		if !ok {
+			continue
+		}
+		if strings.TrimSpace(dim.Task) == "" {
+			dim.Task = query
+		}
+		resolved = append(resolved, dim)
+		agents = append(agents, agent)
+	}
+	if len(resolved) > 0 {
+		return resolved, agents
+	}
+
+	for _, agent := range s.fallbackAgents(all, 2) {
+		resolved = append(resolved, CompareDimension{Name: agent.Role, AgentID: agent.ID, Task: query})
+		agents = append(agents, agent)
+	}
+	return resolved, agents
+}
+
+// buildCompareCoreContext 合并各股票的核心数据包，并附加横向对比矩阵
+func buildCompareCoreContext(stocks []CompareStock, peerMatrix string) string {
+	var sections []string
+	for _, item := range stocks {
+		text := strings.TrimSpace(item.CoreContext)
+		if text == "" {
+			continue
+		}
+		title := item.Stock.Name
+		if title == "" {
+			title = item.StockCode
+		}
+		sections = append(sections, fmt.Sprintf("### %s（%s）\n%s", title, item.StockCode, text))
+	}
+	if matrix := strings.TrimSpace(peerMatrix); matrix != "" {
+		sections = append(sections, "### 横向对比矩阵\n"+matrix)
+	}
+	return strings.Join(sections, "\n\n")
+}
@@ -0,0 +1,64 @@
+package meeting
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestNormalizeCompareDecision(t *testing.T) {
+	agents := []models.AgentConfig{{ID: "value"}}
+	decision := &CompareDecision{Dimensions: []CompareDimension{
+		{Name: " 估值 ", AgentID: " value", Task: "比较估值"},
+		{Name: "资金", AgentID: " ", Task: "无效"},
+	}}
+	if err := normalizeCompareDecision(decision, agents); err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if len(decision.Dimensions) != 1 || decision.Dimensions[0].AgentID != "value" || decision.Dimensions[0].Name != "估值" {
+		t.Fatalf("unexpected dimensions: %+v", decision.Dimensions)
+	}
+
+	if err := normalizeCompareDecision(&CompareDecision{}, agents); err == nil {
+		t.Fatal("expected error when no dimension assigned")
+	}
+	ghost := &CompareDecision{Dimensions: []CompareDimension{{Name: "估值", AgentID: "ghost"}}}
+	if err := normalizeCompareDecision(ghost, agents); err == nil {
+		t.Fatal("expected error when no known agent assigned")
+	}
+}
+
+func TestResolveCompareDimensions(t *testing.T) {
+	s := &Service{}
+	all := []models.AgentConfig{
+		{ID: "value", Name: "价值", Role: "基本面", Enabled: true},
+		{ID: "tech", Name: "技术", Role: "技术面", Enabled: true},
+	}
+
+	dims, agents := s.resolveCompareDimensions(all, []CompareDimension{
+		{Name: "技术", AgentID: "tech"},
+		{Name: "未知", AgentID: "ghost", Task: "x"},
+	}, "谁更好")
+	if len(dims) != 1 || agents[0].ID != "tech" || dims[0].Task != "谁更好" {
+		t.Fatalf("unexpected resolve result: %+v %+v", dims, agents)
+	}
+
+	dims, agents = s.resolveCompareDimensions(all, nil, "谁更好")
+	if len(dims) != 2 || len(agents) != 2 {
+		t.Fatalf("expected fallback agents, got %+v", dims)
+	}
+}
+
+func TestBuildSummarizeComparePrompt(t *testing.T) {
+	m := &Moderator{}
+	prompt := m.buildSummarizeComparePrompt(
+		[]models.Stock{{Symbol: "sh600519", Name: "贵州茅台"}, {Symbol: "sz000858", Name: "五粮液"}},
+		"拿哪个",
+		[]CompareDimension{{Name: "估值"}, {Name: "资金"}},
+		nil,
+	)
+	if !strings.Contains(prompt, "| 排名 | 股票 | 估值 | 资金 | 综合结论 |") {
+		t.Fatalf("prompt missing ranking table header:\n%s", prompt)
+	}
+}
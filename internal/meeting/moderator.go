@@ -2,11 +2,11 @@ package meeting
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/run-bigpig/jcp/internal/adk/openai"
+	"github.com/run-bigpig/jcp/internal/adk/structured"
 	"github.com/run-bigpig/jcp/internal/models"
 
 	"google.golang.org/adk/model"
@@ -52,14 +52,92 @@ type DiscussionEntry struct {
 	Content   string `json:"content"`
 }
 
+// decisionOutput 决策的结构化输出格式，tasks 用数组表示以便各家 schema 约束
+type decisionOutput struct {
+	Intent   string      `json:"intent"`
+	Selected []string    `json:"selected"`
+	Tasks    []agentTask `json:"tasks"`
+	Topic    string      `json:"topic"`
+	Opening  string      `json:"opening"`
+}
+
+// agentTask 专家及其专属分析任务
+type agentTask struct {
+	AgentID string `json:"agentId"`
+	Task    string `json:"task"`
+}
+
+// decisionFormat 决策输出示例，与 decisionSchema 保持一致
+const decisionFormat = `{"intent":"意图","selected":["id1","id2"],"tasks":[{"agentId":"id1","task":"该专家需要分析的具体问题"},{"agentId":"id2","task":"该专家需要分析的具体问题"}],"topic":"议题","opening":"开场白"}`
+
+// decisionSchema 决策输出的 JSON Schema
+var decisionSchema = &genai.Schema{
+	Type: genai.TypeObject,
+	Properties: map[string]*genai.Schema{
+		"intent": {Type: genai.TypeString, Description: "老韭菜问题的核心意图"},
+		"selected": {
+			Type:        genai.TypeArray,
+			Description: "选中的专家 ID，按发言顺序",
+			Items:       &genai.Schema{Type: genai.TypeString},
+			MinItems:    genai.Ptr[int64](1),
+		},
+		"tasks": {
+			Type: genai.TypeArray,
+			Items: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"agentId": {Type: genai.TypeString},
+					"task":    {Type: genai.TypeString, Description: "该专家需要分析的具体问题"},
+				},
+				Required: []string{"agentId", "task"},
+			},
+		},
+		"topic":   {Type: genai.TypeString, Description: "讨论议题"},
+		"opening": {Type: genai.TypeString, Description: "开场白"},
+	},
+	Required:         []string{"intent", "selected", "tasks", "topic", "opening"},
+	PropertyOrdering: []string{"intent", "selected", "tasks", "topic", "opening"},
+}
+
 // Analyze 分析用户意图并选择专家
 func (m *Moderator) Analyze(ctx context.Context, stock *models.Stock, query string, agents []models.AgentConfig) (*ModeratorDecision, error) {
-	prompt := m.buildAnalyzePrompt(stock, query, agents)
-	content, err := m.generate(ctx, prompt)
+	decision, err := m.decide(ctx, m.buildAnalyzePrompt(stock, query, agents), agents)
 	if err != nil {
 		return nil, fmt.Errorf("moderator analyze error: %w", err)
 	}
-	return m.parseDecision(content)
+	return decision, nil
+}
+
+// decide 以结构化输出生成决策，选中的专家须至少有一位在可邀请列表中
+func (m *Moderator) decide(ctx context.Context, prompt string, agents []models.AgentConfig) (*ModeratorDecision, error) {
+	var out decisionOutput
+	check := func() error {
+		for _, id := range out.Selected {
+			for _, a := range agents {
+				if a.ID == strings.TrimSpace(id) {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("selected 中没有可邀请的专家 ID: %v", out.Selected)
+	}
+	if err := structured.Generate(ctx, m.llm, prompt, decisionSchema, &out, check); err != nil {
+		return nil, err
+	}
+
+	decision := &ModeratorDecision{
+		Intent:  out.Intent,
+		Topic:   out.Topic,
+		Opening: out.Opening,
+		Tasks:   make(map[string]string, len(out.Tasks)),
+	}
+	for _, id := range out.Selected {
+		decision.Selected = append(decision.Selected, strings.TrimSpace(id))
+	}
+	for _, t := range out.Tasks {
+		decision.Tasks[strings.TrimSpace(t.AgentID)] = t.Task
+	}
+	return decision, nil
 }
 
 // Summarize 总结讨论并给出结论
@@ -74,6 +152,81 @@ func (m *Moderator) SummarizeWithContext(ctx context.Context, stock *models.Stoc
 	return m.generate(ctx, prompt)
 }
 
+// CompareDimension 对比维度及负责专家
+type CompareDimension struct {
+	Name    string `json:"name"`    // 对比维度，如 估值、成长性、资金面
+	AgentID string `json:"agentId"` // 负责该维度的专家ID
+	Task    string `json:"task"`    // 该专家需要完成的对比任务
+}
+
+// CompareDecision 小韭菜对比会议决策结果
+type CompareDecision struct {
+	Intent     string             `json:"intent"`
+	Topic      string             `json:"topic"`
+	Opening    string             `json:"opening"`
+	Dimensions []CompareDimension `json:"dimensions"`
+}
+
+// compareDecisionFormat 对比决策输出示例，与 compareDecisionSchema 保持一致
+const compareDecisionFormat = `{"intent":"意图","topic":"议题","opening":"开场白","dimensions":[{"name":"维度","agentId":"id1","task":"该专家需要完成的对比任务"}]}`
+
+// compareDecisionSchema 对比决策输出的 JSON Schema
+var compareDecisionSchema = &genai.Schema{
+	Type: genai.TypeObject,
+	Properties: map[string]*genai.Schema{
+		"intent":  {Type: genai.TypeString, Description: "老韭菜问题的核心意图"},
+		"topic":   {Type: genai.TypeString, Description: "讨论议题"},
+		"opening": {Type: genai.TypeString, Description: "开场白"},
+		"dimensions": {
+			Type:     genai.TypeArray,
+			MinItems: genai.Ptr[int64](1),
+			Items: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"name":    {Type: genai.TypeString, Description: "对比维度，如 估值、成长性、资金面"},
+					"agentId": {Type: genai.TypeString, Description: "负责该维度的专家 ID"},
+					"task":    {Type: genai.TypeString, Description: "该专家需要完成的对比任务"},
+				},
+				Required: []string{"name", "agentId", "task"},
+			},
+		},
+	},
+	Required:         []string{"intent", "topic", "opening", "dimensions"},
+	PropertyOrdering: []string{"intent", "topic", "opening", "dimensions"},
+}
+
+// AnalyzeCompare 分析对比意图并按维度分派专家
+func (m *Moderator) AnalyzeCompare(ctx context.Context, stocks []models.Stock, query string, agents []models.AgentConfig) (*CompareDecision, error) {
+	prompt := m.buildAnalyzeComparePrompt(stocks, query, agents)
+	var decision CompareDecision
+	check := func() error { return normalizeCompareDecision(&decision, agents) }
+	if err := structured.Generate(ctx, m.llm, prompt, compareDecisionSchema, &decision, check); err != nil {
+		return nil, fmt.Errorf("moderator analyze compare error: %w", err)
+	}
+	return &decision, nil
+}
+
+// SummarizeCompare 总结对比讨论并输出排名对比表
+func (m *Moderator) SummarizeCompare(ctx context.Context, stocks []models.Stock, query string, dimensions []CompareDimension, history []DiscussionEntry) (string, error) {
+	prompt := m.buildSummarizeComparePrompt(stocks, query, dimensions, history)
+	return m.generate(ctx, prompt)
+}
+
+// AnalyzeBoard 分析板块议题并选择专家
+func (m *Moderator) AnalyzeBoard(ctx context.Context, board *models.BoardFundFlowItem, query string, agents []models.AgentConfig) (*ModeratorDecision, error) {
+	decision, err := m.decide(ctx, m.buildAnalyzeBoardPrompt(board, query, agents), agents)
+	if err != nil {
+		return nil, fmt.Errorf("moderator analyze board error: %w", err)
+	}
+	return decision, nil
+}
+
+// SummarizeBoard 总结板块讨论（轮动阶段、龙头梯队与操作建议）
+func (m *Moderator) SummarizeBoard(ctx context.Context, board *models.BoardFundFlowItem, query string, history []DiscussionEntry) (string, error) {
+	prompt := m.buildSummarizeBoardPrompt(board, query, history)
+	return m.generate(ctx, prompt)
+}
+
 // generate 调用 LLM 生成内容
 func (m *Moderator) generate(ctx context.Context, prompt string) (string, error) {
 	req := &model.LLMRequest{
@@ -119,6 +272,14 @@ func (m *Moderator) buildAnalyzePrompt(stock *models.Stock, query string, agents
 	sb.WriteString(fmt.Sprintf("2. 除非用户特别约束专家数量,否则选择 1-%d 位最相关的专家\n", len(agents)))
 	sb.WriteString("3. 为每位选中的专家制定一个明确的、与其专业匹配的分析任务（不要照搬用户原话，要根据专家角色拆解）\n")
 	sb.WriteString("4. 生成讨论议题和开场白\n\n")
+	m.writeSelectionStyle(&sb)
+	sb.WriteString("## 输出格式（仅输出JSON）\n")
+	sb.WriteString(decisionFormat)
+	return sb.String()
+}
+
+// writeSelectionStyle 写入选人风格说明
+func (m *Moderator) writeSelectionStyle(sb *strings.Builder) {
 	sb.WriteString("## 选人风格\n")
 	switch m.selectionStyle {
 	case models.AgentSelectionConservative:
@@ -128,9 +289,6 @@ func (m *Moderator) buildAnalyzePrompt(stock *models.Stock, query string, agents
 	default:
 		sb.WriteString("综合短中线视角，兼顾风险、基本面和交易节奏，默认推荐。\n\n")
 	}
-	sb.WriteString("## 输出格式（仅输出JSON）\n")
-	sb.WriteString(`{"intent":"意图","selected":["id1","id2"],"tasks":{"id1":"该专家需要分析的具体问题","id2":"该专家需要分析的具体问题"},"topic":"议题","opening":"开场白"}`)
-	return sb.String()
 }
 
 // buildSummarizePrompt 构建总结 Prompt
@@ -156,109 +314,136 @@ func (m *Moderator) buildSummarizePrompt(stock *models.Stock, query string, hist
 	return sb.String()
 }
 
-// parseDecision 解析小韭菜决策 JSON（增强健壮性）
-func (m *Moderator) parseDecision(content string) (*ModeratorDecision, error) {
-	content = strings.TrimSpace(content)
-
-	// 尝试多种方式提取 JSON
-	jsonStr := m.extractJSON(content)
-	if jsonStr == "" {
-		return nil, fmt.Errorf("无法从响应中提取 JSON: %s", truncateString(content, 200))
+// buildAnalyzeComparePrompt 构建对比意图分析 Prompt
+func (m *Moderator) buildAnalyzeComparePrompt(stocks []models.Stock, query string, agents []models.AgentConfig) string {
+	var sb strings.Builder
+	sb.WriteString("你是「财经会议室」的小韭菜，负责组织专家对多只股票进行横向对比。\n\n")
+	sb.WriteString("## 对比标的\n")
+	writeCompareStocks(&sb, stocks)
+	sb.WriteString("\n## 老韭菜问题\n")
+	sb.WriteString(query + "\n\n")
+	sb.WriteString("## 可邀请的专家\n")
+	for _, a := range agents {
+		fmt.Fprintf(&sb, "- %s（ID: %s）：%s\n", a.Name, a.ID, a.Role)
 	}
+	sb.WriteString("\n## 你的任务\n")
+	sb.WriteString("1. 分析老韭菜做对比的核心关注点\n")
+	sb.WriteString("2. 拆解出 2-5 个对比维度（如估值、成长性、盈利质量、资金面、技术面、风险）\n")
+	sb.WriteString("3. 为每个维度指派一位最匹配的专家，并写明该专家需要对所有标的完成的对比任务\n")
+	sb.WriteString("4. 生成讨论议题和开场白\n\n")
+	sb.WriteString("## 输出格式（仅输出JSON）\n")
+	sb.WriteString(compareDecisionFormat)
+	return sb.String()
+}
 
-	var decision ModeratorDecision
-	if err := json.Unmarshal([]byte(jsonStr), &decision); err != nil {
-		return nil, fmt.Errorf("JSON 解析失败: %w, 原文: %s", err, truncateString(jsonStr, 200))
+// buildSummarizeComparePrompt 构建对比总结 Prompt
+func (m *Moderator) buildSummarizeComparePrompt(stocks []models.Stock, query string, dimensions []CompareDimension, history []DiscussionEntry) string {
+	var sb strings.Builder
+	sb.WriteString("你是会议小韭菜，请汇总各维度的对比结论并给老韭菜排序建议。\n\n")
+	sb.WriteString("## 对比标的\n")
+	writeCompareStocks(&sb, stocks)
+	sb.WriteString("\n## 老韭菜问题\n")
+	sb.WriteString(query + "\n\n")
+	sb.WriteString("## 讨论记录\n")
+	for _, e := range history {
+		fmt.Fprintf(&sb, "【%s（%s）】\n%s\n\n", e.AgentName, e.Role, e.Content)
 	}
 
-	// 验证必要字段
-	if len(decision.Selected) == 0 {
-		return nil, fmt.Errorf("小韭菜未选择任何专家")
+	names := make([]string, 0, len(dimensions))
+	for _, d := range dimensions {
+		if d.Name != "" {
+			names = append(names, d.Name)
+		}
 	}
+	header := "| 排名 | 股票 | " + strings.Join(names, " | ")
+	if len(names) > 0 {
+		header += " | "
+	}
+	header += "综合结论 |"
 
-	return &decision, nil
+	sb.WriteString("## 输出要求\n")
+	sb.WriteString("1. 先输出 Markdown 排名对比表，按综合吸引力由高到低排序，表头如下：\n")
+	sb.WriteString(header + "\n")
+	sb.WriteString("   各维度单元格用一句话或评级（优/中/弱）概括\n")
+	sb.WriteString("2. 核心结论：直接回答老韭菜更倾向哪只，并说明理由\n")
+	sb.WriteString("3. 主要风险与切换条件\n\n")
+	sb.WriteString("表格之外的文字控制在 300 字以内。")
+	return sb.String()
 }
 
-// extractJSON 从文本中提取 JSON 对象
-func (m *Moderator) extractJSON(content string) string {
-	// 方法1: 尝试直接解析整个内容
-	content = strings.TrimSpace(content)
-	if strings.HasPrefix(content, "{") && strings.HasSuffix(content, "}") {
-		return content
-	}
-
-	// 方法2: 查找 ```json 代码块
-	if idx := strings.Index(content, "```json"); idx != -1 {
-		start := idx + 7
-		if end := strings.Index(content[start:], "```"); end != -1 {
-			return strings.TrimSpace(content[start : start+end])
-		}
+// buildAnalyzeBoardPrompt 构建板块议题分析 Prompt
+func (m *Moderator) buildAnalyzeBoardPrompt(board *models.BoardFundFlowItem, query string, agents []models.AgentConfig) string {
+	var sb strings.Builder
+	sb.WriteString("你是「财经会议室」的小韭菜，负责组织专家讨论一个行业/概念板块。\n\n")
+	sb.WriteString("## 当前板块\n")
+	fmt.Fprintf(&sb, "%s (%s)，涨跌幅 %.2f%%，主力净流入 %.2f亿\n\n", board.Name, board.Code, board.ChangePercent, board.MainNetInflow/1e8)
+	sb.WriteString("## 老韭菜问题\n")
+	sb.WriteString(query + "\n\n")
+	sb.WriteString("## 可邀请的专家\n")
+	for _, a := range agents {
+		fmt.Fprintf(&sb, "- %s（ID: %s）：%s\n", a.Name, a.ID, a.Role)
 	}
+	sb.WriteString("\n## 你的任务\n")
+	sb.WriteString("1. 分析老韭菜问题的核心意图\n")
+	sb.WriteString(fmt.Sprintf("2. 除非用户特别约束专家数量,否则选择 1-%d 位最相关的专家\n", len(agents)))
+	sb.WriteString("3. 为每位专家制定板块层面的分析任务，覆盖资金轮动、龙头与梯队、涨跌扩散度、持续性与风险等角度\n")
+	sb.WriteString("4. 生成讨论议题和开场白\n\n")
+	m.writeSelectionStyle(&sb)
+	sb.WriteString("## 输出格式（仅输出JSON）\n")
+	sb.WriteString(decisionFormat)
+	return sb.String()
+}
 
-	// 方法3: 查找 ``` 代码块
-	if idx := strings.Index(content, "```"); idx != -1 {
-		start := idx + 3
-		// 跳过可能的语言标识
-		if newline := strings.Index(content[start:], "\n"); newline != -1 {
-			start += newline + 1
-		}
-		if end := strings.Index(content[start:], "```"); end != -1 {
-			extracted := strings.TrimSpace(content[start : start+end])
-			if strings.HasPrefix(extracted, "{") {
-				return extracted
-			}
-		}
+// buildSummarizeBoardPrompt 构建板块总结 Prompt
+func (m *Moderator) buildSummarizeBoardPrompt(board *models.BoardFundFlowItem, query string, history []DiscussionEntry) string {
+	var sb strings.Builder
+	sb.WriteString("你是会议小韭菜，请总结板块讨论并给老韭菜结论。\n\n")
+	fmt.Fprintf(&sb, "## 板块：%s (%s)\n\n", board.Name, board.Code)
+	sb.WriteString("## 老韭菜问题\n")
+	sb.WriteString(query + "\n\n")
+	sb.WriteString("## 讨论记录\n")
+	for _, e := range history {
+		fmt.Fprintf(&sb, "【%s（%s）】\n%s\n\n", e.AgentName, e.Role, e.Content)
 	}
+	sb.WriteString("## 输出要求\n")
+	sb.WriteString("1. 板块所处阶段（启动/主升/分歧/退潮）及判断依据\n")
+	sb.WriteString("2. 龙头与梯队：点名核心标的及其角色\n")
+	sb.WriteString("3. 资金与扩散度：资金是否持续、上涨是否扩散\n")
+	sb.WriteString("4. 综合建议与需要警惕的信号\n\n")
+	sb.WriteString("控制在 400 字以内。")
+	return sb.String()
+}
 
-	// 方法4: 查找第一个完整的 JSON 对象（匹配括号）
-	start := strings.Index(content, "{")
-	if start == -1 {
-		return ""
+// writeCompareStocks 写入对比标的列表
+func writeCompareStocks(sb *strings.Builder, stocks []models.Stock) {
+	for _, stock := range stocks {
+		fmt.Fprintf(sb, "- %s (%s)，现价 %.2f，涨跌幅 %.2f%%\n", stock.Name, stock.Symbol, stock.Price, stock.ChangePercent)
 	}
+}
 
-	depth := 0
-	inString := false
-	escape := false
-
-	for i := start; i < len(content); i++ {
-		c := content[i]
-
-		if escape {
-			escape = false
-			continue
-		}
-
-		if c == '\\' && inString {
-			escape = true
-			continue
-		}
-
-		if c == '"' {
-			inString = !inString
-			continue
-		}
-
-		if inString {
+// normalizeCompareDecision 清理对比维度，须至少有一个维度分派给可邀请的专家
+func normalizeCompareDecision(decision *CompareDecision, agents []models.AgentConfig) error {
+	dimensions := decision.Dimensions[:0]
+	known := false
+	for _, d := range decision.Dimensions {
+		d.Name = strings.TrimSpace(d.Name)
+		d.AgentID = strings.TrimSpace(d.AgentID)
+		if d.AgentID == "" {
 			continue
 		}
-
-		if c == '{' {
-			depth++
-		} else if c == '}' {
-			depth--
-			if depth == 0 {
-				return content[start : i+1]
-			}
+		for _, a := range agents {
+			known = known || a.ID == d.AgentID
 		}
+		dimensions = append(dimensions, d)
 	}
-
-	// 方法5: 回退到简单的首尾匹配
-	end := strings.LastIndex(content, "}")
-	if end > start {
-		return content[start : end+1]
+	decision.Dimensions = dimensions
+	if len(decision.Dimensions) == 0 {
+		return fmt.Errorf("小韭菜未分派任何对比维度")
 	}
-
-	return ""
+	if !known {
+		return fmt.Errorf("dimensions 中没有可邀请的专家 ID")
+	}
+	return nil
 }
 
 // truncateString 截断字符串用于日志输出
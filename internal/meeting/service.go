@@ -108,6 +108,9 @@ func retryRun(ctx context.Context, maxRetries int, fn func() (string, error)) (s
 // 根据 AIConfigID 返回对应的 AI 配置，如果 ID 为空或找不到则返回默认配置
 type AIConfigResolver func(aiConfigID string) *models.AIConfig
 
+// ChartProvider K线图提供者，返回股票日K线图 PNG，供支持图像输入的专家使用
+type ChartProvider func(stockCode string) ([]byte, error)
+
 // MeetingState 中断的会议状态缓存（用于失败后恢复继续执行）
 type MeetingState struct {
 	AIConfig       *models.AIConfig
@@ -136,6 +139,7 @@ type Service struct {
 	memoryAIConfig    *models.AIConfig // 记忆管理使用的 LLM 配置
 	moderatorAIConfig *models.AIConfig // 意图分析(小韭菜)使用的 LLM 配置
 	aiConfigResolver  AIConfigResolver // AI配置解析器
+	chartProvider     ChartProvider    // K线图提供者（可选）
 	retryCount        int
 	verboseAgentIO    bool
 	selectionStyle    models.AgentSelectionStyle
@@ -178,6 +182,11 @@ func (s *Service) SetAIConfigResolver(resolver AIConfigResolver) {
 	s.aiConfigResolver = resolver
 }
 
+// SetChartProvider 设置K线图提供者
+func (s *Service) SetChartProvider(provider ChartProvider) {
+	s.chartProvider = provider
+}
+
 // SetRetryCount 设置 AI 请求重试次数（1-5，超出范围自动收敛）
 func (s *Service) SetRetryCount(count int) {
 	if count < 1 {
@@ -224,8 +233,10 @@ type ChatRequest struct {
 
 // 会议模式常量
 const (
-	MeetingModeSmart  = "smart"  // 串行智能模式（小韭菜编排）
-	MeetingModeDirect = "direct" // 独立模式（@ 指定专家）
+	MeetingModeSmart   = "smart"   // 串行智能模式（小韭菜编排）
+	MeetingModeDirect  = "direct"  // 独立模式（@ 指定专家）
+	MeetingModeCompare = "compare" // 多股对比模式（按维度分派专家）
+	MeetingModeBoard   = "board"   // 板块模式（行业/概念板块）
 )
 
 // ChatResponse 聊天响应
@@ -237,7 +248,7 @@ type ChatResponse struct {
 	Round       int    `json:"round"`
 	MsgType     string `json:"msgType"`               // opening/opinion/summary
 	Error       string `json:"error,omitempty"`       // 失败时的错误信息，前端据此显示重试按钮
-	MeetingMode string `json:"meetingMode,omitempty"` // smart=串行, direct=独立
+	MeetingMode string `json:"meetingMode,omitempty"` // smart=串行, direct=独立, compare=多股对比, board=板块
 }
 
 // ResponseCallback 响应回调函数类型
@@ -246,7 +257,7 @@ type ResponseCallback func(resp ChatResponse)
 
 // ProgressEvent 进度事件（细粒度实时反馈）
 type ProgressEvent struct {
-	Type      string `json:"type"`      // thinking/tool_call/tool_result/streaming/agent_start/agent_done
+	Type      string `json:"type"`      // thinking/tool_call/tool_result/streaming/agent_start/agent_done/quota_wait/quota_ready
 	AgentID   string `json:"agentId"`   // 当前专家 ID
 	AgentName string `json:"agentName"` // 当前专家名称
 	Detail    string `json:"detail"`    // 工具名称或阶段描述
@@ -263,6 +274,25 @@ func emitProgress(cb ProgressCallback, event ProgressEvent) {
 	}
 }
 
+// quotaReasonLabels 配额等待原因的展示文案
+var quotaReasonLabels = map[string]string{
+	adk.QuotaReasonConcurrency: "并发已满",
+	adk.QuotaReasonRPM:         "每分钟请求数已满",
+	adk.QuotaReasonTPM:         "每分钟 token 已满",
+}
+
+// quotaProgressEvent 将配额排队事件转换为进度事件（quota_wait / quota_ready）
+func quotaProgressEvent(cfg *models.AgentConfig, e adk.QuotaEvent) ProgressEvent {
+	if !e.Waiting {
+		return ProgressEvent{Type: "quota_ready", AgentID: cfg.ID, AgentName: cfg.Name, Detail: e.ConfigName}
+	}
+	detail := fmt.Sprintf("等待配额：%s %s", e.ConfigName, quotaReasonLabels[e.Reason])
+	if e.WaitMs > 0 {
+		detail += fmt.Sprintf("，约 %d 秒", (e.WaitMs+999)/1000)
+	}
+	return ProgressEvent{Type: "quota_wait", AgentID: cfg.ID, AgentName: cfg.Name, Detail: detail, Content: e.ConfigName}
+}
+
 func finalizeAgentContent(partialText string, finalText string, sawPartial bool) (string, error) {
 	partialText = openai.FilterVendorToolCallMarkers(partialText)
 	finalText = openai.FilterVendorToolCallMarkers(finalText)
@@ -483,38 +513,10 @@ func (s *Service) RunSmartMeetingWithCallback(ctx context.Context, aiConfig *mod
 
 	var responses []ChatResponse
 
-	// 创建 Moderator LLM（优先使用独立配置）
-	var moderatorLLM model.LLM
-	if s.moderatorAIConfig != nil {
-		moderatorLLM, err = s.modelFactory.CreateModel(meetingCtx, s.moderatorAIConfig)
-		if err != nil {
-			log.Warn("create moderator LLM error, fallback to default: %v", err)
-			moderatorLLM = llm
-		} else {
-			log.Debug("using dedicated moderator LLM: %s", s.moderatorAIConfig.ModelName)
-		}
-	} else {
-		moderatorLLM = llm
-	}
-	moderator := NewModerator(moderatorLLM)
-	moderator.SetSelectionStyle(s.selectionStyle)
+	moderator := s.createModerator(meetingCtx, llm)
 
 	// 设置 LLM 到记忆管理器（启用摘要功能）
-	if s.memoryManager != nil {
-		// 优先使用配置的记忆 LLM，否则使用会议 LLM
-		if s.memoryAIConfig != nil {
-			memoryLLM, err := s.modelFactory.CreateModel(meetingCtx, s.memoryAIConfig)
-			if err == nil {
-				s.memoryManager.SetLLM(memoryLLM)
-				log.Debug("using dedicated memory LLM: %s", s.memoryAIConfig.ModelName)
-			} else {
-				log.Warn("create memory LLM error, fallback to meeting LLM: %v", err)
-				s.memoryManager.SetLLM(llm)
-			}
-		} else {
-			s.memoryManager.SetLLM(llm)
-		}
-	}
+	s.prepareMemoryLLM(meetingCtx, llm)
 
 	// 加载股票记忆（如果启用了记忆管理）
 	var stockMemory *memory.StockMemory
@@ -802,6 +804,40 @@ func (s *Service) RunSmartMeetingWithCallback(ctx context.Context, aiConfig *mod
 	return responses, nil
 }
 
+// createModerator 创建小韭菜（优先使用独立配置的 Moderator LLM）
+func (s *Service) createModerator(ctx context.Context, defaultLLM model.LLM) *Moderator {
+	moderatorLLM := defaultLLM
+	if s.moderatorAIConfig != nil {
+		dedicated, err := s.modelFactory.CreateModel(ctx, s.moderatorAIConfig)
+		if err != nil {
+			log.Warn("create moderator LLM error, fallback to default: %v", err)
+		} else {
+			moderatorLLM = dedicated
+			log.Debug("using dedicated moderator LLM: %s", s.moderatorAIConfig.ModelName)
+		}
+	}
+	moderator := NewModerator(moderatorLLM)
+	moderator.SetSelectionStyle(s.selectionStyle)
+	return moderator
+}
+
+// prepareMemoryLLM 设置记忆管理器的 LLM（优先使用配置的记忆 LLM，否则使用会议 LLM）
+func (s *Service) prepareMemoryLLM(ctx context.Context, meetingLLM model.LLM) {
+	if s.memoryManager == nil {
+		return
+	}
+	if s.memoryAIConfig != nil {
+		memoryLLM, err := s.modelFactory.CreateModel(ctx, s.memoryAIConfig)
+		if err == nil {
+			s.memoryManager.SetLLM(memoryLLM)
+			log.Debug("using dedicated memory LLM: %s", s.memoryAIConfig.ModelName)
+			return
+		}
+		log.Warn("create memory LLM error, fallback to meeting LLM: %v", err)
+	}
+	s.memoryManager.SetLLM(meetingLLM)
+}
+
 // runAgentsParallel 并行运行多个 Agent（带超时控制）
 func (s *Service) runAgentsParallel(ctx context.Context, defaultLLM model.LLM, defaultAIConfig *models.AIConfig, req ChatRequest) ([]ChatResponse, error) {
 	var (
@@ -894,7 +930,25 @@ func (s *Service) runSingleAgent(
 	if err != nil {
 		return "", err
 	}
+	return s.runAgentInstance(ctx, agentInstance, cfg, query, progressCallback, s.chartParts(cfg, stock)...)
+}
 
+// chartParts 为支持图像输入的专家生成K线图附件，失败时降级为纯文本
+func (s *Service) chartParts(cfg *models.AgentConfig, stock *models.Stock) []*genai.Part {
+	if !cfg.Vision || s.chartProvider == nil || stock == nil || stock.Symbol == "" {
+		return nil
+	}
+	data, err := s.chartProvider(stock.Symbol)
+	if err != nil {
+		log.Warn("render chart for %s error: %v", stock.Symbol, err)
+		return nil
+	}
+	caption := fmt.Sprintf("附图：%s(%s) 日K线图，含成交量及已启用的技术指标，红涨绿跌。", stock.Name, stock.Symbol)
+	return []*genai.Part{genai.NewPartFromText(caption), genai.NewPartFromBytes(data, "image/png")}
+}
+
+// runAgentInstance 运行已构建的 Agent 并收集输出，attachments 附加在用户消息之后（如K线图）
+func (s *Service) runAgentInstance(ctx context.Context, agentInstance agent.Agent, cfg *models.AgentConfig, query string, progressCallback ProgressCallback, attachments ...*genai.Part) (string, error) {
 	sessionService := session.InMemoryService()
 	r, err := runner.New(runner.Config{
 		AppName:        "jcp",
@@ -916,18 +970,22 @@ func (s *Service) runSingleAgent(
 
 	userMsg := &genai.Content{
 		Role:  "user",
-		Parts: []*genai.Part{genai.NewPartFromText(query)},
+		Parts: append([]*genai.Part{genai.NewPartFromText(query)}, attachments...),
 	}
 
 	// 有 progressCallback 时启用 streaming，否则普通模式
 	runCfg := agent.RunConfig{}
 	if progressCallback != nil {
 		runCfg.StreamingMode = agent.StreamingModeSSE
+		ctx = adk.WithQuotaNotifier(ctx, func(e adk.QuotaEvent) {
+			emitProgress(progressCallback, quotaProgressEvent(cfg, e))
+		})
 	}
 
 	var partialText strings.Builder
 	var finalText strings.Builder
 	sawPartial := false
+	sawPartialThought := false
 	for event, err := range r.Run(ctx, "user", sessionID, userMsg, runCfg) {
 		if err != nil {
 			return "", err
@@ -936,7 +994,15 @@ func (s *Service) runSingleAgent(
 			continue
 		}
 		for _, part := range event.LLMResponse.Content.Parts {
+			// 思考内容只作为进度事件展示，不计入发言
 			if part.Thought {
+				if progressCallback != nil && part.Text != "" && (event.LLMResponse.Partial || !sawPartialThought) {
+					sawPartialThought = sawPartialThought || event.LLMResponse.Partial
+					progressCallback(ProgressEvent{
+						Type: "thinking", AgentID: cfg.ID, AgentName: cfg.Name,
+						Content: part.Text,
+					})
+				}
 				continue
 			}
 			if part.FunctionCall != nil && progressCallback != nil {
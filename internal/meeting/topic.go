@@ -0,0 +1,130 @@
+package meeting
+
+import (
+	"context"
+
+	"github.com/run-bigpig/jcp/internal/adk"
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/adk/agent"
+)
+
+// topicAssignment 主题会议（多股对比、板块）中的专家分派
+type topicAssignment struct {
+	Agent models.AgentConfig
+	Label string // 维度或职责标签，为空时使用专家角色
+	Task  string
+}
+
+// topicAgentFactory 按任务与前序发言构建专家 Agent
+type topicAgentFactory func(builder *adk.ExpertAgentBuilder, cfg *models.AgentConfig, task string, previousContext string) (agent.Agent, error)
+
+// runTopicExperts 串行运行主题会议专家，后一个参考前面的发言
+// 单个专家失败只记录错误响应，不中断其他专家；会议超时返回 ErrMeetingTimeout
+func (s *Service) runTopicExperts(
+	ctx context.Context,
+	aiConfig *models.AIConfig,
+	assignments []topicAssignment,
+	meetingMode string,
+	memoryContext string,
+	factory topicAgentFactory,
+	respCallback ResponseCallback,
+	progressCallback ProgressCallback,
+) ([]ChatResponse, []DiscussionEntry, error) {
+	var (
+		responses []ChatResponse
+		history   []DiscussionEntry
+	)
+	emit := func(resp ChatResponse) {
+		responses = append(responses, resp)
+		if respCallback != nil {
+			respCallback(resp)
+		}
+	}
+
+	for _, item := range assignments {
+		select {
+		case <-ctx.Done():
+			log.Warn("%s meeting timeout, got %d responses", meetingMode, len(responses))
+			return responses, history, ErrMeetingTimeout
+		default:
+		}
+
+		agentCfg := item.Agent
+		agentAIConfig := s.resolveAgentAIConfig(&agentCfg, aiConfig)
+		agentLLM, err := s.modelFactory.CreateModel(ctx, agentAIConfig)
+		if err != nil {
+			log.Error("create agent LLM error: %v", err)
+			continue
+		}
+		builder := s.createBuilder(agentLLM, agentAIConfig)
+
+		label := item.Label
+		if label == "" {
+			label = agentCfg.Role
+		}
+		emitProgress(progressCallback, ProgressEvent{
+			Type: "agent_start", AgentID: agentCfg.ID, AgentName: agentCfg.Name, Detail: label,
+		})
+
+		previousContext := s.buildPreviousContext(history)
+		if memoryContext != "" {
+			previousContext = memoryContext + "\n" + previousContext
+		}
+		content, err := retryRun(ctx, s.retryCount, func() (string, error) {
+			agentCtx, agentCancel := context.WithTimeout(ctx, AgentTimeout)
+			defer agentCancel()
+			agentInstance, err := factory(builder, &agentCfg, item.Task, previousContext)
+			if err != nil {
+				return "", err
+			}
+			return s.runAgentInstance(agentCtx, agentInstance, &agentCfg, item.Task, progressCallback)
+		})
+
+		if err != nil {
+			emitProgress(progressCallback, ProgressEvent{
+				Type: "agent_error", AgentID: agentCfg.ID, AgentName: agentCfg.Name, Detail: err.Error(),
+			})
+			emitProgress(progressCallback, ProgressEvent{
+				Type: "agent_done", AgentID: agentCfg.ID, AgentName: agentCfg.Name,
+			})
+			log.Error("%s agent %s failed after retries: %v", meetingMode, agentCfg.ID, err)
+			emit(ChatResponse{
+				AgentID:     agentCfg.ID,
+				AgentName:   agentCfg.Name,
+				Role:        agentCfg.Role,
+				Round:       1,
+				MsgType:     "opinion",
+				Error:       err.Error(),
+				MeetingMode: meetingMode,
+			})
+			continue
+		}
+
+		emitProgress(progressCallback, ProgressEvent{
+			Type: "agent_done", AgentID: agentCfg.ID, AgentName: agentCfg.Name,
+		})
+		emit(ChatResponse{
+			AgentID:     agentCfg.ID,
+			AgentName:   agentCfg.Name,
+			Role:        agentCfg.Role,
+			Content:     content,
+			Round:       1,
+			MsgType:     "opinion",
+			MeetingMode: meetingMode,
+		})
+
+		role := agentCfg.Role
+		if item.Label != "" && item.Label != agentCfg.Role {
+			role = item.Label + "·" + agentCfg.Role
+		}
+		history = append(history, DiscussionEntry{
+			Round:     1,
+			AgentID:   agentCfg.ID,
+			AgentName: agentCfg.Name,
+			Role:      role,
+			Content:   content,
+		})
+	}
+	return responses, history, nil
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"google.golang.org/adk/model"
 )
 
@@ -224,6 +225,32 @@ func (m *Manager) ExtractAndAddFacts(ctx context.Context, mem *StockMemory, cont
 	return nil
 }
 
+// AddDatedFact 向股票记忆写入一条带日期的事实并保存
+func (m *Manager) AddDatedFact(stockCode, stockName, date, content, source string, weight float64) error {
+	mem, err := m.GetOrCreate(stockCode, stockName)
+	if err != nil {
+		return err
+	}
+	if date != "" {
+		content = fmt.Sprintf("[%s] %s", date, content)
+	}
+	for _, fact := range mem.KeyFacts {
+		if fact.Content == content {
+			return nil
+		}
+	}
+	m.AddFacts(mem, []MemoryEntry{{
+		ID:        uuid.New().String(),
+		Type:      EntryTypeFact,
+		Content:   content,
+		Source:    source,
+		Keywords:  m.tokenizer.Extract(content, 5),
+		Timestamp: time.Now().UnixMilli(),
+		Weight:    weight,
+	}})
+	return m.Save(mem)
+}
+
 // ExtractKeyPoints 智能提取讨论关键点
 func (m *Manager) ExtractKeyPoints(ctx context.Context, discussions []DiscussionInput) ([]string, error) {
 	if m.summarizer == nil {
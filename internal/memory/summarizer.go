@@ -2,12 +2,12 @@ package memory
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/run-bigpig/jcp/internal/adk/structured"
 	"google.golang.org/adk/model"
 	"google.golang.org/genai"
 )
@@ -98,14 +98,47 @@ func (s *LLMSummarizer) buildSummarizePrompt(rounds []RoundMemory) string {
 	return sb.String()
 }
 
+// factsOutput 事实提取的结构化输出
+type factsOutput struct {
+	Facts []struct {
+		Content string  `json:"content"`
+		Type    string  `json:"type"`
+		Weight  float64 `json:"weight"`
+	} `json:"facts"`
+}
+
+// factsSchema 事实提取输出的 JSON Schema（根节点须为对象）
+var factsSchema = &genai.Schema{
+	Type: genai.TypeObject,
+	Properties: map[string]*genai.Schema{
+		"facts": {
+			Type:     genai.TypeArray,
+			MaxItems: genai.Ptr[int64](5),
+			Items: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"content": {Type: genai.TypeString, Description: "事实内容，不超过50字"},
+					"type": {
+						Type: genai.TypeString,
+						Enum: []string{string(EntryTypeFact), string(EntryTypeOpinion), string(EntryTypeDecision)},
+					},
+					"weight": {Type: genai.TypeNumber, Minimum: genai.Ptr(0.0), Maximum: genai.Ptr(1.0)},
+				},
+				Required:         []string{"content", "type", "weight"},
+				PropertyOrdering: []string{"content", "type", "weight"},
+			},
+		},
+	},
+	Required: []string{"facts"},
+}
+
 // ExtractFacts 从讨论内容中提取关键事实
 func (s *LLMSummarizer) ExtractFacts(ctx context.Context, content, agentName string) ([]MemoryEntry, error) {
-	prompt := s.buildExtractPrompt(content)
-	result, err := s.generate(ctx, prompt)
-	if err != nil {
-		return nil, err
+	var out factsOutput
+	if err := structured.Generate(ctx, s.llm, s.buildExtractPrompt(content), factsSchema, &out, nil); err != nil {
+		return nil, fmt.Errorf("extract facts error: %w", err)
 	}
-	return s.parseFacts(result, agentName)
+	return s.toEntries(out, agentName), nil
 }
 
 func (s *LLMSummarizer) buildExtractPrompt(content string) string {
@@ -114,35 +147,18 @@ func (s *LLMSummarizer) buildExtractPrompt(content string) string {
 内容：
 %s
 
-请以JSON数组格式输出，每个事实包含：
+请以JSON对象格式输出，facts 数组中每个事实包含：
 - content: 事实内容（简洁，不超过50字）
 - type: 类型（fact/opinion/decision）
 - weight: 重要性 0-1
 
-只输出JSON数组，不要其他内容：`, content)
+只输出JSON对象，不要其他内容，例如：{"facts":[{"content":"...","type":"fact","weight":0.8}]}`, content)
 }
 
-func (s *LLMSummarizer) parseFacts(jsonStr, source string) ([]MemoryEntry, error) {
-	// 清理 JSON
-	jsonStr = strings.TrimSpace(jsonStr)
-	jsonStr = strings.TrimPrefix(jsonStr, "```json")
-	jsonStr = strings.TrimPrefix(jsonStr, "```")
-	jsonStr = strings.TrimSuffix(jsonStr, "```")
-	jsonStr = strings.TrimSpace(jsonStr)
-
-	var raw []struct {
-		Content string    `json:"content"`
-		Type    string    `json:"type"`
-		Weight  float64   `json:"weight"`
-	}
-
-	if err := json.Unmarshal([]byte(jsonStr), &raw); err != nil {
-		return nil, fmt.Errorf("parse facts json error: %w", err)
-	}
-
+func (s *LLMSummarizer) toEntries(out factsOutput, source string) []MemoryEntry {
 	now := time.Now().UnixMilli()
-	entries := make([]MemoryEntry, 0, len(raw))
-	for _, r := range raw {
+	entries := make([]MemoryEntry, 0, len(out.Facts))
+	for _, r := range out.Facts {
 		// 使用分词器提取关键词
 		keywords := s.tokenizer.Extract(r.Content, 5)
 
@@ -156,7 +172,7 @@ func (s *LLMSummarizer) parseFacts(jsonStr, source string) ([]MemoryEntry, error
 			Weight:    r.Weight,
 		})
 	}
-	return entries, nil
+	return entries
 }
 
 // ExtractKeyPoints 从讨论中智能提取关键点
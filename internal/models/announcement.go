@@ -0,0 +1,16 @@
+package models
+
+// AnnouncementEvent 自选股新公告事件
+type AnnouncementEvent struct {
+	ID           string `json:"id"` // 公告编号 art_code
+	StockCode    string `json:"stockCode"`
+	StockName    string `json:"stockName"`
+	Title        string `json:"title"`
+	NoticeDate   string `json:"noticeDate"`
+	Category     string `json:"category"`     // earnings/buyback/pledge/reduction/litigation/contract/st_risk/other
+	CategoryName string `json:"categoryName"` // 分类中文名
+	Severity     string `json:"severity"`     // high/medium/low
+	Source       string `json:"source"`       // 分类来源: rule/llm
+	URL          string `json:"url"`
+	DetectedAt   int64  `json:"detectedAt"`
+}
@@ -0,0 +1,52 @@
+package models
+
+// NorthboundDay 北向资金单日持股
+type NorthboundDay struct {
+	TradeDate        string  `json:"tradeDate"`
+	ClosePrice       float64 `json:"closePrice"`
+	ChangePercent    float64 `json:"changePercent"`
+	HoldShares       float64 `json:"holdShares"`       // 持股数量(股)
+	HoldMarketCap    float64 `json:"holdMarketCap"`    // 持股市值(元)
+	FreeSharesRatio  float64 `json:"freeSharesRatio"`  // 占流通股比(%)
+	TotalSharesRatio float64 `json:"totalSharesRatio"` // 占总股本比(%)
+	ShareChange      float64 `json:"shareChange"`      // 较上一披露日持股变化(股)
+	MarketCapChange  float64 `json:"marketCapChange"`  // 持股变化按收盘价折算的金额(元)
+}
+
+// NorthboundHoldings 个股北向资金持股序列（按日期倒序）
+type NorthboundHoldings struct {
+	Code      string          `json:"code"`
+	Name      string          `json:"name"`
+	Items     []NorthboundDay `json:"items"`
+	Change5D  *float64        `json:"change5d,omitempty"`  // 近一周持股变化(股)，披露间隔过长时为空
+	Change20D *float64        `json:"change20d,omitempty"` // 近四周持股变化(股)，披露间隔过长时为空
+	Stale     bool            `json:"stale,omitempty"`     // 最新披露距今超过一周（2024年8月后改为定期披露）
+	UpdatedAt string          `json:"updatedAt"`
+}
+
+// MarginDay 融资融券单日数据
+type MarginDay struct {
+	TradeDate       string  `json:"tradeDate"`
+	ClosePrice      float64 `json:"closePrice"`
+	ChangePercent   float64 `json:"changePercent"`
+	FinBalance      float64 `json:"finBalance"`      // 融资余额(元)
+	FinBuy          float64 `json:"finBuy"`          // 融资买入额(元)
+	FinRepay        float64 `json:"finRepay"`        // 融资偿还额(元)
+	FinNetBuy       float64 `json:"finNetBuy"`       // 融资净买入(元)
+	SecBalance      float64 `json:"secBalance"`      // 融券余额(元)
+	SecVolume       float64 `json:"secVolume"`       // 融券余量(股)
+	SecSell         float64 `json:"secSell"`         // 融券卖出量(股)
+	SecRepay        float64 `json:"secRepay"`        // 融券偿还量(股)
+	TotalBalance    float64 `json:"totalBalance"`    // 融资融券余额(元)
+	FinBalanceRatio float64 `json:"finBalanceRatio"` // 融资余额占流通市值比(%)
+}
+
+// MarginTrading 个股融资融券序列（按日期倒序）
+type MarginTrading struct {
+	Code         string      `json:"code"`
+	Name         string      `json:"name"`
+	Items        []MarginDay `json:"items"`
+	FinChange5D  float64     `json:"finChange5d"`  // 近5日融资余额变化(元)
+	FinChange20D float64     `json:"finChange20d"` // 近20日融资余额变化(元)
+	UpdatedAt    string      `json:"updatedAt"`
+}
@@ -8,8 +8,38 @@ const (
 	AIProviderGemini    AIProvider = "gemini"
 	AIProviderVertexAI  AIProvider = "vertexai"
 	AIProviderAnthropic AIProvider = "anthropic"
+	AIProviderOllama    AIProvider = "ollama"
+	AIProviderRouter    AIProvider = "router" // 路由：按顺序在多个配置间回退
 )
 
+// 路由策略
+const (
+	RouteStrategyOrdered = "ordered" // 按配置顺序
+	RouteStrategyLatency = "latency" // 按近期首包延迟排序
+)
+
+// 路由回退的错误类别
+const (
+	RouteErrRateLimit     = "rate_limit"
+	RouteErrServer        = "server"
+	RouteErrTimeout       = "timeout"
+	RouteErrContextLength = "context_length"
+)
+
+// 推理强度
+const (
+	ReasoningEffortLow    = "low"
+	ReasoningEffortMedium = "medium"
+	ReasoningEffortHigh   = "high"
+)
+
+// ReasoningConfig 推理/扩展思考设置，设置了预算 token 时优先于强度
+type ReasoningConfig struct {
+	Enabled      bool   `json:"enabled"`
+	BudgetTokens int    `json:"budgetTokens"` // 思考预算，0 表示按强度由供应商决定
+	Effort       string `json:"effort"`       // low / medium / high
+}
+
 type OpenAITokenParamMode string
 
 const (
@@ -44,10 +74,20 @@ type AIConfig struct {
 	UseResponses bool `json:"useResponses"`
 	// 不支持 system role（自动检测，用户不可见）
 	NoSystemRole bool `json:"noSystemRole"`
+	// 推理设置（为空则不开启），可被专家配置覆盖
+	Reasoning *ReasoningConfig `json:"reasoning,omitempty"`
 	// Vertex AI 专用字段
 	Project         string `json:"project"`
 	Location        string `json:"location"`
 	CredentialsJSON string `json:"credentialsJson"`
+	// 客户端限流（按配置 ID 共享）：最大并发流、每分钟请求数、每分钟 token 数，0 表示不限制
+	MaxConcurrency    int `json:"maxConcurrency"`
+	RequestsPerMinute int `json:"requestsPerMinute"`
+	TokensPerMinute   int `json:"tokensPerMinute"`
+	// 路由专用字段：成员配置 ID（按回退顺序）、选择策略、触发回退的错误类别（空则全部）
+	RouteConfigIDs  []string `json:"routeConfigIds"`
+	RouteStrategy   string   `json:"routeStrategy"`
+	RouteFallbackOn []string `json:"routeFallbackOn"`
 }
 
 // MCPTransportType MCP传输类型
@@ -69,26 +109,64 @@ type MCPServerConfig struct {
 	Args          []string         `json:"args"`       // 命令行参数
 	ToolFilter    []string         `json:"toolFilter"` // 工具过滤列表（空则全部）
 	Enabled       bool             `json:"enabled"`    // 是否启用
+	// 命令行传输：追加到系统环境的环境变量与工作目录
+	Env        map[string]string `json:"env,omitempty"`
+	WorkingDir string            `json:"workingDir,omitempty"`
+	// HTTP/SSE 传输：自定义请求头与鉴权，取值可用 ${VAR} 引用 Env 或系统环境变量
+	Headers map[string]string `json:"headers,omitempty"`
+	Auth    *MCPAuthConfig    `json:"auth,omitempty"`
+	// 超时（秒），0 使用默认值：连接 10 秒，工具调用 60 秒
+	ConnectTimeoutSeconds int `json:"connectTimeoutSeconds,omitempty"`
+	CallTimeoutSeconds    int `json:"callTimeoutSeconds,omitempty"`
+}
+
+// MCPAuthType MCP 鉴权方式
+type MCPAuthType string
+
+const (
+	MCPAuthNone              MCPAuthType = ""                   // 不鉴权
+	MCPAuthBearer            MCPAuthType = "bearer"             // 固定 Bearer Token
+	MCPAuthClientCredentials MCPAuthType = "client_credentials" // OAuth2 客户端凭据模式
+)
+
+// MCPAuthConfig MCP HTTP 传输鉴权配置
+type MCPAuthConfig struct {
+	Type         MCPAuthType `json:"type"`
+	Token        string      `json:"token,omitempty"`        // bearer
+	TokenURL     string      `json:"tokenUrl,omitempty"`     // client_credentials
+	ClientID     string      `json:"clientId,omitempty"`     // client_credentials
+	ClientSecret string      `json:"clientSecret,omitempty"` // client_credentials
+	Scopes       []string    `json:"scopes,omitempty"`       // client_credentials
+}
+
+// MCPResourceRef 专家附加的 MCP 资源引用
+type MCPResourceRef struct {
+	ServerID string `json:"serverId"`
+	URI      string `json:"uri"`
+	Name     string `json:"name"`
 }
 
 // AppConfig 应用配置
 type AppConfig struct {
-	Theme               string              `json:"theme"`           // 主题色: military, ocean, purple, orange, dark
-	CandleColorMode     string              `json:"candleColorMode"` // 涨跌颜色模式: red-up(红涨绿跌) / green-up(绿涨红跌)
-	AIConfigs           []AIConfig          `json:"aiConfigs"`
-	DefaultAIID         string              `json:"defaultAiId"`
-	StrategyAIID        string              `json:"strategyAiId"`  // 策略生成用AI
-	ModeratorAIID       string              `json:"moderatorAiId"` // 意图分析(小韭菜)用AI
-	AIRetryCount        int                 `json:"aiRetryCount"`
-	VerboseAgentIO      bool                `json:"verboseAgentIO"`
-	AgentSelectionStyle AgentSelectionStyle `json:"agentSelectionStyle"`
-	EnableSecondReview  bool                `json:"enableSecondReview"`
-	MCPServers          []MCPServerConfig   `json:"mcpServers"` // MCP服务器配置列表
-	Memory              MemoryConfig        `json:"memory"`     // 记忆管理配置
-	Proxy               ProxyConfig         `json:"proxy"`      // 代理配置
-	Layout              LayoutConfig        `json:"layout"`     // 界面布局配置
-	OpenClaw            OpenClawConfig      `json:"openClaw"`   // OpenClaw 服务配置
-	Indicators          IndicatorConfig     `json:"indicators"` // 技术指标配置
+	Theme               string                  `json:"theme"`           // 主题色: military, ocean, purple, orange, dark
+	CandleColorMode     string                  `json:"candleColorMode"` // 涨跌颜色模式: red-up(红涨绿跌) / green-up(绿涨红跌)
+	AIConfigs           []AIConfig              `json:"aiConfigs"`
+	DefaultAIID         string                  `json:"defaultAiId"`
+	StrategyAIID        string                  `json:"strategyAiId"`  // 策略生成用AI
+	ModeratorAIID       string                  `json:"moderatorAiId"` // 意图分析(小韭菜)用AI
+	AIRetryCount        int                     `json:"aiRetryCount"`
+	VerboseAgentIO      bool                    `json:"verboseAgentIO"`
+	AgentSelectionStyle AgentSelectionStyle     `json:"agentSelectionStyle"`
+	EnableSecondReview  bool                    `json:"enableSecondReview"`
+	MCPServers          []MCPServerConfig       `json:"mcpServers"`        // MCP服务器配置列表
+	Memory              MemoryConfig            `json:"memory"`            // 记忆管理配置
+	Proxy               ProxyConfig             `json:"proxy"`             // 代理配置
+	Layout              LayoutConfig            `json:"layout"`            // 界面布局配置
+	OpenClaw            OpenClawConfig          `json:"openClaw"`          // OpenClaw 服务配置
+	Indicators          IndicatorConfig         `json:"indicators"`        // 技术指标配置
+	AnnouncementWatch   AnnouncementWatchConfig `json:"announcementWatch"` // 自选股公告监控配置
+	EarningsCalendar    EarningsCalendarConfig  `json:"earningsCalendar"`  // 财报日历配置
+	LLMCache            LLMCacheConfig          `json:"llmCache"`          // LLM 响应缓存配置
 }
 
 // ProxyMode 代理模式
@@ -116,6 +194,26 @@ type MemoryConfig struct {
 	CompressThreshold int    `json:"compressThreshold"` // 触发压缩的轮次数
 }
 
+// AnnouncementWatchConfig 自选股公告监控配置
+type AnnouncementWatchConfig struct {
+	Enabled         bool `json:"enabled"`         // 是否启用
+	IntervalMinutes int  `json:"intervalMinutes"` // 检查间隔(分钟)
+	UseLLM          bool `json:"useLlm"`          // 规则未命中时使用 LLM 分类
+}
+
+// EarningsCalendarConfig 财报日历配置
+type EarningsCalendarConfig struct {
+	AutoBriefing       bool `json:"autoBriefing"`       // 披露前自动召开前瞻会议
+	BriefingDaysBefore int  `json:"briefingDaysBefore"` // 提前天数
+}
+
+// LLMCacheConfig LLM 响应缓存配置
+type LLMCacheConfig struct {
+	Enabled           bool `json:"enabled"`           // 是否启用
+	TradingTTLMinutes int  `json:"tradingTtlMinutes"` // 交易时段缓存有效期（分钟）
+	ClosedTTLMinutes  int  `json:"closedTtlMinutes"`  // 非交易时段缓存有效期（分钟）
+}
+
 // LayoutConfig 界面布局配置
 type LayoutConfig struct {
 	LeftPanelWidth    int `json:"leftPanelWidth"`    // 左侧面板宽度(px)
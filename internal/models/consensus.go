@@ -0,0 +1,68 @@
+package models
+
+// ConsensusYear 单一财年的一致预期
+type ConsensusYear struct {
+	Year      int     `json:"year"`
+	EPS       float64 `json:"eps"`       // 一致预期EPS（各券商最新预测均值）
+	EPSMedian float64 `json:"epsMedian"` // EPS 中位数
+	EPSHigh   float64 `json:"epsHigh"`
+	EPSLow    float64 `json:"epsLow"`
+	PE        float64 `json:"pe,omitempty"` // 一致预期PE（均值）
+	Count     int     `json:"count"`        // 参与预测的券商数
+}
+
+// RatingCount 评级分布条目
+type RatingCount struct {
+	Rating string `json:"rating"`
+	Count  int    `json:"count"`
+}
+
+// BrokerRatingChange 券商评级变动
+type BrokerRatingChange struct {
+	Broker    string `json:"broker"`
+	Date      string `json:"date"`
+	From      string `json:"from,omitempty"`
+	To        string `json:"to"`
+	Direction string `json:"direction"` // upgrade, downgrade, maintain, initiate
+}
+
+// EPSRevision 当年EPS预测修正动量
+type EPSRevision struct {
+	Year           int     `json:"year"`
+	Up             int     `json:"up"`             // 上调券商数
+	Down           int     `json:"down"`           // 下调券商数
+	Unchanged      int     `json:"unchanged"`      // 维持券商数
+	AvgChangePct   float64 `json:"avgChangePct"`   // 券商自身修正幅度均值(%)
+	ConsensusPrior float64 `json:"consensusPrior"` // 回看起点时的一致预期EPS
+	ConsensusNow   float64 `json:"consensusNow"`
+	MomentumPct    float64 `json:"momentumPct"` // 一致预期EPS变化(%)
+}
+
+// AnalystConsensus 个股分析师一致预期
+type AnalystConsensus struct {
+	Code          string               `json:"code"`
+	Name          string               `json:"name,omitempty"`
+	Date          string               `json:"date"`
+	WindowDays    int                  `json:"windowDays"`
+	ReportCount   int                  `json:"reportCount"`
+	BrokerCount   int                  `json:"brokerCount"`
+	Years         []ConsensusYear      `json:"years"`
+	Ratings       []RatingCount        `json:"ratings"`      // 各券商最新评级分布
+	PriorRatings  []RatingCount        `json:"priorRatings"` // 回看起点时的评级分布
+	RatingChanges []BrokerRatingChange `json:"ratingChanges"`
+	Upgrades      int                  `json:"upgrades"`
+	Downgrades    int                  `json:"downgrades"`
+	Revision      *EPSRevision         `json:"revision,omitempty"`
+	RevisionDays  int                  `json:"revisionDays"`
+}
+
+// ConsensusSnapshot 一致预期历史快照（按日保存）
+type ConsensusSnapshot struct {
+	Date        string          `json:"date"`
+	ReportCount int             `json:"reportCount"`
+	BrokerCount int             `json:"brokerCount"`
+	Years       []ConsensusYear `json:"years"`
+	Ratings     []RatingCount   `json:"ratings"`
+	Upgrades    int             `json:"upgrades"`
+	Downgrades  int             `json:"downgrades"`
+}
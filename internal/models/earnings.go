@@ -0,0 +1,40 @@
+package models
+
+// EarningsCalendarItem 自选股财报披露日历条目
+type EarningsCalendarItem struct {
+	StockCode   string `json:"stockCode"`
+	StockName   string `json:"stockName"`
+	ReportDate  string `json:"reportDate"`           // 报告期
+	ReportType  string `json:"reportType,omitempty"` // 报告类型，如 2025年三季报
+	AppointDate string `json:"appointDate"`          // 预约披露日
+	ActualDate  string `json:"actualDate,omitempty"` // 实际披露日
+	DaysLeft    int    `json:"daysLeft"`             // 距预约披露日天数（负数为已过）
+	Status      string `json:"status"`               // upcoming/disclosed/overdue
+	Briefed     bool   `json:"briefed"`              // 是否已召开披露前瞻会议
+}
+
+// EarningsComparison 财报实际结果与预期对比
+type EarningsComparison struct {
+	StockCode    string  `json:"stockCode"`
+	StockName    string  `json:"stockName"`
+	ReportDate   string  `json:"reportDate"`
+	ActualDate   string  `json:"actualDate,omitempty"`
+	ActualSource string  `json:"actualSource"` // report(定期报告)/express(业绩快报)
+	NetProfit    float64 `json:"netProfit"`    // 归母净利润（元）
+	NetProfitYoY float64 `json:"netProfitYoY,omitempty"`
+	Revenue      float64 `json:"revenue,omitempty"`
+	EPS          float64 `json:"eps,omitempty"`
+
+	ForecastLower   float64 `json:"forecastLower,omitempty"` // 公司业绩预告下限（元）
+	ForecastUpper   float64 `json:"forecastUpper,omitempty"`
+	ForecastType    string  `json:"forecastType,omitempty"`    // 预告类型，如 预增
+	ForecastVerdict string  `json:"forecastVerdict,omitempty"` // beat/inline/miss
+
+	ConsensusEPS     float64 `json:"consensusEps,omitempty"` // 分析师一致预期EPS（仅年报）
+	ConsensusCount   int     `json:"consensusCount,omitempty"`
+	EPSSurprisePct   float64 `json:"epsSurprisePct,omitempty"`
+	ConsensusVerdict string  `json:"consensusVerdict,omitempty"` // beat/inline/miss
+
+	Summary   string `json:"summary"`
+	CreatedAt int64  `json:"createdAt"`
+}
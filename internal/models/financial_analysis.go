@@ -0,0 +1,67 @@
+package models
+
+// FinancialPeriod 单个报告期的财务分析指标（利润/现金流为年初至报告期末累计值）
+type FinancialPeriod struct {
+	ReportDate string `json:"reportDate"`
+	ReportType string `json:"reportType"` // 一季报/中报/三季报/年报
+
+	Revenue           float64 `json:"revenue"`
+	NetProfit         float64 `json:"netProfit"` // 归母净利润
+	DeductNetProfit   float64 `json:"deductNetProfit,omitempty"`
+	OperatingCashFlow float64 `json:"operatingCashFlow"`
+	FreeCashFlow      float64 `json:"freeCashFlow"` // 经营现金流 - 购建长期资产支出
+
+	GrossMargin      float64 `json:"grossMargin,omitempty"`     // 毛利率(%)
+	OperatingMargin  float64 `json:"operatingMargin,omitempty"` // 营业利润率(%)
+	NetMargin        float64 `json:"netMargin,omitempty"`       // 归母净利率(%)
+	ROE              float64 `json:"roe,omitempty"`             // 期间ROE(%)，未年化
+	ROA              float64 `json:"roa,omitempty"`             // 期间ROA(%)，未年化
+	AssetTurnover    float64 `json:"assetTurnover,omitempty"`   // 期间总资产周转率(次)
+	EquityMultiplier float64 `json:"equityMultiplier,omitempty"`
+	CashConversion   float64 `json:"cashConversion,omitempty"` // 经营现金流/归母净利润
+	CashToRevenue    float64 `json:"cashToRevenue,omitempty"`  // 销售收现/营业收入
+	ReceivableDays   float64 `json:"receivableDays,omitempty"` // 应收账款周转天数
+	InventoryDays    float64 `json:"inventoryDays,omitempty"`  // 存货周转天数
+	DebtRatio        float64 `json:"debtRatio,omitempty"`      // 资产负债率(%)
+	CurrentRatio     float64 `json:"currentRatio,omitempty"`
+	GoodwillRatio    float64 `json:"goodwillRatio,omitempty"` // 商誉/归母净资产(%)
+
+	RevenueYoY        *float64 `json:"revenueYoY,omitempty"` // 累计同比(%)
+	NetProfitYoY      *float64 `json:"netProfitYoY,omitempty"`
+	QuarterRevenue    *float64 `json:"quarterRevenue,omitempty"` // 单季营业收入
+	QuarterNetProfit  *float64 `json:"quarterNetProfit,omitempty"`
+	QuarterRevenueQoQ *float64 `json:"quarterRevenueQoQ,omitempty"` // 单季环比(%)
+	QuarterProfitQoQ  *float64 `json:"quarterProfitQoQ,omitempty"`
+}
+
+// FinancialTTM 最近十二个月滚动指标
+type FinancialTTM struct {
+	BaseDate          string   `json:"baseDate"`
+	Revenue           float64  `json:"revenue"`
+	NetProfit         float64  `json:"netProfit"`
+	DeductNetProfit   float64  `json:"deductNetProfit,omitempty"`
+	OperatingCashFlow float64  `json:"operatingCashFlow"`
+	NetMargin         float64  `json:"netMargin,omitempty"`
+	ROE               float64  `json:"roe,omitempty"`
+	CashConversion    float64  `json:"cashConversion,omitempty"`
+	RevenueYoY        *float64 `json:"revenueYoY,omitempty"`
+	NetProfitYoY      *float64 `json:"netProfitYoY,omitempty"`
+}
+
+// FinancialRedFlag 财务异常信号
+type FinancialRedFlag struct {
+	Type       string  `json:"type"`
+	Level      string  `json:"level"` // high/medium
+	ReportDate string  `json:"reportDate"`
+	Message    string  `json:"message"`
+	Value      float64 `json:"value"`
+}
+
+// FinancialAnalysis 财务分析结果
+type FinancialAnalysis struct {
+	Code      string             `json:"code"`
+	Periods   []FinancialPeriod  `json:"periods"` // 按报告期倒序
+	TTM       *FinancialTTM      `json:"ttm,omitempty"`
+	RedFlags  []FinancialRedFlag `json:"redFlags"`
+	UpdatedAt string             `json:"updatedAt"`
+}
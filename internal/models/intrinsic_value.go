@@ -0,0 +1,85 @@
+package models
+
+// ValuationParams 内在价值估算参数（百分比参数以 % 表示，0 表示使用默认值）
+type ValuationParams struct {
+	WACC           float64 `json:"wacc,omitempty"`           // 折现率(%)，默认9
+	Stage1Growth   float64 `json:"stage1Growth,omitempty"`   // 高速增长期增速(%)，默认取TTM净利润增速（限制在-5~20）
+	Stage1Years    int     `json:"stage1Years,omitempty"`    // 高速增长期年数，默认5
+	TerminalGrowth float64 `json:"terminalGrowth,omitempty"` // 永续增长率(%)，默认2.5
+	RequiredReturn float64 `json:"requiredReturn,omitempty"` // DDM 要求回报率(%)，默认9
+	DividendGrowth float64 `json:"dividendGrowth,omitempty"` // DDM 股息增长率(%)，默认3
+	Range          string  `json:"range,omitempty"`          // PE/PB 历史区间：1y/3y/5y/10y，默认5y
+}
+
+// SensitivityTable 敏感性分析表，Values[i][j] 对应 Rows[i] 与 Cols[j]
+type SensitivityTable struct {
+	RowLabel string      `json:"rowLabel"`
+	ColLabel string      `json:"colLabel"`
+	Rows     []float64   `json:"rows"`
+	Cols     []float64   `json:"cols"`
+	Values   [][]float64 `json:"values"`
+}
+
+// DCFValuation 两阶段自由现金流折现估值
+type DCFValuation struct {
+	BaseFCF        float64           `json:"baseFcf"` // TTM 自由现金流(元)
+	WACC           float64           `json:"wacc"`
+	Stage1Growth   float64           `json:"stage1Growth"`
+	Stage1Years    int               `json:"stage1Years"`
+	TerminalGrowth float64           `json:"terminalGrowth"`
+	StagePV        float64           `json:"stagePv"`    // 高速增长期现值(元)
+	TerminalPV     float64           `json:"terminalPv"` // 永续价值现值(元)
+	NetCash        float64           `json:"netCash"`    // 货币资金 - 有息负债(元)
+	EquityValue    float64           `json:"equityValue"`
+	PerShare       float64           `json:"perShare"`
+	Upside         float64           `json:"upside"` // 相对现价空间(%)
+	Sensitivity    *SensitivityTable `json:"sensitivity,omitempty"`
+}
+
+// DDMValuation 股利折现（Gordon 增长）估值
+type DDMValuation struct {
+	DividendPerShare float64           `json:"dividendPerShare"` // 近12个月每股派息(元)
+	DividendYield    float64           `json:"dividendYield"`    // 股息率(%)
+	RequiredReturn   float64           `json:"requiredReturn"`
+	DividendGrowth   float64           `json:"dividendGrowth"`
+	PerShare         float64           `json:"perShare"`
+	Upside           float64           `json:"upside"`
+	Sensitivity      *SensitivityTable `json:"sensitivity,omitempty"`
+}
+
+// BandPoint 估值带分位点
+type BandPoint struct {
+	Percentile float64 `json:"percentile"`
+	Multiple   float64 `json:"multiple"`
+	Price      float64 `json:"price"`
+}
+
+// BandValuation PE/PB 历史分位估值带
+type BandValuation struct {
+	Metric            string      `json:"metric"` // pe/pb
+	Current           float64     `json:"current"`
+	CurrentPercentile float64     `json:"currentPercentile"` // 当前倍数所处历史分位(%)
+	Base              float64     `json:"base"`              // 每股收益(TTM)或每股净资产
+	Samples           int         `json:"samples"`
+	Range             string      `json:"range,omitempty"`
+	Points            []BandPoint `json:"points"`
+	FairValue         float64     `json:"fairValue"` // 历史中位数倍数对应价格
+	Upside            float64     `json:"upside"`
+}
+
+// IntrinsicValuation 内在价值估算结果
+type IntrinsicValuation struct {
+	Code          string            `json:"code"`
+	Price         float64           `json:"price"`
+	Shares        float64           `json:"shares"`
+	DCF           *DCFValuation     `json:"dcf,omitempty"`
+	DDM           *DDMValuation     `json:"ddm,omitempty"`
+	PEBand        *BandValuation    `json:"peBand,omitempty"`
+	PBBand        *BandValuation    `json:"pbBand,omitempty"`
+	FairValueLow  float64           `json:"fairValueLow,omitempty"`
+	FairValueMid  float64           `json:"fairValueMid,omitempty"`
+	FairValueHigh float64           `json:"fairValueHigh,omitempty"`
+	Notes         []string          `json:"notes,omitempty"`
+	Errors        map[string]string `json:"errors,omitempty"`
+	UpdatedAt     string            `json:"updatedAt"`
+}
@@ -0,0 +1,87 @@
+package models
+
+// 龙虎榜席位类型
+const (
+	SeatTypeInstitution = "institution" // 机构专用
+	SeatTypeNorthbound  = "northbound"  // 沪股通/深股通专用
+	SeatTypeHotMoney    = "hot_money"   // 知名游资或高频短线席位
+	SeatTypeBranch      = "branch"      // 普通营业部
+)
+
+// LhbSeatRecord 龙虎榜席位单日买卖记录
+type LhbSeatRecord struct {
+	TradeDate     string  `json:"tradeDate"`
+	Code          string  `json:"code"`
+	Name          string  `json:"name"`
+	OperName      string  `json:"operName"`
+	Direction     string  `json:"direction"` // 净买卖方向: buy/sell
+	BuyAmt        float64 `json:"buyAmt"`
+	SellAmt       float64 `json:"sellAmt"`
+	NetAmt        float64 `json:"netAmt"`
+	Reason        string  `json:"reason,omitempty"`
+	ChangePercent float64 `json:"changePercent"` // 上榜当日涨跌幅(%)
+	D1Change      float64 `json:"d1Change"`      // 次日涨跌幅(%)
+	D5Change      float64 `json:"d5Change"`      // 5日涨跌幅(%)
+}
+
+// LhbSeatDay 单个交易日的席位明细
+type LhbSeatDay struct {
+	TradeDate string          `json:"tradeDate"`
+	Horizon   int             `json:"horizon"`             // 已回填的后续涨跌幅周期(0/1/5)
+	Refreshed string          `json:"refreshed,omitempty"` // 最近一次回填日期
+	Records   []LhbSeatRecord `json:"records"`
+}
+
+// SeatStockStat 席位偏好个股统计
+type SeatStockStat struct {
+	Code   string  `json:"code"`
+	Name   string  `json:"name"`
+	Count  int     `json:"count"`
+	NetAmt float64 `json:"netAmt"`
+}
+
+// SeatProfile 龙虎榜席位画像
+type SeatProfile struct {
+	OperName    string          `json:"operName"`
+	SeatType    string          `json:"seatType"`
+	Alias       string          `json:"alias,omitempty"` // 游资别称
+	Appearances int             `json:"appearances"`     // 上榜次数
+	BuyCount    int             `json:"buyCount"`        // 净买入次数
+	SellCount   int             `json:"sellCount"`       // 净卖出次数
+	TotalBuy    float64         `json:"totalBuy"`
+	TotalSell   float64         `json:"totalSell"`
+	NetAmt      float64         `json:"netAmt"`
+	D1Samples   int             `json:"d1Samples"`
+	D1WinRate   float64         `json:"d1WinRate"` // 净买入后次日上涨概率(%)
+	D1AvgChange float64         `json:"d1AvgChange"`
+	D5Samples   int             `json:"d5Samples"`
+	D5WinRate   float64         `json:"d5WinRate"` // 净买入后5日上涨概率(%)
+	D5AvgChange float64         `json:"d5AvgChange"`
+	HoldSamples int             `json:"holdSamples"`
+	HoldDays    float64         `json:"holdDays"` // 买入到卖出上榜的典型间隔（交易日中位数）
+	TopStocks   []SeatStockStat `json:"topStocks"`
+	FirstDate   string          `json:"firstDate"`
+	LastDate    string          `json:"lastDate"`
+}
+
+// StockSeatActivity 个股上榜席位及其历史表现
+type StockSeatActivity struct {
+	OperName  string       `json:"operName"`
+	SeatType  string       `json:"seatType"`
+	Alias     string       `json:"alias,omitempty"`
+	Direction string       `json:"direction"`
+	BuyAmt    float64      `json:"buyAmt"`
+	SellAmt   float64      `json:"sellAmt"`
+	NetAmt    float64      `json:"netAmt"`
+	Profile   *SeatProfile `json:"profile,omitempty"`
+}
+
+// StockSeatAnalysis 个股龙虎榜席位分析
+type StockSeatAnalysis struct {
+	Code      string              `json:"code"`
+	Name      string              `json:"name"`
+	TradeDate string              `json:"tradeDate"`
+	Reason    string              `json:"reason,omitempty"`
+	Buyers    []StockSeatActivity `json:"buyers"`
+	Sellers   []StockSeatActivity `json:"sellers"`
+}
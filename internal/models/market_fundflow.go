@@ -48,6 +48,21 @@ type BoardLeaderList struct {
 	UpdateTime string            `json:"updateTime,omitempty"`
 }
 
+// BoardBreadth 板块成分股涨跌分布
+type BoardBreadth struct {
+	BoardCode    string  `json:"boardCode"`
+	Total        int     `json:"total"` // 参与统计的成分股数（剔除停牌）
+	Up           int     `json:"up"`
+	Down         int     `json:"down"`
+	Flat         int     `json:"flat"`
+	LimitUp      int     `json:"limitUp"`
+	LimitDown    int     `json:"limitDown"`
+	UpRatio      float64 `json:"upRatio"`      // 上涨家数占比（%）
+	AvgChange    float64 `json:"avgChange"`    // 平均涨跌幅（%）
+	MedianChange float64 `json:"medianChange"` // 涨跌幅中位数（%）
+	UpdateTime   string  `json:"updateTime,omitempty"`
+}
+
 // StockMoveItem 盘口异动候选
 type StockMoveItem struct {
 	Rank               int     `json:"rank"`
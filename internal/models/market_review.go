@@ -0,0 +1,54 @@
+package models
+
+// ReviewStock 复盘中的个股条目
+type ReviewStock struct {
+	Code          string  `json:"code"`
+	Name          string  `json:"name"`
+	Price         float64 `json:"price"`
+	ChangePercent float64 `json:"changePercent"`
+	TurnoverRate  float64 `json:"turnoverRate"`
+	Amount        float64 `json:"amount"` // 成交额（元）
+}
+
+// ReviewNews 复盘中的快讯条目
+type ReviewNews struct {
+	Time    string `json:"time"`
+	Content string `json:"content"`
+	URL     string `json:"url,omitempty"`
+}
+
+// ReviewHotTopic 复盘中的热点话题
+type ReviewHotTopic struct {
+	Platform string `json:"platform"`
+	Title    string `json:"title"`
+	Rank     int    `json:"rank"`
+	URL      string `json:"url,omitempty"`
+}
+
+// MarketReview 每日市场复盘
+type MarketReview struct {
+	Date            string              `json:"date"` // 复盘日期 YYYY-MM-DD
+	GeneratedAt     int64               `json:"generatedAt"`
+	Indices         []MarketIndex       `json:"indices"`
+	LimitUpCount    int                 `json:"limitUpCount"`
+	LimitDownCount  int                 `json:"limitDownCount"`
+	LimitUpStocks   []ReviewStock       `json:"limitUpStocks"`
+	LimitDownStocks []ReviewStock       `json:"limitDownStocks"`
+	IndustryInflow  []BoardFundFlowItem `json:"industryInflow"` // 行业板块主力净流入前列
+	ConceptInflow   []BoardFundFlowItem `json:"conceptInflow"`  // 概念板块主力净流入前列
+	LongHuBang      []LongHuBangItem    `json:"longHuBang"`     // 龙虎榜净买入前列
+	LongHuBangDate  string              `json:"longHuBangDate,omitempty"`
+	News            []ReviewNews        `json:"news"`
+	HotTopics       []ReviewHotTopic    `json:"hotTopics"`
+	Narrative       string              `json:"narrative,omitempty"` // 小韭菜点评
+	Errors          map[string]string   `json:"errors,omitempty"`
+}
+
+// MarketReviewSummary 复盘列表摘要
+type MarketReviewSummary struct {
+	Date           string `json:"date"`
+	GeneratedAt    int64  `json:"generatedAt"`
+	LimitUpCount   int    `json:"limitUpCount"`
+	LimitDownCount int    `json:"limitDownCount"`
+	HasNarrative   bool   `json:"hasNarrative"`
+}
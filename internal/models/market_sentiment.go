@@ -0,0 +1,38 @@
+package models
+
+// LadderStock 连板梯队个股
+type LadderStock struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Industry string `json:"industry,omitempty"`
+}
+
+// BoardLadderLevel 连板梯队单层（按连板高度）
+type BoardLadderLevel struct {
+	Height int           `json:"height"`
+	Count  int           `json:"count"`
+	Stocks []LadderStock `json:"stocks,omitempty"`
+}
+
+// MarketSentiment 全市场宽度与情绪指标
+type MarketSentiment struct {
+	Date          string             `json:"date"`
+	UpdateTime    string             `json:"updateTime"`
+	Total         int                `json:"total"`
+	Up            int                `json:"up"`
+	Down          int                `json:"down"`
+	Flat          int                `json:"flat"`
+	UpRatio       float64            `json:"upRatio"` // 上涨家数占比(%)
+	LimitUp       int                `json:"limitUp"`
+	LimitDown     int                `json:"limitDown"`
+	FailedLimitUp int                `json:"failedLimitUp"` // 炸板家数
+	FailedRatio   float64            `json:"failedRatio"`   // 炸板率(%)
+	MaxHeight     int                `json:"maxHeight"`     // 最高连板高度
+	Ladder        []BoardLadderLevel `json:"ladder,omitempty"`
+	NewHigh       int                `json:"newHigh"`       // 60日新高家数
+	NewLow        int                `json:"newLow"`        // 60日新低家数
+	Turnover      float64            `json:"turnover"`      // 沪深两市成交额(亿)
+	TurnoverAvg20 float64            `json:"turnoverAvg20"` // 前20日平均成交额(亿)
+	TurnoverRatio float64            `json:"turnoverRatio"` // 成交额/20日均值
+	Errors        map[string]string  `json:"errors,omitempty"`
+}
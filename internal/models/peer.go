@@ -0,0 +1,61 @@
+package models
+
+// 同业对比来源
+const (
+	PeerSourceIndustry = "industry"
+	PeerSourceConcept  = "concept"
+	PeerSourceCustom   = "custom"
+)
+
+// PeerRequest 同业对比请求
+type PeerRequest struct {
+	Code   string   `json:"code"`
+	Source string   `json:"source,omitempty"` // industry/concept/custom，默认industry
+	Theme  string   `json:"theme,omitempty"`  // concept来源时指定概念名称或板块代码，留空取首个核心题材
+	Peers  []string `json:"peers,omitempty"`  // custom来源的股票代码列表
+	Limit  int      `json:"limit,omitempty"`
+}
+
+// PeerMetric 对比指标定义
+type PeerMetric struct {
+	Name         string `json:"name"`
+	Label        string `json:"label"`
+	Group        string `json:"group"` // valuation/growth/profitability/fundflow
+	HigherBetter bool   `json:"higherBetter"`
+}
+
+// PeerRow 单只股票的指标与百分位
+type PeerRow struct {
+	Code        string             `json:"code"`
+	Name        string             `json:"name"`
+	IsTarget    bool               `json:"isTarget,omitempty"`
+	Metrics     map[string]float64 `json:"metrics"`
+	Percentiles map[string]float64 `json:"percentiles,omitempty"` // 0-100，越高越优
+	GroupScores map[string]float64 `json:"groupScores,omitempty"` // 分组平均百分位
+}
+
+// PeerRank 目标股票在单项指标上的排名
+type PeerRank struct {
+	Metric     string  `json:"metric"`
+	Label      string  `json:"label"`
+	Group      string  `json:"group"`
+	Value      float64 `json:"value"`
+	Median     float64 `json:"median"`
+	Rank       int     `json:"rank"` // 1为最优
+	Count      int     `json:"count"`
+	Percentile float64 `json:"percentile"`
+}
+
+// PeerAnalysis 同业对比矩阵
+type PeerAnalysis struct {
+	Code        string             `json:"code"`
+	Name        string             `json:"name"`
+	Source      string             `json:"source"`
+	SourceName  string             `json:"sourceName,omitempty"` // 行业或概念名称
+	Metrics     []PeerMetric       `json:"metrics"`
+	Rows        []PeerRow          `json:"rows"`
+	Ranks       []PeerRank         `json:"ranks,omitempty"`
+	GroupScores map[string]float64 `json:"groupScores,omitempty"`
+	Errors      map[string]string  `json:"errors,omitempty"`
+	UpdatedAt   string             `json:"updatedAt"`
+}
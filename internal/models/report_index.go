@@ -0,0 +1,34 @@
+package models
+
+// ReportChunk 研报正文片段
+type ReportChunk struct {
+	Index int    `json:"index"`
+	Page  int    `json:"page"` // 页码（从1开始）
+	Text  string `json:"text"`
+}
+
+// ReportIndex 单篇研报的分片索引
+type ReportIndex struct {
+	InfoCode    string        `json:"infoCode"`
+	StockCode   string        `json:"stockCode,omitempty"`
+	StockName   string        `json:"stockName,omitempty"`
+	Broker      string        `json:"broker,omitempty"`
+	Title       string        `json:"title,omitempty"`
+	PublishDate string        `json:"publishDate,omitempty"`
+	PDFUrl      string        `json:"pdfUrl"`
+	Pages       int           `json:"pages"`
+	IngestedAt  int64         `json:"ingestedAt"`
+	Chunks      []ReportChunk `json:"chunks"`
+}
+
+// ReportChunkHit 检索命中的研报片段（附引用信息）
+type ReportChunkHit struct {
+	InfoCode    string  `json:"infoCode"`
+	Broker      string  `json:"broker,omitempty"`
+	Title       string  `json:"title,omitempty"`
+	PublishDate string  `json:"publishDate,omitempty"`
+	Page        int     `json:"page"`
+	Text        string  `json:"text"`
+	Score       float64 `json:"score"`
+	Citation    string  `json:"citation"`
+}
@@ -0,0 +1,36 @@
+package models
+
+// RiskSignal 单条风险信号
+type RiskSignal struct {
+	Type   string  `json:"type"`  // lockup/pledge/reduction/st
+	Level  string  `json:"level"` // high/medium/low
+	Score  float64 `json:"score"`
+	Title  string  `json:"title"`
+	Detail string  `json:"detail,omitempty"`
+	Date   string  `json:"date,omitempty"` // 事件日期（解禁日、公告日等）
+}
+
+// StockRiskProfile 个股风险画像
+type StockRiskProfile struct {
+	StockCode   string            `json:"stockCode"`
+	StockName   string            `json:"stockName"`
+	InWatchlist bool              `json:"inWatchlist"`
+	Held        bool              `json:"held"` // 是否持仓
+	Score       float64           `json:"score"`
+	Level       string            `json:"level"` // high/medium/low/none
+	Signals     []RiskSignal      `json:"signals"`
+	Errors      map[string]string `json:"errors,omitempty"`
+}
+
+// RiskRadar 自选股与持仓风险雷达（按风险分降序）
+type RiskRadar struct {
+	UpdateTime int64              `json:"updateTime"`
+	Stocks     []StockRiskProfile `json:"stocks"`
+}
+
+// RiskAlert 新出现的风险信号提醒
+type RiskAlert struct {
+	StockCode string     `json:"stockCode"`
+	StockName string     `json:"stockName"`
+	Signal    RiskSignal `json:"signal"`
+}
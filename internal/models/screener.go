@@ -0,0 +1,52 @@
+package models
+
+// StockScreen 已保存的选股方案
+type StockScreen struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Expression  string `json:"expression"`            // 筛选表达式，如 pe < 20 and roe > 15
+	SortBy      string `json:"sortBy,omitempty"`      // 排序字段
+	SortDesc    bool   `json:"sortDesc,omitempty"`    // 是否倒序
+	Limit       int    `json:"limit,omitempty"`       // 返回数量上限
+	Schedule    int    `json:"schedule,omitempty"`    // 定时运行间隔（分钟），0 表示不定时
+	TradingOnly bool   `json:"tradingOnly,omitempty"` // 仅在交易时段定时运行
+	Enabled     bool   `json:"enabled"`
+	LastRunAt   int64  `json:"lastRunAt,omitempty"`
+	CreatedAt   int64  `json:"createdAt"`
+	UpdatedAt   int64  `json:"updatedAt"`
+}
+
+// ScreenMatch 选股命中项
+type ScreenMatch struct {
+	Code     string             `json:"code"` // 带市场前缀，如 sh600000
+	Name     string             `json:"name"`
+	Industry string             `json:"industry,omitempty"`
+	Metrics  map[string]float64 `json:"metrics"`
+}
+
+// ScreenResult 选股结果
+type ScreenResult struct {
+	ScreenID   string            `json:"screenId,omitempty"`
+	Expression string            `json:"expression"`
+	RunAt      int64             `json:"runAt"`
+	Universe   int               `json:"universe"`   // 参与筛选的股票数
+	Candidates int               `json:"candidates"` // 通过初筛、需要补充明细数据的股票数
+	Matched    int               `json:"matched"`    // 命中总数（截断前）
+	Items      []ScreenMatch     `json:"items"`
+	Errors     map[string]string `json:"errors,omitempty"`
+}
+
+// ScreenField 选股可用字段说明
+type ScreenField struct {
+	Name        string `json:"name"`
+	Label       string `json:"label"`
+	Source      string `json:"source"` // quote/fundflow/kline/indicator
+	Description string `json:"description,omitempty"`
+}
+
+// ScreenStore 选股方案存储结构
+type ScreenStore struct {
+	Screens []StockScreen           `json:"screens"`
+	Results map[string]ScreenResult `json:"results,omitempty"` // 每个方案最近一次运行结果
+}
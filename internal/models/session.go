@@ -8,13 +8,15 @@ type StockPosition struct {
 
 // StockSession 股票会话（每个自选股独立）
 type StockSession struct {
-	ID        string         `json:"id"`
-	StockCode string         `json:"stockCode"` // 股票代码
-	StockName string         `json:"stockName"` // 股票名称
-	Messages  []ChatMessage  `json:"messages"`  // 讨论历史
-	Position  *StockPosition `json:"position"`  // 持仓信息
-	CreatedAt int64          `json:"createdAt"`
-	UpdatedAt int64          `json:"updatedAt"`
+	ID           string         `json:"id"`
+	StockCode    string         `json:"stockCode"`              // 股票代码（对比/板块会话为 compare_/board_ 开头的会话键）
+	StockName    string         `json:"stockName"`              // 股票名称
+	CompareCodes []string       `json:"compareCodes,omitempty"` // 对比会话包含的股票代码
+	BoardCode    string         `json:"boardCode,omitempty"`    // 板块会话对应的板块代码
+	Messages     []ChatMessage  `json:"messages"`               // 讨论历史
+	Position     *StockPosition `json:"position"`               // 持仓信息
+	CreatedAt    int64          `json:"createdAt"`
+	UpdatedAt    int64          `json:"updatedAt"`
 }
 
 // ChatMessage 聊天消息
@@ -30,5 +32,5 @@ type ChatMessage struct {
 	Round     int      `json:"round,omitempty"`     // 讨论轮次
 	MsgType   string   `json:"msgType,omitempty"`   // 消息类型: opening/opinion/summary
 	Error       string   `json:"error,omitempty"`       // 失败时的错误信息
-	MeetingMode string   `json:"meetingMode,omitempty"` // smart=串行, direct=独立
+	MeetingMode string   `json:"meetingMode,omitempty"` // smart=串行, direct=独立, compare=多股对比, board=板块
 }
@@ -12,6 +12,12 @@ type StrategyAgent struct {
 	MCPServers  []string `json:"mcpServers"`
 	Enabled     bool     `json:"enabled"`
 	AIConfigID  string   `json:"aiConfigId"` // 可选，空则用默认AI
+	// 可选，覆盖 AI 配置的推理设置
+	Reasoning *ReasoningConfig `json:"reasoning,omitempty"`
+	// 模型支持图像输入时开启，发言时随指令附带K线图
+	Vision bool `json:"vision,omitempty"`
+	// 附加到专家上下文的 MCP 资源
+	MCPResources []MCPResourceRef `json:"mcpResources,omitempty"`
 }
 
 // Strategy 策略配置
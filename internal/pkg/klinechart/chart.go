@@ -0,0 +1,502 @@
+// Package klinechart 提供纯 Go 的 K 线图渲染，绘制蜡烛图、成交量与已启用的技术指标并输出 PNG
+package klinechart
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strconv"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// Options 渲染选项
+type Options struct {
+	Width      int                    // 默认 960
+	Height     int                    // 默认 640
+	MaxBars    int                    // 最多绘制的 K 线数，默认 120（指标仍按全部数据计算）
+	Indicators models.IndicatorConfig // MA/EMA/BOLL 叠加在主图，MACD/RSI/KDJ 取首个启用项作副图
+}
+
+const (
+	defaultWidth   = 960
+	defaultHeight  = 640
+	defaultMaxBars = 120
+	axisWidth      = 80 // 右侧刻度区宽度
+	padding        = 8
+	panelGap       = 10
+)
+
+var (
+	colorBackground = color.RGBA{255, 255, 255, 255}
+	colorGrid       = color.RGBA{236, 236, 236, 255}
+	colorAxis       = color.RGBA{160, 160, 160, 255}
+	colorText       = color.RGBA{70, 70, 70, 255}
+	colorUp         = color.RGBA{229, 57, 53, 255}  // 涨：红
+	colorDown       = color.RGBA{38, 166, 154, 255} // 跌：绿
+	colorBoll       = color.RGBA{96, 125, 139, 255}
+	linePalette     = []color.RGBA{
+		{255, 152, 0, 255},
+		{33, 150, 243, 255},
+		{156, 39, 176, 255},
+		{121, 85, 72, 255},
+		{233, 30, 99, 255},
+	}
+)
+
+// series 一条指标折线
+type series struct {
+	label  string
+	values []float64
+	color  color.RGBA
+}
+
+// oscillator 副图指标
+type oscillator struct {
+	label  string // 图例标题，为空时仅显示各折线
+	lines  []series
+	hist   []float64 // MACD 柱，其余指标为空
+	guides []float64 // 参考线，如 RSI 的 30/70
+}
+
+// panel 绘图区域及其数值范围
+type panel struct {
+	top, bottom int
+	min, max    float64
+}
+
+// y 将数值映射为像素纵坐标
+func (p panel) y(v float64) int {
+	if p.max == p.min {
+		return (p.top + p.bottom) / 2
+	}
+	return p.top + int(math.Round((p.max-v)/(p.max-p.min)*float64(p.bottom-p.top)))
+}
+
+// RenderPNG 渲染 K 线图并编码为 PNG
+func RenderPNG(klines []models.KLineData, opts Options) ([]byte, error) {
+	img, err := Render(klines, opts)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Render 渲染 K 线图：主图为蜡烛与均线类指标，其下为成交量与可选的副图指标
+func Render(klines []models.KLineData, opts Options) (*image.RGBA, error) {
+	if len(klines) == 0 {
+		return nil, errors.New("没有K线数据")
+	}
+	if opts.Width <= 0 {
+		opts.Width = defaultWidth
+	}
+	if opts.Height <= 0 {
+		opts.Height = defaultHeight
+	}
+	if opts.MaxBars <= 0 {
+		opts.MaxBars = defaultMaxBars
+	}
+
+	highs := make([]float64, len(klines))
+	lows := make([]float64, len(klines))
+	closes := make([]float64, len(klines))
+	for i, k := range klines {
+		highs[i], lows[i], closes[i] = k.High, k.Low, k.Close
+	}
+	overlays := overlaySeries(closes, opts.Indicators)
+	osc := oscillatorSeries(highs, lows, closes, opts.Indicators)
+
+	// 指标按全量数据计算后再裁剪，避免预热期缺值
+	start := max(0, len(klines)-opts.MaxBars)
+	bars := klines[start:]
+	for i := range overlays {
+		overlays[i].values = overlays[i].values[start:]
+	}
+	if osc != nil {
+		for i := range osc.lines {
+			osc.lines[i].values = osc.lines[i].values[start:]
+		}
+		if osc.hist != nil {
+			osc.hist = osc.hist[start:]
+		}
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, opts.Width, opts.Height))
+	fillRect(img, 0, 0, opts.Width, opts.Height, colorBackground)
+
+	c := &canvas{
+		img:   img,
+		left:  padding,
+		right: opts.Width - axisWidth,
+		n:     len(bars),
+	}
+	textHeight := glyphHeight*defaultScale + 6
+	top := padding + textHeight
+	bottom := opts.Height - padding - textHeight
+	if c.right-c.left < c.n || bottom-top < 3*panelGap {
+		return nil, errors.New("图表尺寸过小")
+	}
+
+	// 主图 / 成交量 / 副图 高度按 3:1:1 分配，无副图时为 3:1
+	parts := 4
+	if osc != nil {
+		parts = 5
+	}
+	unit := (bottom - top - (parts-3)*panelGap) / parts
+	mainBottom := top + 3*unit
+	volTop := mainBottom + panelGap
+	volBottom := volTop + unit
+
+	c.drawMain(bars, overlays, top, mainBottom)
+	c.drawVolume(bars, volTop, volBottom)
+	if osc != nil {
+		c.drawOscillator(osc, volBottom+panelGap, bottom)
+	}
+	c.drawDates(bars, bottom+4)
+	return img, nil
+}
+
+// overlaySeries 构建叠加在主图上的 MA / EMA / BOLL 折线
+func overlaySeries(closes []float64, cfg models.IndicatorConfig) []series {
+	var out []series
+	next := 0
+	pick := func() color.RGBA {
+		c := linePalette[next%len(linePalette)]
+		next++
+		return c
+	}
+	if cfg.MA.Enabled {
+		for _, p := range cfg.MA.Periods {
+			if p > 0 {
+				out = append(out, series{label: "MA" + strconv.Itoa(p), values: sma(closes, p), color: pick()})
+			}
+		}
+	}
+	if cfg.EMA.Enabled {
+		for _, p := range cfg.EMA.Periods {
+			if p > 0 {
+				out = append(out, series{label: "EMA" + strconv.Itoa(p), values: ema(closes, p), color: pick()})
+			}
+		}
+	}
+	if cfg.BOLL.Enabled && cfg.BOLL.Period > 0 {
+		mid, upper, lower := boll(closes, cfg.BOLL.Period, cfg.BOLL.Multiplier)
+		out = append(out,
+			series{label: "BOLL", values: mid, color: colorBoll},
+			series{values: upper, color: colorBoll},
+			series{values: lower, color: colorBoll},
+		)
+	}
+	return out
+}
+
+// oscillatorSeries 按 MACD → RSI → KDJ 的顺序选取首个启用的副图指标
+func oscillatorSeries(highs, lows, closes []float64, cfg models.IndicatorConfig) *oscillator {
+	switch {
+	case cfg.MACD.Enabled && cfg.MACD.Fast > 0 && cfg.MACD.Slow > 0 && cfg.MACD.Signal > 0:
+		dif, dea, hist := macd(closes, cfg.MACD.Fast, cfg.MACD.Slow, cfg.MACD.Signal)
+		return &oscillator{
+			label: "MACD",
+			lines: []series{
+				{label: "DIF", values: dif, color: linePalette[0]},
+				{label: "DEA", values: dea, color: linePalette[1]},
+			},
+			hist:   hist,
+			guides: []float64{0},
+		}
+	case cfg.RSI.Enabled && cfg.RSI.Period > 0:
+		return &oscillator{
+			lines:  []series{{label: "RSI" + strconv.Itoa(cfg.RSI.Period), values: rsi(closes, cfg.RSI.Period), color: linePalette[2]}},
+			guides: []float64{0, 30, 70, 100},
+		}
+	case cfg.KDJ.Enabled && cfg.KDJ.Period > 0:
+		k, d, j := kdj(highs, lows, closes, cfg.KDJ.Period, cfg.KDJ.K, cfg.KDJ.D)
+		return &oscillator{
+			label: "KDJ",
+			lines: []series{
+				{label: "K", values: k, color: linePalette[0]},
+				{label: "D", values: d, color: linePalette[1]},
+				{label: "J", values: j, color: linePalette[2]},
+			},
+			guides: []float64{20, 80},
+		}
+	}
+	return nil
+}
+
+// canvas 绘图上下文，横向按 K 线数等分
+type canvas struct {
+	img         *image.RGBA
+	left, right int
+	n           int
+}
+
+// x 第 i 根 K 线的中心横坐标
+func (c *canvas) x(i int) int {
+	slot := float64(c.right-c.left) / float64(c.n)
+	return c.left + int((float64(i)+0.5)*slot)
+}
+
+// halfBody K 线实体半宽
+func (c *canvas) halfBody() int {
+	return int(float64(c.right-c.left) / float64(c.n) * 0.35)
+}
+
+func (c *canvas) drawMain(bars []models.KLineData, overlays []series, top, bottom int) {
+	p := panel{top: top, bottom: bottom, min: math.Inf(1), max: math.Inf(-1)}
+	for _, k := range bars {
+		p.min, p.max = math.Min(p.min, k.Low), math.Max(p.max, k.High)
+	}
+	for _, s := range overlays {
+		p.min, p.max = extend(p.min, p.max, s.values)
+	}
+	margin := (p.max - p.min) * 0.05
+	p.min, p.max = p.min-margin, p.max+margin
+
+	c.drawGrid(p, 4, formatPrice)
+	half := c.halfBody()
+	for i, k := range bars {
+		col := colorUp
+		if k.Close < k.Open {
+			col = colorDown
+		}
+		x := c.x(i)
+		vline(c.img, x, p.y(k.High), p.y(k.Low), col)
+		y0, y1 := p.y(math.Max(k.Open, k.Close)), p.y(math.Min(k.Open, k.Close))
+		fillRect(c.img, x-half, y0, x+half+1, y1+1, col)
+	}
+	for _, s := range overlays {
+		c.drawSeries(p, s)
+	}
+
+	// 最新收盘价标注在右侧刻度区
+	last := bars[len(bars)-1]
+	col := colorUp
+	if last.Close < last.Open {
+		col = colorDown
+	}
+	y := p.y(last.Close)
+	dashedHLine(c.img, c.left, c.right, y, col)
+	label := formatPrice(last.Close)
+	fillRect(c.img, c.right+2, y-7, c.right+6+textWidth(label, defaultScale), y+7, col)
+	drawText(c.img, c.right+4, y-5, label, defaultScale, colorBackground)
+
+	// 图例：指标名与最新值
+	legend := make([]series, 0, len(overlays))
+	for _, s := range overlays {
+		if s.label != "" {
+			legend = append(legend, s)
+		}
+	}
+	c.drawLegend(top-glyphHeight*defaultScale-4, legend)
+}
+
+func (c *canvas) drawVolume(bars []models.KLineData, top, bottom int) {
+	p := panel{top: top, bottom: bottom}
+	for _, k := range bars {
+		p.max = math.Max(p.max, float64(k.Volume))
+	}
+	hline(c.img, c.left, c.right, bottom, colorGrid)
+	drawText(c.img, c.right+4, top, formatVolume(p.max), defaultScale, colorText)
+	half := c.halfBody()
+	for i, k := range bars {
+		col := colorUp
+		if k.Close < k.Open {
+			col = colorDown
+		}
+		x := c.x(i)
+		fillRect(c.img, x-half, p.y(float64(k.Volume)), x+half+1, bottom+1, col)
+	}
+	drawText(c.img, c.left+2, top, "VOL", defaultScale, colorText)
+}
+
+func (c *canvas) drawOscillator(osc *oscillator, top, bottom int) {
+	p := panel{top: top, bottom: bottom, min: math.Inf(1), max: math.Inf(-1)}
+	for _, s := range osc.lines {
+		p.min, p.max = extend(p.min, p.max, s.values)
+	}
+	p.min, p.max = extend(p.min, p.max, osc.hist)
+	p.min, p.max = extend(p.min, p.max, osc.guides)
+
+	for _, g := range osc.guides {
+		dashedHLine(c.img, c.left, c.right, p.y(g), colorAxis)
+		drawText(c.img, c.right+4, p.y(g)-glyphHeight, formatPrice(g), defaultScale, colorText)
+	}
+	if osc.hist != nil {
+		zero := p.y(0)
+		for i, v := range osc.hist {
+			if math.IsNaN(v) {
+				continue
+			}
+			col := colorUp
+			if v < 0 {
+				col = colorDown
+			}
+			vline(c.img, c.x(i), zero, p.y(v), col)
+		}
+	}
+	for _, s := range osc.lines {
+		c.drawSeries(p, s)
+	}
+
+	legend := osc.lines
+	if osc.label != "" {
+		legend = append([]series{{label: osc.label, color: colorText}}, osc.lines...)
+	}
+	c.drawLegend(top, legend)
+}
+
+// drawLegend 在区域左上角依次绘制指标名及最新值
+func (c *canvas) drawLegend(y int, items []series) {
+	x := c.left + 2
+	for _, s := range items {
+		text := s.label
+		if len(s.values) > 0 {
+			if v := s.values[len(s.values)-1]; !math.IsNaN(v) {
+				text += " " + formatPrice(v)
+			}
+		}
+		if text == "" {
+			continue
+		}
+		drawText(c.img, x, y, text, defaultScale, s.color)
+		x += textWidth(text, defaultScale) + 12
+	}
+}
+
+// drawGrid 绘制水平网格线及右侧刻度
+func (c *canvas) drawGrid(p panel, lines int, format func(float64) string) {
+	for i := 0; i <= lines; i++ {
+		v := p.max - (p.max-p.min)*float64(i)/float64(lines)
+		y := p.y(v)
+		hline(c.img, c.left, c.right, y, colorGrid)
+		drawText(c.img, c.right+4, y-glyphHeight, format(v), defaultScale, colorText)
+	}
+	vline(c.img, c.right, p.top, p.bottom, colorAxis)
+}
+
+// drawSeries 连接相邻有效点绘制折线
+func (c *canvas) drawSeries(p panel, s series) {
+	for i := 1; i < len(s.values); i++ {
+		a, b := s.values[i-1], s.values[i]
+		if math.IsNaN(a) || math.IsNaN(b) {
+			continue
+		}
+		drawLine(c.img, c.x(i-1), p.y(a), c.x(i), p.y(b), s.color)
+	}
+}
+
+// drawDates 在底部标注首、中、末三根 K 线的时间
+func (c *canvas) drawDates(bars []models.KLineData, y int) {
+	first, mid, last := bars[0].Time, bars[len(bars)/2].Time, bars[len(bars)-1].Time
+	drawText(c.img, c.left, y, first, defaultScale, colorText)
+	if len(bars) > 2 {
+		w := textWidth(mid, defaultScale)
+		drawText(c.img, c.x(len(bars)/2)-w/2, y, mid, defaultScale, colorText)
+	}
+	if len(bars) > 1 {
+		drawText(c.img, c.right-textWidth(last, defaultScale), y, last, defaultScale, colorText)
+	}
+}
+
+// extend 用序列中的有效值扩展数值范围
+func extend(lo, hi float64, values []float64) (float64, float64) {
+	for _, v := range values {
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+	}
+	return lo, hi
+}
+
+// formatPrice 价格刻度，绝对值小于 1 时保留三位小数
+func formatPrice(v float64) string {
+	if math.Abs(v) < 1 && v != 0 {
+		return strconv.FormatFloat(v, 'f', 3, 64)
+	}
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// formatVolume 成交量刻度，以 K / M 缩写
+func formatVolume(v float64) string {
+	switch {
+	case v >= 1e6:
+		return strconv.FormatFloat(v/1e6, 'f', 1, 64) + "M"
+	case v >= 1e3:
+		return strconv.FormatFloat(v/1e3, 'f', 1, 64) + "K"
+	default:
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+}
+
+// fillRect 填充 [x0,x1)×[y0,y1) 矩形
+func fillRect(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA) {
+	r := image.Rect(x0, y0, x1, y1).Intersect(img.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+func hline(img *image.RGBA, x0, x1, y int, c color.RGBA) {
+	fillRect(img, x0, y, x1, y+1, c)
+}
+
+func vline(img *image.RGBA, x, y0, y1 int, c color.RGBA) {
+	if y0 > y1 {
+		y0, y1 = y1, y0
+	}
+	fillRect(img, x, y0, x+1, y1+1, c)
+}
+
+func dashedHLine(img *image.RGBA, x0, x1, y int, c color.RGBA) {
+	for x := x0; x < x1; x += 6 {
+		fillRect(img, x, y, min(x+3, x1), y+1, c)
+	}
+}
+
+// drawLine Bresenham 直线
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	bounds := img.Bounds()
+	for e := dx + dy; ; {
+		if (image.Point{X: x0, Y: y0}).In(bounds) {
+			img.SetRGBA(x0, y0, c)
+		}
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,101 @@
+package klinechart
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"math"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func testKLines(n int) []models.KLineData {
+	out := make([]models.KLineData, n)
+	for i := range out {
+		base := 10 + 2*math.Sin(float64(i)/5)
+		open, close := base, base+0.3*math.Cos(float64(i))
+		out[i] = models.KLineData{
+			Time:   fmt.Sprintf("2025-01-%02d", i%28+1),
+			Open:   open,
+			Close:  close,
+			High:   math.Max(open, close) + 0.2,
+			Low:    math.Min(open, close) - 0.2,
+			Volume: int64(1000 + i*37),
+		}
+	}
+	return out
+}
+
+func approx(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+func TestIndicators(t *testing.T) {
+	closes := []float64{1, 2, 3, 4, 5}
+
+	ma := sma(closes, 3)
+	if !math.IsNaN(ma[1]) || !approx(ma[2], 2) || !approx(ma[4], 4) {
+		t.Errorf("sma = %v", ma)
+	}
+	e := ema(closes, 3) // alpha = 0.5
+	if !approx(e[0], 1) || !approx(e[1], 1.5) || !approx(e[2], 2.25) {
+		t.Errorf("ema = %v", e)
+	}
+	mid, upper, lower := boll([]float64{2, 4, 4, 4, 5, 5, 7, 9}, 8, 2) // 总体标准差为 2
+	if !approx(mid[7], 5) || !approx(upper[7], 9) || !approx(lower[7], 1) {
+		t.Errorf("boll = %v %v %v", mid[7], upper[7], lower[7])
+	}
+	if r := rsi(closes, 3); !math.IsNaN(r[2]) || !approx(r[3], 100) {
+		t.Errorf("rsi on rising series = %v", r)
+	}
+	k, d, j := kdj(closes, closes, closes, 3, 3, 3)
+	// 收盘价位于区间最高点，RSV=100：K=(50*2+100)/3, D=(50*2+K)/3
+	if !approx(k[2], 200.0/3) || !approx(d[2], (100+200.0/3)/3) || !approx(j[2], 3*k[2]-2*d[2]) {
+		t.Errorf("kdj = %v %v %v", k[2], d[2], j[2])
+	}
+}
+
+func TestRenderPNG(t *testing.T) {
+	cfg := models.IndicatorConfig{
+		MA:   models.MAConfig{Enabled: true, Periods: []int{5, 10}},
+		BOLL: models.BOLLConfig{Enabled: true, Period: 20, Multiplier: 2},
+		MACD: models.MACDConfig{Enabled: true, Fast: 12, Slow: 26, Signal: 9},
+	}
+	data, err := RenderPNG(testKLines(150), Options{Width: 800, Height: 500, Indicators: cfg})
+	if err != nil {
+		t.Fatalf("RenderPNG: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 800 || b.Dy() != 500 {
+		t.Errorf("size = %v", b)
+	}
+
+	// 应同时出现涨跌两种颜色
+	var up, down bool
+	for y := 0; y < 500; y++ {
+		for x := 0; x < 800; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			c := [3]uint32{r >> 8, g >> 8, b >> 8}
+			up = up || c == [3]uint32{uint32(colorUp.R), uint32(colorUp.G), uint32(colorUp.B)}
+			down = down || c == [3]uint32{uint32(colorDown.R), uint32(colorDown.G), uint32(colorDown.B)}
+		}
+	}
+	if !up || !down {
+		t.Errorf("candle colors missing: up=%v down=%v", up, down)
+	}
+}
+
+func TestRender_Errors(t *testing.T) {
+	if _, err := Render(nil, Options{}); err == nil {
+		t.Error("expected error for empty klines")
+	}
+	if _, err := Render(testKLines(10), Options{Width: 90, Height: 60}); err == nil {
+		t.Error("expected error for tiny canvas")
+	}
+	// 单根 K 线与无指标配置也能渲染
+	if _, err := Render(testKLines(1), Options{}); err != nil {
+		t.Errorf("single bar: %v", err)
+	}
+}
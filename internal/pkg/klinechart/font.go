@@ -0,0 +1,74 @@
+package klinechart
+
+import (
+	"image"
+	"image/color"
+	"strings"
+)
+
+// glyphs 3×5 点阵字形，仅覆盖价格、日期与指标名所需字符
+var glyphs = map[rune][5]string{
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"###", "..#", "###", "#..", "###"},
+	'3': {"###", "..#", "###", "..#", "###"},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "###", "..#", "###"},
+	'6': {"###", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", "..#", "..#", "..#"},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "###"},
+	'.': {"...", "...", "...", "...", ".#."},
+	'-': {"...", "...", "###", "...", "..."},
+	':': {"...", ".#.", "...", ".#.", "..."},
+	'%': {"#.#", "..#", ".#.", "#..", "#.#"},
+	'A': {".#.", "#.#", "###", "#.#", "#.#"},
+	'B': {"##.", "#.#", "##.", "#.#", "##."},
+	'C': {"###", "#..", "#..", "#..", "###"},
+	'D': {"##.", "#.#", "#.#", "#.#", "##."},
+	'E': {"###", "#..", "###", "#..", "###"},
+	'F': {"###", "#..", "###", "#..", "#.."},
+	'G': {"###", "#..", "#.#", "#.#", "###"},
+	'H': {"#.#", "#.#", "###", "#.#", "#.#"},
+	'I': {"###", ".#.", ".#.", ".#.", "###"},
+	'J': {"..#", "..#", "..#", "#.#", "###"},
+	'K': {"#.#", "#.#", "##.", "#.#", "#.#"},
+	'L': {"#..", "#..", "#..", "#..", "###"},
+	'M': {"#.#", "###", "###", "#.#", "#.#"},
+	'N': {"##.", "#.#", "#.#", "#.#", "#.#"},
+	'O': {"###", "#.#", "#.#", "#.#", "###"},
+	'P': {"###", "#.#", "###", "#..", "#.."},
+	'R': {"##.", "#.#", "##.", "#.#", "#.#"},
+	'S': {"###", "#..", "###", "..#", "###"},
+	'T': {"###", ".#.", ".#.", ".#.", ".#."},
+	'U': {"#.#", "#.#", "#.#", "#.#", "###"},
+	'V': {"#.#", "#.#", "#.#", "#.#", ".#."},
+}
+
+// 字形尺寸（含 1 像素字间距）
+const (
+	glyphWidth   = 4
+	glyphHeight  = 5
+	defaultScale = 2
+)
+
+// textWidth 计算文本像素宽度
+func textWidth(s string, scale int) int {
+	return len([]rune(s)) * glyphWidth * scale
+}
+
+// drawText 以左上角 (x, y) 绘制文本，未收录字符按空格处理
+func drawText(img *image.RGBA, x, y int, s string, scale int, c color.RGBA) {
+	for _, r := range strings.ToUpper(s) {
+		if g, ok := glyphs[r]; ok {
+			for row, line := range g {
+				for col, bit := range line {
+					if bit == '#' {
+						fillRect(img, x+col*scale, y+row*scale, x+(col+1)*scale, y+(row+1)*scale, c)
+					}
+				}
+			}
+		}
+		x += glyphWidth * scale
+	}
+}
@@ -0,0 +1,134 @@
+package klinechart
+
+import "math"
+
+// 指标序列与输入等长，预热期不足周期处为 NaN
+
+// sma 简单移动平均
+func sma(values []float64, period int) []float64 {
+	out := nanSeries(len(values))
+	if period <= 0 {
+		return out
+	}
+	sum := 0.0
+	for i, v := range values {
+		sum += v
+		if i >= period {
+			sum -= values[i-period]
+		}
+		if i >= period-1 {
+			out[i] = sum / float64(period)
+		}
+	}
+	return out
+}
+
+// ema 指数移动平均，以首值为初值
+func ema(values []float64, period int) []float64 {
+	out := nanSeries(len(values))
+	if period <= 0 || len(values) == 0 {
+		return out
+	}
+	alpha := 2 / float64(period+1)
+	out[0] = values[0]
+	for i := 1; i < len(values); i++ {
+		out[i] = alpha*values[i] + (1-alpha)*out[i-1]
+	}
+	return out
+}
+
+// boll 布林带：中轨为 SMA，上下轨为中轨 ± multiplier 倍总体标准差
+func boll(closes []float64, period int, multiplier float64) (mid, upper, lower []float64) {
+	mid = sma(closes, period)
+	upper, lower = nanSeries(len(closes)), nanSeries(len(closes))
+	for i := range closes {
+		if math.IsNaN(mid[i]) {
+			continue
+		}
+		variance := 0.0
+		for _, v := range closes[i-period+1 : i+1] {
+			variance += (v - mid[i]) * (v - mid[i])
+		}
+		sd := math.Sqrt(variance / float64(period))
+		upper[i] = mid[i] + multiplier*sd
+		lower[i] = mid[i] - multiplier*sd
+	}
+	return mid, upper, lower
+}
+
+// macd 返回 DIF、DEA 与柱（按 A 股习惯为 2×(DIF−DEA)）
+func macd(closes []float64, fast, slow, signal int) (dif, dea, hist []float64) {
+	fastEMA, slowEMA := ema(closes, fast), ema(closes, slow)
+	dif = make([]float64, len(closes))
+	for i := range closes {
+		dif[i] = fastEMA[i] - slowEMA[i]
+	}
+	dea = ema(dif, signal)
+	hist = make([]float64, len(closes))
+	for i := range closes {
+		hist[i] = 2 * (dif[i] - dea[i])
+	}
+	return dif, dea, hist
+}
+
+// rsi 相对强弱指标（Wilder 平滑）
+func rsi(closes []float64, period int) []float64 {
+	out := nanSeries(len(closes))
+	if period <= 0 || len(closes) <= period {
+		return out
+	}
+	var gain, loss float64
+	for i := 1; i <= period; i++ {
+		d := closes[i] - closes[i-1]
+		gain += math.Max(d, 0)
+		loss += math.Max(-d, 0)
+	}
+	gain, loss = gain/float64(period), loss/float64(period)
+	out[period] = rsiValue(gain, loss)
+	for i := period + 1; i < len(closes); i++ {
+		d := closes[i] - closes[i-1]
+		gain = (gain*float64(period-1) + math.Max(d, 0)) / float64(period)
+		loss = (loss*float64(period-1) + math.Max(-d, 0)) / float64(period)
+		out[i] = rsiValue(gain, loss)
+	}
+	return out
+}
+
+func rsiValue(gain, loss float64) float64 {
+	if loss == 0 {
+		return 100
+	}
+	return 100 - 100/(1+gain/loss)
+}
+
+// kdj 随机指标，K、D 以 50 为初值按 1/k、1/d 平滑，J = 3K − 2D
+func kdj(highs, lows, closes []float64, period, kSmooth, dSmooth int) (k, d, j []float64) {
+	n := len(closes)
+	k, d, j = nanSeries(n), nanSeries(n), nanSeries(n)
+	if period <= 0 || kSmooth <= 0 || dSmooth <= 0 {
+		return k, d, j
+	}
+	prevK, prevD := 50.0, 50.0
+	for i := period - 1; i < n; i++ {
+		hi, lo := highs[i], lows[i]
+		for t := i - period + 1; t < i; t++ {
+			hi, lo = math.Max(hi, highs[t]), math.Min(lo, lows[t])
+		}
+		rsv := 50.0
+		if hi > lo {
+			rsv = (closes[i] - lo) / (hi - lo) * 100
+		}
+		prevK = (prevK*float64(kSmooth-1) + rsv) / float64(kSmooth)
+		prevD = (prevD*float64(dSmooth-1) + prevK) / float64(dSmooth)
+		k[i], d[i], j[i] = prevK, prevD, 3*prevK-2*prevD
+	}
+	return k, d, j
+}
+
+func nanSeries(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	return out
+}
@@ -0,0 +1,382 @@
+package pdftext
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"encoding/ascii85"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// objectHeader 匹配 "N G obj" 对象头
+var objectHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// maxDecodedSize 单个流解码后的大小上限
+const maxDecodedSize = 64 << 20
+
+// document 已加载对象的 PDF 文档
+type document struct {
+	objects map[int]any
+	trailer dict
+}
+
+// loadDocument 扫描文件体中的全部对象（不依赖 xref，兼容增量更新与损坏的交叉引用表）
+func loadDocument(data []byte) (*document, error) {
+	doc := &document{objects: make(map[int]any)}
+
+	skipUntil := 0
+	for _, loc := range objectHeader.FindAllSubmatchIndex(data, -1) {
+		// 跳过落在上一个对象（尤其是流数据）内部的误匹配
+		if loc[0] < skipUntil {
+			continue
+		}
+		num, err := strconv.Atoi(string(data[loc[2]:loc[3]]))
+		if err != nil {
+			continue
+		}
+		p := newParser(data)
+		p.pos = loc[1]
+		obj, err := p.parseObject()
+		if err != nil {
+			continue
+		}
+		if d, ok := obj.(dict); ok {
+			if s, ok := readStreamBody(p, d); ok {
+				obj = s
+			}
+		}
+		// 增量更新中后出现的对象覆盖先前版本
+		doc.objects[num] = obj
+		skipUntil = p.pos
+	}
+	if len(doc.objects) == 0 {
+		return nil, fmt.Errorf("未找到PDF对象")
+	}
+
+	doc.trailer = findTrailer(data)
+	for _, obj := range doc.objects {
+		if s, ok := obj.(*stream); ok && s.dict["Type"] == name("XRef") && doc.trailer == nil {
+			doc.trailer = s.dict
+		}
+	}
+	if doc.trailer != nil {
+		if _, ok := doc.trailer["Encrypt"]; ok {
+			return nil, ErrEncrypted
+		}
+	}
+
+	doc.loadObjectStreams()
+	return doc, nil
+}
+
+// readStreamBody 读取字典后的流数据
+func readStreamBody(p *parser, d dict) (*stream, bool) {
+	p.skipSpace()
+	if !bytes.HasPrefix(p.data[p.pos:], []byte("stream")) {
+		return nil, false
+	}
+	start := p.pos + len("stream")
+	if start < len(p.data) && p.data[start] == '\r' {
+		start++
+	}
+	if start < len(p.data) && p.data[start] == '\n' {
+		start++
+	}
+
+	if length, ok := toNumber(d["Length"]); ok {
+		end := start + int(length)
+		if end <= len(p.data) && end >= start {
+			rest := bytes.TrimLeft(p.data[end:min(end+32, len(p.data))], " \r\n\t")
+			if bytes.HasPrefix(rest, []byte("endstream")) {
+				p.pos = end
+				return &stream{dict: d, data: p.data[start:end]}, true
+			}
+		}
+	}
+	// Length 为间接引用或不准确时，回退到搜索 endstream
+	idx := bytes.Index(p.data[start:], []byte("endstream"))
+	if idx < 0 {
+		p.pos = len(p.data)
+		return &stream{dict: d, data: p.data[start:]}, true
+	}
+	p.pos = start + idx
+	body := p.data[start : start+idx]
+	body = bytes.TrimSuffix(body, []byte("\n"))
+	body = bytes.TrimSuffix(body, []byte("\r"))
+	return &stream{dict: d, data: body}, true
+}
+
+// findTrailer 解析最后一个 trailer 字典
+func findTrailer(data []byte) dict {
+	idx := bytes.LastIndex(data, []byte("trailer"))
+	if idx < 0 {
+		return nil
+	}
+	p := newParser(data)
+	p.pos = idx + len("trailer")
+	obj, err := p.parseObject()
+	if err != nil {
+		return nil
+	}
+	d, _ := obj.(dict)
+	return d
+}
+
+// loadObjectStreams 展开对象流（PDF 1.5+）中压缩存放的对象
+func (doc *document) loadObjectStreams() {
+	nums := make([]int, 0)
+	for num, obj := range doc.objects {
+		if s, ok := obj.(*stream); ok && s.dict["Type"] == name("ObjStm") {
+			nums = append(nums, num)
+		}
+	}
+	sort.Ints(nums)
+	for _, num := range nums {
+		s := doc.objects[num].(*stream)
+		data, err := doc.decodeStream(s)
+		if err != nil {
+			continue
+		}
+		n, _ := toNumber(doc.resolve(s.dict["N"]))
+		first, _ := toNumber(doc.resolve(s.dict["First"]))
+		if int(first) > len(data) {
+			continue
+		}
+
+		header := newParser(data[:int(first)])
+		for i := 0; i < int(n); i++ {
+			objNum, err1 := header.parseObject()
+			offset, err2 := header.parseObject()
+			if err1 != nil || err2 != nil {
+				break
+			}
+			on, ok1 := toNumber(objNum)
+			off, ok2 := toNumber(offset)
+			if !ok1 || !ok2 {
+				break
+			}
+			if _, exists := doc.objects[int(on)]; exists {
+				continue
+			}
+			p := newParser(data)
+			p.pos = int(first) + int(off)
+			if p.pos >= len(data) {
+				continue
+			}
+			obj, err := p.parseObject()
+			if err != nil {
+				continue
+			}
+			doc.objects[int(on)] = obj
+		}
+	}
+}
+
+// resolve 解析间接引用
+func (doc *document) resolve(v any) any {
+	for i := 0; i < 16; i++ {
+		r, ok := v.(ref)
+		if !ok {
+			return v
+		}
+		v = doc.objects[r.num]
+	}
+	return nil
+}
+
+func (doc *document) resolveDict(v any) dict {
+	switch obj := doc.resolve(v).(type) {
+	case dict:
+		return obj
+	case *stream:
+		return obj.dict
+	}
+	return nil
+}
+
+// decodeStream 按 Filter 解码流数据
+func (doc *document) decodeStream(s *stream) ([]byte, error) {
+	data := s.data
+	var filters []any
+	switch f := doc.resolve(s.dict["Filter"]).(type) {
+	case name:
+		filters = []any{f}
+	case array:
+		filters = f
+	}
+	for _, f := range filters {
+		var err error
+		switch doc.resolve(f) {
+		case name("FlateDecode"), name("Fl"):
+			data, err = inflate(data)
+		case name("ASCII85Decode"), name("A85"):
+			data, err = decodeASCII85(data)
+		case name("ASCIIHexDecode"), name("AHx"):
+			data, err = decodeASCIIHex(data)
+		default:
+			return nil, fmt.Errorf("unsupported filter: %v", f)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// inflate 解压 Flate 数据，截断的数据尽量返回已解出的部分
+func inflate(data []byte) ([]byte, error) {
+	var r io.Reader
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err == nil {
+		defer zr.Close()
+		r = zr
+	} else {
+		r = flate.NewReader(bytes.NewReader(data))
+	}
+	out, err := io.ReadAll(io.LimitReader(r, maxDecodedSize))
+	if err != nil && len(out) == 0 {
+		return nil, err
+	}
+	return out, nil
+}
+
+func decodeASCII85(data []byte) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	data = bytes.TrimPrefix(data, []byte("<~"))
+	if idx := bytes.Index(data, []byte("~>")); idx >= 0 {
+		data = data[:idx]
+	}
+	out := make([]byte, len(data)*4/5+4)
+	n, _, err := ascii85.Decode(out, data, true)
+	if err != nil {
+		return nil, err
+	}
+	return out[:n], nil
+}
+
+func decodeASCIIHex(data []byte) ([]byte, error) {
+	var digits []byte
+	for _, c := range data {
+		if c == '>' {
+			break
+		}
+		if !isSpace(c) {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, len(digits)/2)
+	_, err := hex.Decode(out, digits)
+	return out, err
+}
+
+// pageInfo 页面及其（含继承的）资源
+type pageInfo struct {
+	page      dict
+	resources dict
+}
+
+// pages 按页面树顺序返回全部页面
+func (doc *document) pages() []pageInfo {
+	var root dict
+	if doc.trailer != nil {
+		root = doc.resolveDict(doc.trailer["Root"])
+	}
+	if root == nil {
+		root = doc.findCatalog()
+	}
+
+	var result []pageInfo
+	if root != nil {
+		visited := make(map[int]bool)
+		var walk func(node any, inherited dict, depth int)
+		walk = func(node any, inherited dict, depth int) {
+			if r, ok := node.(ref); ok {
+				if visited[r.num] {
+					return
+				}
+				visited[r.num] = true
+			}
+			d := doc.resolveDict(node)
+			if d == nil || depth > 64 {
+				return
+			}
+			resources := inherited
+			if own := doc.resolveDict(d["Resources"]); own != nil {
+				resources = own
+			}
+			kids, hasKids := doc.resolve(d["Kids"]).(array)
+			if d["Type"] == name("Pages") || (hasKids && d["Type"] != name("Page")) {
+				for _, kid := range kids {
+					walk(kid, resources, depth+1)
+				}
+				return
+			}
+			result = append(result, pageInfo{page: d, resources: resources})
+		}
+		walk(root["Pages"], nil, 0)
+	}
+	if len(result) > 0 {
+		return result
+	}
+
+	// 页面树损坏时按对象编号收集页面
+	nums := make([]int, 0)
+	for num, obj := range doc.objects {
+		if d, ok := obj.(dict); ok && d["Type"] == name("Page") {
+			nums = append(nums, num)
+		}
+	}
+	sort.Ints(nums)
+	for _, num := range nums {
+		d := doc.objects[num].(dict)
+		result = append(result, pageInfo{page: d, resources: doc.resolveDict(d["Resources"])})
+	}
+	return result
+}
+
+func (doc *document) findCatalog() dict {
+	nums := make([]int, 0)
+	for num, obj := range doc.objects {
+		if d, ok := obj.(dict); ok && d["Type"] == name("Catalog") {
+			nums = append(nums, num)
+		}
+	}
+	if len(nums) == 0 {
+		return nil
+	}
+	sort.Ints(nums)
+	return doc.objects[nums[len(nums)-1]].(dict)
+}
+
+// pageContent 拼接页面的全部内容流
+func (doc *document) pageContent(page dict) []byte {
+	var streams []any
+	switch c := doc.resolve(page["Contents"]).(type) {
+	case *stream:
+		streams = []any{c}
+	case array:
+		streams = c
+	}
+	var buf bytes.Buffer
+	for _, item := range streams {
+		s, ok := doc.resolve(item).(*stream)
+		if !ok {
+			continue
+		}
+		data, err := doc.decodeStream(s)
+		if err != nil {
+			continue
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,180 @@
+package pdftext
+
+import (
+	"strings"
+	"unicode/utf16"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// fontDecoder 将字符串字节按字体编码转换为文本
+type fontDecoder struct {
+	codeLens  []int             // 码空间字节长度（升序）
+	toUni     map[string]string // ToUnicode 映射
+	composite bool              // Type0 复合字体（默认双字节编码）
+}
+
+// newFontDecoder 根据字体字典构建解码器
+func (doc *document) newFontDecoder(font dict) *fontDecoder {
+	f := &fontDecoder{toUni: make(map[string]string)}
+	if font == nil {
+		return f
+	}
+	f.composite = font["Subtype"] == name("Type0")
+	if s, ok := doc.resolve(font["ToUnicode"]).(*stream); ok {
+		if data, err := doc.decodeStream(s); err == nil {
+			f.parseCMap(data)
+		}
+	}
+	if len(f.codeLens) == 0 {
+		if f.composite {
+			f.codeLens = []int{2}
+		} else {
+			f.codeLens = []int{1}
+		}
+	}
+	return f
+}
+
+// parseCMap 解析 ToUnicode CMap 的码空间、bfchar 与 bfrange
+func (f *fontDecoder) parseCMap(data []byte) {
+	p := newParser(data)
+	var operands []any
+	lens := make(map[int]bool)
+	for !p.eof() {
+		obj, err := p.parseObject()
+		if err != nil {
+			break
+		}
+		kw, ok := obj.(keyword)
+		if !ok {
+			operands = append(operands, obj)
+			continue
+		}
+		switch kw {
+		case "endcodespacerange":
+			for i := 0; i+1 < len(operands); i += 2 {
+				if lo, ok := operands[i].(pdfString); ok && len(lo) > 0 {
+					lens[len(lo)] = true
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].(pdfString)
+				dst, ok2 := operands[i+1].(pdfString)
+				if ok1 && ok2 {
+					f.toUni[string(src)] = decodeUTF16(dst)
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].(pdfString)
+				hi, ok2 := operands[i+1].(pdfString)
+				if !ok1 || !ok2 || len(lo) != len(hi) || len(lo) == 0 {
+					continue
+				}
+				f.addRange(lo, hi, operands[i+2])
+			}
+		}
+		operands = operands[:0]
+	}
+	for n := 1; n <= 4; n++ {
+		if lens[n] {
+			f.codeLens = append(f.codeLens, n)
+		}
+	}
+}
+
+// addRange 展开 bfrange 条目
+func (f *fontDecoder) addRange(lo, hi pdfString, dst any) {
+	start, end := bytesToInt(lo), bytesToInt(hi)
+	if end < start || end-start > 0xFFFF {
+		return
+	}
+	for code := start; code <= end; code++ {
+		src := intToBytes(code, len(lo))
+		switch d := dst.(type) {
+		case pdfString:
+			// 目标为起始值时，末尾码元随源码递增
+			runes := utf16Units(d)
+			if len(runes) == 0 {
+				continue
+			}
+			runes[len(runes)-1] += uint16(code - start)
+			f.toUni[string(src)] = string(utf16.Decode(runes))
+		case array:
+			idx := code - start
+			if idx < len(d) {
+				if s, ok := d[idx].(pdfString); ok {
+					f.toUni[string(src)] = decodeUTF16(s)
+				}
+			}
+		}
+	}
+}
+
+// decode 解码字符串对象
+func (f *fontDecoder) decode(b []byte) string {
+	var sb strings.Builder
+	for i := 0; i < len(b); {
+		matched := false
+		if len(f.toUni) > 0 {
+			for _, n := range f.codeLens {
+				if i+n > len(b) {
+					break
+				}
+				if text, ok := f.toUni[string(b[i:i+n])]; ok {
+					sb.WriteString(text)
+					i += n
+					matched = true
+					break
+				}
+			}
+		}
+		if matched {
+			continue
+		}
+		if f.composite {
+			// 无映射的复合字体码无法还原文本，直接跳过
+			i += f.codeLens[len(f.codeLens)-1]
+			continue
+		}
+		if r := charmap.Windows1252.DecodeByte(b[i]); r >= 0x20 {
+			sb.WriteRune(r)
+		}
+		i++
+	}
+	return sb.String()
+}
+
+func utf16Units(b []byte) []uint16 {
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return units
+}
+
+func decodeUTF16(b []byte) string {
+	if len(b) == 1 {
+		return string(rune(b[0]))
+	}
+	return string(utf16.Decode(utf16Units(b)))
+}
+
+func bytesToInt(b []byte) int {
+	v := 0
+	for _, c := range b {
+		v = v<<8 | int(c)
+	}
+	return v
+}
+
+func intToBytes(v int, n int) []byte {
+	b := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		b[i] = byte(v)
+		v >>= 8
+	}
+	return b
+}
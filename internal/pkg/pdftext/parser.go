@@ -0,0 +1,345 @@
+package pdftext
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// name PDF 名称对象
+type name string
+
+// keyword 关键字或内容流操作符
+type keyword string
+
+// ref 间接对象引用
+type ref struct {
+	num int
+	gen int
+}
+
+// dict 字典对象
+type dict map[name]any
+
+// array 数组对象
+type array []any
+
+// pdfString 字符串对象（原始字节）
+type pdfString []byte
+
+// stream 流对象（未解码）
+type stream struct {
+	dict dict
+	data []byte
+}
+
+// parser PDF 语法解析器，同时用于文件体、对象流、内容流与 CMap
+type parser struct {
+	data []byte
+	pos  int
+}
+
+func newParser(data []byte) *parser {
+	return &parser{data: data}
+}
+
+func isSpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\r', '\n', '\f', 0:
+		return true
+	}
+	return false
+}
+
+func isDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+// skipSpace 跳过空白与注释
+func (p *parser) skipSpace() {
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		if isSpace(c) {
+			p.pos++
+			continue
+		}
+		if c == '%' {
+			for p.pos < len(p.data) && p.data[p.pos] != '\n' && p.data[p.pos] != '\r' {
+				p.pos++
+			}
+			continue
+		}
+		return
+	}
+}
+
+func (p *parser) eof() bool {
+	p.skipSpace()
+	return p.pos >= len(p.data)
+}
+
+// parseObject 解析一个对象，遇到数组或字典结束符时返回对应关键字
+func (p *parser) parseObject() (any, error) {
+	p.skipSpace()
+	if p.pos >= len(p.data) {
+		return nil, fmt.Errorf("unexpected EOF")
+	}
+	c := p.data[p.pos]
+	switch {
+	case c == '/':
+		return p.parseName(), nil
+	case c == '(':
+		return p.parseLiteralString(), nil
+	case c == '<':
+		if p.pos+1 < len(p.data) && p.data[p.pos+1] == '<' {
+			p.pos += 2
+			return p.parseDict()
+		}
+		return p.parseHexString(), nil
+	case c == '>':
+		if p.pos+1 < len(p.data) && p.data[p.pos+1] == '>' {
+			p.pos += 2
+			return keyword(">>"), nil
+		}
+		p.pos++
+		return keyword(">"), nil
+	case c == '[':
+		p.pos++
+		return p.parseArray()
+	case c == ']':
+		p.pos++
+		return keyword("]"), nil
+	case c == '{' || c == '}' || c == ')':
+		p.pos++
+		return keyword(string(c)), nil
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return p.parseNumberOrRef(), nil
+	default:
+		return p.parseKeyword(), nil
+	}
+}
+
+func (p *parser) parseName() name {
+	p.pos++
+	var buf []byte
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		if isSpace(c) || isDelimiter(c) {
+			break
+		}
+		if c == '#' && p.pos+2 < len(p.data) {
+			if v, err := strconv.ParseUint(string(p.data[p.pos+1:p.pos+3]), 16, 8); err == nil {
+				buf = append(buf, byte(v))
+				p.pos += 3
+				continue
+			}
+		}
+		buf = append(buf, c)
+		p.pos++
+	}
+	return name(buf)
+}
+
+func (p *parser) parseLiteralString() pdfString {
+	p.pos++
+	var buf []byte
+	depth := 1
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		p.pos++
+		switch c {
+		case '(':
+			depth++
+			buf = append(buf, c)
+		case ')':
+			depth--
+			if depth == 0 {
+				return buf
+			}
+			buf = append(buf, c)
+		case '\\':
+			if p.pos >= len(p.data) {
+				return buf
+			}
+			e := p.data[p.pos]
+			p.pos++
+			switch e {
+			case 'n':
+				buf = append(buf, '\n')
+			case 'r':
+				buf = append(buf, '\r')
+			case 't':
+				buf = append(buf, '\t')
+			case 'b':
+				buf = append(buf, '\b')
+			case 'f':
+				buf = append(buf, '\f')
+			case '\r':
+				if p.pos < len(p.data) && p.data[p.pos] == '\n' {
+					p.pos++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && p.pos < len(p.data) && p.data[p.pos] >= '0' && p.data[p.pos] <= '7'; i++ {
+						v = v*8 + int(p.data[p.pos]-'0')
+						p.pos++
+					}
+					buf = append(buf, byte(v))
+				} else {
+					buf = append(buf, e)
+				}
+			}
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return buf
+}
+
+func (p *parser) parseHexString() pdfString {
+	p.pos++
+	var digits []byte
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		p.pos++
+		if c == '>' {
+			break
+		}
+		if isSpace(c) {
+			continue
+		}
+		digits = append(digits, c)
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	buf := make([]byte, 0, len(digits)/2)
+	for i := 0; i+1 < len(digits); i += 2 {
+		v, err := strconv.ParseUint(string(digits[i:i+2]), 16, 8)
+		if err != nil {
+			continue
+		}
+		buf = append(buf, byte(v))
+	}
+	return buf
+}
+
+func (p *parser) parseDict() (dict, error) {
+	d := make(dict)
+	for {
+		key, err := p.parseObject()
+		if err != nil {
+			return d, err
+		}
+		if kw, ok := key.(keyword); ok && kw == ">>" {
+			return d, nil
+		}
+		k, ok := key.(name)
+		if !ok {
+			// 非法键，跳过以尽量容错
+			continue
+		}
+		value, err := p.parseObject()
+		if err != nil {
+			return d, err
+		}
+		if kw, ok := value.(keyword); ok && kw == ">>" {
+			return d, nil
+		}
+		d[k] = value
+	}
+}
+
+func (p *parser) parseArray() (array, error) {
+	var a array
+	for {
+		obj, err := p.parseObject()
+		if err != nil {
+			return a, err
+		}
+		if kw, ok := obj.(keyword); ok && kw == "]" {
+			return a, nil
+		}
+		a = append(a, obj)
+	}
+}
+
+func (p *parser) readRegular() []byte {
+	start := p.pos
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		if isSpace(c) || isDelimiter(c) {
+			break
+		}
+		p.pos++
+	}
+	return p.data[start:p.pos]
+}
+
+// parseNumberOrRef 解析数字，若后续为 "gen R" 则返回引用
+func (p *parser) parseNumberOrRef() any {
+	text := p.readRegular()
+	if len(text) == 0 {
+		p.pos++
+		return keyword("")
+	}
+	if bytes.IndexByte(text, '.') >= 0 {
+		v, _ := strconv.ParseFloat(string(text), 64)
+		return v
+	}
+	num, err := strconv.Atoi(string(text))
+	if err != nil {
+		v, _ := strconv.ParseFloat(string(text), 64)
+		return v
+	}
+	if num < 0 {
+		return float64(num)
+	}
+
+	save := p.pos
+	p.skipSpace()
+	genText := p.readRegular()
+	if gen, err := strconv.Atoi(string(genText)); err == nil && len(genText) > 0 {
+		p.skipSpace()
+		if p.pos < len(p.data) && p.data[p.pos] == 'R' && (p.pos+1 >= len(p.data) || isSpace(p.data[p.pos+1]) || isDelimiter(p.data[p.pos+1])) {
+			p.pos++
+			return ref{num: num, gen: gen}
+		}
+	}
+	p.pos = save
+	return float64(num)
+}
+
+func (p *parser) parseKeyword() any {
+	text := p.readRegular()
+	if len(text) == 0 {
+		p.pos++
+		return keyword("")
+	}
+	switch string(text) {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	return keyword(text)
+}
+
+// toNumber 将数字对象转换为 float64
+func toNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,253 @@
+// Package pdftext 提供纯 Go 的 PDF 文本提取，支持 Flate 压缩、对象流与 ToUnicode 映射的中文字体
+package pdftext
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+)
+
+// ErrEncrypted PDF 已加密，无法提取文本
+var ErrEncrypted = errors.New("PDF 已加密，暂不支持提取")
+
+// ErrNoText PDF 中未提取到文本
+var ErrNoText = errors.New("PDF 中未提取到文本")
+
+// maxFormDepth 表单 XObject 最大嵌套深度
+const maxFormDepth = 5
+
+// ExtractPages 提取 PDF 各页文本，返回结果下标即页码减一
+func ExtractPages(data []byte) ([]string, error) {
+	if !bytes.Contains(data[:min(len(data), 1024)], []byte("%PDF")) {
+		return nil, errors.New("不是有效的PDF文件")
+	}
+	doc, err := loadDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := doc.pages()
+	result := make([]string, 0, len(pages))
+	hasText := false
+	for _, page := range pages {
+		w := &textWriter{doc: doc, fonts: make(map[string]*fontDecoder), scaleY: 1}
+		w.run(doc.pageContent(page.page), page.resources, 0)
+		text := w.text()
+		if text != "" {
+			hasText = true
+		}
+		result = append(result, text)
+	}
+	if !hasText {
+		return result, ErrNoText
+	}
+	return result, nil
+}
+
+// textWriter 解释内容流并输出文本
+type textWriter struct {
+	doc   *document
+	fonts map[string]*fontDecoder
+	sb    strings.Builder
+
+	// 文本行位置：curY 为当前行基线，lastY 为上次输出文本的基线
+	curY    float64
+	lastY   float64
+	scaleY  float64
+	emitted bool
+}
+
+// run 解释一段内容流
+func (w *textWriter) run(content []byte, resources dict, depth int) {
+	p := newParser(content)
+	var (
+		operands []any
+		font     *fontDecoder
+	)
+	for !p.eof() {
+		obj, err := p.parseObject()
+		if err != nil {
+			break
+		}
+		op, ok := obj.(keyword)
+		if !ok {
+			operands = append(operands, obj)
+			continue
+		}
+
+		switch op {
+		case "BT":
+			w.curY, w.scaleY = 0, 1
+		case "Tf":
+			if len(operands) >= 2 {
+				if fontName, ok := operands[len(operands)-2].(name); ok {
+					font = w.font(resources, fontName)
+				}
+			}
+		case "Td", "TD":
+			if len(operands) >= 2 {
+				if ty, ok := toNumber(operands[len(operands)-1]); ok {
+					w.curY += ty * w.scaleY
+				}
+			}
+		case "T*":
+			w.newline()
+		case "Tm":
+			if len(operands) >= 6 {
+				if y, ok := toNumber(operands[len(operands)-1]); ok {
+					w.curY = y
+				}
+				if d, ok := toNumber(operands[len(operands)-3]); ok && d != 0 {
+					w.scaleY = math.Abs(d)
+				}
+			}
+		case "Tj":
+			if len(operands) >= 1 {
+				w.show(font, operands[len(operands)-1])
+			}
+		case "'":
+			w.newline()
+			if len(operands) >= 1 {
+				w.show(font, operands[len(operands)-1])
+			}
+		case "\"":
+			w.newline()
+			if len(operands) >= 3 {
+				w.show(font, operands[len(operands)-1])
+			}
+		case "TJ":
+			if len(operands) >= 1 {
+				if items, ok := operands[len(operands)-1].(array); ok {
+					for _, item := range items {
+						if n, ok := toNumber(item); ok {
+							// 较大的负向字距通常代表西文单词间的空格
+							if n < -250 && w.endsWithWordChar() {
+								w.sb.WriteByte(' ')
+							}
+							continue
+						}
+						w.show(font, item)
+					}
+				}
+			}
+		case "Do":
+			if len(operands) >= 1 && depth < maxFormDepth {
+				if xName, ok := operands[len(operands)-1].(name); ok {
+					w.runForm(resources, xName, depth)
+				}
+			}
+		case "BI":
+			skipInlineImage(p)
+		}
+		operands = operands[:0]
+	}
+}
+
+// runForm 解释表单 XObject 中的文本
+func (w *textWriter) runForm(resources dict, xName name, depth int) {
+	xobjects := w.doc.resolveDict(resources["XObject"])
+	if xobjects == nil {
+		return
+	}
+	s, ok := w.doc.resolve(xobjects[xName]).(*stream)
+	if !ok || s.dict["Subtype"] != name("Form") {
+		return
+	}
+	data, err := w.doc.decodeStream(s)
+	if err != nil {
+		return
+	}
+	formResources := resources
+	if own := w.doc.resolveDict(s.dict["Resources"]); own != nil {
+		formResources = own
+	}
+	// 表单拥有独立字体作用域，缓存按资源字典区分
+	saved := w.fonts
+	w.fonts = make(map[string]*fontDecoder)
+	w.run(data, formResources, depth+1)
+	w.fonts = saved
+}
+
+// font 获取资源中的字体解码器
+func (w *textWriter) font(resources dict, fontName name) *fontDecoder {
+	if f, ok := w.fonts[string(fontName)]; ok {
+		return f
+	}
+	var fontDict dict
+	if fonts := w.doc.resolveDict(resources["Font"]); fonts != nil {
+		fontDict = w.doc.resolveDict(fonts[fontName])
+	}
+	f := w.doc.newFontDecoder(fontDict)
+	w.fonts[string(fontName)] = f
+	return f
+}
+
+func (w *textWriter) show(font *fontDecoder, v any) {
+	s, ok := v.(pdfString)
+	if !ok {
+		return
+	}
+	if font == nil {
+		font = w.doc.newFontDecoder(nil)
+	}
+	// 基线变化即换行
+	if w.emitted && math.Abs(w.curY-w.lastY) > 1 {
+		w.newline()
+	}
+	w.lastY, w.emitted = w.curY, true
+	w.sb.WriteString(font.decode(s))
+}
+
+func (w *textWriter) newline() {
+	text := w.sb.String()
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		w.sb.WriteByte('\n')
+	}
+}
+
+func (w *textWriter) endsWithWordChar() bool {
+	text := w.sb.String()
+	if text == "" {
+		return false
+	}
+	c := text[len(text)-1]
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == ',' || c == '.'
+}
+
+// text 整理输出文本：去除行首尾空白与空行
+func (w *textWriter) text() string {
+	lines := strings.Split(w.sb.String(), "\n")
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(strings.ReplaceAll(line, "\u0000", ""))
+		if line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// skipInlineImage 跳过内联图像数据（BI ... ID <data> EI）
+func skipInlineImage(p *parser) {
+	idx := bytes.Index(p.data[p.pos:], []byte("ID"))
+	if idx < 0 {
+		p.pos = len(p.data)
+		return
+	}
+	p.pos += idx + 3
+	for p.pos+2 <= len(p.data) {
+		idx := bytes.Index(p.data[p.pos:], []byte("EI"))
+		if idx < 0 {
+			p.pos = len(p.data)
+			return
+		}
+		at := p.pos + idx
+		before := at == 0 || isSpace(p.data[at-1])
+		after := at+2 >= len(p.data) || isSpace(p.data[at+2])
+		p.pos = at + 2
+		if before && after {
+			return
+		}
+	}
+}
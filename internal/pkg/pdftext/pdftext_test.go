@@ -0,0 +1,82 @@
+package pdftext
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func flateStream(dict string, content string) string {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	zw.Write([]byte(content))
+	zw.Close()
+	return fmt.Sprintf("<< %s /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream", dict, buf.Len(), buf.String())
+}
+
+func buildPDF(objects []string) []byte {
+	var sb strings.Builder
+	sb.WriteString("%PDF-1.7\n")
+	for i, obj := range objects {
+		fmt.Fprintf(&sb, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	sb.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	return []byte(sb.String())
+}
+
+func TestExtractPages(t *testing.T) {
+	cmap := `/CIDInit /ProcSet findresource begin
+12 dict begin
+begincmap
+1 begincodespacerange
+<0000> <FFFF>
+endcodespacerange
+2 beginbfchar
+<0001> <7814>
+<0002> <62A5>
+endbfchar
+1 beginbfrange
+<0010> <0011> <0041>
+endbfrange
+endcmap
+end end`
+	page1 := "BT /F1 12 Tf 72 700 Td <00010002> Tj 0 -14 Td <00100011> Tj ET\n" +
+		"BT /F2 10 Tf 1 0 0 1 72 600 Tm [(Net) -300 (profit)] TJ ET"
+	page2 := "q /Fm1 Do Q"
+
+	pdf := buildPDF([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 /Resources << /Font << /F1 5 0 R /F2 7 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 8 0 R >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 9 0 R /Resources << /XObject << /Fm1 10 0 R >> /Font << /F1 5 0 R >> >> >>",
+		"<< /Type /Font /Subtype /Type0 /BaseFont /SimSun /Encoding /Identity-H /ToUnicode 6 0 R >>",
+		flateStream("", cmap),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		flateStream("", page1),
+		flateStream("", page2),
+		flateStream("/Type /XObject /Subtype /Form /BBox [0 0 100 100]", "BT /F1 9 Tf (\\000\\001\\000\\002) Tj ET"),
+	})
+
+	pages, err := ExtractPages(pdf)
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("pages = %d, want 2", len(pages))
+	}
+	if want := "研报\nAB\nNet profit"; pages[0] != want {
+		t.Errorf("page1 = %q, want %q", pages[0], want)
+	}
+	if want := "研报"; pages[1] != want {
+		t.Errorf("page2 = %q, want %q", pages[1], want)
+	}
+}
+
+func TestExtractPagesEncrypted(t *testing.T) {
+	pdf := []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj\ntrailer\n<< /Root 1 0 R /Encrypt 2 0 R >>\n")
+	if _, err := ExtractPages(pdf); err != ErrEncrypted {
+		t.Fatalf("err = %v, want ErrEncrypted", err)
+	}
+}
@@ -106,12 +106,37 @@ func (m *Manager) rebuildTransport() {
 		}
 	}
 
+	// 本地服务（如 Ollama）始终直连，不走代理
+	if m.transport.Proxy != nil {
+		m.transport.Proxy = bypassLoopback(m.transport.Proxy)
+	}
+
 	m.client = &http.Client{
 		Transport: m.transport,
 		Timeout:   30 * time.Second,
 	}
 }
 
+// bypassLoopback 包装代理函数，回环地址直接返回 nil
+func bypassLoopback(next func(*http.Request) (*url.URL, error)) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		if req != nil && req.URL != nil && isLoopbackHost(req.URL.Hostname()) {
+			return nil, nil
+		}
+		return next(req)
+	}
+}
+
+// isLoopbackHost 判断主机名是否为本机回环地址
+func isLoopbackHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
 // systemProxyFunc 获取系统代理（作为 Transport.Proxy 函数）
 func (m *Manager) systemProxyFunc(req *http.Request) (*url.URL, error) {
 	return resolveSystemProxy(req, runtime.GOOS, http.ProxyFromEnvironment, m.getOSProxy)
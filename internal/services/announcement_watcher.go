@@ -0,0 +1,388 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/models"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+var announceLog = logger.New("announce")
+
+const (
+	announceScheduleTick = time.Minute
+	announcePageSize     = 20
+	announceSeenMax      = 100 // 每只股票保留的已见公告数
+	announceEventsMax    = 200
+	announceLLMTimeout   = 60 * time.Second
+)
+
+// 公告事件分类
+const (
+	AnnounceEarnings   = "earnings"
+	AnnounceBuyback    = "buyback"
+	AnnouncePledge     = "pledge"
+	AnnounceReduction  = "reduction"
+	AnnounceLitigation = "litigation"
+	AnnounceContract   = "contract"
+	AnnounceSTRisk     = "st_risk"
+	AnnounceOther      = "other"
+)
+
+// announceRule 标题关键词分类规则
+type announceRule struct {
+	category string
+	name     string
+	severity string
+	keywords []string
+}
+
+// announceRules 按优先级排列的分类规则（先命中者生效）
+var announceRules = []announceRule{
+	{AnnounceSTRisk, "ST风险", "high", []string{"实施退市风险警示", "实施其他风险警示", "撤销退市风险警示", "撤销其他风险警示", "撤销风险警示", "暂停上市", "终止上市", "立案", "非标准", "无法表示意见", "资金占用"}},
+	{AnnounceReduction, "减持", "high", []string{"减持"}},
+	{AnnouncePledge, "股份质押", "medium", []string{"质押", "冻结"}},
+	{AnnounceBuyback, "回购", "medium", []string{"回购"}},
+	{AnnounceLitigation, "诉讼仲裁", "medium", []string{"诉讼", "仲裁", "起诉", "判决", "行政处罚"}},
+	{AnnounceContract, "重大合同", "medium", []string{"重大合同", "重大订单", "中标"}},
+	{AnnounceEarnings, "业绩", "medium", []string{"年度报告", "年报", "季度报告", "业绩预告", "业绩快报", "预增", "预减", "预亏", "预盈", "扭亏"}},
+}
+
+// announceState 公告监控持久化状态
+type announceState struct {
+	Seen   map[string][]string        `json:"seen"` // 股票代码 -> 已见公告编号
+	Events []models.AnnouncementEvent `json:"events"`
+}
+
+// AnnouncementWatcher 自选股公告监控服务
+type AnnouncementWatcher struct {
+	marketService *MarketService
+	configService *ConfigService
+	statePath     string
+
+	mu      sync.Mutex
+	state   announceState
+	lastRun time.Time
+
+	handler    func(models.AnnouncementEvent)
+	llmFactory func(ctx context.Context) (model.LLM, error)
+
+	checkMu  sync.Mutex
+	stopChan chan struct{}
+	stopOnce sync.Once
+}
+
+// NewAnnouncementWatcher 创建公告监控服务
+func NewAnnouncementWatcher(dataDir string, marketService *MarketService, configService *ConfigService) *AnnouncementWatcher {
+	w := &AnnouncementWatcher{
+		marketService: marketService,
+		configService: configService,
+		statePath:     filepath.Join(dataDir, "announcement_watch.json"),
+		state:         announceState{Seen: make(map[string][]string)},
+		stopChan:      make(chan struct{}),
+	}
+	w.load()
+	return w
+}
+
+// SetEventHandler 设置新公告事件回调
+func (w *AnnouncementWatcher) SetEventHandler(handler func(models.AnnouncementEvent)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handler = handler
+}
+
+// SetLLMFactory 设置 LLM 创建函数（用于规则未命中的公告分类）
+func (w *AnnouncementWatcher) SetLLMFactory(factory func(ctx context.Context) (model.LLM, error)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.llmFactory = factory
+}
+
+// load 从文件加载监控状态
+func (w *AnnouncementWatcher) load() {
+	data, err := os.ReadFile(w.statePath)
+	if err != nil {
+		return
+	}
+	var state announceState
+	if err := json.Unmarshal(data, &state); err != nil {
+		announceLog.Warn("解析公告监控状态失败: %v", err)
+		return
+	}
+	if state.Seen == nil {
+		state.Seen = make(map[string][]string)
+	}
+	w.state = state
+}
+
+// saveLocked 保存监控状态，调用方需持有锁
+func (w *AnnouncementWatcher) saveLocked() error {
+	if err := os.MkdirAll(filepath.Dir(w.statePath), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(w.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(w.statePath, data, 0644)
+}
+
+// Start 启动定时公告检查
+func (w *AnnouncementWatcher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(announceScheduleTick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.stopChan:
+				return
+			case <-ticker.C:
+				if w.due() {
+					w.CheckNow(ctx)
+				}
+			}
+		}
+	}()
+}
+
+// Stop 停止公告检查
+func (w *AnnouncementWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stopChan)
+	})
+}
+
+// due 判断是否到达配置的检查间隔
+func (w *AnnouncementWatcher) due() bool {
+	cfg := w.configService.GetConfig().AnnouncementWatch
+	if !cfg.Enabled {
+		return false
+	}
+	interval := time.Duration(cfg.IntervalMinutes) * time.Minute
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return time.Since(w.lastRun) >= interval
+}
+
+// CheckNow 立即检查自选股新公告，返回本次发现的事件
+func (w *AnnouncementWatcher) CheckNow(ctx context.Context) []models.AnnouncementEvent {
+	w.checkMu.Lock()
+	defer w.checkMu.Unlock()
+
+	w.mu.Lock()
+	w.lastRun = time.Now()
+	w.mu.Unlock()
+
+	var fresh []models.AnnouncementEvent
+	for _, stock := range w.configService.GetWatchlist() {
+		events, err := w.checkStock(stock)
+		if err != nil {
+			announceLog.Warn("获取公告失败 %s: %v", stock.Symbol, err)
+			continue
+		}
+		fresh = append(fresh, events...)
+	}
+	if len(fresh) == 0 {
+		return nil
+	}
+
+	if w.configService.GetConfig().AnnouncementWatch.UseLLM {
+		w.classifyWithLLM(ctx, fresh)
+	}
+
+	w.mu.Lock()
+	w.state.Events = append(w.state.Events, fresh...)
+	if len(w.state.Events) > announceEventsMax {
+		w.state.Events = w.state.Events[len(w.state.Events)-announceEventsMax:]
+	}
+	if err := w.saveLocked(); err != nil {
+		announceLog.Warn("保存公告监控状态失败: %v", err)
+	}
+	handler := w.handler
+	w.mu.Unlock()
+
+	announceLog.Info("发现 %d 条自选股新公告", len(fresh))
+	if handler != nil {
+		for _, event := range fresh {
+			handler(event)
+		}
+	}
+	return fresh
+}
+
+// checkStock 检查单只股票的新公告，首次检查仅建立基线不产生事件
+func (w *AnnouncementWatcher) checkStock(stock models.Stock) ([]models.AnnouncementEvent, error) {
+	result, err := w.marketService.GetStockAnnouncements(stock.Symbol, 1, announcePageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	seen, known := w.state.Seen[stock.Symbol]
+	seenSet := make(map[string]bool, len(seen))
+	for _, id := range seen {
+		seenSet[id] = true
+	}
+
+	var events []models.AnnouncementEvent
+	var newIDs []string
+	now := time.Now().UnixMilli()
+	for _, item := range result.Items {
+		if item.ArtCode == "" || seenSet[item.ArtCode] {
+			continue
+		}
+		seenSet[item.ArtCode] = true
+		newIDs = append(newIDs, item.ArtCode)
+		if !known {
+			continue
+		}
+		category := classifyAnnouncement(item.Title)
+		events = append(events, models.AnnouncementEvent{
+			ID:           item.ArtCode,
+			StockCode:    stock.Symbol,
+			StockName:    stock.Name,
+			Title:        item.Title,
+			NoticeDate:   announceDate(item.NoticeDate),
+			Category:     category.category,
+			CategoryName: category.name,
+			Severity:     category.severity,
+			Source:       "rule",
+			URL:          fmt.Sprintf("https://data.eastmoney.com/notices/detail/%s/%s.html", result.Code, item.ArtCode),
+			DetectedAt:   now,
+		})
+	}
+
+	seen = append(newIDs, seen...)
+	if len(seen) > announceSeenMax {
+		seen = seen[:announceSeenMax]
+	}
+	w.state.Seen[stock.Symbol] = seen
+	if !known {
+		if err := w.saveLocked(); err != nil {
+			announceLog.Warn("保存公告监控状态失败: %v", err)
+		}
+	}
+	return events, nil
+}
+
+// GetEvents 获取公告事件（按发现时间倒序），code 为空时返回全部
+func (w *AnnouncementWatcher) GetEvents(code string, limit int) []models.AnnouncementEvent {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	result := make([]models.AnnouncementEvent, 0)
+	for i := len(w.state.Events) - 1; i >= 0; i-- {
+		event := w.state.Events[i]
+		if code != "" && event.StockCode != code {
+			continue
+		}
+		result = append(result, event)
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+	}
+	return result
+}
+
+// classifyAnnouncement 按标题关键词对公告分类
+func classifyAnnouncement(title string) announceRule {
+	for _, rule := range announceRules {
+		for _, kw := range rule.keywords {
+			if strings.Contains(title, kw) {
+				return rule
+			}
+		}
+	}
+	return announceRule{category: AnnounceOther, name: "其他", severity: "low"}
+}
+
+// classifyWithLLM 使用 LLM 对规则未命中的公告补充分类
+func (w *AnnouncementWatcher) classifyWithLLM(ctx context.Context, events []models.AnnouncementEvent) {
+	w.mu.Lock()
+	factory := w.llmFactory
+	w.mu.Unlock()
+	if factory == nil {
+		return
+	}
+
+	var pending []int
+	for i, event := range events {
+		if event.Category == AnnounceOther {
+			pending = append(pending, i)
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, announceLLMTimeout)
+	defer cancel()
+	llm, err := factory(ctx)
+	if err != nil {
+		announceLog.Warn("创建公告分类模型失败: %v", err)
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("请将以下A股公告标题归类，可选类别：earnings(业绩)、buyback(回购)、pledge(质押)、reduction(减持)、litigation(诉讼仲裁)、contract(重大合同)、st_risk(ST/退市风险)、other(其他)。\n")
+	sb.WriteString("只输出JSON，格式为 {\"编号\": \"类别\"}。\n\n")
+	for _, i := range pending {
+		fmt.Fprintf(&sb, "%s: %s\n", events[i].ID, events[i].Title)
+	}
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{
+			{Role: "user", Parts: []*genai.Part{genai.NewPartFromText(sb.String())}},
+		},
+	}
+	var response strings.Builder
+	for resp, err := range llm.GenerateContent(ctx, req, false) {
+		if err != nil {
+			announceLog.Warn("LLM 公告分类失败: %v", err)
+			return
+		}
+		if resp != nil && resp.Content != nil {
+			for _, part := range resp.Content.Parts {
+				if !part.Thought && part.Text != "" {
+					response.WriteString(part.Text)
+				}
+			}
+		}
+	}
+
+	var labels map[string]string
+	if err := json.Unmarshal([]byte(extractJSON(response.String())), &labels); err != nil {
+		announceLog.Warn("解析 LLM 公告分类失败: %v", err)
+		return
+	}
+	for _, i := range pending {
+		label := strings.TrimSpace(labels[events[i].ID])
+		for _, rule := range announceRules {
+			if rule.category == label {
+				events[i].Category = rule.category
+				events[i].CategoryName = rule.name
+				events[i].Severity = rule.severity
+				events[i].Source = "llm"
+				break
+			}
+		}
+	}
+}
+
+// announceDate 截取公告日期部分
+func announceDate(noticeDate string) string {
+	if len(noticeDate) > 10 {
+		return noticeDate[:10]
+	}
+	return noticeDate
+}
@@ -0,0 +1,36 @@
+package services
+
+import "testing"
+
+func TestClassifyAnnouncement(t *testing.T) {
+	cases := []struct {
+		title    string
+		category string
+		severity string
+	}{
+		{"关于公司股票被实施退市风险警示的公告", AnnounceSTRisk, "high"},
+		{"关于公司股票交易被实施其他风险警示的公告", AnnounceSTRisk, "high"},
+		{"关于公司股票撤销退市风险警示及实施其他风险警示的公告", AnnounceSTRisk, "high"},
+		{"关于申请撤销其他风险警示的公告", AnnounceSTRisk, "high"},
+		{"关于持股5%以上股东减持股份计划的预披露公告", AnnounceReduction, "high"},
+		{"关于控股股东部分股份质押的公告", AnnouncePledge, "medium"},
+		{"关于以集中竞价交易方式回购公司股份的进展公告", AnnounceBuyback, "medium"},
+		{"关于公司涉及诉讼的公告", AnnounceLitigation, "medium"},
+		{"关于签订日常经营重大合同的公告", AnnounceContract, "medium"},
+		{"2025年半年度报告摘要", AnnounceEarnings, "medium"},
+		{"2025年第三季度业绩预告", AnnounceEarnings, "medium"},
+		{"关于召开2025年第一次临时股东大会的通知", AnnounceOther, "low"},
+		// 反例：ST 公司的常规公告、日常协议不应被误判
+		{"*ST东方关于股票交易异常波动的公告", AnnounceOther, "low"},
+		{"ST海华关于召开2025年年度股东大会的通知", AnnounceOther, "low"},
+		{"关于签订募集资金专户存储三方监管协议的公告", AnnounceOther, "low"},
+		{"关于签订战略合作框架协议的公告", AnnounceOther, "low"},
+		{"关于下属公司收到订单的自愿性信息披露公告", AnnounceOther, "low"},
+	}
+	for _, c := range cases {
+		got := classifyAnnouncement(c.title)
+		if got.category != c.category || got.severity != c.severity {
+			t.Errorf("classifyAnnouncement(%q) = %s/%s, want %s/%s", c.title, got.category, got.severity, c.category, c.severity)
+		}
+	}
+}
@@ -84,6 +84,12 @@ func (cs *ConfigService) loadConfig() error {
 				Enabled *bool `json:"enabled"`
 			} `json:"kdj"`
 		} `json:"indicators"`
+		AnnouncementWatch struct {
+			Enabled *bool `json:"enabled"`
+		} `json:"announcementWatch"`
+		EarningsCalendar struct {
+			AutoBriefing *bool `json:"autoBriefing"`
+		} `json:"earningsCalendar"`
 	}
 	if err := json.Unmarshal(data, &raw); err != nil {
 		return err
@@ -158,6 +164,24 @@ func (cs *ConfigService) loadConfig() error {
 	if ind.KDJ.D == 0 {
 		ind.KDJ.D = d.KDJ.D
 	}
+	if raw.AnnouncementWatch.Enabled == nil {
+		config.AnnouncementWatch.Enabled = defaultConfig.AnnouncementWatch.Enabled
+	}
+	if config.AnnouncementWatch.IntervalMinutes <= 0 {
+		config.AnnouncementWatch.IntervalMinutes = defaultConfig.AnnouncementWatch.IntervalMinutes
+	}
+	if raw.EarningsCalendar.AutoBriefing == nil {
+		config.EarningsCalendar.AutoBriefing = defaultConfig.EarningsCalendar.AutoBriefing
+	}
+	if config.EarningsCalendar.BriefingDaysBefore <= 0 {
+		config.EarningsCalendar.BriefingDaysBefore = defaultConfig.EarningsCalendar.BriefingDaysBefore
+	}
+	if config.LLMCache.TradingTTLMinutes <= 0 {
+		config.LLMCache.TradingTTLMinutes = defaultConfig.LLMCache.TradingTTLMinutes
+	}
+	if config.LLMCache.ClosedTTLMinutes <= 0 {
+		config.LLMCache.ClosedTTLMinutes = defaultConfig.LLMCache.ClosedTTLMinutes
+	}
 	cs.config = &config
 	return nil
 }
@@ -188,6 +212,19 @@ func (cs *ConfigService) defaultConfig() *models.AppConfig {
 			RSI:  models.RSIConfig{Enabled: false, Period: 14},
 			KDJ:  models.KDJConfig{Enabled: false, Period: 9, K: 3, D: 3},
 		},
+		AnnouncementWatch: models.AnnouncementWatchConfig{
+			Enabled:         true,
+			IntervalMinutes: 10,
+		},
+		EarningsCalendar: models.EarningsCalendarConfig{
+			AutoBriefing:       false, // 自动会议消耗 AI 额度，需用户主动开启
+			BriefingDaysBefore: 3,
+		},
+		LLMCache: models.LLMCacheConfig{
+			Enabled:           false,
+			TradingTTLMinutes: 5,
+			ClosedTTLMinutes:  720,
+		},
 	}
 }
 
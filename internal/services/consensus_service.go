@@ -0,0 +1,462 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+var consensusLog = logger.New("consensus")
+
+const (
+	consensusDefaultDays  = 180
+	consensusMaxDays      = 730
+	consensusRevisionDays = 30
+	consensusPageSize     = 100
+	consensusMaxPages     = 10
+	consensusHistoryMax   = 365
+)
+
+// consensusRatingRank 评级强弱排序（越大越看多）
+var consensusRatingRank = map[string]int{
+	"买入":   5,
+	"强烈推荐": 5,
+	"增持":   4,
+	"推荐":   4,
+	"优于大市": 4,
+	"跑赢行业": 4,
+	"谨慎推荐": 4,
+	"中性":   3,
+	"持有":   3,
+	"同步大市": 3,
+	"观望":   3,
+	"减持":   2,
+	"弱于大市": 2,
+	"跑输行业": 2,
+	"卖出":   1,
+}
+
+// consensusEstimate 单篇研报的解析结果
+type consensusEstimate struct {
+	Date       time.Time
+	Broker     string
+	Rating     string
+	LastRating string
+	EPS        map[int]float64
+	PE         map[int]float64
+}
+
+// ConsensusService 分析师一致预期服务
+type ConsensusService struct {
+	researchReportService *ResearchReportService
+	historyDir            string
+	mu                    sync.Mutex
+}
+
+// NewConsensusService 创建一致预期服务
+func NewConsensusService(dataDir string, researchReportService *ResearchReportService) *ConsensusService {
+	return &ConsensusService{
+		researchReportService: researchReportService,
+		historyDir:            filepath.Join(dataDir, "consensus"),
+	}
+}
+
+// GetConsensus 统计个股近 days 天的分析师一致预期，并写入当日历史快照
+func (s *ConsensusService) GetConsensus(code string, days int) (*models.AnalystConsensus, error) {
+	code = normalizeStockListCode(code)
+	if code == "" {
+		return nil, fmt.Errorf("未提供股票代码")
+	}
+	if s.researchReportService == nil {
+		return nil, fmt.Errorf("研报服务未初始化")
+	}
+	if days <= 0 {
+		days = consensusDefaultDays
+	}
+	if days > consensusMaxDays {
+		days = consensusMaxDays
+	}
+
+	now := time.Now().In(reviewLocation())
+	reports, err := s.fetchReports(code, now.AddDate(0, 0, -days))
+	if err != nil {
+		return nil, err
+	}
+	consensus := buildConsensus(code, reports, now, days, consensusRevisionDays)
+	if err := s.record(consensus); err != nil {
+		consensusLog.Warn("保存一致预期历史失败: %v", err)
+	}
+	return consensus, nil
+}
+
+// GetHistory 获取个股一致预期历史快照（按日期升序）
+func (s *ConsensusService) GetHistory(code string) []models.ConsensusSnapshot {
+	code = normalizeStockListCode(code)
+	if code == "" {
+		return []models.ConsensusSnapshot{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.loadLocked(code)
+}
+
+// fetchReports 翻页获取窗口期内的研报（接口按发布日期倒序返回）
+func (s *ConsensusService) fetchReports(code string, since time.Time) ([]ResearchReport, error) {
+	var reports []ResearchReport
+	for page := 1; page <= consensusMaxPages; page++ {
+		result, err := s.researchReportService.GetResearchReports(code, consensusPageSize, page)
+		if err != nil {
+			if len(reports) > 0 {
+				consensusLog.Warn("研报分页获取失败: code=%s, page=%d, err=%v", code, page, err)
+				break
+			}
+			return nil, err
+		}
+		reports = append(reports, result.Data...)
+		if len(result.Data) < consensusPageSize || page >= result.TotalPage {
+			break
+		}
+		if last := parseReportDate(result.Data[len(result.Data)-1].PublishDate); !last.IsZero() && last.Before(since) {
+			break
+		}
+	}
+	return reports, nil
+}
+
+// loadLocked 读取历史快照，调用方需持有锁
+func (s *ConsensusService) loadLocked(code string) []models.ConsensusSnapshot {
+	data, err := os.ReadFile(filepath.Join(s.historyDir, code+".json"))
+	if err != nil {
+		return []models.ConsensusSnapshot{}
+	}
+	var history []models.ConsensusSnapshot
+	if err := json.Unmarshal(data, &history); err != nil {
+		consensusLog.Warn("解析一致预期历史失败: code=%s, err=%v", code, err)
+		return []models.ConsensusSnapshot{}
+	}
+	return history
+}
+
+// record 按日写入历史快照（同日覆盖）
+func (s *ConsensusService) record(consensus *models.AnalystConsensus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history := s.loadLocked(consensus.Code)
+	snapshot := models.ConsensusSnapshot{
+		Date:        consensus.Date,
+		ReportCount: consensus.ReportCount,
+		BrokerCount: consensus.BrokerCount,
+		Years:       consensus.Years,
+		Ratings:     consensus.Ratings,
+		Upgrades:    consensus.Upgrades,
+		Downgrades:  consensus.Downgrades,
+	}
+	if n := len(history); n > 0 && history[n-1].Date == snapshot.Date {
+		history[n-1] = snapshot
+	} else {
+		history = append(history, snapshot)
+	}
+	if len(history) > consensusHistoryMax {
+		history = history[len(history)-consensusHistoryMax:]
+	}
+
+	if err := os.MkdirAll(s.historyDir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.historyDir, consensus.Code+".json"), data, 0644)
+}
+
+// buildConsensus 汇总研报得到一致预期
+// 每家券商只取其最新预测参与一致预期；评级变动与EPS修正按券商自身前后研报比较
+func buildConsensus(code string, reports []ResearchReport, now time.Time, days int, revisionDays int) *models.AnalystConsensus {
+	consensus := &models.AnalystConsensus{
+		Code:          code,
+		Date:          now.Format("2006-01-02"),
+		WindowDays:    days,
+		RevisionDays:  revisionDays,
+		Years:         []models.ConsensusYear{},
+		Ratings:       []models.RatingCount{},
+		PriorRatings:  []models.RatingCount{},
+		RatingChanges: []models.BrokerRatingChange{},
+	}
+	since := now.AddDate(0, 0, -days)
+	cutoff := now.AddDate(0, 0, -revisionDays)
+
+	byBroker := make(map[string][]consensusEstimate)
+	for _, report := range reports {
+		estimate, ok := parseConsensusEstimate(report)
+		if !ok || estimate.Date.Before(since) || estimate.Date.After(now) {
+			continue
+		}
+		if consensus.Name == "" {
+			consensus.Name = report.StockName
+		}
+		consensus.ReportCount++
+		byBroker[estimate.Broker] = append(byBroker[estimate.Broker], estimate)
+	}
+	consensus.BrokerCount = len(byBroker)
+
+	latest := make([]consensusEstimate, 0, len(byBroker))
+	prior := make([]consensusEstimate, 0, len(byBroker))
+	for broker, items := range byBroker {
+		sort.Slice(items, func(i, j int) bool { return items[i].Date.Before(items[j].Date) })
+		byBroker[broker] = items
+		latest = append(latest, items[len(items)-1])
+		for i := len(items) - 1; i >= 0; i-- {
+			if !items[i].Date.After(cutoff) {
+				prior = append(prior, items[i])
+				break
+			}
+		}
+		if change, ok := brokerRatingChange(items); ok {
+			consensus.RatingChanges = append(consensus.RatingChanges, change)
+			switch change.Direction {
+			case "upgrade":
+				consensus.Upgrades++
+			case "downgrade":
+				consensus.Downgrades++
+			}
+		}
+	}
+	sort.Slice(consensus.RatingChanges, func(i, j int) bool {
+		if consensus.RatingChanges[i].Date != consensus.RatingChanges[j].Date {
+			return consensus.RatingChanges[i].Date > consensus.RatingChanges[j].Date
+		}
+		return consensus.RatingChanges[i].Broker < consensus.RatingChanges[j].Broker
+	})
+
+	consensus.Years = consensusYears(latest, now.Year())
+	consensus.Ratings = ratingDistribution(latest)
+	consensus.PriorRatings = ratingDistribution(prior)
+	if len(consensus.Years) > 0 {
+		consensus.Revision = epsRevision(byBroker, prior, consensus.Years[0], cutoff)
+	}
+	return consensus
+}
+
+// consensusYears 按财年汇总各券商最新EPS/PE预测
+func consensusYears(latest []consensusEstimate, fromYear int) []models.ConsensusYear {
+	epsByYear := make(map[int][]float64)
+	peByYear := make(map[int][]float64)
+	for _, estimate := range latest {
+		for year, eps := range estimate.EPS {
+			if year >= fromYear {
+				epsByYear[year] = append(epsByYear[year], eps)
+			}
+		}
+		for year, pe := range estimate.PE {
+			if year >= fromYear && pe > 0 {
+				peByYear[year] = append(peByYear[year], pe)
+			}
+		}
+	}
+
+	years := make([]models.ConsensusYear, 0, len(epsByYear))
+	for year, values := range epsByYear {
+		sort.Float64s(values)
+		item := models.ConsensusYear{
+			Year:      year,
+			EPS:       roundConsensus(meanFloat(values), 4),
+			EPSMedian: roundConsensus(medianSorted(values), 4),
+			EPSLow:    values[0],
+			EPSHigh:   values[len(values)-1],
+			Count:     len(values),
+		}
+		if pes := peByYear[year]; len(pes) > 0 {
+			item.PE = roundConsensus(meanFloat(pes), 2)
+		}
+		years = append(years, item)
+	}
+	sort.Slice(years, func(i, j int) bool { return years[i].Year < years[j].Year })
+	return years
+}
+
+// ratingDistribution 统计评级分布（按评级强弱降序）
+func ratingDistribution(estimates []consensusEstimate) []models.RatingCount {
+	counts := make(map[string]int)
+	for _, estimate := range estimates {
+		if estimate.Rating != "" {
+			counts[estimate.Rating]++
+		}
+	}
+	result := make([]models.RatingCount, 0, len(counts))
+	for rating, count := range counts {
+		result = append(result, models.RatingCount{Rating: rating, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		ri, rj := consensusRatingRank[result[i].Rating], consensusRatingRank[result[j].Rating]
+		if ri != rj {
+			return ri > rj
+		}
+		return result[i].Rating < result[j].Rating
+	})
+	return result
+}
+
+// brokerRatingChange 比较券商最新评级与其上一篇研报（或接口给出的上次评级）
+func brokerRatingChange(items []consensusEstimate) (models.BrokerRatingChange, bool) {
+	last := items[len(items)-1]
+	if last.Rating == "" {
+		return models.BrokerRatingChange{}, false
+	}
+	change := models.BrokerRatingChange{
+		Broker: last.Broker,
+		Date:   last.Date.Format("2006-01-02"),
+		To:     last.Rating,
+	}
+	for i := len(items) - 2; i >= 0; i-- {
+		if items[i].Rating != "" {
+			change.From = items[i].Rating
+			break
+		}
+	}
+	if change.From == "" {
+		change.From = last.LastRating
+	}
+
+	from, to := consensusRatingRank[change.From], consensusRatingRank[change.To]
+	switch {
+	case change.From == "":
+		change.Direction = "initiate"
+	case from == 0 || to == 0 || from == to:
+		change.Direction = "maintain"
+	case to > from:
+		change.Direction = "upgrade"
+	default:
+		change.Direction = "downgrade"
+	}
+	return change, true
+}
+
+// epsRevision 计算指定财年的EPS修正动量
+func epsRevision(byBroker map[string][]consensusEstimate, prior []consensusEstimate, year models.ConsensusYear, cutoff time.Time) *models.EPSRevision {
+	revision := &models.EPSRevision{Year: year.Year, ConsensusNow: year.EPS}
+
+	var changes []float64
+	for _, items := range byBroker {
+		var values []float64
+		for _, item := range items {
+			if eps, ok := item.EPS[year.Year]; ok {
+				values = append(values, eps)
+			}
+		}
+		if len(values) < 2 {
+			continue
+		}
+		first, last := values[0], values[len(values)-1]
+		switch {
+		case last > first:
+			revision.Up++
+		case last < first:
+			revision.Down++
+		default:
+			revision.Unchanged++
+		}
+		if first != 0 {
+			changes = append(changes, (last-first)/math.Abs(first)*100)
+		}
+	}
+	if len(changes) > 0 {
+		revision.AvgChangePct = roundConsensus(meanFloat(changes), 2)
+	}
+
+	var priorValues []float64
+	for _, item := range prior {
+		if eps, ok := item.EPS[year.Year]; ok && !item.Date.After(cutoff) {
+			priorValues = append(priorValues, eps)
+		}
+	}
+	if len(priorValues) > 0 {
+		revision.ConsensusPrior = roundConsensus(meanFloat(priorValues), 4)
+		if revision.ConsensusPrior != 0 {
+			revision.MomentumPct = roundConsensus((revision.ConsensusNow-revision.ConsensusPrior)/math.Abs(revision.ConsensusPrior)*100, 2)
+		}
+	}
+	return revision
+}
+
+// parseConsensusEstimate 解析研报的券商、评级与分年度预测（今年按发布年份计）
+func parseConsensusEstimate(report ResearchReport) (consensusEstimate, bool) {
+	date := parseReportDate(report.PublishDate)
+	broker := strings.TrimSpace(report.OrgSName)
+	if date.IsZero() || broker == "" {
+		return consensusEstimate{}, false
+	}
+	estimate := consensusEstimate{
+		Date:       date,
+		Broker:     broker,
+		Rating:     strings.TrimSpace(report.EmRatingName),
+		LastRating: strings.TrimSpace(report.LastEmRatingName),
+		EPS:        make(map[int]float64),
+		PE:         make(map[int]float64),
+	}
+	year := date.Year()
+	for offset, pair := range [][2]string{
+		{report.PredictThisYearEps, report.PredictThisYearPe},
+		{report.PredictNextYearEps, report.PredictNextYearPe},
+		{report.PredictNextTwoYearEps, report.PredictNextTwoYearPe},
+	} {
+		if eps, err := strconv.ParseFloat(strings.TrimSpace(pair[0]), 64); err == nil {
+			estimate.EPS[year+offset] = eps
+		}
+		if pe, err := strconv.ParseFloat(strings.TrimSpace(pair[1]), 64); err == nil {
+			estimate.PE[year+offset] = pe
+		}
+	}
+	return estimate, true
+}
+
+// parseReportDate 解析研报发布日期（如 2024-04-30 00:00:00.000）
+func parseReportDate(text string) time.Time {
+	text = strings.TrimSpace(text)
+	if len(text) < 10 {
+		return time.Time{}
+	}
+	date, err := time.ParseInLocation("2006-01-02", text[:10], reviewLocation())
+	if err != nil {
+		return time.Time{}
+	}
+	return date
+}
+
+func meanFloat(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func medianSorted(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
+
+func roundConsensus(value float64, digits int) float64 {
+	scale := math.Pow(10, float64(digits))
+	return math.Round(value*scale) / scale
+}
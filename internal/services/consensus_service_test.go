@@ -0,0 +1,43 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildConsensus(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, reviewLocation())
+	reports := []ResearchReport{
+		{StockName: "测试股份", OrgSName: "甲证券", PublishDate: "2025-06-20 00:00:00.000", EmRatingName: "买入", PredictThisYearEps: "1.2", PredictThisYearPe: "20", PredictNextYearEps: "1.5"},
+		{StockName: "测试股份", OrgSName: "甲证券", PublishDate: "2025-03-01 00:00:00.000", EmRatingName: "增持", PredictThisYearEps: "1.0", PredictThisYearPe: "24"},
+		{StockName: "测试股份", OrgSName: "乙证券", PublishDate: "2025-06-10 00:00:00.000", EmRatingName: "中性", LastEmRatingName: "买入", PredictThisYearEps: "0.8", PredictThisYearPe: "30"},
+		{StockName: "测试股份", OrgSName: "丙证券", PublishDate: "2025-04-01 00:00:00.000", EmRatingName: "买入", PredictThisYearEps: "1.0"},
+		{StockName: "测试股份", OrgSName: "丁证券", PublishDate: "2024-01-01 00:00:00.000", EmRatingName: "卖出", PredictThisYearEps: "0.1"},
+	}
+
+	got := buildConsensus("600000", reports, now, 180, 30)
+	if got.ReportCount != 4 || got.BrokerCount != 3 || got.Name != "测试股份" {
+		t.Fatalf("unexpected counts: %+v", got)
+	}
+	if len(got.Years) != 2 || got.Years[0].Year != 2025 || got.Years[0].EPS != 1 || got.Years[0].Count != 3 {
+		t.Fatalf("unexpected years: %+v", got.Years)
+	}
+	if got.Years[0].EPSHigh != 1.2 || got.Years[0].EPSLow != 0.8 || got.Years[0].PE != 25 {
+		t.Fatalf("unexpected year stats: %+v", got.Years[0])
+	}
+	if got.Upgrades != 1 || got.Downgrades != 1 {
+		t.Fatalf("upgrades=%d downgrades=%d, changes=%+v", got.Upgrades, got.Downgrades, got.RatingChanges)
+	}
+	if len(got.Ratings) != 2 || got.Ratings[0].Rating != "买入" || got.Ratings[0].Count != 2 {
+		t.Fatalf("unexpected ratings: %+v", got.Ratings)
+	}
+
+	revision := got.Revision
+	if revision == nil || revision.Year != 2025 || revision.Up != 1 || revision.AvgChangePct != 20 {
+		t.Fatalf("unexpected revision: %+v", revision)
+	}
+	// 30天前：甲1.0、丙1.0 → 当前一致预期1.0
+	if revision.ConsensusPrior != 1 || revision.MomentumPct != 0 {
+		t.Fatalf("unexpected momentum: %+v", revision)
+	}
+}
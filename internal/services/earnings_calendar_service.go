@@ -0,0 +1,476 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+var earningsLog = logger.New("earnings")
+
+const (
+	earningsScheduleTick   = 30 * time.Minute
+	earningsRecentDays     = 30  // 已披露条目保留天数
+	earningsConsensusDays  = 180 // 一致预期统计窗口
+	earningsBeatThreshold  = 5.0 // 超/低于一致预期的判定阈值(%)
+	earningsComparisonsMax = 200
+)
+
+// earningsState 财报日历持久化状态
+type earningsState struct {
+	Briefed     map[string]string           `json:"briefed"` // 股票|报告期 -> 前瞻会议日期
+	Comparisons []models.EarningsComparison `json:"comparisons"`
+}
+
+// EarningsCalendarService 自选股财报日历服务
+type EarningsCalendarService struct {
+	f10Service       *F10Service
+	consensusService *ConsensusService
+	configService    *ConfigService
+	statePath        string
+
+	mu    sync.Mutex
+	state earningsState
+
+	briefingHandler   func(models.EarningsCalendarItem) error
+	comparisonHandler func(models.EarningsComparison)
+	briefing          map[string]bool // 进行中的前瞻会议，避免下次调度重复发起
+
+	stopChan chan struct{}
+	stopOnce sync.Once
+}
+
+// NewEarningsCalendarService 创建财报日历服务
+func NewEarningsCalendarService(dataDir string, f10Service *F10Service, consensusService *ConsensusService, configService *ConfigService) *EarningsCalendarService {
+	s := &EarningsCalendarService{
+		f10Service:       f10Service,
+		consensusService: consensusService,
+		configService:    configService,
+		statePath:        filepath.Join(dataDir, "earnings_calendar.json"),
+		state:            earningsState{Briefed: make(map[string]string)},
+		briefing:         make(map[string]bool),
+		stopChan:         make(chan struct{}),
+	}
+	s.load()
+	return s
+}
+
+// SetBriefingHandler 设置披露前瞻会议回调，返回错误时不标记已召开，下次调度重试
+func (s *EarningsCalendarService) SetBriefingHandler(handler func(models.EarningsCalendarItem) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.briefingHandler = handler
+}
+
+// SetComparisonHandler 设置披露后业绩对比回调
+func (s *EarningsCalendarService) SetComparisonHandler(handler func(models.EarningsComparison)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.comparisonHandler = handler
+}
+
+// load 从文件加载状态
+func (s *EarningsCalendarService) load() {
+	data, err := os.ReadFile(s.statePath)
+	if err != nil {
+		return
+	}
+	var state earningsState
+	if err := json.Unmarshal(data, &state); err != nil {
+		earningsLog.Warn("解析财报日历状态失败: %v", err)
+		return
+	}
+	if state.Briefed == nil {
+		state.Briefed = make(map[string]string)
+	}
+	s.state = state
+}
+
+// saveLocked 保存状态，调用方需持有锁
+func (s *EarningsCalendarService) saveLocked() error {
+	if err := os.MkdirAll(filepath.Dir(s.statePath), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.statePath, data, 0644)
+}
+
+// Start 启动财报日历定时任务（前瞻会议与披露后对比）
+func (s *EarningsCalendarService) Start() {
+	go func() {
+		ticker := time.NewTicker(earningsScheduleTick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stopChan:
+				return
+			case <-ticker.C:
+				s.runSchedule()
+			}
+		}
+	}()
+}
+
+// Stop 停止定时任务
+func (s *EarningsCalendarService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopChan)
+	})
+}
+
+// GetCalendar 获取自选股财报日历，days 为向后查看的天数
+func (s *EarningsCalendarService) GetCalendar(days int) []models.EarningsCalendarItem {
+	if days <= 0 {
+		days = 60
+	}
+	today := time.Now().In(reviewLocation())
+	var items []models.EarningsCalendarItem
+	for _, stock := range s.configService.GetWatchlist() {
+		events, err := s.f10Service.GetPerformanceEventsByCode(stock.Symbol)
+		if err != nil && len(events.Schedule) == 0 {
+			earningsLog.Warn("获取业绩事件失败 %s: %v", stock.Symbol, err)
+			continue
+		}
+		items = append(items, buildEarningsCalendar(stock, events.Schedule, today, days)...)
+	}
+
+	s.mu.Lock()
+	for i := range items {
+		_, items[i].Briefed = s.state.Briefed[earningsKey(items[i].StockCode, items[i].ReportDate)]
+	}
+	s.mu.Unlock()
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].AppointDate < items[j].AppointDate
+	})
+	return items
+}
+
+// GetComparisons 获取业绩对比记录（按生成时间倒序），code 为空时返回全部
+func (s *EarningsCalendarService) GetComparisons(code string) []models.EarningsComparison {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]models.EarningsComparison, 0)
+	for i := len(s.state.Comparisons) - 1; i >= 0; i-- {
+		if code == "" || s.state.Comparisons[i].StockCode == code {
+			result = append(result, s.state.Comparisons[i])
+		}
+	}
+	return result
+}
+
+// Compare 对指定股票最近一期已披露财报做预期对比
+func (s *EarningsCalendarService) Compare(stock models.Stock, reportDate string) (*models.EarningsComparison, error) {
+	events, err := s.f10Service.GetPerformanceEventsByCode(stock.Symbol)
+	if err != nil && len(events.Schedule) == 0 && len(events.Express) == 0 {
+		return nil, err
+	}
+	actualDate := ""
+	for _, row := range events.Schedule {
+		item := normalizedRecord(row)
+		date := recordDate(item["actualDate"])
+		if date == "" {
+			continue
+		}
+		if reportDate == "" || recordDate(item["reportDate"]) == reportDate {
+			reportDate = recordDate(item["reportDate"])
+			actualDate = date
+			break
+		}
+	}
+	if reportDate == "" {
+		return nil, fmt.Errorf("暂无已披露财报")
+	}
+
+	actual, ok := s.findActual(stock.Symbol, reportDate, events.Express)
+	if !ok {
+		return nil, fmt.Errorf("未找到 %s 报告期实际业绩", reportDate)
+	}
+	actual.ReportDate = reportDate
+
+	var consensus *models.ConsensusYear
+	if strings.HasSuffix(reportDate, "12-31") && s.consensusService != nil {
+		year := 0
+		fmt.Sscanf(reportDate[:4], "%d", &year)
+		if data, err := s.consensusService.GetConsensus(stock.Symbol, earningsConsensusDays); err == nil {
+			for i := range data.Years {
+				if data.Years[i].Year == year {
+					consensus = &data.Years[i]
+					break
+				}
+			}
+		}
+	}
+
+	comparison := compareEarnings(actual, events.Forecast, consensus)
+	comparison.StockCode = stock.Symbol
+	comparison.StockName = stock.Name
+	comparison.ActualDate = actualDate
+	comparison.CreatedAt = time.Now().UnixMilli()
+
+	s.mu.Lock()
+	kept := s.state.Comparisons[:0]
+	for _, c := range s.state.Comparisons {
+		if c.StockCode != comparison.StockCode || c.ReportDate != comparison.ReportDate {
+			kept = append(kept, c)
+		}
+	}
+	s.state.Comparisons = append(kept, comparison)
+	if len(s.state.Comparisons) > earningsComparisonsMax {
+		s.state.Comparisons = s.state.Comparisons[len(s.state.Comparisons)-earningsComparisonsMax:]
+	}
+	if err := s.saveLocked(); err != nil {
+		earningsLog.Warn("保存业绩对比失败: %v", err)
+	}
+	s.mu.Unlock()
+	return &comparison, nil
+}
+
+// runSchedule 检查需召开前瞻会议与需做披露后对比的条目
+func (s *EarningsCalendarService) runSchedule() {
+	cfg := s.configService.GetConfig().EarningsCalendar
+	stocks := make(map[string]models.Stock)
+	for _, stock := range s.configService.GetWatchlist() {
+		stocks[stock.Symbol] = stock
+	}
+
+	for _, item := range s.GetCalendar(cfg.BriefingDaysBefore) {
+		key := earningsKey(item.StockCode, item.ReportDate)
+		switch {
+		case item.Status == "upcoming" && cfg.AutoBriefing && !item.Briefed && item.DaysLeft <= cfg.BriefingDaysBefore:
+			s.mu.Lock()
+			handler := s.briefingHandler
+			running := s.briefing[key]
+			if handler != nil && !running {
+				s.briefing[key] = true
+			}
+			s.mu.Unlock()
+			if handler == nil || running {
+				continue
+			}
+			// 每场会议独立运行，避免阻塞后续条目
+			go s.brief(key, item, handler)
+		case item.Status == "disclosed" && !s.hasComparison(item.StockCode, item.ReportDate):
+			comparison, err := s.Compare(stocks[item.StockCode], item.ReportDate)
+			if err != nil {
+				earningsLog.Warn("业绩对比失败 %s %s: %v", item.StockCode, item.ReportDate, err)
+				continue
+			}
+			s.mu.Lock()
+			handler := s.comparisonHandler
+			s.mu.Unlock()
+			if handler != nil {
+				handler(*comparison)
+			}
+		}
+	}
+}
+
+// brief 召开前瞻会议，成功后才标记已召开
+func (s *EarningsCalendarService) brief(key string, item models.EarningsCalendarItem, handler func(models.EarningsCalendarItem) error) {
+	earningsLog.Info("召开财报前瞻会议: %s %s", item.StockCode, item.ReportDate)
+	err := handler(item)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.briefing, key)
+	if err != nil {
+		earningsLog.Warn("财报前瞻会议未完成 %s %s，下次调度重试: %v", item.StockCode, item.ReportDate, err)
+		return
+	}
+	s.state.Briefed[key] = time.Now().In(reviewLocation()).Format("2006-01-02")
+	if err := s.saveLocked(); err != nil {
+		earningsLog.Warn("保存财报日历状态失败: %v", err)
+	}
+}
+
+// hasComparison 判断报告期是否已生成对比
+func (s *EarningsCalendarService) hasComparison(code, reportDate string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.state.Comparisons {
+		if c.StockCode == code && c.ReportDate == reportDate {
+			return true
+		}
+	}
+	return false
+}
+
+// findActual 获取报告期实际业绩，优先定期报告利润表，其次业绩快报
+func (s *EarningsCalendarService) findActual(code, reportDate string, express []map[string]any) (models.EarningsComparison, bool) {
+	// 上游失败时可能返回缓存数据，直接按报告期查找
+	statements, _ := s.f10Service.GetFinancialStatementsByCode(code)
+	for _, row := range statements.Income {
+		if recordDate(row["REPORT_DATE"]) != reportDate {
+			continue
+		}
+		return models.EarningsComparison{
+			ActualSource: "report",
+			NetProfit:    toFloat(row["PARENT_NETPROFIT"]),
+			NetProfitYoY: toFloat(row["PARENT_NETPROFIT_YOY"]),
+			Revenue:      toFloat(row["TOTAL_OPERATE_INCOME"]),
+			EPS:          toFloat(row["BASIC_EPS"]),
+		}, true
+	}
+	for _, row := range express {
+		item := normalizedRecord(row)
+		if recordDate(item["reportDate"]) != reportDate {
+			continue
+		}
+		return models.EarningsComparison{
+			ActualSource: "express",
+			NetProfit:    toFloat(item["netProfit"]),
+			NetProfitYoY: toFloat(item["netProfitYoY"]),
+			Revenue:      toFloat(item["revenue"]),
+			EPS:          toFloat(item["eps"]),
+		}, true
+	}
+	return models.EarningsComparison{}, false
+}
+
+// buildEarningsCalendar 由预约披露记录构建日历条目
+func buildEarningsCalendar(stock models.Stock, schedule []map[string]any, today time.Time, days int) []models.EarningsCalendarItem {
+	todayDate := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	var items []models.EarningsCalendarItem
+	for _, row := range schedule {
+		data := normalizedRecord(row)
+		appoint := recordDate(data["appointDate"])
+		appointTime, err := time.ParseInLocation("2006-01-02", appoint, today.Location())
+		if err != nil {
+			continue
+		}
+		item := models.EarningsCalendarItem{
+			StockCode:   stock.Symbol,
+			StockName:   stock.Name,
+			ReportDate:  recordDate(data["reportDate"]),
+			ReportType:  toStringLocal(data["reportType"]),
+			AppointDate: appoint,
+			ActualDate:  recordDate(data["actualDate"]),
+			DaysLeft:    int(math.Round(appointTime.Sub(todayDate).Hours() / 24)),
+		}
+		switch {
+		case item.ActualDate != "":
+			item.Status = "disclosed"
+			actualTime, err := time.ParseInLocation("2006-01-02", item.ActualDate, today.Location())
+			if err == nil && todayDate.Sub(actualTime) > earningsRecentDays*24*time.Hour {
+				continue
+			}
+		case item.DaysLeft < 0:
+			item.Status = "overdue"
+		default:
+			item.Status = "upcoming"
+			if item.DaysLeft > days {
+				continue
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// compareEarnings 对比实际业绩与公司预告、分析师一致预期
+func compareEarnings(actual models.EarningsComparison, forecasts []map[string]any, consensus *models.ConsensusYear) models.EarningsComparison {
+	result := actual
+	var parts []string
+
+	for _, row := range forecasts {
+		data := normalizedRecord(row)
+		if result.ReportDate != "" && recordDate(data["reportDate"]) != result.ReportDate {
+			continue
+		}
+		// 只对比归母净利润预告，跳过营收、扣非等指标
+		if finance := toStringLocal(data["predictFinance"]); finance != "" && (!strings.Contains(finance, "净利润") || strings.Contains(finance, "扣除") || strings.Contains(finance, "扣非")) {
+			continue
+		}
+		lower, upper := toFloat(data["netProfitLower"]), toFloat(data["netProfitUpper"])
+		if lower == 0 && upper == 0 {
+			continue
+		}
+		if upper == 0 {
+			upper = lower
+		}
+		if lower > upper {
+			lower, upper = upper, lower
+		}
+		result.ForecastLower = lower
+		result.ForecastUpper = upper
+		result.ForecastType = toStringLocal(data["predictType"])
+		switch {
+		case actual.NetProfit > upper:
+			result.ForecastVerdict = "beat"
+		case actual.NetProfit < lower:
+			result.ForecastVerdict = "miss"
+		default:
+			result.ForecastVerdict = "inline"
+		}
+		parts = append(parts, fmt.Sprintf("归母净利润%.2f亿元，公司预告区间%.2f~%.2f亿元，%s", actual.NetProfit/1e8, lower/1e8, upper/1e8, earningsVerdictName(result.ForecastVerdict)))
+		break
+	}
+
+	if consensus != nil && consensus.EPS != 0 && actual.EPS != 0 {
+		result.ConsensusEPS = consensus.EPS
+		result.ConsensusCount = consensus.Count
+		result.EPSSurprisePct = roundConsensus((actual.EPS-consensus.EPS)/math.Abs(consensus.EPS)*100, 2)
+		switch {
+		case result.EPSSurprisePct > earningsBeatThreshold:
+			result.ConsensusVerdict = "beat"
+		case result.EPSSurprisePct < -earningsBeatThreshold:
+			result.ConsensusVerdict = "miss"
+		default:
+			result.ConsensusVerdict = "inline"
+		}
+		parts = append(parts, fmt.Sprintf("EPS %.3f元，%d家券商一致预期%.3f元，偏差%+.2f%%，%s", actual.EPS, consensus.Count, consensus.EPS, result.EPSSurprisePct, earningsVerdictName(result.ConsensusVerdict)))
+	}
+
+	if len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("归母净利润%.2f亿元，暂无可对比的预告或一致预期", actual.NetProfit/1e8))
+	}
+	result.Summary = strings.Join(parts, "；")
+	return result
+}
+
+// earningsVerdictName 对比结论中文名
+func earningsVerdictName(verdict string) string {
+	switch verdict {
+	case "beat":
+		return "超预期"
+	case "miss":
+		return "低于预期"
+	default:
+		return "符合预期"
+	}
+}
+
+// normalizedRecord 取 F10 记录中的标准化字段
+func normalizedRecord(row map[string]any) map[string]any {
+	if data, ok := row["normalized"].(map[string]any); ok {
+		return data
+	}
+	return row
+}
+
+// recordDate 截取日期部分（YYYY-MM-DD）
+func recordDate(value any) string {
+	text := strings.TrimSpace(toStringLocal(value))
+	if len(text) > 10 {
+		text = text[:10]
+	}
+	return text
+}
+
+// earningsKey 股票与报告期组合键
+func earningsKey(code, reportDate string) string {
+	return code + "|" + reportDate
+}
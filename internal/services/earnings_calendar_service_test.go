@@ -0,0 +1,79 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestBuildEarningsCalendar(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	today := time.Date(2025, 10, 20, 10, 0, 0, 0, loc)
+	stock := models.Stock{Symbol: "sh600519", Name: "贵州茅台"}
+	schedule := []map[string]any{
+		{"normalized": map[string]any{"reportDate": "2025-09-30 00:00:00", "reportType": "2025年三季报", "appointDate": "2025-10-23 00:00:00"}},
+		{"normalized": map[string]any{"reportDate": "2025-06-30 00:00:00", "appointDate": "2025-08-10", "actualDate": "2025-08-09"}},
+		{"normalized": map[string]any{"reportDate": "2025-03-31 00:00:00", "appointDate": "2025-04-20", "actualDate": "2025-04-19"}},
+		{"normalized": map[string]any{"reportDate": "2025-12-31 00:00:00", "appointDate": "2026-03-30"}},
+	}
+
+	items := buildEarningsCalendar(stock, schedule, today, 60)
+	if len(items) != 1 {
+		t.Fatalf("items = %+v, want 1", items)
+	}
+	item := items[0]
+	if item.ReportDate != "2025-09-30" || item.AppointDate != "2025-10-23" || item.DaysLeft != 3 || item.Status != "upcoming" {
+		t.Fatalf("unexpected item: %+v", item)
+	}
+
+	items = buildEarningsCalendar(stock, schedule[1:2], time.Date(2025, 8, 20, 0, 0, 0, 0, loc), 60)
+	if len(items) != 1 || items[0].Status != "disclosed" || items[0].DaysLeft != -10 {
+		t.Fatalf("unexpected disclosed items: %+v", items)
+	}
+}
+
+func TestCompareEarnings(t *testing.T) {
+	forecasts := []map[string]any{
+		{"normalized": map[string]any{"reportDate": "2024-12-31", "predictFinance": "扣除非经常性损益后的净利润", "netProfitLower": 1e8, "netProfitUpper": 2e8}},
+		{"normalized": map[string]any{"reportDate": "2024-12-31", "predictFinance": "归属于上市公司股东的净利润", "netProfitLower": 8e8, "netProfitUpper": 9e8, "predictType": "预增"}},
+	}
+	actual := models.EarningsComparison{ReportDate: "2024-12-31", NetProfit: 9.5e8, EPS: 1.10}
+	consensus := &models.ConsensusYear{Year: 2024, EPS: 1.00, Count: 8}
+
+	result := compareEarnings(actual, forecasts, consensus)
+	if result.ForecastLower != 8e8 || result.ForecastUpper != 9e8 || result.ForecastVerdict != "beat" || result.ForecastType != "预增" {
+		t.Fatalf("unexpected forecast comparison: %+v", result)
+	}
+	if result.EPSSurprisePct != 10 || result.ConsensusVerdict != "beat" || result.ConsensusCount != 8 {
+		t.Fatalf("unexpected consensus comparison: %+v", result)
+	}
+
+	actual.NetProfit = 8.5e8
+	actual.EPS = 0.97
+	result = compareEarnings(actual, forecasts, consensus)
+	if result.ForecastVerdict != "inline" || result.ConsensusVerdict != "inline" {
+		t.Fatalf("unexpected inline comparison: %+v", result)
+	}
+	if result := compareEarnings(actual, nil, nil); result.ForecastVerdict != "" || result.Summary == "" {
+		t.Fatalf("unexpected empty comparison: %+v", result)
+	}
+}
+
+func TestBrief_MarksOnlyOnSuccess(t *testing.T) {
+	s := NewEarningsCalendarService(t.TempDir(), nil, nil, nil)
+	item := models.EarningsCalendarItem{StockCode: "sh600519", ReportDate: "2025-12-31"}
+	key := earningsKey(item.StockCode, item.ReportDate)
+
+	s.briefing[key] = true
+	s.brief(key, item, func(models.EarningsCalendarItem) error { return errors.New("会议进行中") })
+	if _, ok := s.state.Briefed[key]; ok || s.briefing[key] {
+		t.Fatalf("failed briefing should stay pending: briefed=%v running=%v", s.state.Briefed, s.briefing)
+	}
+
+	s.brief(key, item, func(models.EarningsCalendarItem) error { return nil })
+	if _, ok := s.state.Briefed[key]; !ok {
+		t.Fatal("successful briefing should be marked")
+	}
+}
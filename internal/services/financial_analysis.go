@@ -0,0 +1,429 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+// 财务异常信号类型
+const (
+	FinFlagProfitWithoutCash = "profit_without_cash"
+	FinFlagReceivables       = "receivables_outpacing_revenue"
+	FinFlagInventory         = "inventory_outpacing_revenue"
+	FinFlagGoodwill          = "high_goodwill"
+	FinFlagLeverage          = "high_leverage"
+	FinFlagNonRecurring      = "non_recurring_profit"
+)
+
+// finSnapshot 单个报告期的三表关键科目
+type finSnapshot struct {
+	date  string
+	year  int
+	month string // 报告期月日，如 03-31
+
+	revenue, cost, opProfit, parentNP, deductNP float64
+	assets, liabilities, equity                 float64
+	receivables, inventory, goodwill            float64
+	curAssets, curLiab                          float64
+	cash, debt                                  float64
+	ocf, capex, salesCash                       float64
+
+	hasIncome, hasBalance, hasCash bool
+}
+
+// GetFinancialAnalysisByCode 根据股票代码计算财务分析指标
+func (s *F10Service) GetFinancialAnalysisByCode(code string) (models.FinancialAnalysis, error) {
+	normalized := normalizeStockCode(code)
+	statements, err := s.GetFinancialStatements(normalized)
+	if len(statements.Income) == 0 {
+		if err == nil {
+			err = fmt.Errorf("暂无 %s 的财务报表数据", normalized.Raw)
+		}
+		return models.FinancialAnalysis{Code: normalized.Raw}, err
+	}
+	result := analyzeFinancials(statements)
+	result.Code = normalized.Raw
+	result.UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
+	return result, err
+}
+
+// analyzeFinancials 基于三表计算多期比率、TTM、同比环比与异常信号
+func analyzeFinancials(statements models.FinancialStatements) models.FinancialAnalysis {
+	snaps := collectFinSnapshots(statements)
+	dates := make([]string, 0, len(snaps))
+	for date, snap := range snaps {
+		if snap.hasIncome {
+			dates = append(dates, date)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(dates)))
+
+	result := models.FinancialAnalysis{
+		Periods:  make([]models.FinancialPeriod, 0, len(dates)),
+		RedFlags: make([]models.FinancialRedFlag, 0),
+	}
+	for _, date := range dates {
+		result.Periods = append(result.Periods, buildFinancialPeriod(snaps, snaps[date]))
+	}
+	if len(dates) > 0 {
+		result.TTM = buildFinancialTTM(snaps, snaps[dates[0]])
+		result.RedFlags = detectFinancialRedFlags(snaps, snaps[dates[0]], result.TTM)
+	}
+	return result
+}
+
+// collectFinSnapshots 按报告期合并利润表、资产负债表与现金流量表
+func collectFinSnapshots(statements models.FinancialStatements) map[string]*finSnapshot {
+	snaps := make(map[string]*finSnapshot)
+	get := func(row map[string]any) *finSnapshot {
+		date := recordDate(row["REPORT_DATE"])
+		if len(date) != 10 {
+			return nil
+		}
+		year, err := strconv.Atoi(date[:4])
+		if err != nil {
+			return nil
+		}
+		snap, ok := snaps[date]
+		if !ok {
+			snap = &finSnapshot{date: date, year: year, month: date[5:]}
+			snaps[date] = snap
+		}
+		return snap
+	}
+
+	for _, row := range statements.Income {
+		if snap := get(row); snap != nil {
+			snap.hasIncome = true
+			snap.revenue = toFloat(firstNonEmpty(row, "TOTAL_OPERATE_INCOME", "OPERATE_INCOME"))
+			snap.cost = toFloat(row["OPERATE_COST"])
+			snap.opProfit = toFloat(row["OPERATE_PROFIT"])
+			snap.parentNP = toFloat(firstNonEmpty(row, "PARENT_NETPROFIT", "NETPROFIT"))
+			snap.deductNP = toFloat(row["DEDUCT_PARENT_NETPROFIT"])
+		}
+	}
+	for _, row := range statements.Balance {
+		if snap := get(row); snap != nil {
+			snap.hasBalance = true
+			snap.assets = toFloat(row["TOTAL_ASSETS"])
+			snap.liabilities = toFloat(row["TOTAL_LIABILITIES"])
+			snap.equity = toFloat(firstNonEmpty(row, "TOTAL_PARENT_EQUITY", "TOTAL_EQUITY"))
+			snap.receivables = toFloat(firstNonEmpty(row, "ACCOUNTS_RECE", "NOTE_ACCOUNTS_RECE"))
+			snap.inventory = toFloat(row["INVENTORY"])
+			snap.goodwill = toFloat(row["GOODWILL"])
+			snap.curAssets = toFloat(row["TOTAL_CURRENT_ASSETS"])
+			snap.curLiab = toFloat(row["TOTAL_CURRENT_LIAB"])
+			snap.cash = toFloat(row["MONETARYFUNDS"])
+			snap.debt = toFloat(row["SHORT_LOAN"]) + toFloat(row["LONG_LOAN"]) + toFloat(row["BOND_PAYABLE"])
+		}
+	}
+	for _, row := range statements.Cashflow {
+		if snap := get(row); snap != nil {
+			snap.hasCash = true
+			snap.ocf = toFloat(row["NETCASH_OPERATE"])
+			snap.capex = toFloat(row["CONSTRUCT_LONG_ASSET"])
+			snap.salesCash = toFloat(row["SALES_SERVICES"])
+		}
+	}
+	return snaps
+}
+
+// buildFinancialPeriod 计算单期比率、杜邦拆解、周转天数与增速
+func buildFinancialPeriod(snaps map[string]*finSnapshot, cur *finSnapshot) models.FinancialPeriod {
+	period := models.FinancialPeriod{
+		ReportDate:        cur.date,
+		ReportType:        finReportType(cur.month),
+		Revenue:           cur.revenue,
+		NetProfit:         cur.parentNP,
+		DeductNetProfit:   cur.deductNP,
+		OperatingCashFlow: cur.ocf,
+		FreeCashFlow:      cur.ocf - cur.capex,
+	}
+	if cur.revenue > 0 {
+		if cur.cost > 0 {
+			period.GrossMargin = finPercent(cur.revenue-cur.cost, cur.revenue)
+		}
+		period.OperatingMargin = finPercent(cur.opProfit, cur.revenue)
+		period.NetMargin = finPercent(cur.parentNP, cur.revenue)
+		if cur.hasCash && cur.salesCash > 0 {
+			period.CashToRevenue = finRatio(cur.salesCash, cur.revenue)
+		}
+	}
+	if cur.hasCash && cur.parentNP > 0 {
+		period.CashConversion = finRatio(cur.ocf, cur.parentNP)
+	}
+
+	if cur.hasBalance {
+		opening := snaps[fmt.Sprintf("%d-12-31", cur.year-1)]
+		avg := func(value func(*finSnapshot) float64) float64 {
+			if opening != nil && opening.hasBalance && value(opening) > 0 {
+				return (value(cur) + value(opening)) / 2
+			}
+			return value(cur)
+		}
+		avgAssets := avg(func(s *finSnapshot) float64 { return s.assets })
+		avgEquity := avg(func(s *finSnapshot) float64 { return s.equity })
+		if avgAssets > 0 && avgEquity > 0 {
+			// 杜邦拆解：ROE = 净利率 × 资产周转率 × 权益乘数
+			period.ROE = finPercent(cur.parentNP, avgEquity)
+			period.ROA = finPercent(cur.parentNP, avgAssets)
+			period.AssetTurnover = finRatio(cur.revenue, avgAssets)
+			period.EquityMultiplier = finRatio(avgAssets, avgEquity)
+		}
+		days := finPeriodDays(cur.month)
+		if cur.revenue > 0 && cur.receivables > 0 {
+			period.ReceivableDays = math.Round(avg(func(s *finSnapshot) float64 { return s.receivables }) / cur.revenue * days)
+		}
+		if cur.cost > 0 && cur.inventory > 0 {
+			period.InventoryDays = math.Round(avg(func(s *finSnapshot) float64 { return s.inventory }) / cur.cost * days)
+		}
+		if cur.assets > 0 {
+			period.DebtRatio = finPercent(cur.liabilities, cur.assets)
+		}
+		if cur.curLiab > 0 {
+			period.CurrentRatio = finRatio(cur.curAssets, cur.curLiab)
+		}
+		if cur.equity > 0 && cur.goodwill > 0 {
+			period.GoodwillRatio = finPercent(cur.goodwill, cur.equity)
+		}
+	}
+
+	if prev := snaps[fmt.Sprintf("%d-%s", cur.year-1, cur.month)]; prev != nil && prev.hasIncome {
+		period.RevenueYoY = finGrowth(cur.revenue, prev.revenue)
+		period.NetProfitYoY = finGrowth(cur.parentNP, prev.parentNP)
+	}
+	if qRev, ok := finQuarterValue(snaps, cur, finRevenue); ok {
+		qNP, _ := finQuarterValue(snaps, cur, finNetProfit)
+		period.QuarterRevenue = &qRev
+		period.QuarterNetProfit = &qNP
+		if prev := finPrevQuarter(snaps, cur); prev != nil {
+			if pRev, ok := finQuarterValue(snaps, prev, finRevenue); ok {
+				pNP, _ := finQuarterValue(snaps, prev, finNetProfit)
+				period.QuarterRevenueQoQ = finGrowth(qRev, pRev)
+				period.QuarterProfitQoQ = finGrowth(qNP, pNP)
+			}
+		}
+	}
+	return period
+}
+
+// buildFinancialTTM 计算最近十二个月滚动值：本期累计 + 上年年报 - 上年同期累计
+func buildFinancialTTM(snaps map[string]*finSnapshot, cur *finSnapshot) *models.FinancialTTM {
+	revenue, ok := finTTMValue(snaps, cur, finRevenue, false)
+	if !ok {
+		return nil
+	}
+	netProfit, _ := finTTMValue(snaps, cur, finNetProfit, false)
+	deduct, _ := finTTMValue(snaps, cur, func(s *finSnapshot) float64 { return s.deductNP }, false)
+	ocf, _ := finTTMValue(snaps, cur, finOperatingCash, true)
+
+	ttm := &models.FinancialTTM{
+		BaseDate:          cur.date,
+		Revenue:           revenue,
+		NetProfit:         netProfit,
+		DeductNetProfit:   deduct,
+		OperatingCashFlow: ocf,
+	}
+	if revenue > 0 {
+		ttm.NetMargin = finPercent(netProfit, revenue)
+	}
+	if netProfit > 0 {
+		ttm.CashConversion = finRatio(ocf, netProfit)
+	}
+	if cur.hasBalance && cur.equity > 0 {
+		equity := cur.equity
+		if prev := snaps[fmt.Sprintf("%d-%s", cur.year-1, cur.month)]; prev != nil && prev.hasBalance && prev.equity > 0 {
+			equity = (equity + prev.equity) / 2
+		}
+		ttm.ROE = finPercent(netProfit, equity)
+	}
+	if prev := snaps[fmt.Sprintf("%d-%s", cur.year-1, cur.month)]; prev != nil {
+		if prevRevenue, ok := finTTMValue(snaps, prev, finRevenue, false); ok {
+			prevNP, _ := finTTMValue(snaps, prev, finNetProfit, false)
+			ttm.RevenueYoY = finGrowth(revenue, prevRevenue)
+			ttm.NetProfitYoY = finGrowth(netProfit, prevNP)
+		}
+	}
+	return ttm
+}
+
+// detectFinancialRedFlags 识别常见财务异常：有利润无现金、应收/存货增速背离、高商誉、高杠杆、依赖非经常性损益
+func detectFinancialRedFlags(snaps map[string]*finSnapshot, cur *finSnapshot, ttm *models.FinancialTTM) []models.FinancialRedFlag {
+	flags := make([]models.FinancialRedFlag, 0)
+	add := func(flagType, level, message string, value float64) {
+		flags = append(flags, models.FinancialRedFlag{
+			Type:       flagType,
+			Level:      level,
+			ReportDate: cur.date,
+			Message:    message,
+			Value:      value,
+		})
+	}
+
+	netProfit, ocf, hasCash := cur.parentNP, cur.ocf, cur.hasCash
+	basis := "本期累计"
+	if ttmOCF, ok := finTTMValue(snaps, cur, finOperatingCash, true); ok && ttm != nil {
+		netProfit, ocf, hasCash = ttm.NetProfit, ttmOCF, true
+		basis = "近12个月"
+	}
+	if netProfit > 0 && hasCash {
+		ratio := finRatio(ocf, netProfit)
+		if ocf < 0 {
+			add(FinFlagProfitWithoutCash, "high", fmt.Sprintf("%s归母净利润为正但经营现金流为负，现金含量 %.2f", basis, ratio), ratio)
+		} else if ratio < 0.5 {
+			add(FinFlagProfitWithoutCash, "medium", fmt.Sprintf("%s经营现金流仅为归母净利润的 %.2f 倍", basis, ratio), ratio)
+		}
+	}
+
+	if prev := snaps[fmt.Sprintf("%d-%s", cur.year-1, cur.month)]; prev != nil && prev.hasBalance && cur.hasBalance && prev.hasIncome {
+		revenueGrowth := finGrowth(cur.revenue, prev.revenue)
+		if growth := finGrowth(cur.receivables, prev.receivables); growth != nil && revenueGrowth != nil && *growth > 20 && *growth-*revenueGrowth > 20 {
+			level := "medium"
+			if *revenueGrowth < 0 {
+				level = "high"
+			}
+			add(FinFlagReceivables, level, fmt.Sprintf("应收账款同比 %+.1f%%，营业收入同比 %+.1f%%，回款恶化", *growth, *revenueGrowth), *growth-*revenueGrowth)
+		}
+		if growth := finGrowth(cur.inventory, prev.inventory); growth != nil && revenueGrowth != nil && *growth > 30 && *growth-*revenueGrowth > 30 {
+			add(FinFlagInventory, "medium", fmt.Sprintf("存货同比 %+.1f%%，营业收入同比 %+.1f%%，存在积压风险", *growth, *revenueGrowth), *growth-*revenueGrowth)
+		}
+	}
+
+	if cur.hasBalance && cur.equity > 0 && cur.goodwill > 0 {
+		ratio := finPercent(cur.goodwill, cur.equity)
+		if ratio >= 30 {
+			add(FinFlagGoodwill, "high", fmt.Sprintf("商誉占归母净资产 %.1f%%，减值风险较大", ratio), ratio)
+		} else if ratio >= 15 {
+			add(FinFlagGoodwill, "medium", fmt.Sprintf("商誉占归母净资产 %.1f%%", ratio), ratio)
+		}
+	}
+
+	if cur.hasBalance && cur.assets > 0 {
+		if ratio := finPercent(cur.liabilities, cur.assets); ratio >= 70 {
+			add(FinFlagLeverage, "medium", fmt.Sprintf("资产负债率 %.1f%%（金融、地产等行业需结合行业特性判断）", ratio), ratio)
+		}
+	}
+
+	if cur.parentNP > 0 && cur.deductNP != 0 {
+		if ratio := finRatio(cur.deductNP, cur.parentNP); ratio < 0.5 {
+			add(FinFlagNonRecurring, "medium", fmt.Sprintf("扣非净利润仅为归母净利润的 %.2f 倍，利润依赖非经常性损益", ratio), ratio)
+		}
+	}
+	return flags
+}
+
+// finTTMValue 计算累计科目的滚动十二个月值，cash 为 true 时要求各期均有现金流量表
+func finTTMValue(snaps map[string]*finSnapshot, cur *finSnapshot, value func(*finSnapshot) float64, cash bool) (float64, bool) {
+	has := func(s *finSnapshot) bool {
+		if cash {
+			return s != nil && s.hasCash
+		}
+		return s != nil && s.hasIncome
+	}
+	if !has(cur) {
+		return 0, false
+	}
+	if cur.month == "12-31" {
+		return value(cur), true
+	}
+	annual := snaps[fmt.Sprintf("%d-12-31", cur.year-1)]
+	prev := snaps[fmt.Sprintf("%d-%s", cur.year-1, cur.month)]
+	if !has(annual) || !has(prev) {
+		return 0, false
+	}
+	return value(cur) + value(annual) - value(prev), true
+}
+
+func finRevenue(s *finSnapshot) float64       { return s.revenue }
+func finNetProfit(s *finSnapshot) float64     { return s.parentNP }
+func finOperatingCash(s *finSnapshot) float64 { return s.ocf }
+func finCapex(s *finSnapshot) float64         { return s.capex }
+
+// finQuarterValue 由累计值推算单季值
+func finQuarterValue(snaps map[string]*finSnapshot, cur *finSnapshot, value func(*finSnapshot) float64) (float64, bool) {
+	if cur.month == "03-31" {
+		return value(cur), true
+	}
+	prev := finPrevQuarter(snaps, cur)
+	if prev == nil || prev.year != cur.year {
+		return 0, false
+	}
+	return value(cur) - value(prev), true
+}
+
+// finPrevQuarter 获取上一个季度报告期
+func finPrevQuarter(snaps map[string]*finSnapshot, cur *finSnapshot) *finSnapshot {
+	var date string
+	switch cur.month {
+	case "03-31":
+		date = fmt.Sprintf("%d-12-31", cur.year-1)
+	case "06-30":
+		date = fmt.Sprintf("%d-03-31", cur.year)
+	case "09-30":
+		date = fmt.Sprintf("%d-06-30", cur.year)
+	case "12-31":
+		date = fmt.Sprintf("%d-09-30", cur.year)
+	default:
+		return nil
+	}
+	if prev := snaps[date]; prev != nil && prev.hasIncome {
+		return prev
+	}
+	return nil
+}
+
+// finReportType 报告期类型名称
+func finReportType(month string) string {
+	switch month {
+	case "03-31":
+		return "一季报"
+	case "06-30":
+		return "中报"
+	case "09-30":
+		return "三季报"
+	case "12-31":
+		return "年报"
+	}
+	return ""
+}
+
+// finPeriodDays 报告期累计天数，用于周转天数计算
+func finPeriodDays(month string) float64 {
+	switch month {
+	case "03-31":
+		return 90
+	case "06-30":
+		return 181
+	case "09-30":
+		return 273
+	}
+	return 365
+}
+
+func finPercent(part, base float64) float64 {
+	if base == 0 {
+		return 0
+	}
+	return round2(part / base * 100)
+}
+
+func finRatio(part, base float64) float64 {
+	if base == 0 {
+		return 0
+	}
+	return round2(part / base)
+}
+
+// finGrowth 计算增速(%)，基数为0时返回 nil；基数为负时按绝对值计算
+func finGrowth(cur, prev float64) *float64 {
+	if prev == 0 {
+		return nil
+	}
+	growth := round2((cur - prev) / math.Abs(prev) * 100)
+	return &growth
+}
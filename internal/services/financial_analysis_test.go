@@ -0,0 +1,83 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestAnalyzeFinancials(t *testing.T) {
+	statements := models.FinancialStatements{
+		Income: []map[string]any{
+			{"REPORT_DATE": "2025-06-30 00:00:00", "TOTAL_OPERATE_INCOME": 500.0, "OPERATE_COST": 300.0, "OPERATE_PROFIT": 60.0, "PARENT_NETPROFIT": 50.0, "DEDUCT_PARENT_NETPROFIT": 20.0},
+			{"REPORT_DATE": "2025-03-31 00:00:00", "TOTAL_OPERATE_INCOME": 250.0, "OPERATE_COST": 150.0, "PARENT_NETPROFIT": 25.0, "DEDUCT_PARENT_NETPROFIT": 10.0},
+			{"REPORT_DATE": "2024-12-31 00:00:00", "TOTAL_OPERATE_INCOME": 1000.0, "OPERATE_COST": 600.0, "PARENT_NETPROFIT": 100.0, "DEDUCT_PARENT_NETPROFIT": 95.0},
+			{"REPORT_DATE": "2024-06-30 00:00:00", "TOTAL_OPERATE_INCOME": 400.0, "OPERATE_COST": 240.0, "PARENT_NETPROFIT": 40.0, "DEDUCT_PARENT_NETPROFIT": 38.0},
+		},
+		Balance: []map[string]any{
+			{"REPORT_DATE": "2025-06-30 00:00:00", "TOTAL_ASSETS": 2400.0, "TOTAL_LIABILITIES": 1700.0, "TOTAL_PARENT_EQUITY": 700.0, "ACCOUNTS_RECE": 300.0, "INVENTORY": 150.0, "GOODWILL": 250.0},
+			{"REPORT_DATE": "2024-12-31 00:00:00", "TOTAL_ASSETS": 2000.0, "TOTAL_LIABILITIES": 1000.0, "TOTAL_PARENT_EQUITY": 1000.0, "ACCOUNTS_RECE": 100.0, "INVENTORY": 150.0, "GOODWILL": 50.0},
+			{"REPORT_DATE": "2024-06-30 00:00:00", "TOTAL_ASSETS": 1800.0, "TOTAL_LIABILITIES": 900.0, "TOTAL_PARENT_EQUITY": 900.0, "ACCOUNTS_RECE": 150.0, "INVENTORY": 150.0},
+		},
+		Cashflow: []map[string]any{
+			{"REPORT_DATE": "2025-06-30 00:00:00", "NETCASH_OPERATE": -20.0, "CONSTRUCT_LONG_ASSET": 30.0},
+			{"REPORT_DATE": "2024-12-31 00:00:00", "NETCASH_OPERATE": 40.0},
+			{"REPORT_DATE": "2024-06-30 00:00:00", "NETCASH_OPERATE": 10.0},
+		},
+	}
+
+	got := analyzeFinancials(statements)
+	if len(got.Periods) != 4 || got.Periods[0].ReportDate != "2025-06-30" || got.Periods[0].ReportType != "中报" {
+		t.Fatalf("unexpected periods: %+v", got.Periods)
+	}
+
+	p := got.Periods[0]
+	if p.GrossMargin != 40 || p.NetMargin != 10 || p.FreeCashFlow != -50 {
+		t.Fatalf("unexpected margins: %+v", p)
+	}
+	// 杜邦：平均总资产2200、平均归母净资产850
+	if p.ROE != 5.88 || p.AssetTurnover != 0.23 || p.EquityMultiplier != 2.59 {
+		t.Fatalf("unexpected dupont: roe=%v turnover=%v multiplier=%v", p.ROE, p.AssetTurnover, p.EquityMultiplier)
+	}
+	if p.ReceivableDays != 72 || p.DebtRatio != 70.83 || p.GoodwillRatio != 35.71 {
+		t.Fatalf("unexpected balance ratios: %+v", p)
+	}
+	if p.RevenueYoY == nil || *p.RevenueYoY != 25 || p.NetProfitYoY == nil || *p.NetProfitYoY != 25 {
+		t.Fatalf("unexpected yoy: %+v", p)
+	}
+	if p.QuarterRevenue == nil || *p.QuarterRevenue != 250 || p.QuarterRevenueQoQ == nil || *p.QuarterRevenueQoQ != 0 {
+		t.Fatalf("unexpected quarter values: %+v", p)
+	}
+	if got.Periods[1].QuarterRevenue == nil || *got.Periods[1].QuarterRevenue != 250 {
+		t.Fatalf("q1 quarter revenue should equal cumulative: %+v", got.Periods[1])
+	}
+	// 年报缺少三季报时无法推算单季
+	if got.Periods[2].QuarterRevenue != nil {
+		t.Fatalf("annual quarter revenue should be nil: %+v", got.Periods[2])
+	}
+
+	ttm := got.TTM
+	if ttm == nil || ttm.Revenue != 1100 || ttm.NetProfit != 110 || ttm.OperatingCashFlow != 10 || ttm.RevenueYoY != nil {
+		t.Fatalf("unexpected ttm: %+v", ttm)
+	}
+
+	flags := make(map[string]string)
+	for _, flag := range got.RedFlags {
+		flags[flag.Type] = flag.Level
+	}
+	want := map[string]string{
+		FinFlagProfitWithoutCash: "medium",
+		FinFlagReceivables:       "medium",
+		FinFlagGoodwill:          "high",
+		FinFlagLeverage:          "medium",
+		FinFlagNonRecurring:      "medium",
+	}
+	if len(flags) != len(want) {
+		t.Fatalf("unexpected flags: %+v", got.RedFlags)
+	}
+	for flagType, level := range want {
+		if flags[flagType] != level {
+			t.Fatalf("flag %s level=%q, all=%+v", flagType, flags[flagType], got.RedFlags)
+		}
+	}
+}
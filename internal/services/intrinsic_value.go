@@ -0,0 +1,441 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+const (
+	defaultValuationWACC     = 9.0
+	defaultStage1Growth      = 8.0
+	defaultStage1Years       = 5
+	maxStage1Years           = 10
+	defaultTerminalGrowth    = 2.5
+	defaultRequiredReturn    = 9.0
+	defaultDividendGrowth    = 3.0
+	valuationMinSpread       = 0.5 // 折现率需至少高于永续增长率的百分点
+	valuationBandMinSamples  = 20
+	valuationAutoGrowthFloor = -5.0
+	valuationAutoGrowthCap   = 20.0
+)
+
+// valuationBandPercentiles 估值带分位点
+var valuationBandPercentiles = []float64{10, 25, 50, 75, 90}
+
+// dividendPlanPattern 解析分红方案中的每N股派现金额，如 "10转4派3元"
+var dividendPlanPattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)[^派]*派\s*(\d+(?:\.\d+)?)`)
+
+// valuationInputs 内在价值计算所需的基础数据
+type valuationInputs struct {
+	price, shares        float64
+	epsTTM, bps          float64
+	fcfTTM, netCash      float64
+	dividendPerShare     float64
+	profitGrowth         *float64
+	currentPE, currentPB float64
+	peHistory, pbHistory []float64
+	rangeLabel           string
+}
+
+// GetIntrinsicValue 估算个股内在价值（DCF、DDM、PE/PB 历史分位估值带）
+func (s *F10Service) GetIntrinsicValue(code string, params models.ValuationParams) (models.IntrinsicValuation, error) {
+	normalized := normalizeStockCode(code)
+	errs := make(map[string]string)
+
+	valuation, err := s.GetValuation(normalized)
+	if valuation.Price <= 0 {
+		if err == nil {
+			err = fmt.Errorf("缺少 %s 的最新价格", normalized.Raw)
+		}
+		return models.IntrinsicValuation{Code: normalized.Raw, Errors: map[string]string{"valuation": err.Error()}}, err
+	}
+	if err != nil {
+		errs["valuation"] = err.Error()
+	}
+	inputs := valuationInputs{
+		price:     valuation.Price,
+		shares:    valuation.TotalShares,
+		currentPE: valuation.PETTM,
+		currentPB: valuation.PB,
+	}
+
+	statements, err := s.GetFinancialStatements(normalized)
+	if err != nil {
+		errs["financials"] = err.Error()
+	}
+	fillValuationFinancials(&inputs, statements)
+	if inputs.epsTTM == 0 && valuation.PETTM > 0 {
+		inputs.epsTTM = valuation.Price / valuation.PETTM
+	}
+	if inputs.bps == 0 && valuation.PB > 0 {
+		inputs.bps = valuation.Price / valuation.PB
+	}
+
+	bonus, err := s.GetBonusFinancing(normalized)
+	if err != nil {
+		errs["dividend"] = err.Error()
+	}
+	inputs.dividendPerShare = trailingDividendPerShare(bonus.Dividend, time.Now())
+
+	trend, err := s.GetValuationTrend(normalized.Raw, params.Range)
+	if err != nil {
+		errs["trend"] = err.Error()
+	}
+	// 报告期兜底序列以现价折算，不代表历史估值，不用于估值带
+	if trend.Source != "report" {
+		inputs.peHistory = trendIndicatorValues(trend.PE)
+		inputs.pbHistory = trendIndicatorValues(trend.PB)
+		inputs.rangeLabel = trend.Range
+	}
+
+	result := computeIntrinsicValue(inputs, params)
+	result.Code = normalized.Raw
+	if len(errs) > 0 {
+		result.Errors = errs
+	}
+	result.UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
+	return result, nil
+}
+
+// fillValuationFinancials 从三表提取 TTM 自由现金流、EPS、BPS、净现金与利润增速
+func fillValuationFinancials(in *valuationInputs, statements models.FinancialStatements) {
+	snaps := collectFinSnapshots(statements)
+	var latest, latestBalance *finSnapshot
+	for _, snap := range snaps {
+		if snap.hasIncome && (latest == nil || snap.date > latest.date) {
+			latest = snap
+		}
+		if snap.hasBalance && (latestBalance == nil || snap.date > latestBalance.date) {
+			latestBalance = snap
+		}
+	}
+	if latest == nil {
+		return
+	}
+
+	if ttm := buildFinancialTTM(snaps, latest); ttm != nil {
+		if in.shares > 0 {
+			in.epsTTM = ttm.NetProfit / in.shares
+		}
+		in.profitGrowth = ttm.NetProfitYoY
+	}
+	ocf, ocfOK := finTTMValue(snaps, latest, finOperatingCash, true)
+	capex, capexOK := finTTMValue(snaps, latest, finCapex, true)
+	if ocfOK && capexOK {
+		in.fcfTTM = ocf - capex
+	}
+	if latestBalance != nil {
+		in.netCash = latestBalance.cash - latestBalance.debt
+		if in.shares > 0 && latestBalance.equity > 0 {
+			in.bps = latestBalance.equity / in.shares
+		}
+	}
+}
+
+// computeIntrinsicValue 根据基础数据与参数计算各模型估值及敏感性
+func computeIntrinsicValue(in valuationInputs, params models.ValuationParams) models.IntrinsicValuation {
+	params = normalizeValuationParams(params, in.profitGrowth)
+	result := models.IntrinsicValuation{Price: in.price, Shares: in.shares}
+
+	var notes []string
+	if dcf, note := buildDCFValuation(in, params); dcf != nil {
+		result.DCF = dcf
+	} else {
+		notes = append(notes, note)
+	}
+	if ddm, note := buildDDMValuation(in, params); ddm != nil {
+		result.DDM = ddm
+	} else {
+		notes = append(notes, note)
+	}
+	if band, note := buildBandValuation("pe", in.peHistory, in.currentPE, in.epsTTM, in.price, in.rangeLabel); band != nil {
+		result.PEBand = band
+	} else {
+		notes = append(notes, note)
+	}
+	if band, note := buildBandValuation("pb", in.pbHistory, in.currentPB, in.bps, in.price, in.rangeLabel); band != nil {
+		result.PBBand = band
+	} else {
+		notes = append(notes, note)
+	}
+	result.Notes = notes
+
+	var fair []float64
+	if result.DCF != nil && result.DCF.PerShare > 0 {
+		fair = append(fair, result.DCF.PerShare)
+	}
+	if result.DDM != nil && result.DDM.PerShare > 0 {
+		fair = append(fair, result.DDM.PerShare)
+	}
+	if result.PEBand != nil {
+		fair = append(fair, result.PEBand.FairValue)
+	}
+	if result.PBBand != nil {
+		fair = append(fair, result.PBBand.FairValue)
+	}
+	if len(fair) > 0 {
+		sort.Float64s(fair)
+		result.FairValueLow = round2(fair[0])
+		result.FairValueMid = round2(percentileSorted(fair, 50))
+		result.FairValueHigh = round2(fair[len(fair)-1])
+	}
+	return result
+}
+
+// normalizeValuationParams 补全默认参数，增速未指定时取 TTM 净利润增速并限制在合理区间
+func normalizeValuationParams(params models.ValuationParams, profitGrowth *float64) models.ValuationParams {
+	if params.WACC <= 0 {
+		params.WACC = defaultValuationWACC
+	}
+	if params.Stage1Years <= 0 {
+		params.Stage1Years = defaultStage1Years
+	}
+	if params.Stage1Years > maxStage1Years {
+		params.Stage1Years = maxStage1Years
+	}
+	if params.TerminalGrowth <= 0 {
+		params.TerminalGrowth = defaultTerminalGrowth
+	}
+	if params.Stage1Growth == 0 {
+		params.Stage1Growth = defaultStage1Growth
+		if profitGrowth != nil {
+			params.Stage1Growth = math.Max(valuationAutoGrowthFloor, math.Min(valuationAutoGrowthCap, *profitGrowth))
+		}
+	}
+	if params.RequiredReturn <= 0 {
+		params.RequiredReturn = defaultRequiredReturn
+	}
+	if params.DividendGrowth <= 0 {
+		params.DividendGrowth = defaultDividendGrowth
+	}
+	return params
+}
+
+// buildDCFValuation 两阶段 DCF：高速增长期逐年折现 + Gordon 永续价值，再加净现金
+func buildDCFValuation(in valuationInputs, params models.ValuationParams) (*models.DCFValuation, string) {
+	if in.shares <= 0 {
+		return nil, "缺少总股本，DCF不适用"
+	}
+	if in.fcfTTM <= 0 {
+		return nil, "TTM自由现金流为负或缺失，DCF不适用"
+	}
+	if params.WACC-params.TerminalGrowth < valuationMinSpread {
+		return nil, fmt.Sprintf("折现率 %.2f%% 需高于永续增长率 %.2f%% 至少 %.1f 个百分点", params.WACC, params.TerminalGrowth, valuationMinSpread)
+	}
+
+	stagePV, terminalPV, perShare := dcfPerShare(in.fcfTTM, params.WACC, params.Stage1Growth, params.Stage1Years, params.TerminalGrowth, in.netCash, in.shares)
+	dcf := &models.DCFValuation{
+		BaseFCF:        in.fcfTTM,
+		WACC:           params.WACC,
+		Stage1Growth:   round2(params.Stage1Growth),
+		Stage1Years:    params.Stage1Years,
+		TerminalGrowth: params.TerminalGrowth,
+		StagePV:        math.Round(stagePV),
+		TerminalPV:     math.Round(terminalPV),
+		NetCash:        in.netCash,
+		EquityValue:    math.Round(stagePV + terminalPV + in.netCash),
+		PerShare:       round2(perShare),
+		Upside:         valuationUpside(perShare, in.price),
+	}
+	dcf.Sensitivity = buildSensitivityTable("WACC(%)", "永续增长率(%)",
+		valuationSteps(params.WACC, 1), valuationSteps(params.TerminalGrowth, 0.5),
+		func(wacc, growth float64) float64 {
+			if wacc-growth < valuationMinSpread {
+				return 0
+			}
+			_, _, value := dcfPerShare(in.fcfTTM, wacc, params.Stage1Growth, params.Stage1Years, growth, in.netCash, in.shares)
+			return value
+		})
+	return dcf, ""
+}
+
+// dcfPerShare 计算 DCF 各部分现值及每股价值（百分比参数）
+func dcfPerShare(base, wacc, growth float64, years int, terminal, netCash, shares float64) (float64, float64, float64) {
+	rate := wacc / 100
+	cf := base
+	stagePV := 0.0
+	for t := 1; t <= years; t++ {
+		cf *= 1 + growth/100
+		stagePV += cf / math.Pow(1+rate, float64(t))
+	}
+	terminalValue := cf * (1 + terminal/100) / (rate - terminal/100)
+	terminalPV := terminalValue / math.Pow(1+rate, float64(years))
+	return stagePV, terminalPV, (stagePV + terminalPV + netCash) / shares
+}
+
+// buildDDMValuation Gordon 股利增长模型
+func buildDDMValuation(in valuationInputs, params models.ValuationParams) (*models.DDMValuation, string) {
+	if in.dividendPerShare <= 0 {
+		return nil, "近12个月无已实施现金分红，DDM不适用"
+	}
+	if params.RequiredReturn-params.DividendGrowth < valuationMinSpread {
+		return nil, fmt.Sprintf("要求回报率 %.2f%% 需高于股息增长率 %.2f%% 至少 %.1f 个百分点", params.RequiredReturn, params.DividendGrowth, valuationMinSpread)
+	}
+
+	gordon := func(required, growth float64) float64 {
+		if required-growth < valuationMinSpread {
+			return 0
+		}
+		return in.dividendPerShare * (1 + growth/100) / ((required - growth) / 100)
+	}
+	perShare := gordon(params.RequiredReturn, params.DividendGrowth)
+	ddm := &models.DDMValuation{
+		DividendPerShare: round2(in.dividendPerShare),
+		RequiredReturn:   params.RequiredReturn,
+		DividendGrowth:   params.DividendGrowth,
+		PerShare:         round2(perShare),
+		Upside:           valuationUpside(perShare, in.price),
+	}
+	if in.price > 0 {
+		ddm.DividendYield = round2(in.dividendPerShare / in.price * 100)
+	}
+	ddm.Sensitivity = buildSensitivityTable("要求回报率(%)", "股息增长率(%)",
+		valuationSteps(params.RequiredReturn, 1), valuationSteps(params.DividendGrowth, 0.5), gordon)
+	return ddm, ""
+}
+
+// buildBandValuation 按历史倍数分位点乘以当前每股指标得到估值带
+func buildBandValuation(metric string, history []float64, current, base, price float64, rangeLabel string) (*models.BandValuation, string) {
+	label := "PE"
+	baseLabel := "TTM每股收益"
+	if metric == "pb" {
+		label = "PB"
+		baseLabel = "每股净资产"
+	}
+	if base <= 0 {
+		return nil, fmt.Sprintf("%s为负或缺失，%s估值带不适用", baseLabel, label)
+	}
+	values := make([]float64, 0, len(history))
+	for _, value := range history {
+		if value > 0 {
+			values = append(values, value)
+		}
+	}
+	if len(values) < valuationBandMinSamples {
+		return nil, fmt.Sprintf("%s历史样本不足（%d），估值带不适用", label, len(values))
+	}
+	sort.Float64s(values)
+	if current <= 0 {
+		current = price / base
+	}
+
+	band := &models.BandValuation{
+		Metric:            metric,
+		Current:           round2(current),
+		CurrentPercentile: percentileRank(values, current),
+		Base:              round2(base),
+		Samples:           len(values),
+		Range:             rangeLabel,
+		Points:            make([]models.BandPoint, 0, len(valuationBandPercentiles)),
+	}
+	for _, p := range valuationBandPercentiles {
+		multiple := percentileSorted(values, p)
+		band.Points = append(band.Points, models.BandPoint{
+			Percentile: p,
+			Multiple:   round2(multiple),
+			Price:      round2(multiple * base),
+		})
+		if p == 50 {
+			band.FairValue = round2(multiple * base)
+		}
+	}
+	band.Upside = valuationUpside(band.FairValue, price)
+	return band, ""
+}
+
+// buildSensitivityTable 生成二维敏感性表
+func buildSensitivityTable(rowLabel, colLabel string, rows, cols []float64, value func(row, col float64) float64) *models.SensitivityTable {
+	table := &models.SensitivityTable{
+		RowLabel: rowLabel,
+		ColLabel: colLabel,
+		Rows:     rows,
+		Cols:     cols,
+		Values:   make([][]float64, len(rows)),
+	}
+	for i, row := range rows {
+		table.Values[i] = make([]float64, len(cols))
+		for j, col := range cols {
+			table.Values[i][j] = round2(value(row, col))
+		}
+	}
+	return table
+}
+
+// valuationSteps 以 center 为中心生成前后各两档的参数序列
+func valuationSteps(center, step float64) []float64 {
+	steps := make([]float64, 0, 5)
+	for i := -2; i <= 2; i++ {
+		steps = append(steps, round2(center+float64(i)*step))
+	}
+	return steps
+}
+
+// valuationUpside 估值相对现价的空间(%)
+func valuationUpside(value, price float64) float64 {
+	if price <= 0 || value <= 0 {
+		return 0
+	}
+	return round2((value/price - 1) * 100)
+}
+
+// percentileSorted 线性插值分位数，values 需升序
+func percentileSorted(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	pos := p / 100 * float64(len(values)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	if lower == upper {
+		return values[lower]
+	}
+	return values[lower] + (values[upper]-values[lower])*(pos-float64(lower))
+}
+
+// percentileRank 计算 value 在升序序列中的分位(%)
+func percentileRank(values []float64, value float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	count := sort.Search(len(values), func(i int) bool { return values[i] > value })
+	return round2(float64(count) / float64(len(values)) * 100)
+}
+
+// trendIndicatorValues 提取估值趋势序列中的指标值
+func trendIndicatorValues(records []map[string]any) []float64 {
+	values := make([]float64, 0, len(records))
+	for _, record := range records {
+		if value := toFloat(firstNonEmpty(record, "INDICATOR_VALUE", "VALUE")); value > 0 {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// trailingDividendPerShare 汇总近12个月已除权的每股现金分红
+func trailingDividendPerShare(records []map[string]any, now time.Time) float64 {
+	since := now.AddDate(-1, 0, 0)
+	total := 0.0
+	for _, record := range records {
+		exDate, err := time.ParseInLocation("2006-01-02", recordDate(record["EX_DIVIDEND_DATE"]), now.Location())
+		if err != nil || exDate.Before(since) || exDate.After(now) {
+			continue
+		}
+		match := dividendPlanPattern.FindStringSubmatch(toStringLocal(record["IMPL_PLAN_PROFILE"]))
+		if len(match) != 3 {
+			continue
+		}
+		base, _ := strconv.ParseFloat(match[1], 64)
+		cash, _ := strconv.ParseFloat(match[2], 64)
+		if base > 0 {
+			total += cash / base
+		}
+	}
+	return total
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestDCFPerShare(t *testing.T) {
+	stagePV, terminalPV, perShare := dcfPerShare(100, 10, 10, 1, 5, 0, 1)
+	if math.Abs(stagePV-100) > 1e-9 || math.Abs(terminalPV-2100) > 1e-9 || math.Abs(perShare-2200) > 1e-9 {
+		t.Fatalf("stagePV=%v terminalPV=%v perShare=%v", stagePV, terminalPV, perShare)
+	}
+}
+
+func TestComputeIntrinsicValue(t *testing.T) {
+	history := make([]float64, 0, 40)
+	for i := 1; i <= 40; i++ {
+		history = append(history, float64(i))
+	}
+	growth := 35.0
+	in := valuationInputs{
+		price:            30,
+		shares:           100,
+		epsTTM:           2,
+		bps:              -1,
+		fcfTTM:           1000,
+		netCash:          500,
+		dividendPerShare: 1,
+		profitGrowth:     &growth,
+		peHistory:        history,
+		rangeLabel:       "5y",
+	}
+
+	got := computeIntrinsicValue(in, models.ValuationParams{RequiredReturn: 9, DividendGrowth: 4})
+	if got.DCF == nil || got.DCF.Stage1Growth != valuationAutoGrowthCap || got.DCF.WACC != defaultValuationWACC {
+		t.Fatalf("unexpected dcf params: %+v", got.DCF)
+	}
+	_, _, want := dcfPerShare(1000, defaultValuationWACC, valuationAutoGrowthCap, defaultStage1Years, defaultTerminalGrowth, 500, 100)
+	if got.DCF.PerShare != round2(want) {
+		t.Fatalf("dcf perShare=%v want %v", got.DCF.PerShare, want)
+	}
+	sens := got.DCF.Sensitivity
+	if sens == nil || len(sens.Rows) != 5 || len(sens.Cols) != 5 || sens.Values[2][2] != got.DCF.PerShare {
+		t.Fatalf("unexpected sensitivity: %+v", sens)
+	}
+	// 折现率越高估值越低
+	if sens.Values[0][2] <= sens.Values[4][2] {
+		t.Fatalf("sensitivity not monotonic: %+v", sens.Values)
+	}
+
+	if got.DDM == nil || got.DDM.PerShare != 20.8 || got.DDM.DividendYield != 3.33 {
+		t.Fatalf("unexpected ddm: %+v", got.DDM)
+	}
+
+	band := got.PEBand
+	if band == nil || band.Current != 15 || band.CurrentPercentile != 37.5 || band.FairValue != 41 || band.Samples != 40 {
+		t.Fatalf("unexpected pe band: %+v", band)
+	}
+	if got.PBBand != nil || len(got.Notes) != 1 {
+		t.Fatalf("pb band should be skipped: %+v, notes=%v", got.PBBand, got.Notes)
+	}
+	if got.FairValueLow != 20.8 || got.FairValueHigh != got.DCF.PerShare {
+		t.Fatalf("unexpected fair range: low=%v mid=%v high=%v", got.FairValueLow, got.FairValueMid, got.FairValueHigh)
+	}
+
+	invalid := computeIntrinsicValue(valuationInputs{price: 10, shares: 1, fcfTTM: 1}, models.ValuationParams{WACC: 3, TerminalGrowth: 3})
+	if invalid.DCF != nil || invalid.FairValueMid != 0 || len(invalid.Notes) != 4 {
+		t.Fatalf("expected all models skipped: %+v", invalid)
+	}
+}
+
+func TestTrailingDividendPerShare(t *testing.T) {
+	now := time.Date(2025, 7, 1, 0, 0, 0, 0, time.Local)
+	records := []map[string]any{
+		{"IMPL_PLAN_PROFILE": "10派20元(含税)", "EX_DIVIDEND_DATE": "2025-06-20 00:00:00"},
+		{"IMPL_PLAN_PROFILE": "10转4派3.5元(含税)", "EX_DIVIDEND_DATE": "2024-12-10 00:00:00"},
+		{"IMPL_PLAN_PROFILE": "10派15元(含税)", "EX_DIVIDEND_DATE": "2024-06-10 00:00:00"},
+		{"IMPL_PLAN_PROFILE": "10派8元(含税)"},
+		{"IMPL_PLAN_PROFILE": "不分配不转增", "EX_DIVIDEND_DATE": "2025-05-01"},
+	}
+	if got := trailingDividendPerShare(records, now); math.Abs(got-2.35) > 1e-9 {
+		t.Fatalf("trailing dividend=%v", got)
+	}
+}
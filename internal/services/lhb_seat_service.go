@@ -0,0 +1,683 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+var seatLog = logger.New("lhbseat")
+
+const (
+	seatScheduleTick   = 30 * time.Minute
+	seatCollectHour    = 18 // 龙虎榜通常在收盘后 17-18 点披露完毕
+	seatRetentionDays  = 250
+	seatRefreshWindow  = 15 // 回填后续涨跌幅的回看天数
+	seatListPageSize   = 200
+	seatListMaxPages   = 5
+	seatConcurrency    = 4
+	seatTopStocks      = 5
+	seatHotMoneyMinApp = 10 // 高频短线席位判定的最少上榜次数
+	seatHotMoneyHold   = 3  // 高频短线席位判定的持有天数上限
+)
+
+// seatAliasRules 知名游资席位（按营业部名称关键字匹配）
+var seatAliasRules = []struct {
+	keyword string
+	alias   string
+}{
+	{"银河证券股份有限公司绍兴", "赵老哥"},
+	{"上海江苏路", "章盟主"},
+	{"上海溧阳路", "孙哥"},
+	{"华鑫证券有限责任公司上海分公司", "炒股养家"},
+	{"宁波解放南路", "宁波解放南"},
+	{"杭州上塘路", "上塘路"},
+	{"拉萨团结路", "拉萨天团"},
+	{"拉萨东环路", "拉萨天团"},
+	{"拉萨金融城南环路", "拉萨天团"},
+}
+
+// LhbSeatService 龙虎榜席位数据库服务
+type LhbSeatService struct {
+	longHuBangService *LongHuBangService
+	marketService     *MarketService
+	dir               string
+
+	mu       sync.RWMutex
+	days     map[string]*models.LhbSeatDay
+	profiles map[string]*models.SeatProfile // 画像缓存，数据变化时置空
+
+	collectMu sync.Mutex
+	stopChan  chan struct{}
+	stopOnce  sync.Once
+}
+
+// NewLhbSeatService 创建龙虎榜席位数据库服务
+func NewLhbSeatService(dataDir string, longHuBangService *LongHuBangService, marketService *MarketService) *LhbSeatService {
+	s := &LhbSeatService{
+		longHuBangService: longHuBangService,
+		marketService:     marketService,
+		dir:               filepath.Join(dataDir, "lhb_seats"),
+		days:              make(map[string]*models.LhbSeatDay),
+		stopChan:          make(chan struct{}),
+	}
+	s.load()
+	return s
+}
+
+// load 加载本地席位明细
+func (s *LhbSeatService) load() {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var day models.LhbSeatDay
+		if err := json.Unmarshal(data, &day); err != nil {
+			seatLog.Warn("解析席位明细失败 %s: %v", entry.Name(), err)
+			continue
+		}
+		s.days[day.TradeDate] = &day
+	}
+}
+
+// saveDay 保存单日席位明细并清理过期数据
+func (s *LhbSeatService) saveDay(day *models.LhbSeatDay) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(day, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(s.dir, day.TradeDate+".json"), data, 0644); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.days[day.TradeDate] = day
+	s.profiles = nil
+	dates := s.sortedDatesLocked()
+	for len(dates) > seatRetentionDays {
+		delete(s.days, dates[0])
+		os.Remove(filepath.Join(s.dir, dates[0]+".json"))
+		dates = dates[1:]
+	}
+	return nil
+}
+
+// sortedDatesLocked 已采集交易日（升序），调用方需持有锁
+func (s *LhbSeatService) sortedDatesLocked() []string {
+	dates := make([]string, 0, len(s.days))
+	for date := range s.days {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	return dates
+}
+
+// Start 启动每日收盘后采集任务
+func (s *LhbSeatService) Start() {
+	go func() {
+		ticker := time.NewTicker(seatScheduleTick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stopChan:
+				return
+			case <-ticker.C:
+				s.runSchedule()
+			}
+		}
+	}()
+}
+
+// Stop 停止采集任务
+func (s *LhbSeatService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopChan)
+	})
+}
+
+// runSchedule 交易日收盘后采集当日席位，并回填近期的后续涨跌幅
+func (s *LhbSeatService) runSchedule() {
+	now := time.Now().In(reviewLocation())
+	today := now.Format("2006-01-02")
+	if s.marketService != nil && s.marketService.GetMarketStatus().IsTradeDay && now.Hour() >= seatCollectHour {
+		s.mu.RLock()
+		_, collected := s.days[today]
+		s.mu.RUnlock()
+		if !collected {
+			if n, err := s.Collect(today); err != nil {
+				seatLog.Warn("采集龙虎榜席位失败: %v", err)
+			} else {
+				seatLog.Info("采集龙虎榜席位完成: %s, %d 条", today, n)
+			}
+		}
+	}
+	s.refreshChanges(today)
+}
+
+// Backfill 采集最近 days 个自然日内缺失的席位明细（跳过周末）
+func (s *LhbSeatService) Backfill(days int) (int, error) {
+	if days <= 0 {
+		days = 10
+	}
+	now := time.Now().In(reviewLocation())
+	total := 0
+	var lastErr error
+	for i := 0; i < days; i++ {
+		date := now.AddDate(0, 0, -i)
+		if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			continue
+		}
+		dateStr := date.Format("2006-01-02")
+		s.mu.RLock()
+		_, collected := s.days[dateStr]
+		s.mu.RUnlock()
+		if collected {
+			continue
+		}
+		n, err := s.Collect(dateStr)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		total += n
+	}
+	return total, lastErr
+}
+
+// Collect 采集指定交易日的全部上榜个股席位明细
+func (s *LhbSeatService) Collect(date string) (int, error) {
+	s.collectMu.Lock()
+	defer s.collectMu.Unlock()
+
+	items, err := s.fetchList(date)
+	if err != nil {
+		return 0, err
+	}
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	var mu sync.Mutex
+	var records []models.LhbSeatRecord
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, seatConcurrency)
+	for _, item := range items {
+		wg.Add(1)
+		go func(item models.LongHuBangItem) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			details, err := s.longHuBangService.GetStockDetail(item.Code, date)
+			if err != nil {
+				seatLog.Warn("获取席位明细失败 %s %s: %v", item.Code, date, err)
+				return
+			}
+			rows := mergeSeatDetails(item, details)
+			mu.Lock()
+			records = append(records, rows...)
+			mu.Unlock()
+		}(item)
+	}
+	wg.Wait()
+
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].Code != records[j].Code {
+			return records[i].Code < records[j].Code
+		}
+		return math.Abs(records[i].NetAmt) > math.Abs(records[j].NetAmt)
+	})
+	day := &models.LhbSeatDay{TradeDate: date, Records: records}
+	day.Horizon = seatDayHorizon(items)
+	if err := s.saveDay(day); err != nil {
+		return 0, err
+	}
+	return len(records), nil
+}
+
+// fetchList 获取指定日期龙虎榜个股（每只股票保留一条）
+func (s *LhbSeatService) fetchList(date string) ([]models.LongHuBangItem, error) {
+	var items []models.LongHuBangItem
+	seen := make(map[string]bool)
+	for page := 1; page <= seatListMaxPages; page++ {
+		result, err := s.longHuBangService.GetLongHuBangList(seatListPageSize, page, date)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range result.Items {
+			if item.TradeDate != date || seen[item.Code] {
+				continue
+			}
+			seen[item.Code] = true
+			items = append(items, item)
+		}
+		if len(result.Items) < seatListPageSize || page*seatListPageSize >= result.Total {
+			break
+		}
+	}
+	return items, nil
+}
+
+// refreshChanges 回填近期交易日的次日/5日涨跌幅
+func (s *LhbSeatService) refreshChanges(today string) {
+	s.mu.RLock()
+	var pending []models.LhbSeatDay
+	for _, date := range s.sortedDatesLocked() {
+		day := s.days[date]
+		if day.Horizon >= 5 || day.Refreshed == today || date == today {
+			continue
+		}
+		if days, ok := riskDaysFrom(time.Now().In(reviewLocation()), date); !ok || -days > seatRefreshWindow {
+			continue
+		}
+		copied := *day
+		copied.Records = append([]models.LhbSeatRecord(nil), day.Records...)
+		pending = append(pending, copied)
+	}
+	s.mu.RUnlock()
+
+	for i := range pending {
+		day := &pending[i]
+		items, err := s.fetchList(day.TradeDate)
+		if err != nil {
+			seatLog.Warn("回填龙虎榜涨跌幅失败 %s: %v", day.TradeDate, err)
+			continue
+		}
+		byCode := make(map[string]models.LongHuBangItem, len(items))
+		for _, item := range items {
+			byCode[item.Code] = item
+		}
+		for j := range day.Records {
+			if item, ok := byCode[day.Records[j].Code]; ok {
+				day.Records[j].D1Change = item.D1Change
+				day.Records[j].D5Change = item.D5Change
+			}
+		}
+		day.Horizon = seatDayHorizon(items)
+		day.Refreshed = today
+		if err := s.saveDay(day); err != nil {
+			seatLog.Warn("保存席位明细失败 %s: %v", day.TradeDate, err)
+		}
+	}
+}
+
+// getProfiles 获取全部席位画像（带缓存）
+func (s *LhbSeatService) getProfiles() map[string]*models.SeatProfile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.profiles == nil {
+		dates := s.sortedDatesLocked()
+		days := make([]*models.LhbSeatDay, 0, len(dates))
+		for _, date := range dates {
+			days = append(days, s.days[date])
+		}
+		s.profiles = buildSeatProfiles(days, s.tradeDayFunc())
+	}
+	return s.profiles
+}
+
+// tradeDayFunc 返回带缓存的交易日判断（含节假日），Market 服务未初始化时返回 nil
+func (s *LhbSeatService) tradeDayFunc() func(time.Time) bool {
+	if s.marketService == nil {
+		return nil
+	}
+	cache := make(map[string]bool)
+	return func(date time.Time) bool {
+		key := date.Format("2006-01-02")
+		if v, ok := cache[key]; ok {
+			return v
+		}
+		v, _ := s.marketService.isTradeDay(date)
+		cache[key] = v
+		return v
+	}
+}
+
+// GetSeatProfile 获取席位画像，支持营业部名称或游资别称模糊匹配
+func (s *LhbSeatService) GetSeatProfile(name string) (*models.SeatProfile, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("未提供席位名称")
+	}
+	profiles := s.getProfiles()
+	if profile, ok := profiles[name]; ok {
+		copied := *profile
+		return &copied, nil
+	}
+	var best *models.SeatProfile
+	for _, profile := range profiles {
+		if !strings.Contains(profile.OperName, name) && profile.Alias != name {
+			continue
+		}
+		if best == nil || profile.Appearances > best.Appearances {
+			best = profile
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("席位数据库中未找到 %s", name)
+	}
+	copied := *best
+	return &copied, nil
+}
+
+// ListSeats 按上榜次数列出席位画像，keyword/seatType 为空时不过滤
+func (s *LhbSeatService) ListSeats(keyword, seatType string, limit int) []models.SeatProfile {
+	if limit <= 0 {
+		limit = 20
+	}
+	var result []models.SeatProfile
+	for _, profile := range s.getProfiles() {
+		if seatType != "" && profile.SeatType != seatType {
+			continue
+		}
+		if keyword != "" && !strings.Contains(profile.OperName, keyword) && !strings.Contains(profile.Alias, keyword) {
+			continue
+		}
+		result = append(result, *profile)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Appearances != result[j].Appearances {
+			return result[i].Appearances > result[j].Appearances
+		}
+		return result[i].OperName < result[j].OperName
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// AnalyzeStock 分析个股上榜席位及其历史表现，tradeDate 为空时取数据库中最近一次上榜
+func (s *LhbSeatService) AnalyzeStock(code, tradeDate string) (*models.StockSeatAnalysis, error) {
+	code = normalizeStockListCode(code)
+	if code == "" {
+		return nil, fmt.Errorf("未提供股票代码")
+	}
+
+	var records []models.LhbSeatRecord
+	s.mu.RLock()
+	dates := s.sortedDatesLocked()
+	for i := len(dates) - 1; i >= 0 && records == nil; i-- {
+		if tradeDate != "" && dates[i] != tradeDate {
+			continue
+		}
+		for _, record := range s.days[dates[i]].Records {
+			if record.Code == code {
+				records = append(records, record)
+			}
+		}
+		if tradeDate != "" {
+			break
+		}
+	}
+	s.mu.RUnlock()
+
+	if records == nil {
+		if tradeDate == "" {
+			return nil, fmt.Errorf("席位数据库中暂无 %s 的龙虎榜记录", code)
+		}
+		details, err := s.longHuBangService.GetStockDetail(code, tradeDate)
+		if err != nil {
+			return nil, err
+		}
+		records = mergeSeatDetails(models.LongHuBangItem{Code: code, TradeDate: tradeDate}, details)
+		if len(records) == 0 {
+			return nil, fmt.Errorf("%s 在 %s 未上龙虎榜", code, tradeDate)
+		}
+	}
+
+	profiles := s.getProfiles()
+	analysis := &models.StockSeatAnalysis{
+		Code:      code,
+		Name:      records[0].Name,
+		TradeDate: records[0].TradeDate,
+		Reason:    records[0].Reason,
+		Buyers:    []models.StockSeatActivity{},
+		Sellers:   []models.StockSeatActivity{},
+	}
+	for _, record := range records {
+		seatType, alias := classifySeat(record.OperName, nil)
+		activity := models.StockSeatActivity{
+			OperName:  record.OperName,
+			SeatType:  seatType,
+			Alias:     alias,
+			Direction: record.Direction,
+			BuyAmt:    record.BuyAmt,
+			SellAmt:   record.SellAmt,
+			NetAmt:    record.NetAmt,
+		}
+		if profile, ok := profiles[record.OperName]; ok {
+			copied := *profile
+			activity.Profile = &copied
+			activity.SeatType = profile.SeatType
+		}
+		if record.NetAmt >= 0 {
+			analysis.Buyers = append(analysis.Buyers, activity)
+		} else {
+			analysis.Sellers = append(analysis.Sellers, activity)
+		}
+	}
+	return analysis, nil
+}
+
+// mergeSeatDetails 合并同一席位在买入榜与卖出榜的重复明细
+func mergeSeatDetails(item models.LongHuBangItem, details []models.LongHuBangDetail) []models.LhbSeatRecord {
+	index := make(map[string]int)
+	var records []models.LhbSeatRecord
+	for _, detail := range details {
+		name := strings.TrimSpace(detail.OperName)
+		if name == "" {
+			continue
+		}
+		// 机构专用等席位同日可能出现多个，按名称加金额区分
+		key := name
+		if strings.Contains(name, "专用") {
+			key = fmt.Sprintf("%s|%.2f|%.2f", name, detail.BuyAmt, detail.SellAmt)
+		}
+		if _, ok := index[key]; ok {
+			continue
+		}
+		index[key] = len(records)
+		direction := "buy"
+		if detail.BuyAmt < detail.SellAmt {
+			direction = "sell"
+		}
+		records = append(records, models.LhbSeatRecord{
+			TradeDate:     item.TradeDate,
+			Code:          item.Code,
+			Name:          item.Name,
+			OperName:      name,
+			Direction:     direction,
+			BuyAmt:        detail.BuyAmt,
+			SellAmt:       detail.SellAmt,
+			NetAmt:        detail.BuyAmt - detail.SellAmt,
+			Reason:        item.Reason,
+			ChangePercent: item.ChangePercent,
+			D1Change:      item.D1Change,
+			D5Change:      item.D5Change,
+		})
+	}
+	return records
+}
+
+// seatDayHorizon 判断列表中后续涨跌幅已披露到第几日
+func seatDayHorizon(items []models.LongHuBangItem) int {
+	horizon := 0
+	for _, item := range items {
+		if item.D5Change != 0 {
+			return 5
+		}
+		if item.D1Change != 0 {
+			horizon = 1
+		}
+	}
+	return horizon
+}
+
+// buildSeatProfiles 根据按日期升序的席位明细构建席位画像
+// isTradeDay 用于按实际日期计算持有交易日数，为空时按工作日估算
+func buildSeatProfiles(days []*models.LhbSeatDay, isTradeDay func(time.Time) bool) map[string]*models.SeatProfile {
+	type seatAcc struct {
+		profile *models.SeatProfile
+		d1, d5  []float64
+		holds   []float64
+		stocks  map[string]*models.SeatStockStat
+		openBuy map[string]string // 股票 -> 未平仓买入日期
+	}
+	accs := make(map[string]*seatAcc)
+
+	for _, day := range days {
+		for _, record := range day.Records {
+			acc, ok := accs[record.OperName]
+			if !ok {
+				acc = &seatAcc{
+					profile: &models.SeatProfile{OperName: record.OperName, FirstDate: day.TradeDate},
+					stocks:  make(map[string]*models.SeatStockStat),
+					openBuy: make(map[string]string),
+				}
+				accs[record.OperName] = acc
+			}
+			p := acc.profile
+			p.Appearances++
+			p.TotalBuy += record.BuyAmt
+			p.TotalSell += record.SellAmt
+			p.NetAmt += record.NetAmt
+			p.LastDate = day.TradeDate
+
+			stat, ok := acc.stocks[record.Code]
+			if !ok {
+				stat = &models.SeatStockStat{Code: record.Code, Name: record.Name}
+				acc.stocks[record.Code] = stat
+			}
+			stat.Count++
+			stat.NetAmt += record.NetAmt
+
+			if record.NetAmt > 0 {
+				p.BuyCount++
+				if day.Horizon >= 1 {
+					acc.d1 = append(acc.d1, record.D1Change)
+				}
+				if day.Horizon >= 5 {
+					acc.d5 = append(acc.d5, record.D5Change)
+				}
+				if _, open := acc.openBuy[record.Code]; !open {
+					acc.openBuy[record.Code] = day.TradeDate
+				}
+			} else if record.NetAmt < 0 {
+				p.SellCount++
+				if start, open := acc.openBuy[record.Code]; open && start < day.TradeDate {
+					if held := seatHoldDays(start, day.TradeDate, isTradeDay); held > 0 {
+						acc.holds = append(acc.holds, float64(held))
+					}
+					delete(acc.openBuy, record.Code)
+				}
+			}
+		}
+	}
+
+	profiles := make(map[string]*models.SeatProfile, len(accs))
+	for name, acc := range accs {
+		p := acc.profile
+		p.D1Samples, p.D1WinRate, p.D1AvgChange = seatWinStats(acc.d1)
+		p.D5Samples, p.D5WinRate, p.D5AvgChange = seatWinStats(acc.d5)
+		p.HoldSamples = len(acc.holds)
+		if len(acc.holds) > 0 {
+			sort.Float64s(acc.holds)
+			p.HoldDays = medianSorted(acc.holds)
+		}
+		p.TotalBuy = roundConsensus(p.TotalBuy, 2)
+		p.TotalSell = roundConsensus(p.TotalSell, 2)
+		p.NetAmt = roundConsensus(p.NetAmt, 2)
+
+		stocks := make([]models.SeatStockStat, 0, len(acc.stocks))
+		for _, stat := range acc.stocks {
+			stocks = append(stocks, *stat)
+		}
+		sort.Slice(stocks, func(i, j int) bool {
+			if stocks[i].Count != stocks[j].Count {
+				return stocks[i].Count > stocks[j].Count
+			}
+			return math.Abs(stocks[i].NetAmt) > math.Abs(stocks[j].NetAmt)
+		})
+		if len(stocks) > seatTopStocks {
+			stocks = stocks[:seatTopStocks]
+		}
+		p.TopStocks = stocks
+		p.SeatType, p.Alias = classifySeat(name, p)
+		profiles[name] = p
+	}
+	return profiles
+}
+
+// seatHoldDays 计算买入日（不含）到卖出日（含）之间的交易日数
+func seatHoldDays(from, to string, isTradeDay func(time.Time) bool) int {
+	start, err1 := time.Parse("2006-01-02", from)
+	end, err2 := time.Parse("2006-01-02", to)
+	if err1 != nil || err2 != nil {
+		return 0
+	}
+	count := 0
+	for d := start.AddDate(0, 0, 1); !d.After(end); d = d.AddDate(0, 0, 1) {
+		if isTradeDay != nil {
+			if isTradeDay(d) {
+				count++
+			}
+		} else if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
+			count++
+		}
+	}
+	return count
+}
+
+// seatWinStats 计算样本数、上涨概率(%)与平均涨跌幅
+func seatWinStats(changes []float64) (int, float64, float64) {
+	if len(changes) == 0 {
+		return 0, 0, 0
+	}
+	wins := 0
+	for _, change := range changes {
+		if change > 0 {
+			wins++
+		}
+	}
+	return len(changes), roundConsensus(float64(wins)/float64(len(changes))*100, 2), roundConsensus(meanFloat(changes), 2)
+}
+
+// classifySeat 识别席位类型与游资别称，profile 为空时仅按名称判断
+func classifySeat(name string, profile *models.SeatProfile) (string, string) {
+	switch {
+	case strings.Contains(name, "机构专用"):
+		return models.SeatTypeInstitution, ""
+	case strings.Contains(name, "沪股通专用") || strings.Contains(name, "深股通专用"):
+		return models.SeatTypeNorthbound, ""
+	}
+	for _, rule := range seatAliasRules {
+		if strings.Contains(name, rule.keyword) {
+			return models.SeatTypeHotMoney, rule.alias
+		}
+	}
+	if profile != nil && profile.Appearances >= seatHotMoneyMinApp && profile.HoldSamples > 0 && profile.HoldDays <= seatHotMoneyHold {
+		return models.SeatTypeHotMoney, ""
+	}
+	return models.SeatTypeBranch, ""
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestMergeSeatDetails(t *testing.T) {
+	item := models.LongHuBangItem{TradeDate: "2025-10-20", Code: "600000", Name: "浦发银行", D1Change: 2.5}
+	records := mergeSeatDetails(item, []models.LongHuBangDetail{
+		{OperName: "华鑫证券有限责任公司上海分公司", BuyAmt: 5e7, SellAmt: 1e6, Direction: "buy"},
+		{OperName: "机构专用", BuyAmt: 3e7, Direction: "buy"},
+		{OperName: "机构专用", BuyAmt: 2e7, Direction: "buy"},
+		{OperName: "华鑫证券有限责任公司上海分公司", BuyAmt: 5e7, SellAmt: 1e6, Direction: "sell"},
+		{OperName: "某证券营业部", SellAmt: 4e7, Direction: "sell"},
+	})
+	if len(records) != 4 {
+		t.Fatalf("records = %+v, want 4", records)
+	}
+	if records[0].NetAmt != 4.9e7 || records[0].Direction != "buy" || records[0].D1Change != 2.5 {
+		t.Errorf("unexpected merged record: %+v", records[0])
+	}
+	if records[3].Direction != "sell" || records[3].NetAmt != -4e7 {
+		t.Errorf("unexpected sell record: %+v", records[3])
+	}
+}
+
+func TestBuildSeatProfiles(t *testing.T) {
+	seat := "某证券股份有限公司某路证券营业部"
+	days := []*models.LhbSeatDay{
+		{TradeDate: "2025-10-13", Horizon: 5, Records: []models.LhbSeatRecord{
+			{Code: "000001", Name: "甲", OperName: seat, BuyAmt: 1e7, NetAmt: 1e7, D1Change: 3, D5Change: -2},
+			{Code: "000002", Name: "乙", OperName: "机构专用", BuyAmt: 2e7, NetAmt: 2e7, D1Change: 1, D5Change: 4},
+		}},
+		{TradeDate: "2025-10-14", Horizon: 5, Records: []models.LhbSeatRecord{
+			{Code: "000003", Name: "丙", OperName: seat, BuyAmt: 5e6, NetAmt: 5e6, D1Change: -1, D5Change: 6},
+		}},
+		{TradeDate: "2025-10-15", Horizon: 1, Records: []models.LhbSeatRecord{
+			{Code: "000001", Name: "甲", OperName: seat, SellAmt: 1.2e7, NetAmt: -1.2e7, D1Change: 2},
+		}},
+		{TradeDate: "2025-10-16", Horizon: 0, Records: []models.LhbSeatRecord{
+			{Code: "000001", Name: "甲", OperName: seat, BuyAmt: 8e6, NetAmt: 8e6},
+		}},
+	}
+
+	profiles := buildSeatProfiles(days, nil)
+	p := profiles[seat]
+	if p == nil {
+		t.Fatal("missing seat profile")
+	}
+	if p.Appearances != 4 || p.BuyCount != 3 || p.SellCount != 1 || p.FirstDate != "2025-10-13" || p.LastDate != "2025-10-16" {
+		t.Fatalf("unexpected counts: %+v", p)
+	}
+	if p.D1Samples != 2 || p.D1WinRate != 50 || p.D1AvgChange != 1 {
+		t.Errorf("unexpected d1 stats: %+v", p)
+	}
+	if p.D5Samples != 2 || p.D5WinRate != 50 || p.D5AvgChange != 2 {
+		t.Errorf("unexpected d5 stats: %+v", p)
+	}
+	if p.HoldSamples != 1 || p.HoldDays != 2 {
+		t.Errorf("unexpected hold stats: %+v", p)
+	}
+	if len(p.TopStocks) != 2 || p.TopStocks[0].Code != "000001" || p.TopStocks[0].Count != 3 {
+		t.Errorf("unexpected top stocks: %+v", p.TopStocks)
+	}
+	if p.SeatType != models.SeatTypeBranch || profiles["机构专用"].SeatType != models.SeatTypeInstitution {
+		t.Errorf("unexpected seat types: %s / %s", p.SeatType, profiles["机构专用"].SeatType)
+	}
+}
+
+func TestSeatHoldDays(t *testing.T) {
+	// 跨周末与国庆假期：9月26日（周五）买入，10月9日卖出
+	if got := seatHoldDays("2025-09-26", "2025-10-09", nil); got != 9 {
+		t.Errorf("weekday estimate = %d, want 9", got)
+	}
+	isTradeDay := func(d time.Time) bool {
+		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+			return false
+		}
+		return d.Format("2006-01-02") < "2025-10-01" || d.Format("2006-01-02") > "2025-10-08"
+	}
+	if got := seatHoldDays("2025-09-26", "2025-10-09", isTradeDay); got != 3 {
+		t.Errorf("trade day count = %d, want 3", got)
+	}
+
+	// 中间日期未采集时仍按实际间隔计算
+	seat := "某营业部"
+	profiles := buildSeatProfiles([]*models.LhbSeatDay{
+		{TradeDate: "2025-10-13", Records: []models.LhbSeatRecord{{Code: "000001", OperName: seat, NetAmt: 1e7}}},
+		{TradeDate: "2025-10-20", Records: []models.LhbSeatRecord{{Code: "000001", OperName: seat, NetAmt: -1e7}}},
+	}, nil)
+	if p := profiles[seat]; p.HoldSamples != 1 || p.HoldDays != 5 {
+		t.Errorf("unexpected hold stats: %+v", p)
+	}
+}
+
+func TestClassifySeat(t *testing.T) {
+	cases := []struct {
+		name     string
+		profile  *models.SeatProfile
+		seatType string
+		alias    string
+	}{
+		{"沪股通专用", nil, models.SeatTypeNorthbound, ""},
+		{"中国银河证券股份有限公司绍兴证券营业部", nil, models.SeatTypeHotMoney, "赵老哥"},
+		{"某证券营业部", &models.SeatProfile{Appearances: 12, HoldSamples: 5, HoldDays: 2}, models.SeatTypeHotMoney, ""},
+		{"某证券营业部", &models.SeatProfile{Appearances: 12, HoldSamples: 5, HoldDays: 8}, models.SeatTypeBranch, ""},
+	}
+	for _, c := range cases {
+		seatType, alias := classifySeat(c.name, c.profile)
+		if seatType != c.seatType || alias != c.alias {
+			t.Errorf("classifySeat(%q) = %s/%s, want %s/%s", c.name, seatType, alias, c.seatType, c.alias)
+		}
+	}
+}
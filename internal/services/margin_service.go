@@ -0,0 +1,138 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+const marginDetailReport = "RPTA_WEB_RZRQ_GGMX"
+
+// MarginService 融资融券数据服务
+type MarginService struct {
+	f10Service *F10Service
+
+	cacheTTL time.Duration
+	cacheMu  sync.RWMutex
+	cache    map[string]cacheEntry
+}
+
+// NewMarginService 创建融资融券数据服务
+func NewMarginService(f10Service *F10Service) *MarginService {
+	return &MarginService{
+		f10Service: f10Service,
+		cacheTTL:   capitalFlowCacheTTL,
+		cache:      make(map[string]cacheEntry),
+	}
+}
+
+// GetMarginTrading 获取个股融资融券余额序列，days 为返回的交易日数
+func (s *MarginService) GetMarginTrading(code string, days int) (models.MarginTrading, error) {
+	normalized := normalizeStockCode(code)
+	if normalized.Raw == "" {
+		return models.MarginTrading{}, fmt.Errorf("股票代码不能为空")
+	}
+	days = capitalFlowDays(days)
+
+	cacheKey := "margin:" + normalized.Raw
+	entry, ok, fresh := s.getCacheEntry(cacheKey)
+	if ok && fresh {
+		if data, ok := entry.value.(models.MarginTrading); ok {
+			return trimMarginTrading(data, days), nil
+		}
+	}
+
+	filter := fmt.Sprintf(`(SCODE="%s")`, normalized.Raw)
+	records, err := s.f10Service.fetchDataCenterWebWithFilter(marginDetailReport, filter, capitalFlowFetchSize, "DATE", "-1")
+	if err != nil {
+		if ok {
+			if cached, ok := entry.value.(models.MarginTrading); ok {
+				return trimMarginTrading(cached, days), fmt.Errorf("使用缓存数据（%s），上游错误: %v", entry.timestamp.Format("2006-01-02 15:04:05"), err)
+			}
+		}
+		return models.MarginTrading{}, err
+	}
+	if len(records) == 0 {
+		return models.MarginTrading{}, fmt.Errorf("暂无 %s 的融资融券数据（可能不是两融标的）", normalized.Raw)
+	}
+
+	result := parseMarginRecords(normalized.Raw, records)
+	result.UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
+	s.setCache(cacheKey, result)
+	return trimMarginTrading(result, days), nil
+}
+
+func (s *MarginService) getCacheEntry(key string) (cacheEntry, bool, bool) {
+	s.cacheMu.RLock()
+	defer s.cacheMu.RUnlock()
+	entry, ok := s.cache[key]
+	if !ok {
+		return cacheEntry{}, false, false
+	}
+	return entry, true, time.Since(entry.timestamp) <= s.cacheTTL
+}
+
+func (s *MarginService) setCache(key string, value any) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	s.cache[key] = cacheEntry{value: value, timestamp: time.Now()}
+}
+
+// parseMarginRecords 解析融资融券明细，按日期倒序
+func parseMarginRecords(code string, records []map[string]any) models.MarginTrading {
+	result := models.MarginTrading{Code: code}
+	for _, record := range records {
+		date := recordDate(record["DATE"])
+		if date == "" {
+			continue
+		}
+		if result.Name == "" {
+			result.Name = strings.TrimSpace(toStringLocal(record["SECNAME"]))
+		}
+		day := models.MarginDay{
+			TradeDate:       date,
+			ClosePrice:      toFloat(record["SPJ"]),
+			ChangePercent:   toFloat(record["ZDF"]),
+			FinBalance:      toFloat(record["RZYE"]),
+			FinBuy:          toFloat(record["RZMRE"]),
+			FinRepay:        toFloat(record["RZCHE"]),
+			FinNetBuy:       toFloat(record["RZJME"]),
+			SecBalance:      toFloat(record["RQYE"]),
+			SecVolume:       toFloat(record["RQYL"]),
+			SecSell:         toFloat(record["RQMCL"]),
+			SecRepay:        toFloat(record["RQCHL"]),
+			TotalBalance:    toFloat(record["RZRQYE"]),
+			FinBalanceRatio: toFloat(record["RZYEZB"]),
+		}
+		if day.FinNetBuy == 0 && (day.FinBuy != 0 || day.FinRepay != 0) {
+			day.FinNetBuy = day.FinBuy - day.FinRepay
+		}
+		if day.TotalBalance == 0 {
+			day.TotalBalance = day.FinBalance + day.SecBalance
+		}
+		result.Items = append(result.Items, day)
+	}
+	sort.SliceStable(result.Items, func(i, j int) bool {
+		return result.Items[i].TradeDate > result.Items[j].TradeDate
+	})
+
+	balances := make([]float64, len(result.Items))
+	for i, item := range result.Items {
+		balances[i] = item.FinBalance
+	}
+	result.FinChange5D = seriesChange(balances, 5)
+	result.FinChange20D = seriesChange(balances, 20)
+	return result
+}
+
+// trimMarginTrading 截取最近 days 个交易日
+func trimMarginTrading(data models.MarginTrading, days int) models.MarginTrading {
+	if len(data.Items) > days {
+		data.Items = append([]models.MarginDay(nil), data.Items[:days]...)
+	}
+	return data
+}
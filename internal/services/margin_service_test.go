@@ -0,0 +1,29 @@
+package services
+
+import "testing"
+
+func TestParseMarginRecords(t *testing.T) {
+	records := []map[string]any{
+		{"DATE": "2025-06-04 00:00:00", "SECNAME": "测试股份", "RZYE": 9e8, "RZMRE": 1e8, "RZCHE": 0.5e8, "RQYE": 1e7},
+		{"DATE": "2025-06-05 00:00:00", "SECNAME": "测试股份", "RZYE": 1e9, "RZMRE": 2e8, "RZCHE": 1e8, "RZJME": 1e8, "RQYE": 2e7, "RZRQYE": 1.02e9},
+		{"DATE": "2025-06-03 00:00:00", "SECNAME": "测试股份", "RZYE": 8e8},
+	}
+
+	got := parseMarginRecords("000001", records)
+	if got.Name != "测试股份" || len(got.Items) != 3 || got.Items[0].TradeDate != "2025-06-05" {
+		t.Fatalf("unexpected margin: %+v", got)
+	}
+	// 缺失净买入与两融余额时按买入-偿还、融资+融券补算
+	if got.Items[1].FinNetBuy != 0.5e8 || got.Items[1].TotalBalance != 9.1e8 {
+		t.Fatalf("unexpected derived fields: %+v", got.Items[1])
+	}
+	if got.Items[0].TotalBalance != 1.02e9 {
+		t.Fatalf("unexpected total balance: %+v", got.Items[0])
+	}
+	if got.FinChange5D != 2e8 || got.FinChange20D != 2e8 {
+		t.Fatalf("finChange5d=%v finChange20d=%v", got.FinChange5D, got.FinChange20D)
+	}
+	if trimmed := trimMarginTrading(got, 1); len(trimmed.Items) != 1 || trimmed.Items[0].TradeDate != "2025-06-05" {
+		t.Fatalf("unexpected trim: %+v", trimmed)
+	}
+}
@@ -0,0 +1,455 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/services/hottrend"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/genai"
+)
+
+var reviewLog = logger.New("review")
+
+const (
+	reviewLimitPageSize  = 200
+	reviewLimitMaxPages  = 10
+	reviewLimitStopPct   = 4.5 // 低于 ST 涨跌幅（5%，低价股按分取整后略低）时停止翻页
+	reviewStockTopN      = 20
+	reviewBoardTopN      = 8
+	reviewLongHuBangTopN = 10
+	reviewNewsTopN       = 12
+	reviewHotTopN        = 8
+)
+
+// reviewHotPlatforms 复盘采集的热点平台
+var reviewHotPlatforms = []string{"weibo", "baidu", "toutiao"}
+
+var reviewDatePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+
+// MarketReviewService 每日市场复盘服务
+type MarketReviewService struct {
+	reviewsDir        string
+	marketService     *MarketService
+	longHuBangService *LongHuBangService
+	newsService       *NewsService
+	hotTrendService   *hottrend.HotTrendService
+	mu                sync.Mutex
+}
+
+// NewMarketReviewService 创建复盘服务
+func NewMarketReviewService(dataDir string, marketService *MarketService, longHuBangService *LongHuBangService, newsService *NewsService, hotTrendService *hottrend.HotTrendService) *MarketReviewService {
+	return &MarketReviewService{
+		reviewsDir:        filepath.Join(dataDir, "reviews"),
+		marketService:     marketService,
+		longHuBangService: longHuBangService,
+		newsService:       newsService,
+		hotTrendService:   hotTrendService,
+	}
+}
+
+// Generate 生成当日复盘并保存，llm 不为空时附加小韭菜点评
+func (s *MarketReviewService) Generate(ctx context.Context, llm model.LLM) (*models.MarketReview, error) {
+	review := s.collect(time.Now().In(reviewLocation()).Format("2006-01-02"))
+	if llm != nil {
+		narrative, err := s.narrate(ctx, llm, review)
+		if err != nil {
+			reviewLog.Warn("生成复盘点评失败: %v", err)
+			review.Errors["narrative"] = err.Error()
+		} else {
+			review.Narrative = strings.TrimSpace(narrative)
+		}
+	}
+	if len(review.Errors) == 0 {
+		review.Errors = nil
+	}
+	if err := s.save(review); err != nil {
+		return review, fmt.Errorf("保存复盘失败: %w", err)
+	}
+	return review, nil
+}
+
+// ListReviews 获取历史复盘列表（按日期倒序）
+func (s *MarketReviewService) ListReviews() []models.MarketReviewSummary {
+	entries, err := os.ReadDir(s.reviewsDir)
+	if err != nil {
+		return []models.MarketReviewSummary{}
+	}
+	result := make([]models.MarketReviewSummary, 0, len(entries))
+	for _, entry := range entries {
+		date := strings.TrimSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !reviewDatePattern.MatchString(date) {
+			continue
+		}
+		review, err := s.GetReview(date)
+		if err != nil {
+			continue
+		}
+		result = append(result, models.MarketReviewSummary{
+			Date:           review.Date,
+			GeneratedAt:    review.GeneratedAt,
+			LimitUpCount:   review.LimitUpCount,
+			LimitDownCount: review.LimitDownCount,
+			HasNarrative:   review.Narrative != "",
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Date > result[j].Date })
+	return result
+}
+
+// GetReview 获取指定日期的复盘
+func (s *MarketReviewService) GetReview(date string) (*models.MarketReview, error) {
+	if !reviewDatePattern.MatchString(date) {
+		return nil, fmt.Errorf("无效日期: %s", date)
+	}
+	data, err := os.ReadFile(filepath.Join(s.reviewsDir, date+".json"))
+	if err != nil {
+		return nil, err
+	}
+	var review models.MarketReview
+	if err := json.Unmarshal(data, &review); err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// DeleteReview 删除指定日期的复盘
+func (s *MarketReviewService) DeleteReview(date string) error {
+	if !reviewDatePattern.MatchString(date) {
+		return fmt.Errorf("无效日期: %s", date)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return os.Remove(filepath.Join(s.reviewsDir, date+".json"))
+}
+
+// save 按日期保存复盘（同日重复生成会覆盖）
+func (s *MarketReviewService) save(review *models.MarketReview) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(s.reviewsDir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(review, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.reviewsDir, review.Date+".json"), data, 0644)
+}
+
+// collect 并发采集各项复盘数据，单项失败记录到 Errors
+func (s *MarketReviewService) collect(date string) *models.MarketReview {
+	review := &models.MarketReview{
+		Date:        date,
+		GeneratedAt: time.Now().UnixMilli(),
+		Errors:      make(map[string]string),
+	}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	run := func(name string, fn func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(); err != nil {
+				mu.Lock()
+				review.Errors[name] = err.Error()
+				mu.Unlock()
+			}
+		}()
+	}
+
+	if s.marketService != nil {
+		run("indices", func() error {
+			indices, err := s.marketService.GetMarketIndices()
+			review.Indices = indices
+			return err
+		})
+		run("limitUp", func() error {
+			items, err := s.collectLimitStocks("change_up")
+			review.LimitUpCount = len(items)
+			review.LimitUpStocks = truncateReviewStocks(items, reviewStockTopN)
+			return err
+		})
+		run("limitDown", func() error {
+			items, err := s.collectLimitStocks("change_down")
+			review.LimitDownCount = len(items)
+			review.LimitDownStocks = truncateReviewStocks(items, reviewStockTopN)
+			return err
+		})
+		run("industry", func() error {
+			list, err := s.marketService.GetBoardFundFlowList("industry", 1, reviewBoardTopN)
+			review.IndustryInflow = list.Items
+			return err
+		})
+		run("concept", func() error {
+			list, err := s.marketService.GetBoardFundFlowList("concept", 1, reviewBoardTopN)
+			review.ConceptInflow = list.Items
+			return err
+		})
+	}
+	if s.longHuBangService != nil {
+		run("longHuBang", func() error {
+			items, tradeDate, err := s.collectLongHuBang(date)
+			review.LongHuBang = items
+			review.LongHuBangDate = tradeDate
+			return err
+		})
+	}
+	if s.newsService != nil {
+		run("news", func() error {
+			telegraphs, err := s.newsService.GetTelegraphList()
+			for i, t := range telegraphs {
+				if i >= reviewNewsTopN {
+					break
+				}
+				review.News = append(review.News, models.ReviewNews{Time: t.Time, Content: t.Content, URL: t.URL})
+			}
+			return err
+		})
+	}
+	if s.hotTrendService != nil {
+		run("hotTopics", func() error {
+			var errs []string
+			for _, result := range s.hotTrendService.GetHotTrends(reviewHotPlatforms) {
+				if result.Error != "" {
+					errs = append(errs, result.PlatformCN+": "+result.Error)
+					continue
+				}
+				for i, item := range result.Items {
+					if i >= reviewHotTopN {
+						break
+					}
+					review.HotTopics = append(review.HotTopics, models.ReviewHotTopic{
+						Platform: result.PlatformCN,
+						Title:    item.Title,
+						Rank:     item.Rank,
+						URL:      item.URL,
+					})
+				}
+			}
+			if len(errs) > 0 {
+				return fmt.Errorf("%s", strings.Join(errs, "；"))
+			}
+			return nil
+		})
+	}
+
+	wg.Wait()
+	return review
+}
+
+// collectLimitStocks 按涨跌幅排行翻页收集涨停/跌停个股
+func (s *MarketReviewService) collectLimitStocks(moveType string) ([]models.ReviewStock, error) {
+	var result []models.ReviewStock
+	for page := 1; page <= reviewLimitMaxPages; page++ {
+		list, err := s.marketService.GetStockMovesList(moveType, page, reviewLimitPageSize)
+		if err != nil {
+			return result, err
+		}
+		matched, done := filterLimitStocks(list.Items, moveType == "change_up")
+		result = append(result, matched...)
+		if done || len(list.Items) < reviewLimitPageSize {
+			break
+		}
+	}
+	return result, nil
+}
+
+// filterLimitStocks 从涨跌幅排行中筛选涨停/跌停个股，按板块与 ST 状态比较现价与涨跌停价
+// 排行按涨跌幅排序，出现绝对涨跌幅低于 ST 涨跌停幅度的个股后即可停止翻页
+func filterLimitStocks(items []models.StockMoveItem, up bool) ([]models.ReviewStock, bool) {
+	var result []models.ReviewStock
+	for _, item := range items {
+		change := item.ChangePercent
+		if !up {
+			change = -change
+		}
+		if change < reviewLimitStopPct {
+			return result, true
+		}
+		if item.PreClose <= 0 || item.Price <= 0 {
+			continue
+		}
+		upPrice, downPrice := stockLimitPrices(item.Code, item.Name, item.PreClose)
+		if (up && item.Price < upPrice-0.001) || (!up && item.Price > downPrice+0.001) {
+			continue
+		}
+		result = append(result, models.ReviewStock{
+			Code:          item.Code,
+			Name:          item.Name,
+			Price:         item.Price,
+			ChangePercent: item.ChangePercent,
+			TurnoverRate:  item.TurnoverRate,
+			Amount:        item.Amount,
+		})
+	}
+	return result, false
+}
+
+// collectLongHuBang 获取龙虎榜（当日未公布时使用最近一个交易日），按净买入取前列
+func (s *MarketReviewService) collectLongHuBang(date string) ([]models.LongHuBangItem, string, error) {
+	result, err := s.longHuBangService.GetLongHuBangList(100, 1, date)
+	if err == nil && len(result.Items) == 0 {
+		result, err = s.longHuBangService.GetLongHuBangList(100, 1, "")
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if len(result.Items) == 0 {
+		return nil, "", nil
+	}
+
+	tradeDate := result.Items[0].TradeDate
+	items := make([]models.LongHuBangItem, 0, len(result.Items))
+	seen := make(map[string]bool)
+	for _, item := range result.Items {
+		// 同一股票可能因多个原因重复上榜
+		if item.TradeDate != tradeDate || seen[item.Code] {
+			continue
+		}
+		seen[item.Code] = true
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].NetBuyAmt > items[j].NetBuyAmt })
+	if len(items) > reviewLongHuBangTopN {
+		items = items[:reviewLongHuBangTopN]
+	}
+	if len(tradeDate) > 10 {
+		tradeDate = tradeDate[:10]
+	}
+	return items, tradeDate, nil
+}
+
+// narrate 由小韭菜根据复盘数据撰写点评
+func (s *MarketReviewService) narrate(ctx context.Context, llm model.LLM, review *models.MarketReview) (string, error) {
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{
+			{Role: "user", Parts: []*genai.Part{genai.NewPartFromText(buildReviewPrompt(review))}},
+		},
+	}
+	var result strings.Builder
+	for resp, err := range llm.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return "", err
+		}
+		if resp != nil && resp.Content != nil {
+			for _, part := range resp.Content.Parts {
+				if !part.Thought && part.Text != "" {
+					result.WriteString(part.Text)
+				}
+			}
+		}
+	}
+	return result.String(), nil
+}
+
+// buildReviewPrompt 构建复盘点评 Prompt
+func buildReviewPrompt(review *models.MarketReview) string {
+	var sb strings.Builder
+	sb.WriteString("你是「财经会议室」的小韭菜，请根据以下数据撰写今日A股收盘复盘。\n\n")
+	fmt.Fprintf(&sb, "## 日期：%s\n\n", review.Date)
+
+	if len(review.Indices) > 0 {
+		sb.WriteString("## 指数表现\n")
+		for _, idx := range review.Indices {
+			fmt.Fprintf(&sb, "- %s %.2f（%.2f%%），成交额 %.0f亿\n", idx.Name, idx.Price, idx.ChangePercent, idx.Amount/1e4)
+		}
+		sb.WriteString("\n")
+	}
+
+	fmt.Fprintf(&sb, "## 涨跌停\n涨停 %d 家，跌停 %d 家\n", review.LimitUpCount, review.LimitDownCount)
+	if names := reviewStockNames(review.LimitUpStocks, 10); names != "" {
+		sb.WriteString("涨停代表：" + names + "\n")
+	}
+	if names := reviewStockNames(review.LimitDownStocks, 5); names != "" {
+		sb.WriteString("跌停代表：" + names + "\n")
+	}
+	sb.WriteString("\n")
+
+	writeBoards := func(title string, items []models.BoardFundFlowItem) {
+		if len(items) == 0 {
+			return
+		}
+		sb.WriteString("## " + title + "\n")
+		for _, item := range items {
+			fmt.Fprintf(&sb, "- %s 涨跌幅 %.2f%%，主力净流入 %.2f亿\n", item.Name, item.ChangePercent, item.MainNetInflow/1e8)
+		}
+		sb.WriteString("\n")
+	}
+	writeBoards("行业资金流入前列", review.IndustryInflow)
+	writeBoards("概念资金流入前列", review.ConceptInflow)
+
+	if len(review.LongHuBang) > 0 {
+		fmt.Fprintf(&sb, "## 龙虎榜净买入前列（%s）\n", review.LongHuBangDate)
+		for _, item := range review.LongHuBang {
+			fmt.Fprintf(&sb, "- %s(%s) 涨跌幅 %.2f%%，净买入 %.2f亿，%s\n", item.Name, item.Code, item.ChangePercent, item.NetBuyAmt/1e8, item.Reason)
+		}
+		sb.WriteString("\n")
+	}
+	if len(review.News) > 0 {
+		sb.WriteString("## 重要快讯\n")
+		for _, n := range review.News {
+			fmt.Fprintf(&sb, "- [%s] %s\n", n.Time, truncateRunes(n.Content, 80))
+		}
+		sb.WriteString("\n")
+	}
+	if len(review.HotTopics) > 0 {
+		sb.WriteString("## 全网热点\n")
+		for _, t := range review.HotTopics {
+			fmt.Fprintf(&sb, "- %s#%d %s\n", t.Platform, t.Rank, t.Title)
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("## 输出要求\n")
+	sb.WriteString("1. 大盘综述：指数、量能与赚钱效应\n")
+	sb.WriteString("2. 主线与资金：领涨方向、资金流向及龙虎榜看点\n")
+	sb.WriteString("3. 情绪与风险：涨跌停结构、值得警惕的信号\n")
+	sb.WriteString("4. 明日关注：需要跟踪的方向与事件\n\n")
+	sb.WriteString("仅依据上述数据，不要编造，控制在 600 字以内。")
+	return sb.String()
+}
+
+func reviewStockNames(items []models.ReviewStock, limit int) string {
+	names := make([]string, 0, limit)
+	for _, item := range items {
+		if len(names) >= limit {
+			break
+		}
+		names = append(names, item.Name)
+	}
+	return strings.Join(names, "、")
+}
+
+func truncateReviewStocks(items []models.ReviewStock, limit int) []models.ReviewStock {
+	if len(items) > limit {
+		return items[:limit]
+	}
+	return items
+}
+
+func truncateRunes(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit]) + "..."
+}
+
+func reviewLocation() *time.Location {
+	return time.FixedZone("CST", 8*60*60)
+}
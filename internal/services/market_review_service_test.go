@@ -0,0 +1,73 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestFilterLimitStocks(t *testing.T) {
+	items := []models.StockMoveItem{
+		{Code: "688001", Name: "科创A", PreClose: 10, Price: 12, ChangePercent: 20},
+		{Code: "300001", Name: "创业A", PreClose: 10, Price: 11.5, ChangePercent: 15},
+		{Code: "300002", Name: "创业B", PreClose: 10, Price: 11, ChangePercent: 10},
+		{Code: "600001", Name: "主板A", PreClose: 9.99, Price: 10.99, ChangePercent: 10.01},
+		{Code: "000001", Name: "主板B", PreClose: 10.1, Price: 11.11, ChangePercent: 10},
+		{Code: "000002", Name: "*ST主板", PreClose: 3.17, Price: 3.33, ChangePercent: 5.05},
+		{Code: "000003", Name: "ST主板", PreClose: 2.01, Price: 2.11, ChangePercent: 4.98},
+		{Code: "000004", Name: "主板C", PreClose: 10, Price: 10.5, ChangePercent: 5},
+		{Code: "000005", Name: "主板D", PreClose: 10, Price: 10.4, ChangePercent: 4},
+		{Code: "000006", Name: "ST主板E", PreClose: 10, Price: 10.5, ChangePercent: 5},
+	}
+	got, done := filterLimitStocks(items, true)
+	if !done {
+		t.Fatal("expected paging to stop below the ST limit threshold")
+	}
+	var codes []string
+	for _, stock := range got {
+		codes = append(codes, stock.Code)
+	}
+	if want := "688001,600001,000001,000002,000003"; strings.Join(codes, ",") != want {
+		t.Fatalf("limit-up codes = %v, want %s", codes, want)
+	}
+
+	down, _ := filterLimitStocks([]models.StockMoveItem{
+		{Code: "600002", Name: "主板", PreClose: 10, Price: 9, ChangePercent: -10},
+		{Code: "300003", Name: "创业", PreClose: 10, Price: 8.8, ChangePercent: -12},
+		{Code: "600003", Name: "ST主板", PreClose: 4, Price: 3.8, ChangePercent: -5},
+	}, false)
+	if len(down) != 2 || down[0].Code != "600002" || down[1].Code != "600003" {
+		t.Fatalf("unexpected limit-down stocks: %+v", down)
+	}
+}
+
+func TestMarketReviewPersistence(t *testing.T) {
+	s := NewMarketReviewService(t.TempDir(), nil, nil, nil, nil)
+	for _, date := range []string{"2024-05-06", "2024-05-08", "2024-05-07"} {
+		if err := s.save(&models.MarketReview{Date: date, LimitUpCount: 1}); err != nil {
+			t.Fatalf("save %s: %v", date, err)
+		}
+	}
+	if err := s.save(&models.MarketReview{Date: "2024-05-07", LimitUpCount: 42, Narrative: "ok"}); err != nil {
+		t.Fatalf("overwrite: %v", err)
+	}
+
+	list := s.ListReviews()
+	if len(list) != 3 || list[0].Date != "2024-05-08" || list[2].Date != "2024-05-06" {
+		t.Fatalf("unexpected review list: %+v", list)
+	}
+	if list[1].LimitUpCount != 42 || !list[1].HasNarrative {
+		t.Fatalf("same-day review should be overwritten: %+v", list[1])
+	}
+
+	if _, err := s.GetReview("../config"); err == nil {
+		t.Fatal("expected invalid date error")
+	}
+	if err := s.DeleteReview("2024-05-06"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if len(s.ListReviews()) != 2 {
+		t.Fatal("review not deleted")
+	}
+}
@@ -0,0 +1,513 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+var sentimentLog = logger.New("sentiment")
+
+const (
+	emLimitUpPoolURL   = "https://push2ex.eastmoney.com/getTopicZTPool"
+	emStockChangesURL  = "https://push2ex.eastmoney.com/getAllStockChanges"
+	emIndexKLineURL    = "https://push2his.eastmoney.com/api/qt/stock/kline/get"
+	emStockChangeNewHi = "8213" // 60日新高
+	emStockChangeNewLo = "8214" // 60日新低
+
+	sentimentCacheTTL     = 60 * time.Second
+	sentimentHistoryMax   = 250
+	sentimentTurnoverDays = 20
+	sentimentScheduleTick = 5 * time.Minute
+)
+
+// sentimentQuoteFields 情绪统计所需的行情快照字段
+var sentimentQuoteFields = []string{"f2", "f3", "f12", "f14", "f15", "f18", "f124"}
+
+// sentimentTurnoverIndices 两市成交额统计口径（上证指数 + 深证综指）
+var sentimentTurnoverIndices = []string{"1.000001", "0.399106"}
+
+// sentimentQuote 全市场快照中的单只股票行情
+type sentimentQuote struct {
+	Code          string
+	Name          string
+	Price         float64
+	High          float64
+	PreClose      float64
+	ChangePercent float64
+}
+
+// limitUpEntry 涨停池个股
+type limitUpEntry struct {
+	Code     string
+	Name     string
+	Industry string
+	Boards   int
+}
+
+// MarketSentimentService 市场宽度与情绪服务
+type MarketSentimentService struct {
+	marketService *MarketService
+	historyPath   string
+
+	mu      sync.Mutex
+	history []models.MarketSentiment
+	cache   *models.MarketSentiment
+	cacheAt time.Time
+
+	closeRecorded string
+	stopChan      chan struct{}
+	stopOnce      sync.Once
+}
+
+// NewMarketSentimentService 创建市场情绪服务
+func NewMarketSentimentService(dataDir string, marketService *MarketService) *MarketSentimentService {
+	s := &MarketSentimentService{
+		marketService: marketService,
+		historyPath:   filepath.Join(dataDir, "sentiment_history.json"),
+		stopChan:      make(chan struct{}),
+	}
+	s.load()
+	return s
+}
+
+// load 从文件加载历史情绪序列
+func (s *MarketSentimentService) load() {
+	data, err := os.ReadFile(s.historyPath)
+	if err != nil {
+		return
+	}
+	var history []models.MarketSentiment
+	if err := json.Unmarshal(data, &history); err != nil {
+		sentimentLog.Warn("解析情绪历史失败: %v", err)
+		return
+	}
+	s.history = history
+}
+
+// saveLocked 保存历史情绪序列，调用方需持有锁
+func (s *MarketSentimentService) saveLocked() error {
+	if err := os.MkdirAll(filepath.Dir(s.historyPath), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s.history, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.historyPath, data, 0644)
+}
+
+// GetSentiment 获取当前市场情绪（带短时缓存），成功后写入当日历史
+func (s *MarketSentimentService) GetSentiment() (*models.MarketSentiment, error) {
+	s.mu.Lock()
+	if s.cache != nil && time.Since(s.cacheAt) < sentimentCacheTTL {
+		cached := *s.cache
+		s.mu.Unlock()
+		return &cached, nil
+	}
+	s.mu.Unlock()
+	return s.refresh()
+}
+
+// GetHistory 获取最近 days 个交易日的情绪序列（按日期升序）
+func (s *MarketSentimentService) GetHistory(days int) []models.MarketSentiment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	history := s.history
+	if days > 0 && len(history) > days {
+		history = history[len(history)-days:]
+	}
+	result := make([]models.MarketSentiment, len(history))
+	copy(result, history)
+	return result
+}
+
+// Start 启动收盘后情绪记录任务
+func (s *MarketSentimentService) Start() {
+	go func() {
+		ticker := time.NewTicker(sentimentScheduleTick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stopChan:
+				return
+			case <-ticker.C:
+				s.recordClose()
+			}
+		}
+	}()
+}
+
+// Stop 停止情绪记录任务
+func (s *MarketSentimentService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopChan)
+	})
+}
+
+// recordClose 交易日收盘后记录一次完整情绪数据
+func (s *MarketSentimentService) recordClose() {
+	if s.marketService == nil {
+		return
+	}
+	status := s.marketService.GetMarketStatus()
+	now := time.Now().In(reviewLocation())
+	if !status.IsTradeDay || status.Status != "closed" || now.Hour()*60+now.Minute() < 15*60+5 {
+		return
+	}
+	today := now.Format("2006-01-02")
+	if s.closeRecorded == today {
+		return
+	}
+	if _, err := s.refresh(); err != nil {
+		sentimentLog.Warn("收盘情绪记录失败: %v", err)
+		return
+	}
+	s.closeRecorded = today
+}
+
+// refresh 重新采集情绪数据
+func (s *MarketSentimentService) refresh() (*models.MarketSentiment, error) {
+	if s.marketService == nil {
+		return nil, fmt.Errorf("Market 服务未初始化")
+	}
+	quotes, updateTime, err := s.fetchQuotes()
+	if err != nil {
+		return nil, fmt.Errorf("获取全市场行情失败: %w", err)
+	}
+
+	sentiment := calculateMarketBreadth(quotes)
+	sentiment.UpdateTime = updateTime
+	sentiment.Date = time.Now().In(reviewLocation()).Format("2006-01-02")
+	if len(updateTime) >= 10 {
+		sentiment.Date = updateTime[:10]
+	}
+	sentiment.Errors = make(map[string]string)
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	run := func(name string, fn func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(); err != nil {
+				mu.Lock()
+				sentiment.Errors[name] = err.Error()
+				mu.Unlock()
+			}
+		}()
+	}
+	run("ladder", func() error {
+		pool, err := s.fetchLimitUpPool(sentiment.Date)
+		sentiment.Ladder, sentiment.MaxHeight = buildBoardLadder(pool)
+		return err
+	})
+	run("newHigh", func() error {
+		count, err := s.fetchStockChangeCount(emStockChangeNewHi)
+		sentiment.NewHigh = count
+		return err
+	})
+	run("newLow", func() error {
+		count, err := s.fetchStockChangeCount(emStockChangeNewLo)
+		sentiment.NewLow = count
+		return err
+	})
+	run("turnover", func() error {
+		amounts, err := s.fetchMarketTurnover()
+		sentiment.Turnover, sentiment.TurnoverAvg20, sentiment.TurnoverRatio = summarizeTurnover(amounts)
+		return err
+	})
+	wg.Wait()
+	if len(sentiment.Errors) == 0 {
+		sentiment.Errors = nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keepLastGoodLocked(&sentiment)
+	s.cache = &sentiment
+	s.cacheAt = time.Now()
+	s.recordLocked(sentiment)
+	if err := s.saveLocked(); err != nil {
+		sentimentLog.Warn("保存情绪历史失败: %v", err)
+	}
+	result := sentiment
+	return &result, nil
+}
+
+// keepLastGoodLocked 新高/新低获取失败时沿用同日已记录的数值，避免以 0 覆盖
+func (s *MarketSentimentService) keepLastGoodLocked(sentiment *models.MarketSentiment) {
+	_, highFailed := sentiment.Errors["newHigh"]
+	_, lowFailed := sentiment.Errors["newLow"]
+	if !highFailed && !lowFailed {
+		return
+	}
+	idx := sort.Search(len(s.history), func(i int) bool { return s.history[i].Date >= sentiment.Date })
+	if idx >= len(s.history) || s.history[idx].Date != sentiment.Date {
+		return
+	}
+	if highFailed {
+		sentiment.NewHigh = s.history[idx].NewHigh
+	}
+	if lowFailed {
+		sentiment.NewLow = s.history[idx].NewLow
+	}
+}
+
+// recordLocked 写入当日历史（同日覆盖），历史中不保存梯队个股明细
+func (s *MarketSentimentService) recordLocked(sentiment models.MarketSentiment) {
+	entry := sentiment
+	entry.Errors = nil
+	entry.Ladder = make([]models.BoardLadderLevel, len(sentiment.Ladder))
+	for i, level := range sentiment.Ladder {
+		entry.Ladder[i] = models.BoardLadderLevel{Height: level.Height, Count: level.Count}
+	}
+
+	idx := sort.Search(len(s.history), func(i int) bool { return s.history[i].Date >= entry.Date })
+	switch {
+	case idx < len(s.history) && s.history[idx].Date == entry.Date:
+		s.history[idx] = entry
+	default:
+		s.history = append(s.history, models.MarketSentiment{})
+		copy(s.history[idx+1:], s.history[idx:])
+		s.history[idx] = entry
+	}
+	if len(s.history) > sentimentHistoryMax {
+		s.history = s.history[len(s.history)-sentimentHistoryMax:]
+	}
+}
+
+// fetchQuotes 分页获取沪深京A股行情快照，返回行情与最新更新时间
+func (s *MarketSentimentService) fetchQuotes() ([]sentimentQuote, string, error) {
+	rows, err := s.marketService.fetchAShareQuotes(sentimentQuoteFields)
+	if err != nil {
+		return nil, "", err
+	}
+
+	quotes := make([]sentimentQuote, 0, len(rows))
+	var latest int64
+	for _, row := range rows {
+		// 停牌股涨跌幅为 "-"，不计入统计
+		if text := strings.TrimSpace(toStringLocal(row["f3"])); text == "" || text == "-" {
+			continue
+		}
+		quotes = append(quotes, sentimentQuote{
+			Code:          strings.TrimSpace(toStringLocal(row["f12"])),
+			Name:          strings.TrimSpace(toStringLocal(row["f14"])),
+			Price:         toFloat64Any(row["f2"]),
+			High:          toFloat64Any(row["f15"]),
+			PreClose:      toFloat64Any(row["f18"]),
+			ChangePercent: toFloat64Any(row["f3"]),
+		})
+		if ts := toInt64Any(row["f124"]); ts > latest {
+			latest = ts
+		}
+	}
+	return quotes, formatEastmoneyTimestamp(latest), nil
+}
+
+// fetchLimitUpPool 获取指定交易日的涨停池（含连板数）
+func (s *MarketSentimentService) fetchLimitUpPool(date string) ([]limitUpEntry, error) {
+	params := url.Values{}
+	params.Set("ut", "7eea3edcaed734bea9cbfc24409ed989")
+	params.Set("dpt", "wz.ztzt")
+	params.Set("Pageindex", "0")
+	params.Set("pagesize", "10000")
+	params.Set("sort", "fbt:asc")
+	params.Set("date", strings.ReplaceAll(date, "-", ""))
+
+	raw, err := s.marketService.fetchMarketJSON(emLimitUpPoolURL+"?"+params.Encode(), map[string]string{
+		"Referer":    "https://quote.eastmoney.com/ztb/",
+		"User-Agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
+	})
+	if err != nil {
+		return nil, err
+	}
+	data, ok := raw["data"].(map[string]any)
+	if !ok || data == nil {
+		return nil, fmt.Errorf("涨停池响应缺少data")
+	}
+	rows := toMapSliceLocal(toSliceAnyLocal(data["pool"]))
+	pool := make([]limitUpEntry, 0, len(rows))
+	for _, row := range rows {
+		pool = append(pool, limitUpEntry{
+			Code:     strings.TrimSpace(toStringLocal(row["c"])),
+			Name:     strings.TrimSpace(toStringLocal(row["n"])),
+			Industry: strings.TrimSpace(toStringLocal(row["hybk"])),
+			Boards:   int(toInt64Any(row["lbc"])),
+		})
+	}
+	return pool, nil
+}
+
+// fetchStockChangeCount 获取当日盘口异动（如60日新高/新低）的去重个股数
+func (s *MarketSentimentService) fetchStockChangeCount(changeType string) (int, error) {
+	params := url.Values{}
+	params.Set("type", changeType)
+	params.Set("pageindex", "0")
+	params.Set("pagesize", "10000")
+	params.Set("ut", "7eea3edcaed734bea9cbfc24409ed989")
+	params.Set("dpt", "wzchanges")
+
+	raw, err := s.marketService.fetchMarketJSON(emStockChangesURL+"?"+params.Encode(), map[string]string{
+		"Referer":    "https://quote.eastmoney.com/changes/",
+		"User-Agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
+	})
+	if err != nil {
+		return 0, err
+	}
+	data, ok := raw["data"].(map[string]any)
+	if !ok || data == nil {
+		// 收盘后接口可能不再返回当日异动，返回错误以免记录为 0
+		return 0, fmt.Errorf("盘口异动响应缺少data")
+	}
+	seen := make(map[string]bool)
+	for _, row := range toMapSliceLocal(toSliceAnyLocal(data["allstock"])) {
+		if code := strings.TrimSpace(toStringLocal(row["c"])); code != "" {
+			seen[code] = true
+		}
+	}
+	return len(seen), nil
+}
+
+// fetchMarketTurnover 获取近21个交易日两市合计成交额（元，按日期升序）
+func (s *MarketSentimentService) fetchMarketTurnover() ([]float64, error) {
+	totals := make(map[string]float64)
+	for _, secid := range sentimentTurnoverIndices {
+		params := url.Values{}
+		params.Set("secid", secid)
+		params.Set("klt", "101")
+		params.Set("fqt", "1")
+		params.Set("end", "20500101")
+		params.Set("lmt", strconv.Itoa(sentimentTurnoverDays+1))
+		params.Set("fields1", "f1,f2,f3")
+		params.Set("fields2", "f51,f52,f53,f54,f55,f56,f57")
+		params.Set("ut", "fa5fd1943c7b386f172d6893dbfba10b")
+
+		raw, err := s.marketService.fetchMarketJSON(emIndexKLineURL+"?"+params.Encode(), map[string]string{
+			"Referer":    "https://quote.eastmoney.com/",
+			"User-Agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
+		})
+		if err != nil {
+			return nil, err
+		}
+		data, ok := raw["data"].(map[string]any)
+		if !ok || data == nil {
+			return nil, fmt.Errorf("指数K线响应缺少data")
+		}
+		for _, line := range toStringSlice(data["klines"]) {
+			parts := strings.Split(line, ",")
+			if len(parts) < 7 {
+				continue
+			}
+			totals[strings.TrimSpace(parts[0])] += parseFloat64Safe(parts[6])
+		}
+	}
+
+	dates := make([]string, 0, len(totals))
+	for date := range totals {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	amounts := make([]float64, 0, len(dates))
+	for _, date := range dates {
+		amounts = append(amounts, totals[date])
+	}
+	return amounts, nil
+}
+
+// calculateMarketBreadth 统计涨跌家数、涨跌停与炸板
+// 涨停价按昨收与涨跌幅限制计算，最高价触及涨停价但收盘未封住记为炸板
+func calculateMarketBreadth(quotes []sentimentQuote) models.MarketSentiment {
+	sentiment := models.MarketSentiment{Total: len(quotes)}
+	for _, q := range quotes {
+		switch {
+		case q.ChangePercent > 0:
+			sentiment.Up++
+		case q.ChangePercent < 0:
+			sentiment.Down++
+		default:
+			sentiment.Flat++
+		}
+		if q.PreClose <= 0 || q.Price <= 0 {
+			continue
+		}
+		upPrice, downPrice := stockLimitPrices(q.Code, q.Name, q.PreClose)
+		switch {
+		case q.Price >= upPrice-0.001:
+			sentiment.LimitUp++
+		case q.High >= upPrice-0.001:
+			sentiment.FailedLimitUp++
+		}
+		if q.Price <= downPrice+0.001 {
+			sentiment.LimitDown++
+		}
+	}
+	if sentiment.Total > 0 {
+		sentiment.UpRatio = math.Round(float64(sentiment.Up)/float64(sentiment.Total)*10000) / 100
+	}
+	if touched := sentiment.LimitUp + sentiment.FailedLimitUp; touched > 0 {
+		sentiment.FailedRatio = math.Round(float64(sentiment.FailedLimitUp)/float64(touched)*10000) / 100
+	}
+	return sentiment
+}
+
+// buildBoardLadder 按连板高度分组涨停池，返回梯队（高度降序）与最高连板数
+func buildBoardLadder(pool []limitUpEntry) ([]models.BoardLadderLevel, int) {
+	groups := make(map[int][]models.LadderStock)
+	for _, entry := range pool {
+		height := entry.Boards
+		if height < 1 {
+			height = 1
+		}
+		groups[height] = append(groups[height], models.LadderStock{Code: entry.Code, Name: entry.Name, Industry: entry.Industry})
+	}
+	ladder := make([]models.BoardLadderLevel, 0, len(groups))
+	for height, stocks := range groups {
+		ladder = append(ladder, models.BoardLadderLevel{Height: height, Count: len(stocks), Stocks: stocks})
+	}
+	sort.Slice(ladder, func(i, j int) bool { return ladder[i].Height > ladder[j].Height })
+	if len(ladder) == 0 {
+		return ladder, 0
+	}
+	return ladder, ladder[0].Height
+}
+
+// summarizeTurnover 计算最新成交额、前20日均值(亿)及二者比值，amounts 按日期升序
+func summarizeTurnover(amounts []float64) (float64, float64, float64) {
+	if len(amounts) == 0 {
+		return 0, 0, 0
+	}
+	latest := amounts[len(amounts)-1]
+	previous := amounts[:len(amounts)-1]
+	if len(previous) > sentimentTurnoverDays {
+		previous = previous[len(previous)-sentimentTurnoverDays:]
+	}
+	turnover := math.Round(latest/1e6) / 100
+	if len(previous) == 0 {
+		return turnover, 0, 0
+	}
+	sum := 0.0
+	for _, v := range previous {
+		sum += v
+	}
+	avg := sum / float64(len(previous))
+	ratio := 0.0
+	if avg > 0 {
+		ratio = math.Round(latest/avg*100) / 100
+	}
+	return turnover, math.Round(avg/1e6) / 100, ratio
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestCalculateMarketBreadth(t *testing.T) {
+	quotes := []sentimentQuote{
+		{Code: "600000", Name: "浦发银行", Price: 11, High: 11, PreClose: 10, ChangePercent: 10},
+		{Code: "600001", Name: "测试A", Price: 10.5, High: 11, PreClose: 10, ChangePercent: 5},
+		{Code: "300001", Name: "测试B", Price: 12, High: 12, PreClose: 10, ChangePercent: 20},
+		{Code: "000002", Name: "*ST测试", Price: 9.5, High: 10, PreClose: 10, ChangePercent: -5},
+		{Code: "000003", Name: "测试C", Price: 10, High: 10.2, PreClose: 10, ChangePercent: 0},
+	}
+
+	got := calculateMarketBreadth(quotes)
+	if got.Total != 5 || got.Up != 3 || got.Down != 1 || got.Flat != 1 {
+		t.Fatalf("unexpected counts: %+v", got)
+	}
+	if got.LimitUp != 2 || got.FailedLimitUp != 1 || got.LimitDown != 1 {
+		t.Fatalf("unexpected limit counts: %+v", got)
+	}
+	if got.UpRatio != 60 {
+		t.Errorf("UpRatio = %v, want 60", got.UpRatio)
+	}
+	if got.FailedRatio != 33.33 {
+		t.Errorf("FailedRatio = %v, want 33.33", got.FailedRatio)
+	}
+}
+
+func TestBuildBoardLadder(t *testing.T) {
+	ladder, maxHeight := buildBoardLadder([]limitUpEntry{
+		{Code: "000001", Boards: 1},
+		{Code: "000002", Boards: 3},
+		{Code: "000003", Boards: 1},
+		{Code: "000004", Boards: 0},
+	})
+	if maxHeight != 3 {
+		t.Fatalf("maxHeight = %d, want 3", maxHeight)
+	}
+	if len(ladder) != 2 || ladder[0].Height != 3 || ladder[1].Height != 1 || ladder[1].Count != 3 {
+		t.Fatalf("unexpected ladder: %+v", ladder)
+	}
+}
+
+func TestSummarizeTurnover(t *testing.T) {
+	amounts := make([]float64, 0, 22)
+	for i := 0; i < 21; i++ {
+		amounts = append(amounts, 1e12)
+	}
+	amounts = append(amounts, 1.5e12)
+
+	turnover, avg, ratio := summarizeTurnover(amounts)
+	if turnover != 15000 || avg != 10000 || ratio != 1.5 {
+		t.Fatalf("got turnover=%v avg=%v ratio=%v", turnover, avg, ratio)
+	}
+}
+
+func TestKeepLastGoodLocked(t *testing.T) {
+	s := &MarketSentimentService{history: []models.MarketSentiment{
+		{Date: "2025-06-19", NewHigh: 50, NewLow: 20},
+		{Date: "2025-06-20", NewHigh: 120, NewLow: 30},
+	}}
+
+	sentiment := models.MarketSentiment{Date: "2025-06-20", NewLow: 35, Errors: map[string]string{"newHigh": "盘口异动响应缺少data"}}
+	s.keepLastGoodLocked(&sentiment)
+	if sentiment.NewHigh != 120 || sentiment.NewLow != 35 {
+		t.Fatalf("got newHigh=%d newLow=%d, want 120/35", sentiment.NewHigh, sentiment.NewLow)
+	}
+
+	sentiment = models.MarketSentiment{Date: "2025-06-23", Errors: map[string]string{"newLow": "timeout"}}
+	s.keepLastGoodLocked(&sentiment)
+	if sentiment.NewLow != 0 {
+		t.Fatalf("should not carry values across days, got newLow=%d", sentiment.NewLow)
+	}
+}
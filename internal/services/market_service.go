@@ -36,6 +36,7 @@ const (
 	sinaStockURL       = "http://hq.sinajs.cn/rn=%d&list=%s"
 	sinaKLineURL       = "http://quotes.sina.cn/cn/api/json_v2.php/CN_MarketDataService.getKLineData?symbol=%s&scale=%s&ma=5,10,20&datalen=%d"
 	emBoardFundFlowURL = "https://push2.eastmoney.com/api/qt/clist/get"
+	emBoardQuoteURL    = "https://push2.eastmoney.com/api/qt/ulist.np/get"
 	emFundFlowKLineURL = "https://push2.eastmoney.com/api/qt/stock/fflow/kline/get"
 	emAnnouncementURL  = "https://np-anotice-stock.eastmoney.com/api/security/ann"
 )
@@ -45,6 +46,12 @@ const (
 	klineCacheTTLDefault  = 30 * time.Second
 )
 
+// 全市场行情快照分页参数
+const (
+	aShareQuotePageSize    = 100
+	aShareQuoteConcurrency = 6
+)
+
 // 默认大盘指数代码
 var defaultIndexCodes = []string{
 	"s_sh000001", // 上证指数
@@ -1269,6 +1276,130 @@ func (ms *MarketService) GetBoardLeaders(boardCode string, limit int) (models.Bo
 	}, nil
 }
 
+// GetBoardSnapshot 获取单个板块的行情与资金流快照
+func (ms *MarketService) GetBoardSnapshot(boardCode string) (models.BoardFundFlowItem, error) {
+	normalizedBoard := normalizeBoardCode(boardCode)
+	if normalizedBoard == "" {
+		return models.BoardFundFlowItem{}, fmt.Errorf("无效板块代码: %s", boardCode)
+	}
+
+	params := url.Values{}
+	params.Set("fltt", "2")
+	params.Set("invt", "2")
+	params.Set("fields", "f12,f14,f2,f3,f62,f184,f66,f69,f72,f75,f78,f81,f84,f87,f124")
+	params.Set("ut", "8dec03ba335b81bf4ebdf7b29ec27d15")
+	params.Set("secids", "90."+normalizedBoard)
+
+	raw, err := ms.fetchMarketJSON(emBoardQuoteURL+"?"+params.Encode(), map[string]string{
+		"Referer":    "https://data.eastmoney.com/",
+		"User-Agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
+	})
+	if err != nil {
+		return models.BoardFundFlowItem{}, err
+	}
+
+	data, ok := raw["data"].(map[string]any)
+	if !ok || data == nil {
+		return models.BoardFundFlowItem{}, fmt.Errorf("板块快照响应缺少data")
+	}
+	rows := toMapSliceLocal(toSliceAnyLocal(data["diff"]))
+	if len(rows) == 0 {
+		return models.BoardFundFlowItem{}, fmt.Errorf("未找到板块: %s", normalizedBoard)
+	}
+	row := rows[0]
+	item := models.BoardFundFlowItem{
+		Code:                 strings.TrimSpace(toStringLocal(row["f12"])),
+		Name:                 strings.TrimSpace(toStringLocal(row["f14"])),
+		Price:                toFloat64Any(row["f2"]),
+		ChangePercent:        toFloat64Any(row["f3"]),
+		MainNetInflow:        toFloat64Any(row["f62"]),
+		MainNetInflowRatio:   toFloat64Any(row["f184"]),
+		SuperNetInflow:       toFloat64Any(row["f66"]),
+		SuperNetInflowRatio:  toFloat64Any(row["f69"]),
+		LargeNetInflow:       toFloat64Any(row["f72"]),
+		LargeNetInflowRatio:  toFloat64Any(row["f75"]),
+		MediumNetInflow:      toFloat64Any(row["f78"]),
+		MediumNetInflowRatio: toFloat64Any(row["f81"]),
+		SmallNetInflow:       toFloat64Any(row["f84"]),
+		SmallNetInflowRatio:  toFloat64Any(row["f87"]),
+	}
+	if ts := toInt64Any(row["f124"]); ts > 0 {
+		item.UpdateTime = formatEastmoneyTimestamp(ts)
+	}
+	if item.Code == "" {
+		item.Code = normalizedBoard
+	}
+	return item, nil
+}
+
+// GetBoardBreadth 获取板块成分股涨跌分布
+func (ms *MarketService) GetBoardBreadth(boardCode string) (models.BoardBreadth, error) {
+	normalizedBoard := normalizeBoardCode(boardCode)
+	if normalizedBoard == "" {
+		return models.BoardBreadth{}, fmt.Errorf("无效板块代码: %s", boardCode)
+	}
+
+	const pageSize = 100
+	var (
+		constituents []boardConstituent
+		updateTime   string
+	)
+	for page := 1; page <= 20; page++ {
+		params := url.Values{}
+		params.Set("np", "1")
+		params.Set("fltt", "2")
+		params.Set("invt", "2")
+		params.Set("po", "1")
+		params.Set("fid", "f3")
+		params.Set("fields", "f12,f3,f124")
+		params.Set("ut", "8dec03ba335b81bf4ebdf7b29ec27d15")
+		params.Set("pn", strconv.Itoa(page))
+		params.Set("pz", strconv.Itoa(pageSize))
+		params.Set("fs", "b:"+normalizedBoard)
+
+		raw, err := ms.fetchMarketJSON(emBoardFundFlowURL+"?"+params.Encode(), map[string]string{
+			"Referer":    "https://data.eastmoney.com/",
+			"User-Agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
+		})
+		if err != nil {
+			if len(constituents) > 0 {
+				break
+			}
+			return models.BoardBreadth{}, err
+		}
+		data, ok := raw["data"].(map[string]any)
+		if !ok || data == nil {
+			if len(constituents) > 0 {
+				break
+			}
+			return models.BoardBreadth{}, fmt.Errorf("板块成分股响应缺少data")
+		}
+
+		rows := toMapSliceLocal(toSliceAnyLocal(data["diff"]))
+		for _, row := range rows {
+			// 停牌股涨跌幅为 "-"，不计入分布
+			if text := strings.TrimSpace(toStringLocal(row["f3"])); text == "" || text == "-" {
+				continue
+			}
+			constituents = append(constituents, boardConstituent{
+				Code:          strings.TrimSpace(toStringLocal(row["f12"])),
+				ChangePercent: toFloat64Any(row["f3"]),
+			})
+			if ts := toInt64Any(row["f124"]); ts > 0 && updateTime == "" {
+				updateTime = formatEastmoneyTimestamp(ts)
+			}
+		}
+		if len(rows) < pageSize || int64(page*pageSize) >= toInt64Any(data["total"]) {
+			break
+		}
+	}
+
+	breadth := calculateBoardBreadth(constituents)
+	breadth.BoardCode = normalizedBoard
+	breadth.UpdateTime = updateTime
+	return breadth, nil
+}
+
 // GetIndexFundFlowSeries 获取指数资金流曲线
 func (ms *MarketService) GetIndexFundFlowSeries(code string, interval string, limit int) (models.FundFlowKLineSeries, error) {
 	if strings.TrimSpace(code) == "" {
@@ -1468,6 +1599,73 @@ func calculateBoardLeaderScore(changePercent, mainNetInflow, mainNetInflowRatio
 	return math.Round(score*100) / 100
 }
 
+// boardConstituent 板块成分股涨跌幅
+type boardConstituent struct {
+	Code          string
+	ChangePercent float64
+}
+
+// calculateBoardBreadth 统计成分股涨跌家数、涨跌停与涨幅中位数
+func calculateBoardBreadth(items []boardConstituent) models.BoardBreadth {
+	breadth := models.BoardBreadth{Total: len(items)}
+	if len(items) == 0 {
+		return breadth
+	}
+	changes := make([]float64, 0, len(items))
+	sum := 0.0
+	for _, item := range items {
+		change := item.ChangePercent
+		changes = append(changes, change)
+		sum += change
+		switch {
+		case change > 0:
+			breadth.Up++
+		case change < 0:
+			breadth.Down++
+		default:
+			breadth.Flat++
+		}
+		limit := stockPriceLimit(item.Code)
+		if change >= limit-0.2 {
+			breadth.LimitUp++
+		} else if change <= -(limit - 0.2) {
+			breadth.LimitDown++
+		}
+	}
+	sort.Float64s(changes)
+	mid := len(changes) / 2
+	median := changes[mid]
+	if len(changes)%2 == 0 {
+		median = (changes[mid-1] + changes[mid]) / 2
+	}
+	breadth.AvgChange = math.Round(sum/float64(len(items))*100) / 100
+	breadth.MedianChange = math.Round(median*100) / 100
+	breadth.UpRatio = math.Round(float64(breadth.Up)/float64(len(items))*10000) / 100
+	return breadth
+}
+
+// stockPriceLimit 按代码估算涨跌幅限制（主板10%，创业板/科创板20%，北交所30%）
+func stockPriceLimit(code string) float64 {
+	code = normalizeStockListCode(code)
+	switch {
+	case strings.HasPrefix(code, "300"), strings.HasPrefix(code, "301"), strings.HasPrefix(code, "688"), strings.HasPrefix(code, "689"):
+		return 20
+	case strings.HasPrefix(code, "8"), strings.HasPrefix(code, "4"), strings.HasPrefix(code, "92"):
+		return 30
+	default:
+		return 10
+	}
+}
+
+// stockLimitPrices 按板块与 ST 状态计算涨停价、跌停价（四舍五入到分）
+func stockLimitPrices(code, name string, preClose float64) (float64, float64) {
+	limit := stockPriceLimit(code)
+	if strings.Contains(strings.ToUpper(name), "ST") && limit == 10 {
+		limit = 5
+	}
+	return math.Round(preClose*(100+limit)) / 100, math.Round(preClose*(100-limit)) / 100
+}
+
 func boardFundFlowFS(category string) string {
 	switch category {
 	case "concept":
@@ -1528,6 +1726,10 @@ func normalizeMarketCode(code string) string {
 }
 
 func indexSecID(code string) string {
+	// 板块代码（BKxxxx）使用 90 市场
+	if board := normalizeBoardCode(code); board != "" && strings.HasPrefix(strings.ToUpper(strings.TrimSpace(code)), "B") {
+		return "90." + board
+	}
 	normalized := normalizeMarketCode(code)
 	switch normalized {
 	case "sh000001":
@@ -1715,6 +1917,71 @@ func toInt64Any(value any) int64 {
 	}
 }
 
+// fetchAShareQuotes 并发分页获取沪深京A股行情快照（fields 为东财 clist 字段），部分分页失败仅记录日志
+func (ms *MarketService) fetchAShareQuotes(fields []string) ([]map[string]any, error) {
+	rows, total, err := ms.fetchAShareQuotePage(fields, 1)
+	if err != nil {
+		return nil, err
+	}
+	pages := int((total + aShareQuotePageSize - 1) / aShareQuotePageSize)
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		pageErr error
+		sem     = make(chan struct{}, aShareQuoteConcurrency)
+	)
+	for page := 2; page <= pages; page++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(page int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			pageRows, _, err := ms.fetchAShareQuotePage(fields, page)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				pageErr = err
+				return
+			}
+			rows = append(rows, pageRows...)
+		}(page)
+	}
+	wg.Wait()
+	if pageErr != nil {
+		log.Warn("部分行情分页获取失败: %v", pageErr)
+	}
+	return rows, nil
+}
+
+// fetchAShareQuotePage 获取单页行情快照，返回行情与总数
+func (ms *MarketService) fetchAShareQuotePage(fields []string, page int) ([]map[string]any, int64, error) {
+	params := url.Values{}
+	params.Set("np", "1")
+	params.Set("fltt", "2")
+	params.Set("invt", "2")
+	params.Set("fid", "f12")
+	params.Set("po", "0")
+	params.Set("pn", strconv.Itoa(page))
+	params.Set("pz", strconv.Itoa(aShareQuotePageSize))
+	params.Set("fs", "m:0 t:6,m:0 t:80,m:1 t:2,m:1 t:23,m:0 t:81 s:2048")
+	params.Set("fields", strings.Join(fields, ","))
+	params.Set("ut", "8dec03ba335b81bf4ebdf7b29ec27d15")
+
+	raw, err := ms.fetchMarketJSON(emBoardFundFlowURL+"?"+params.Encode(), map[string]string{
+		"Referer":    "https://quote.eastmoney.com/",
+		"User-Agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	data, ok := raw["data"].(map[string]any)
+	if !ok || data == nil {
+		return nil, 0, fmt.Errorf("行情快照响应缺少data")
+	}
+	return toMapSliceLocal(toSliceAnyLocal(data["diff"])), toInt64Any(data["total"]), nil
+}
+
 func (ms *MarketService) fetchMarketJSON(urlStr string, headers map[string]string) (map[string]any, error) {
 	var lastErr error
 	for attempt := 0; attempt < 3; attempt++ {
@@ -140,3 +140,25 @@ func TestGetKLineData(t *testing.T) {
 		}
 	})
 }
+
+// TestCalculateBoardBreadth 测试板块涨跌分布统计
+func TestCalculateBoardBreadth(t *testing.T) {
+	breadth := calculateBoardBreadth([]boardConstituent{
+		{Code: "600001", ChangePercent: 10},
+		{Code: "300001", ChangePercent: 10.5},
+		{Code: "688001", ChangePercent: 19.98},
+		{Code: "000001", ChangePercent: 0},
+		{Code: "000002", ChangePercent: -9.95},
+		{Code: "000003", ChangePercent: -1.5},
+	})
+
+	if breadth.Total != 6 || breadth.Up != 3 || breadth.Down != 2 || breadth.Flat != 1 {
+		t.Fatalf("涨跌家数错误: %+v", breadth)
+	}
+	if breadth.LimitUp != 2 || breadth.LimitDown != 1 {
+		t.Fatalf("涨跌停统计错误: %+v", breadth)
+	}
+	if breadth.MedianChange != 5 || breadth.UpRatio != 50 {
+		t.Fatalf("中位数/上涨占比错误: %+v", breadth)
+	}
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+const (
+	northboundHoldReport = "RPT_MUTUAL_HOLDSTOCKNORTH_STA"
+
+	capitalFlowFetchSize   = 120 // 单次拉取的最大交易日数
+	capitalFlowDefaultDays = 20
+	capitalFlowCacheTTL    = 10 * time.Minute
+
+	northboundWeekDays  = 7  // 约5个交易日
+	northboundMonthDays = 28 // 约20个交易日
+)
+
+// NorthboundService 北向资金（沪深港通）持股服务
+type NorthboundService struct {
+	f10Service *F10Service
+
+	cacheTTL time.Duration
+	cacheMu  sync.RWMutex
+	cache    map[string]cacheEntry
+}
+
+// NewNorthboundService 创建北向资金持股服务
+func NewNorthboundService(f10Service *F10Service) *NorthboundService {
+	return &NorthboundService{
+		f10Service: f10Service,
+		cacheTTL:   capitalFlowCacheTTL,
+		cache:      make(map[string]cacheEntry),
+	}
+}
+
+// GetHoldings 获取个股北向持股及每日增减持，days 为返回的交易日数
+func (s *NorthboundService) GetHoldings(code string, days int) (models.NorthboundHoldings, error) {
+	normalized := normalizeStockCode(code)
+	if normalized.Raw == "" {
+		return models.NorthboundHoldings{}, fmt.Errorf("股票代码不能为空")
+	}
+	days = capitalFlowDays(days)
+
+	cacheKey := "northbound:" + normalized.Raw
+	entry, ok, fresh := s.getCacheEntry(cacheKey)
+	if ok && fresh {
+		if data, ok := entry.value.(models.NorthboundHoldings); ok {
+			return trimNorthboundHoldings(data, days), nil
+		}
+	}
+
+	filter := fmt.Sprintf(`(SECURITY_CODE="%s")`, normalized.Raw)
+	records, err := s.f10Service.fetchDataCenterWebWithFilter(northboundHoldReport, filter, capitalFlowFetchSize, "TRADE_DATE", "-1")
+	if err != nil {
+		if ok {
+			if cached, ok := entry.value.(models.NorthboundHoldings); ok {
+				return trimNorthboundHoldings(cached, days), fmt.Errorf("使用缓存数据（%s），上游错误: %v", entry.timestamp.Format("2006-01-02 15:04:05"), err)
+			}
+		}
+		return models.NorthboundHoldings{}, err
+	}
+	if len(records) == 0 {
+		return models.NorthboundHoldings{}, fmt.Errorf("暂无 %s 的北向持股数据（可能不是沪深港通标的）", normalized.Raw)
+	}
+
+	result := parseNorthboundRecords(normalized.Raw, records)
+	result.Stale = northboundStale(result.Items[0].TradeDate, time.Now())
+	result.UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
+	s.setCache(cacheKey, result)
+	return trimNorthboundHoldings(result, days), nil
+}
+
+func (s *NorthboundService) getCacheEntry(key string) (cacheEntry, bool, bool) {
+	s.cacheMu.RLock()
+	defer s.cacheMu.RUnlock()
+	entry, ok := s.cache[key]
+	if !ok {
+		return cacheEntry{}, false, false
+	}
+	return entry, true, time.Since(entry.timestamp) <= s.cacheTTL
+}
+
+func (s *NorthboundService) setCache(key string, value any) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	s.cache[key] = cacheEntry{value: value, timestamp: time.Now()}
+}
+
+// parseNorthboundRecords 解析北向持股记录，按日期倒序并计算逐日持股变化
+func parseNorthboundRecords(code string, records []map[string]any) models.NorthboundHoldings {
+	result := models.NorthboundHoldings{Code: code}
+	for _, record := range records {
+		date := recordDate(record["TRADE_DATE"])
+		if date == "" {
+			continue
+		}
+		if result.Name == "" {
+			result.Name = strings.TrimSpace(toStringLocal(firstNonEmpty(record, "SECURITY_NAME", "SECURITY_NAME_ABBR")))
+		}
+		result.Items = append(result.Items, models.NorthboundDay{
+			TradeDate:        date,
+			ClosePrice:       toFloat(record["CLOSE_PRICE"]),
+			ChangePercent:    toFloat(record["CHANGE_RATE"]),
+			HoldShares:       toFloat(record["HOLD_SHARES"]),
+			HoldMarketCap:    toFloat(record["HOLD_MARKET_CAP"]),
+			FreeSharesRatio:  toFloat(firstNonEmpty(record, "FREE_SHARES_RATIO", "A_SHARES_RATIO")),
+			TotalSharesRatio: toFloat(record["TOTAL_SHARES_RATIO"]),
+		})
+	}
+	sort.SliceStable(result.Items, func(i, j int) bool {
+		return result.Items[i].TradeDate > result.Items[j].TradeDate
+	})
+
+	for i := range result.Items {
+		if i+1 < len(result.Items) {
+			result.Items[i].ShareChange = result.Items[i].HoldShares - result.Items[i+1].HoldShares
+			result.Items[i].MarketCapChange = result.Items[i].ShareChange * result.Items[i].ClosePrice
+		}
+	}
+	result.Change5D = holdingChangeSince(result.Items, northboundWeekDays)
+	result.Change20D = holdingChangeSince(result.Items, northboundMonthDays)
+	return result
+}
+
+// holdingChangeSince 按披露日期计算最新持股相对 days 个自然日前的变化；
+// 基准披露日早于 2*days 时（如季度披露）视为不可比，返回 nil
+func holdingChangeSince(items []models.NorthboundDay, days int) *float64 {
+	if len(items) < 2 {
+		return nil
+	}
+	latest, err := time.Parse("2006-01-02", items[0].TradeDate)
+	if err != nil {
+		return nil
+	}
+	target := latest.AddDate(0, 0, -days).Format("2006-01-02")
+	floor := latest.AddDate(0, 0, -2*days).Format("2006-01-02")
+	for _, item := range items[1:] {
+		if item.TradeDate > target {
+			continue
+		}
+		if item.TradeDate < floor {
+			return nil
+		}
+		change := items[0].HoldShares - item.HoldShares
+		return &change
+	}
+	return nil
+}
+
+// northboundStale 最新披露日距今是否超过一周
+func northboundStale(latestDate string, now time.Time) bool {
+	latest, err := time.ParseInLocation("2006-01-02", latestDate, now.Location())
+	if err != nil {
+		return true
+	}
+	return now.Sub(latest) > northboundWeekDays*24*time.Hour
+}
+
+// trimNorthboundHoldings 截取最近 days 个交易日
+func trimNorthboundHoldings(data models.NorthboundHoldings, days int) models.NorthboundHoldings {
+	if len(data.Items) > days {
+		data.Items = append([]models.NorthboundDay(nil), data.Items[:days]...)
+	}
+	return data
+}
+
+// capitalFlowDays 规范化返回的交易日数
+func capitalFlowDays(days int) int {
+	if days <= 0 {
+		return capitalFlowDefaultDays
+	}
+	if days > capitalFlowFetchSize {
+		return capitalFlowFetchSize
+	}
+	return days
+}
+
+// seriesChange 计算倒序序列最新值与 n 期前的差值，数据不足时取最早一期
+func seriesChange(values []float64, n int) float64 {
+	if len(values) < 2 || n <= 0 {
+		return 0
+	}
+	if n > len(values)-1 {
+		n = len(values) - 1
+	}
+	return values[0] - values[n]
+}
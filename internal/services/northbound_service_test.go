@@ -0,0 +1,93 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestParseNorthboundRecords(t *testing.T) {
+	records := []map[string]any{
+		{"TRADE_DATE": "2025-06-03 00:00:00", "SECURITY_NAME": "测试股份", "CLOSE_PRICE": 10.0, "HOLD_SHARES": 1000.0, "FREE_SHARES_RATIO": 1.5},
+		{"TRADE_DATE": "2025-06-05 00:00:00", "SECURITY_NAME": "测试股份", "CLOSE_PRICE": 12.0, "HOLD_SHARES": 1500.0},
+		{"TRADE_DATE": "2025-06-04 00:00:00", "SECURITY_NAME": "测试股份", "CLOSE_PRICE": 11.0, "HOLD_SHARES": 1200.0},
+		{"TRADE_DATE": ""},
+	}
+
+	got := parseNorthboundRecords("600000", records)
+	if got.Name != "测试股份" || len(got.Items) != 3 {
+		t.Fatalf("unexpected holdings: %+v", got)
+	}
+	if got.Items[0].TradeDate != "2025-06-05" || got.Items[2].TradeDate != "2025-06-03" {
+		t.Fatalf("items not sorted desc: %+v", got.Items)
+	}
+	if got.Items[0].ShareChange != 300 || got.Items[0].MarketCapChange != 3600 || got.Items[2].ShareChange != 0 {
+		t.Fatalf("unexpected daily change: %+v", got.Items)
+	}
+	if got.Items[2].FreeSharesRatio != 1.5 {
+		t.Fatalf("unexpected ratio: %+v", got.Items[2])
+	}
+	// 披露跨度不足一周时不计算周/月变化
+	if got.Change5D != nil || got.Change20D != nil {
+		t.Fatalf("change5d=%v change20d=%v, want nil", got.Change5D, got.Change20D)
+	}
+
+	trimmed := trimNorthboundHoldings(got, 2)
+	if len(trimmed.Items) != 2 || len(got.Items) != 3 {
+		t.Fatalf("unexpected trim: %+v", trimmed)
+	}
+}
+
+func TestHoldingChangeSince(t *testing.T) {
+	daily := []models.NorthboundDay{
+		{TradeDate: "2025-06-20", HoldShares: 1500},
+		{TradeDate: "2025-06-16", HoldShares: 1400},
+		{TradeDate: "2025-06-13", HoldShares: 1200},
+		{TradeDate: "2025-05-23", HoldShares: 800},
+	}
+	if got := holdingChangeSince(daily, northboundWeekDays); got == nil || *got != 300 {
+		t.Fatalf("week change = %v, want 300", got)
+	}
+	if got := holdingChangeSince(daily, northboundMonthDays); got == nil || *got != 700 {
+		t.Fatalf("month change = %v, want 700", got)
+	}
+
+	// 季度披露：相邻两期相隔约三个月，不能当作近一周/四周变化
+	quarterly := []models.NorthboundDay{
+		{TradeDate: "2025-06-30", HoldShares: 2000},
+		{TradeDate: "2025-03-31", HoldShares: 1000},
+	}
+	if got := holdingChangeSince(quarterly, northboundWeekDays); got != nil {
+		t.Fatalf("quarterly week change = %v, want nil", *got)
+	}
+	if got := holdingChangeSince(quarterly, northboundMonthDays); got != nil {
+		t.Fatalf("quarterly month change = %v, want nil", *got)
+	}
+}
+
+func TestNorthboundStale(t *testing.T) {
+	now := time.Date(2025, 7, 15, 10, 0, 0, 0, time.Local)
+	if northboundStale("2025-07-14", now) {
+		t.Error("recent disclosure should not be stale")
+	}
+	if !northboundStale("2025-06-30", now) || !northboundStale("", now) {
+		t.Error("old or missing disclosure should be stale")
+	}
+}
+
+func TestSeriesChange(t *testing.T) {
+	values := []float64{10, 8, 7, 6, 5, 4, 1}
+	if got := seriesChange(values, 5); got != 6 {
+		t.Fatalf("seriesChange(5)=%v", got)
+	}
+	if got := seriesChange(values, 20); got != 9 {
+		t.Fatalf("seriesChange(20)=%v", got)
+	}
+	if got := seriesChange([]float64{3}, 5); got != 0 {
+		t.Fatalf("single value change=%v", got)
+	}
+	if capitalFlowDays(0) != capitalFlowDefaultDays || capitalFlowDays(500) != capitalFlowFetchSize || capitalFlowDays(7) != 7 {
+		t.Fatalf("unexpected capitalFlowDays")
+	}
+}
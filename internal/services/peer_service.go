@@ -0,0 +1,492 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+const (
+	peerDefaultLimit      = 10
+	peerMaxLimit          = 20
+	peerDetailConcurrency = 4
+)
+
+// 同业对比指标分组
+const (
+	peerGroupValuation     = "valuation"
+	peerGroupGrowth        = "growth"
+	peerGroupProfitability = "profitability"
+	peerGroupFundFlow      = "fundflow"
+)
+
+var peerGroupLabels = map[string]string{
+	peerGroupValuation:     "估值",
+	peerGroupGrowth:        "成长",
+	peerGroupProfitability: "盈利",
+	peerGroupFundFlow:      "资金",
+}
+
+var peerGroupOrder = []string{peerGroupValuation, peerGroupGrowth, peerGroupProfitability, peerGroupFundFlow}
+
+// peerMetrics 参与排名的指标，估值越低越优
+var peerMetrics = []models.PeerMetric{
+	{Name: "pe", Label: "市盈率TTM", Group: peerGroupValuation},
+	{Name: "pb", Label: "市净率", Group: peerGroupValuation},
+	{Name: "revenue_yoy", Label: "营收同比(%)", Group: peerGroupGrowth, HigherBetter: true},
+	{Name: "profit_yoy", Label: "净利同比(%)", Group: peerGroupGrowth, HigherBetter: true},
+	{Name: "roe", Label: "ROE(%)", Group: peerGroupProfitability, HigherBetter: true},
+	{Name: "gross_margin", Label: "毛利率(%)", Group: peerGroupProfitability, HigherBetter: true},
+	{Name: "net_margin", Label: "净利率(%)", Group: peerGroupProfitability, HigherBetter: true},
+	{Name: "main_net_ratio", Label: "主力净占比(%)", Group: peerGroupFundFlow, HigherBetter: true},
+	{Name: "main_net_5d", Label: "5日主力净流入(万)", Group: peerGroupFundFlow, HigherBetter: true},
+}
+
+// peerQuoteFields 批量行情中需要的字段
+var peerQuoteFields = []string{"price", "change_pct", "pe", "pb", "total_mv", "main_net", "main_net_ratio"}
+
+// PeerService 同业对比服务
+type PeerService struct {
+	f10Service    *F10Service
+	marketService *MarketService
+}
+
+// NewPeerService 创建同业对比服务
+func NewPeerService(f10Service *F10Service, marketService *MarketService) *PeerService {
+	return &PeerService{
+		f10Service:    f10Service,
+		marketService: marketService,
+	}
+}
+
+// peerCandidate 对比候选股票
+type peerCandidate struct {
+	code string
+	name string
+}
+
+// Analyze 构建同业对比矩阵，并计算目标股票在各指标上的百分位
+func (s *PeerService) Analyze(req models.PeerRequest) (models.PeerAnalysis, error) {
+	target := normalizeStockCode(req.Code)
+	if target.Raw == "" {
+		return models.PeerAnalysis{}, fmt.Errorf("股票代码不能为空")
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = peerDefaultLimit
+	}
+	if limit > peerMaxLimit {
+		limit = peerMaxLimit
+	}
+	source := strings.ToLower(strings.TrimSpace(req.Source))
+	if source == "" {
+		source = models.PeerSourceIndustry
+	}
+
+	result := models.PeerAnalysis{
+		Code:      target.Raw,
+		Source:    source,
+		Metrics:   peerMetrics,
+		Errors:    make(map[string]string),
+		UpdatedAt: time.Now().Format("2006-01-02 15:04:05"),
+	}
+
+	var peers []peerCandidate
+	var err error
+	switch source {
+	case models.PeerSourceIndustry:
+		result.SourceName, peers, err = s.industryPeers(target.Raw, limit)
+	case models.PeerSourceConcept:
+		result.SourceName, peers, err = s.conceptPeers(target.Raw, req.Theme, limit)
+	case models.PeerSourceCustom:
+		peers = customPeers(target.Raw, req.Peers, limit)
+		if len(peers) == 0 {
+			err = fmt.Errorf("自定义对比列表为空")
+		}
+	default:
+		return models.PeerAnalysis{}, fmt.Errorf("不支持的对比来源: %s", req.Source)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	candidates := append([]peerCandidate{{code: target.Raw}}, peers...)
+	rows := s.collectPeerRows(candidates, result.Errors)
+	rows[0].IsTarget = true
+	computePeerPercentiles(rows, peerMetrics)
+
+	result.Name = rows[0].Name
+	result.Rows = rows
+	result.Ranks = buildPeerRanks(rows, peerMetrics)
+	result.GroupScores = rows[0].GroupScores
+	if len(result.Errors) == 0 {
+		result.Errors = nil
+	}
+	return result, nil
+}
+
+// industryPeers 按行业分类获取同业
+func (s *PeerService) industryPeers(code string, limit int) (string, []peerCandidate, error) {
+	if s.f10Service == nil {
+		return "", nil, fmt.Errorf("F10 服务未初始化")
+	}
+	industry, list := s.f10Service.getIndustryPeers(code, limit)
+	if industry == "" {
+		return "", nil, fmt.Errorf("未找到 %s 的行业分类", code)
+	}
+	peers := make([]peerCandidate, 0, len(list))
+	for _, item := range list {
+		peers = append(peers, peerCandidate{code: normalizeStockCode(item.Symbol).Raw, name: item.Name})
+	}
+	return industry, peers, nil
+}
+
+// conceptPeers 按核心题材板块获取同业，theme 为空时取首个题材
+func (s *PeerService) conceptPeers(code, theme string, limit int) (string, []peerCandidate, error) {
+	if s.f10Service == nil || s.marketService == nil {
+		return "", nil, fmt.Errorf("F10 或 Market 服务未初始化")
+	}
+	themes, err := s.f10Service.GetCoreThemes(code)
+	if err != nil && len(themes.BoardTypes) == 0 {
+		return "", nil, fmt.Errorf("获取核心题材失败: %w", err)
+	}
+	boardName, boardCode := pickPeerBoard(themes.BoardTypes, theme)
+	if boardCode == "" {
+		if theme != "" {
+			return "", nil, fmt.Errorf("未找到匹配的概念板块: %s", theme)
+		}
+		return "", nil, fmt.Errorf("未找到 %s 的概念板块", code)
+	}
+
+	leaders, err := s.marketService.GetBoardLeaders(boardCode, limit+1)
+	if err != nil {
+		return boardName, nil, fmt.Errorf("获取板块成分股失败: %w", err)
+	}
+	peers := make([]peerCandidate, 0, len(leaders.Items))
+	for _, item := range leaders.Items {
+		if item.Code == "" || item.Code == code || len(peers) >= limit {
+			continue
+		}
+		peers = append(peers, peerCandidate{code: item.Code, name: item.Name})
+	}
+	return boardName, peers, nil
+}
+
+// pickPeerBoard 从核心题材板块中选出对比板块，返回板块名称与代码
+func pickPeerBoard(boards []map[string]any, theme string) (string, string) {
+	theme = strings.TrimSpace(theme)
+	for _, board := range boards {
+		name := strings.TrimSpace(toStringLocal(board["BOARD_NAME"]))
+		code := normalizeBoardCode(toStringLocal(firstNonEmpty(board, "NEW_BOARD_CODE", "DERIVE_BOARD_CODE", "BOARD_CODE")))
+		if code == "" {
+			continue
+		}
+		if theme == "" || strings.Contains(name, theme) || strings.EqualFold(code, normalizeBoardCode(theme)) {
+			return name, code
+		}
+	}
+	return "", ""
+}
+
+// customPeers 整理自定义对比列表，去重并排除目标股票
+func customPeers(target string, codes []string, limit int) []peerCandidate {
+	seen := map[string]bool{target: true}
+	var peers []peerCandidate
+	for _, code := range codes {
+		raw := normalizeStockCode(code).Raw
+		if raw == "" || seen[raw] || len(peers) >= limit {
+			continue
+		}
+		seen[raw] = true
+		peers = append(peers, peerCandidate{code: raw})
+	}
+	return peers
+}
+
+// collectPeerRows 拉取行情、财务指标与资金流，组装对比行
+func (s *PeerService) collectPeerRows(candidates []peerCandidate, errs map[string]string) []models.PeerRow {
+	rows := make([]models.PeerRow, len(candidates))
+	codes := make([]string, len(candidates))
+	for i, c := range candidates {
+		rows[i] = models.PeerRow{Code: c.code, Name: c.name, Metrics: make(map[string]float64)}
+		codes[i] = c.code
+	}
+
+	quotes, names, err := s.fetchPeerQuotes(codes)
+	if err != nil {
+		errs["quote"] = err.Error()
+	}
+	for i := range rows {
+		for k, v := range quotes[rows[i].Code] {
+			rows[i].Metrics[k] = v
+		}
+		if name := names[rows[i].Code]; name != "" {
+			rows[i].Name = name
+		}
+	}
+
+	if s.f10Service == nil {
+		errs["detail"] = "F10 服务未初始化"
+		return rows
+	}
+
+	var mu sync.Mutex
+	var failed []string
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, peerDetailConcurrency)
+	for i := range rows {
+		wg.Add(1)
+		go func(row *models.PeerRow) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			metrics := make(map[string]float64)
+			ok := true
+			indicators, err := s.f10Service.GetMainIndicators(row.Code)
+			if err != nil || len(indicators.Latest) == 0 {
+				ok = false
+			} else {
+				latest := indicators.Latest[0]
+				for name, keys := range screenIndicatorKeys {
+					for _, key := range keys {
+						if v, found := screenNumberAny(latest[key]); found {
+							metrics[name] = v
+							break
+						}
+					}
+				}
+			}
+			flow, err := s.f10Service.GetFundFlowByCode(row.Code)
+			if err != nil && len(flow.Lines) == 0 {
+				ok = false
+			} else {
+				for k, v := range computeScreenFundFlow(flow) {
+					metrics[k] = v
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for k, v := range metrics {
+				row.Metrics[k] = v
+			}
+			if !ok {
+				failed = append(failed, row.Code)
+			}
+		}(&rows[i])
+	}
+	wg.Wait()
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		errs["detail"] = "部分股票财务或资金流数据获取失败: " + strings.Join(failed, ",")
+	}
+	return rows
+}
+
+// fetchPeerQuotes 批量获取对比股票的行情快照，返回指标与名称
+func (s *PeerService) fetchPeerQuotes(codes []string) (map[string]map[string]float64, map[string]string, error) {
+	quotes := make(map[string]map[string]float64)
+	names := make(map[string]string)
+	if s.marketService == nil {
+		return quotes, names, fmt.Errorf("Market 服务未初始化")
+	}
+
+	secids := make([]string, 0, len(codes))
+	for _, code := range codes {
+		secids = append(secids, normalizeStockCode(code).SecID)
+	}
+	keys := []string{"f12", "f14"}
+	for _, name := range peerQuoteFields {
+		keys = append(keys, screenQuoteColumns[name].key)
+	}
+
+	params := url.Values{}
+	params.Set("fltt", "2")
+	params.Set("invt", "2")
+	params.Set("fields", strings.Join(keys, ","))
+	params.Set("ut", "8dec03ba335b81bf4ebdf7b29ec27d15")
+	params.Set("secids", strings.Join(secids, ","))
+
+	raw, err := s.marketService.fetchMarketJSON(emBoardQuoteURL+"?"+params.Encode(), map[string]string{
+		"Referer":    "https://quote.eastmoney.com/",
+		"User-Agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
+	})
+	if err != nil {
+		return quotes, names, err
+	}
+	data, ok := raw["data"].(map[string]any)
+	if !ok || data == nil {
+		return quotes, names, fmt.Errorf("同业行情响应缺少data")
+	}
+	for _, row := range toMapSliceLocal(toSliceAnyLocal(data["diff"])) {
+		code := strings.TrimSpace(toStringLocal(row["f12"]))
+		if code == "" {
+			continue
+		}
+		metrics := make(map[string]float64)
+		for _, name := range peerQuoteFields {
+			col := screenQuoteColumns[name]
+			if v, ok := screenNumberAny(row[col.key]); ok {
+				metrics[name] = v * col.scale
+			}
+		}
+		quotes[code] = metrics
+		names[code] = strings.TrimSpace(toStringLocal(row["f14"]))
+	}
+	return quotes, names, nil
+}
+
+// peerMetricValue 取可参与排名的指标值，亏损或净资产为负时估值不参与排名
+func peerMetricValue(row models.PeerRow, metric models.PeerMetric) (float64, bool) {
+	v, ok := row.Metrics[metric.Name]
+	if !ok {
+		return 0, false
+	}
+	if metric.Group == peerGroupValuation && v <= 0 {
+		return 0, false
+	}
+	return v, true
+}
+
+// computePeerPercentiles 计算每只股票在各指标上的百分位（0-100，越高越优）及分组得分
+func computePeerPercentiles(rows []models.PeerRow, metrics []models.PeerMetric) {
+	for i := range rows {
+		rows[i].Percentiles = make(map[string]float64)
+	}
+	for _, metric := range metrics {
+		for i := range rows {
+			v, ok := peerMetricValue(rows[i], metric)
+			if !ok {
+				continue
+			}
+			var worse, ties, count float64
+			for j := range rows {
+				other, ok := peerMetricValue(rows[j], metric)
+				if !ok || j == i {
+					continue
+				}
+				count++
+				switch {
+				case other == v:
+					ties++
+				case (other < v) == metric.HigherBetter:
+					worse++
+				}
+			}
+			if count == 0 {
+				continue
+			}
+			rows[i].Percentiles[metric.Name] = round2((worse + ties/2) / count * 100)
+		}
+	}
+
+	for i := range rows {
+		sums := make(map[string]float64)
+		counts := make(map[string]int)
+		for _, metric := range metrics {
+			if p, ok := rows[i].Percentiles[metric.Name]; ok {
+				sums[metric.Group] += p
+				counts[metric.Group]++
+			}
+		}
+		if len(counts) == 0 {
+			continue
+		}
+		rows[i].GroupScores = make(map[string]float64, len(counts))
+		for group, n := range counts {
+			rows[i].GroupScores[group] = round2(sums[group] / float64(n))
+		}
+	}
+}
+
+// buildPeerRanks 生成目标股票（rows[0]）在各指标上的排名
+func buildPeerRanks(rows []models.PeerRow, metrics []models.PeerMetric) []models.PeerRank {
+	if len(rows) == 0 {
+		return nil
+	}
+	target := rows[0]
+	var ranks []models.PeerRank
+	for _, metric := range metrics {
+		p, ok := target.Percentiles[metric.Name]
+		if !ok {
+			continue
+		}
+		value, _ := peerMetricValue(target, metric)
+		var values []float64
+		rank := 1
+		for _, row := range rows {
+			v, ok := peerMetricValue(row, metric)
+			if !ok {
+				continue
+			}
+			values = append(values, v)
+			if v != value && (v > value) == metric.HigherBetter {
+				rank++
+			}
+		}
+		sort.Float64s(values)
+		ranks = append(ranks, models.PeerRank{
+			Metric:     metric.Name,
+			Label:      metric.Label,
+			Group:      metric.Group,
+			Value:      round2(value),
+			Median:     round2(percentileSorted(values, 50)),
+			Rank:       rank,
+			Count:      len(values),
+			Percentile: p,
+		})
+	}
+	return ranks
+}
+
+// FormatPeerMatrix 将同业对比矩阵格式化为文本表格，供上下文注入
+func FormatPeerMatrix(analysis models.PeerAnalysis) string {
+	if len(analysis.Rows) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	header := []string{"股票", "总市值(亿)"}
+	for _, metric := range analysis.Metrics {
+		header = append(header, metric.Label)
+	}
+	for _, group := range peerGroupOrder {
+		header = append(header, peerGroupLabels[group]+"分位")
+	}
+	sb.WriteString("| " + strings.Join(header, " | ") + " |\n")
+	sb.WriteString("|" + strings.Repeat(" --- |", len(header)) + "\n")
+
+	for _, row := range analysis.Rows {
+		name := row.Name
+		if name == "" {
+			name = row.Code
+		}
+		if row.IsTarget {
+			name = "**" + name + "**"
+		}
+		cells := []string{fmt.Sprintf("%s(%s)", name, row.Code), formatPeerValue(row.Metrics, "total_mv")}
+		for _, metric := range analysis.Metrics {
+			cells = append(cells, formatPeerValue(row.Metrics, metric.Name))
+		}
+		for _, group := range peerGroupOrder {
+			cells = append(cells, formatPeerValue(row.GroupScores, group))
+		}
+		sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	sb.WriteString("注：分位为0-100的同组百分位，越高越优（估值越低越优，亏损或负净资产不参与估值排名）")
+	return sb.String()
+}
+
+func formatPeerValue(values map[string]float64, key string) string {
+	v, ok := values[key]
+	if !ok {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", v)
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestComputePeerPercentiles(t *testing.T) {
+	rows := []models.PeerRow{
+		{Code: "600001", Name: "目标", IsTarget: true, Metrics: map[string]float64{"pe": 10, "roe": 20, "revenue_yoy": 5}},
+		{Code: "600002", Name: "甲", Metrics: map[string]float64{"pe": 20, "roe": 10, "revenue_yoy": 5}},
+		{Code: "600003", Name: "乙", Metrics: map[string]float64{"pe": -5, "roe": 30, "revenue_yoy": 15}},
+		{Code: "600004", Name: "丙", Metrics: map[string]float64{"pe": 30, "roe": 15}},
+	}
+	metrics := []models.PeerMetric{
+		{Name: "pe", Label: "市盈率TTM", Group: peerGroupValuation},
+		{Name: "roe", Label: "ROE(%)", Group: peerGroupProfitability, HigherBetter: true},
+		{Name: "revenue_yoy", Label: "营收同比(%)", Group: peerGroupGrowth, HigherBetter: true},
+	}
+
+	computePeerPercentiles(rows, metrics)
+	target := rows[0]
+	// 亏损股不参与估值排名：PE 10 优于 20、30
+	if target.Percentiles["pe"] != 100 {
+		t.Fatalf("pe percentile = %v", target.Percentiles["pe"])
+	}
+	if target.Percentiles["roe"] != 66.67 {
+		t.Fatalf("roe percentile = %v", target.Percentiles["roe"])
+	}
+	// 与甲并列、落后于乙：(0 + 1/2) / 2
+	if target.Percentiles["revenue_yoy"] != 25 {
+		t.Fatalf("revenue_yoy percentile = %v", target.Percentiles["revenue_yoy"])
+	}
+	if _, ok := rows[2].Percentiles["pe"]; ok {
+		t.Fatalf("loss-making peer should not be ranked on pe")
+	}
+	if target.GroupScores[peerGroupProfitability] != 66.67 {
+		t.Fatalf("unexpected group scores: %+v", target.GroupScores)
+	}
+
+	ranks := buildPeerRanks(rows, metrics)
+	if len(ranks) != 3 {
+		t.Fatalf("unexpected ranks: %+v", ranks)
+	}
+	if ranks[0].Rank != 1 || ranks[0].Count != 3 || ranks[0].Median != 20 {
+		t.Fatalf("unexpected pe rank: %+v", ranks[0])
+	}
+	if ranks[1].Rank != 2 || ranks[1].Count != 4 || ranks[1].Median != 17.5 {
+		t.Fatalf("unexpected roe rank: %+v", ranks[1])
+	}
+	if ranks[2].Rank != 2 || ranks[2].Count != 3 {
+		t.Fatalf("unexpected revenue rank: %+v", ranks[2])
+	}
+
+	text := FormatPeerMatrix(models.PeerAnalysis{Metrics: metrics, Rows: rows})
+	if !strings.Contains(text, "**目标**(600001)") || !strings.Contains(text, "| 乙(600003) | - | -5.00 | 30.00 | 15.00 | - |") {
+		t.Fatalf("unexpected matrix:\n%s", text)
+	}
+}
+
+func TestPickPeerBoard(t *testing.T) {
+	boards := []map[string]any{
+		{"BOARD_NAME": "无代码"},
+		{"BOARD_NAME": "白酒", "NEW_BOARD_CODE": "BK0896"},
+		{"BOARD_NAME": "消费电子", "NEW_BOARD_CODE": "1037"},
+	}
+	if name, code := pickPeerBoard(boards, ""); name != "白酒" || code != "BK0896" {
+		t.Fatalf("default board = %s %s", name, code)
+	}
+	if name, code := pickPeerBoard(boards, "电子"); name != "消费电子" || code != "BK1037" {
+		t.Fatalf("theme board = %s %s", name, code)
+	}
+	if _, code := pickPeerBoard(boards, "半导体"); code != "" {
+		t.Fatalf("unexpected match: %s", code)
+	}
+
+	peers := customPeers("600001", []string{"sh600001", "000002", "sz000002", " 600003 "}, 10)
+	if len(peers) != 2 || peers[0].code != "000002" || peers[1].code != "600003" {
+		t.Fatalf("unexpected custom peers: %+v", peers)
+	}
+}
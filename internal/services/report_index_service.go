@@ -0,0 +1,456 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/models"
+	"github.com/run-bigpig/jcp/internal/pkg/pdftext"
+	"github.com/run-bigpig/jcp/internal/pkg/proxy"
+)
+
+var reportIndexLog = logger.New("report-index")
+
+const (
+	reportPDFMaxSize       = 30 << 20
+	reportChunkSize        = 400
+	reportChunkOverlap     = 60
+	reportDefaultReports   = 5
+	reportMaxReports       = 10
+	reportDefaultHits      = 6
+	reportMaxHits          = 15
+	reportIngestConcurrent = 3
+	reportLookupPageSize   = 50 // 按 infoCode 检索时，在个股最近研报中查找元信息
+)
+
+var reportInfoCodePattern = regexp.MustCompile(`^[A-Za-z0-9]+$`)
+
+// ReportIndexService 研报PDF入库与片段检索服务
+type ReportIndexService struct {
+	researchReportService *ResearchReportService
+	client                *http.Client
+	indexDir              string
+	locks                 sync.Map // infoCode -> *sync.Mutex，避免同一研报重复下载
+}
+
+// NewReportIndexService 创建研报索引服务
+func NewReportIndexService(dataDir string, researchReportService *ResearchReportService) *ReportIndexService {
+	return &ReportIndexService{
+		researchReportService: researchReportService,
+		client:                proxy.GetManager().GetClientWithTimeout(60 * time.Second),
+		indexDir:              filepath.Join(dataDir, "report_index"),
+	}
+}
+
+// Ingest 下载研报PDF并分片入库，已入库的直接返回（缺失的券商/日期/标题用 report 补全）
+func (s *ReportIndexService) Ingest(ctx context.Context, report ResearchReport) (*models.ReportIndex, error) {
+	infoCode := strings.TrimSpace(report.InfoCode)
+	if !reportInfoCodePattern.MatchString(infoCode) {
+		return nil, fmt.Errorf("无效的研报标识: %s", report.InfoCode)
+	}
+	lock, _ := s.locks.LoadOrStore(infoCode, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	if index, err := s.load(infoCode); err == nil {
+		if fillReportMeta(index, report) {
+			if err := s.save(index); err != nil {
+				reportIndexLog.Warn("更新研报元信息失败: infoCode=%s, err=%v", infoCode, err)
+			}
+		}
+		return index, nil
+	}
+
+	pdfURL := s.researchReportService.GetReportPDFUrl(infoCode)
+	data, err := s.download(ctx, pdfURL)
+	if err != nil {
+		return nil, fmt.Errorf("下载研报PDF失败: %w", err)
+	}
+	pages, err := pdftext.ExtractPages(data)
+	if err != nil {
+		return nil, fmt.Errorf("解析研报PDF失败: %w", err)
+	}
+
+	index := &models.ReportIndex{
+		InfoCode:    infoCode,
+		StockCode:   report.StockCode,
+		StockName:   report.StockName,
+		Broker:      report.OrgSName,
+		Title:       report.Title,
+		PublishDate: formatReportDate(report.PublishDate),
+		PDFUrl:      pdfURL,
+		Pages:       len(pages),
+		IngestedAt:  time.Now().UnixMilli(),
+		Chunks:      chunkReportPages(pages, reportChunkSize, reportChunkOverlap),
+	}
+	if err := s.save(index); err != nil {
+		reportIndexLog.Warn("保存研报索引失败: infoCode=%s, err=%v", infoCode, err)
+	}
+	reportIndexLog.Info("研报入库完成: infoCode=%s, pages=%d, chunks=%d", infoCode, index.Pages, len(index.Chunks))
+	return index, nil
+}
+
+// IngestByInfoCode 按研报标识入库，提供股票代码时从该股最近研报中查找券商、日期等元信息
+func (s *ReportIndexService) IngestByInfoCode(ctx context.Context, code string, infoCode string) (*models.ReportIndex, error) {
+	report := ResearchReport{InfoCode: infoCode}
+	if code != "" {
+		if result, err := s.researchReportService.GetResearchReports(code, reportLookupPageSize, 1); err != nil {
+			reportIndexLog.Warn("查找研报元信息失败: code=%s, err=%v", code, err)
+		} else {
+			for _, r := range result.Data {
+				if r.InfoCode == infoCode {
+					report = r
+					break
+				}
+			}
+		}
+	}
+	return s.Ingest(ctx, report)
+}
+
+// fillReportMeta 用研报列表信息补全索引中缺失的元信息，有变化时返回 true
+func fillReportMeta(index *models.ReportIndex, report ResearchReport) bool {
+	changed := false
+	fill := func(dst *string, src string) {
+		if *dst == "" && src != "" {
+			*dst = src
+			changed = true
+		}
+	}
+	fill(&index.StockCode, report.StockCode)
+	fill(&index.StockName, report.StockName)
+	fill(&index.Broker, report.OrgSName)
+	fill(&index.Title, report.Title)
+	fill(&index.PublishDate, formatReportDate(report.PublishDate))
+	return changed
+}
+
+// IngestStockReports 入库个股最近 limit 篇研报，单篇失败记录到 errors
+func (s *ReportIndexService) IngestStockReports(ctx context.Context, code string, limit int) ([]*models.ReportIndex, map[string]string) {
+	errs := make(map[string]string)
+	if limit <= 0 {
+		limit = reportDefaultReports
+	}
+	if limit > reportMaxReports {
+		limit = reportMaxReports
+	}
+	result, err := s.researchReportService.GetResearchReports(code, limit, 1)
+	if err != nil {
+		errs["reports"] = err.Error()
+		return nil, errs
+	}
+	reports := result.Data
+	if len(reports) > limit {
+		reports = reports[:limit]
+	}
+
+	indexes := make([]*models.ReportIndex, len(reports))
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		sem = make(chan struct{}, reportIngestConcurrent)
+	)
+	for i, report := range reports {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, report ResearchReport) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			index, err := s.Ingest(ctx, report)
+			if err != nil {
+				mu.Lock()
+				errs[report.InfoCode] = err.Error()
+				mu.Unlock()
+				return
+			}
+			indexes[i] = index
+		}(i, report)
+	}
+	wg.Wait()
+
+	kept := make([]*models.ReportIndex, 0, len(indexes))
+	for _, index := range indexes {
+		if index != nil {
+			kept = append(kept, index)
+		}
+	}
+	return kept, errs
+}
+
+// download 下载PDF（限制大小）
+func (s *ReportIndexService) download(ctx context.Context, pdfURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pdfURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+	req.Header.Set("Referer", "https://data.eastmoney.com/")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, reportPDFMaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > reportPDFMaxSize {
+		return nil, fmt.Errorf("PDF 超过 %dMB", reportPDFMaxSize>>20)
+	}
+	return data, nil
+}
+
+func (s *ReportIndexService) load(infoCode string) (*models.ReportIndex, error) {
+	data, err := os.ReadFile(filepath.Join(s.indexDir, infoCode+".json"))
+	if err != nil {
+		return nil, err
+	}
+	var index models.ReportIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, err
+	}
+	return &index, nil
+}
+
+func (s *ReportIndexService) save(index *models.ReportIndex) error {
+	if err := os.MkdirAll(s.indexDir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.indexDir, index.InfoCode+".json"), data, 0644)
+}
+
+// Search 检索研报片段：提供 infoCode 时仅检索该研报，否则检索个股最近 reports 篇研报
+func (s *ReportIndexService) Search(ctx context.Context, code string, infoCode string, query string, reports int, limit int) ([]models.ReportChunkHit, map[string]string) {
+	if strings.TrimSpace(query) == "" {
+		return nil, map[string]string{"query": "请提供检索问题"}
+	}
+	var (
+		indexes []*models.ReportIndex
+		errs    = make(map[string]string)
+	)
+	switch {
+	case strings.TrimSpace(infoCode) != "":
+		index, err := s.IngestByInfoCode(ctx, strings.TrimSpace(code), strings.TrimSpace(infoCode))
+		if err != nil {
+			errs[infoCode] = err.Error()
+		} else {
+			indexes = append(indexes, index)
+		}
+	case strings.TrimSpace(code) != "":
+		indexes, errs = s.IngestStockReports(ctx, code, reports)
+	default:
+		return nil, map[string]string{"code": "请提供股票代码或研报 infoCode"}
+	}
+	return searchReportChunks(indexes, query, limit), errs
+}
+
+// searchReportChunks 在给定研报中按 BM25 检索与问题最相关的片段
+func searchReportChunks(indexes []*models.ReportIndex, query string, limit int) []models.ReportChunkHit {
+	if limit <= 0 {
+		limit = reportDefaultHits
+	}
+	if limit > reportMaxHits {
+		limit = reportMaxHits
+	}
+	queryTokens := reportTokens(query)
+	if len(queryTokens) == 0 {
+		return []models.ReportChunkHit{}
+	}
+
+	type candidate struct {
+		index *models.ReportIndex
+		chunk models.ReportChunk
+		freq  map[string]int
+		size  int
+	}
+	var (
+		candidates []candidate
+		totalLen   int
+		docFreq    = make(map[string]int)
+	)
+	for _, index := range indexes {
+		for _, chunk := range index.Chunks {
+			tokens := reportTokens(chunk.Text)
+			freq := make(map[string]int, len(tokens))
+			for _, token := range tokens {
+				freq[token]++
+			}
+			for token := range freq {
+				docFreq[token]++
+			}
+			candidates = append(candidates, candidate{index: index, chunk: chunk, freq: freq, size: len(tokens)})
+			totalLen += len(tokens)
+		}
+	}
+	if len(candidates) == 0 {
+		return []models.ReportChunkHit{}
+	}
+
+	const k1, b = 1.2, 0.75
+	avgLen := float64(totalLen) / float64(len(candidates))
+	n := float64(len(candidates))
+	uniqueQuery := make(map[string]bool)
+	for _, token := range queryTokens {
+		uniqueQuery[token] = true
+	}
+
+	hits := make([]models.ReportChunkHit, 0)
+	for _, c := range candidates {
+		score := 0.0
+		for token := range uniqueQuery {
+			tf := float64(c.freq[token])
+			if tf == 0 {
+				continue
+			}
+			df := float64(docFreq[token])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			score += idf * tf * (k1 + 1) / (tf + k1*(1-b+b*float64(c.size)/avgLen))
+		}
+		if score <= 0 {
+			continue
+		}
+		hits = append(hits, models.ReportChunkHit{
+			InfoCode:    c.index.InfoCode,
+			Broker:      c.index.Broker,
+			Title:       c.index.Title,
+			PublishDate: c.index.PublishDate,
+			Page:        c.chunk.Page,
+			Text:        c.chunk.Text,
+			Score:       math.Round(score*1000) / 1000,
+			Citation:    reportCitation(c.index, c.chunk.Page),
+		})
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// reportCitation 生成引用标注，如：中信证券 2025-06-20《标题》第3页
+func reportCitation(index *models.ReportIndex, page int) string {
+	var parts []string
+	if index.Broker != "" {
+		parts = append(parts, index.Broker)
+	}
+	if index.PublishDate != "" {
+		parts = append(parts, index.PublishDate)
+	}
+	title := index.Title
+	if title == "" {
+		title = index.InfoCode
+	}
+	return strings.TrimSpace(strings.Join(parts, " ") + "《" + title + "》第" + fmt.Sprint(page) + "页")
+}
+
+// chunkReportPages 按页切分正文，片段间保留少量重叠，尽量在句末或换行处断开
+func chunkReportPages(pages []string, size int, overlap int) []models.ReportChunk {
+	chunks := make([]models.ReportChunk, 0)
+	for i, page := range pages {
+		runes := []rune(strings.TrimSpace(page))
+		for start := 0; start < len(runes); {
+			end := start + size
+			if end >= len(runes) {
+				end = len(runes)
+			} else if cut := reportBreakPoint(runes[start:end], size*7/10); cut > 0 {
+				end = start + cut
+			}
+			text := strings.TrimSpace(string(runes[start:end]))
+			if text != "" {
+				chunks = append(chunks, models.ReportChunk{Index: len(chunks), Page: i + 1, Text: text})
+			}
+			if end >= len(runes) {
+				break
+			}
+			next := end - overlap
+			if next <= start {
+				next = end
+			}
+			start = next
+		}
+	}
+	return chunks
+}
+
+// reportBreakPoint 在片段后段寻找换行或句末标点作为断点
+func reportBreakPoint(runes []rune, from int) int {
+	for i := len(runes) - 1; i >= from; i-- {
+		switch runes[i] {
+		case '\n', '。', '！', '？', '；', '.':
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// reportTokens 研报检索分词：西文按单词，中文按相邻二元组
+func reportTokens(text string) []string {
+	var (
+		tokens []string
+		word   []rune
+		han    []rune
+	)
+	flushWord := func() {
+		if len(word) > 0 {
+			tokens = append(tokens, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+	flushHan := func() {
+		switch {
+		case len(han) == 1:
+			tokens = append(tokens, string(han))
+		case len(han) > 1:
+			for i := 0; i+1 < len(han); i++ {
+				tokens = append(tokens, string(han[i:i+2]))
+			}
+		}
+		han = han[:0]
+	}
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flushWord()
+			han = append(han, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || ((r == '.' || r == '%') && len(word) > 0):
+			flushHan()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushHan()
+		}
+	}
+	flushWord()
+	flushHan()
+	return tokens
+}
+
+// formatReportDate 截取研报发布日期
+func formatReportDate(text string) string {
+	text = strings.TrimSpace(text)
+	if len(text) > 10 {
+		return text[:10]
+	}
+	return text
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestChunkReportPages(t *testing.T) {
+	page := strings.Repeat("营收增长。", 30) // 150 字
+	chunks := chunkReportPages([]string{page, "", "短页"}, 60, 10)
+	if len(chunks) < 3 {
+		t.Fatalf("chunks = %d, want >= 3", len(chunks))
+	}
+	for _, chunk := range chunks[:len(chunks)-1] {
+		if chunk.Page != 1 || len([]rune(chunk.Text)) > 60 || !strings.HasSuffix(chunk.Text, "。") {
+			t.Fatalf("unexpected chunk: %+v", chunk)
+		}
+	}
+	last := chunks[len(chunks)-1]
+	if last.Page != 3 || last.Text != "短页" || last.Index != len(chunks)-1 {
+		t.Fatalf("unexpected last chunk: %+v", last)
+	}
+}
+
+func TestSearchReportChunks(t *testing.T) {
+	indexes := []*models.ReportIndex{{
+		InfoCode:    "AP1",
+		Broker:      "甲证券",
+		Title:       "深度报告",
+		PublishDate: "2025-06-20",
+		Chunks: []models.ReportChunk{
+			{Index: 0, Page: 1, Text: "公司毛利率提升至35%，主要受益于产品结构优化。"},
+			{Index: 1, Page: 2, Text: "风险提示：原材料价格波动、下游需求不及预期。"},
+			{Index: 2, Page: 3, Text: "我们预计公司2025年营业收入增长20%。"},
+		},
+	}}
+
+	hits := searchReportChunks(indexes, "毛利率变化的原因", 2)
+	if len(hits) == 0 || hits[0].Page != 1 {
+		t.Fatalf("unexpected hits: %+v", hits)
+	}
+	if want := "甲证券 2025-06-20《深度报告》第1页"; hits[0].Citation != want {
+		t.Errorf("citation = %q, want %q", hits[0].Citation, want)
+	}
+	if hits := searchReportChunks(indexes, "风险", 5); len(hits) != 1 || hits[0].Page != 2 {
+		t.Fatalf("unexpected risk hits: %+v", hits)
+	}
+}
+
+func TestIngest_FillsCachedMetadata(t *testing.T) {
+	s := NewReportIndexService(t.TempDir(), nil)
+	if err := s.save(&models.ReportIndex{InfoCode: "AP1", Title: "旧标题"}); err != nil {
+		t.Fatal(err)
+	}
+
+	index, err := s.Ingest(context.Background(), ResearchReport{
+		InfoCode:    "AP1",
+		OrgSName:    "甲证券",
+		Title:       "新标题",
+		PublishDate: "2025-06-20 00:00:00.000",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index.Broker != "甲证券" || index.PublishDate != "2025-06-20" || index.Title != "旧标题" {
+		t.Fatalf("unexpected metadata: %+v", index)
+	}
+	if saved, err := s.load("AP1"); err != nil || saved.Broker != "甲证券" {
+		t.Fatalf("metadata not persisted: %+v, err=%v", saved, err)
+	}
+}
@@ -17,20 +17,23 @@ const (
 
 // ResearchReport 个股研报数据结构
 type ResearchReport struct {
-	Title              string `json:"title"`              // 研报标题
-	StockName          string `json:"stockName"`          // 股票名称
-	StockCode          string `json:"stockCode"`          // 股票代码
-	OrgSName           string `json:"orgSName"`           // 券商简称
-	PublishDate        string `json:"publishDate"`        // 发布日期
-	PredictThisYearEps string `json:"predictThisYearEps"` // 今年预测EPS
-	PredictThisYearPe  string `json:"predictThisYearPe"`  // 今年预测PE
-	PredictNextYearEps string `json:"predictNextYearEps"` // 明年预测EPS
-	PredictNextYearPe  string `json:"predictNextYearPe"`  // 明年预测PE
-	IndvInduName       string `json:"indvInduName"`       // 行业名称
-	EmRatingName       string `json:"emRatingName"`       // 评级名称
-	Researcher         string `json:"researcher"`         // 研究员
-	EncodeUrl          string `json:"encodeUrl"`          // 报告链接编码
-	InfoCode           string `json:"infoCode"`           // 研报唯一标识码
+	Title                 string `json:"title"`                 // 研报标题
+	StockName             string `json:"stockName"`             // 股票名称
+	StockCode             string `json:"stockCode"`             // 股票代码
+	OrgSName              string `json:"orgSName"`              // 券商简称
+	PublishDate           string `json:"publishDate"`           // 发布日期
+	PredictThisYearEps    string `json:"predictThisYearEps"`    // 今年预测EPS
+	PredictThisYearPe     string `json:"predictThisYearPe"`     // 今年预测PE
+	PredictNextYearEps    string `json:"predictNextYearEps"`    // 明年预测EPS
+	PredictNextYearPe     string `json:"predictNextYearPe"`     // 明年预测PE
+	PredictNextTwoYearEps string `json:"predictNextTwoYearEps"` // 后年预测EPS
+	PredictNextTwoYearPe  string `json:"predictNextTwoYearPe"`  // 后年预测PE
+	IndvInduName          string `json:"indvInduName"`          // 行业名称
+	EmRatingName          string `json:"emRatingName"`          // 评级名称
+	LastEmRatingName      string `json:"lastEmRatingName"`      // 上次评级名称
+	Researcher            string `json:"researcher"`            // 研究员
+	EncodeUrl             string `json:"encodeUrl"`             // 报告链接编码
+	InfoCode              string `json:"infoCode"`              // 研报唯一标识码
 }
 
 // ReportContentResponse 研报内容响应
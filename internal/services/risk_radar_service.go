@@ -0,0 +1,435 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+var riskLog = logger.New("risk")
+
+const (
+	riskScheduleTick     = 10 * time.Minute
+	riskScanInterval     = 6 * time.Hour
+	riskConcurrency      = 4
+	riskLockupDays       = 90 // 解禁前瞻窗口
+	riskReductionDays    = 90 // 减持回看窗口
+	riskAnnouncementDays = 60 // 公告回看窗口
+	riskAnnouncementMax  = 30
+)
+
+// riskState 风险雷达持久化状态
+type riskState struct {
+	Radar   *models.RiskRadar `json:"radar,omitempty"`
+	Alerted []string          `json:"alerted"` // 已提醒的信号键
+}
+
+// RiskRadarService 自选股与持仓风险雷达服务
+type RiskRadarService struct {
+	f10Service     *F10Service
+	marketService  *MarketService
+	configService  *ConfigService
+	sessionService *SessionService
+	statePath      string
+
+	mu      sync.Mutex
+	state   riskState
+	handler func(models.RiskAlert)
+
+	scanMu   sync.Mutex
+	stopChan chan struct{}
+	stopOnce sync.Once
+}
+
+// NewRiskRadarService 创建风险雷达服务
+func NewRiskRadarService(dataDir string, f10Service *F10Service, marketService *MarketService, configService *ConfigService, sessionService *SessionService) *RiskRadarService {
+	s := &RiskRadarService{
+		f10Service:     f10Service,
+		marketService:  marketService,
+		configService:  configService,
+		sessionService: sessionService,
+		statePath:      filepath.Join(dataDir, "risk_radar.json"),
+		stopChan:       make(chan struct{}),
+	}
+	s.load()
+	return s
+}
+
+// SetAlertHandler 设置新风险信号回调
+func (s *RiskRadarService) SetAlertHandler(handler func(models.RiskAlert)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handler = handler
+}
+
+// load 从文件加载状态
+func (s *RiskRadarService) load() {
+	data, err := os.ReadFile(s.statePath)
+	if err != nil {
+		return
+	}
+	var state riskState
+	if err := json.Unmarshal(data, &state); err != nil {
+		riskLog.Warn("解析风险雷达状态失败: %v", err)
+		return
+	}
+	s.state = state
+}
+
+// saveLocked 保存状态，调用方需持有锁
+func (s *RiskRadarService) saveLocked() error {
+	if err := os.MkdirAll(filepath.Dir(s.statePath), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.statePath, data, 0644)
+}
+
+// Start 启动定时风险扫描
+func (s *RiskRadarService) Start() {
+	go func() {
+		ticker := time.NewTicker(riskScheduleTick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stopChan:
+				return
+			case <-ticker.C:
+				if s.due() {
+					s.Scan()
+				}
+			}
+		}
+	}()
+}
+
+// Stop 停止定时扫描
+func (s *RiskRadarService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopChan)
+	})
+}
+
+// due 判断距上次扫描是否已超过扫描间隔
+func (s *RiskRadarService) due() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state.Radar == nil {
+		return true
+	}
+	return time.Since(time.UnixMilli(s.state.Radar.UpdateTime)) >= riskScanInterval
+}
+
+// GetRadar 获取最近一次扫描结果，从未扫描时立即扫描
+func (s *RiskRadarService) GetRadar() *models.RiskRadar {
+	s.mu.Lock()
+	radar := s.state.Radar
+	s.mu.Unlock()
+	if radar == nil {
+		return s.Scan()
+	}
+	return radar
+}
+
+// Scan 扫描全部自选股与持仓股，生成风险排行并提醒新信号
+func (s *RiskRadarService) Scan() *models.RiskRadar {
+	s.scanMu.Lock()
+	defer s.scanMu.Unlock()
+
+	targets := s.collectTargets()
+	profiles := make([]models.StockRiskProfile, len(targets))
+	today := time.Now().In(reviewLocation())
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, riskConcurrency)
+	for i := range targets {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			profiles[i] = s.scanStock(targets[i], today)
+		}(i)
+	}
+	wg.Wait()
+
+	sort.SliceStable(profiles, func(i, j int) bool {
+		return profiles[i].Score > profiles[j].Score
+	})
+	radar := &models.RiskRadar{
+		UpdateTime: time.Now().UnixMilli(),
+		Stocks:     profiles,
+	}
+
+	s.mu.Lock()
+	firstScan := s.state.Radar == nil
+	alerted := make(map[string]bool, len(s.state.Alerted))
+	for _, key := range s.state.Alerted {
+		alerted[key] = true
+	}
+	var alerts []models.RiskAlert
+	var keys []string
+	for _, profile := range profiles {
+		for _, signal := range profile.Signals {
+			key := riskSignalKey(profile.StockCode, signal)
+			keys = append(keys, key)
+			if !firstScan && !alerted[key] && signal.Level != "low" {
+				alerts = append(alerts, models.RiskAlert{StockCode: profile.StockCode, StockName: profile.StockName, Signal: signal})
+			}
+		}
+	}
+	s.state.Radar = radar
+	s.state.Alerted = keys
+	if err := s.saveLocked(); err != nil {
+		riskLog.Warn("保存风险雷达失败: %v", err)
+	}
+	handler := s.handler
+	s.mu.Unlock()
+
+	riskLog.Info("风险雷达扫描完成: %d 只股票, %d 条新提醒", len(profiles), len(alerts))
+	if handler != nil {
+		for _, alert := range alerts {
+			handler(alert)
+		}
+	}
+	return radar
+}
+
+// collectTargets 合并自选股与持仓股
+func (s *RiskRadarService) collectTargets() []models.StockRiskProfile {
+	var targets []models.StockRiskProfile
+	index := make(map[string]int)
+	for _, stock := range s.configService.GetWatchlist() {
+		index[stock.Symbol] = len(targets)
+		targets = append(targets, models.StockRiskProfile{StockCode: stock.Symbol, StockName: stock.Name, InWatchlist: true})
+	}
+	if s.sessionService != nil {
+		for _, stock := range s.sessionService.GetPositionStocks() {
+			if i, ok := index[stock.Symbol]; ok {
+				targets[i].Held = true
+				continue
+			}
+			index[stock.Symbol] = len(targets)
+			targets = append(targets, models.StockRiskProfile{StockCode: stock.Symbol, StockName: stock.Name, Held: true})
+		}
+	}
+	return targets
+}
+
+// scanStock 扫描单只股票的各类风险信号
+func (s *RiskRadarService) scanStock(profile models.StockRiskProfile, today time.Time) models.StockRiskProfile {
+	errs := make(map[string]string)
+	var signals []models.RiskSignal
+
+	if lockup, err := s.f10Service.GetLockupReleaseByCode(profile.StockCode); err != nil && len(lockup.Records) == 0 {
+		errs["lockup"] = err.Error()
+	} else {
+		signals = append(signals, lockupRiskSignals(lockup.Records, today)...)
+	}
+	if pledge, err := s.f10Service.GetEquityPledgeByCode(profile.StockCode); err != nil && pledge.Latest == nil {
+		errs["pledge"] = err.Error()
+	} else if signal, ok := pledgeRiskSignal(pledge.Latest); ok {
+		signals = append(signals, signal)
+	}
+	if changes, err := s.f10Service.GetShareholderChangesByCode(profile.StockCode); err != nil && len(changes.Records) == 0 {
+		errs["reduction"] = err.Error()
+	} else {
+		signals = append(signals, reductionRiskSignals(changes.Records, today)...)
+	}
+	signals = append(signals, stNameRiskSignals(profile.StockName)...)
+	if announcements, err := s.marketService.GetStockAnnouncements(profile.StockCode, 1, riskAnnouncementMax); err != nil {
+		errs["announcement"] = err.Error()
+	} else {
+		signals = append(signals, announcementRiskSignals(announcements.Items, today)...)
+	}
+
+	profile.Signals = signals
+	scoreRiskProfile(&profile)
+	if len(errs) > 0 {
+		profile.Errors = errs
+	}
+	return profile
+}
+
+// lockupRiskSignals 未来解禁风险，按占流通股比例分级
+func lockupRiskSignals(records []map[string]any, today time.Time) []models.RiskSignal {
+	var signals []models.RiskSignal
+	for _, row := range records {
+		data := normalizedRecord(row)
+		freeDate := recordDate(data["freeDate"])
+		days, ok := riskDaysFrom(today, freeDate)
+		if !ok || days < 0 || days > riskLockupDays {
+			continue
+		}
+		// 接口返回的流通比例为小数
+		ratio := toFloat(data["freeRatio"]) * 100
+		signal := models.RiskSignal{Type: "lockup", Date: freeDate}
+		switch {
+		case ratio >= 10:
+			signal.Level, signal.Score = "high", 30
+		case ratio >= 3:
+			signal.Level, signal.Score = "medium", 15
+		default:
+			signal.Level, signal.Score = "low", 5
+		}
+		if days > 30 {
+			signal.Score *= 0.6
+		}
+		signal.Title = fmt.Sprintf("%d天后解禁，占流通股%.2f%%", days, ratio)
+		signal.Detail = fmt.Sprintf("%s，解禁市值%.2f亿元", toStringLocal(data["freeSharesType"]), toFloat(data["liftMarketCap"])/1e8)
+		signals = append(signals, signal)
+	}
+	return signals
+}
+
+// pledgeRiskSignal 股权质押风险，质押比例高且股价大幅下跌时视为接近平仓线
+func pledgeRiskSignal(latest map[string]any) (models.RiskSignal, bool) {
+	if latest == nil {
+		return models.RiskSignal{}, false
+	}
+	data := normalizedRecord(latest)
+	ratio := toFloat(data["pledgeRatio"])
+	yearChange := toFloat(data["yearChangeRate"])
+	signal := models.RiskSignal{Type: "pledge", Date: recordDate(data["tradeDate"])}
+	switch {
+	case ratio >= 50:
+		signal.Level, signal.Score = "high", 30
+	case ratio >= 30:
+		signal.Level, signal.Score = "medium", 18
+	case ratio >= 15:
+		signal.Level, signal.Score = "low", 8
+	default:
+		return models.RiskSignal{}, false
+	}
+	signal.Title = fmt.Sprintf("质押比例%.2f%%", ratio)
+	if yearChange <= -30 {
+		signal.Score += 15
+		if signal.Level == "low" {
+			signal.Level = "medium"
+		} else {
+			signal.Level = "high"
+		}
+		signal.Detail = fmt.Sprintf("近一年股价下跌%.2f%%，部分质押可能已接近平仓线", -yearChange)
+	}
+	return signal, true
+}
+
+// reductionRiskSignals 近期重要股东减持
+func reductionRiskSignals(records []map[string]any, today time.Time) []models.RiskSignal {
+	var signals []models.RiskSignal
+	for _, row := range records {
+		data := normalizedRecord(row)
+		if !strings.Contains(toStringLocal(data["direction"]), "减持") {
+			continue
+		}
+		date := recordDate(data["noticeDate"])
+		if date == "" {
+			date = recordDate(data["endDate"])
+		}
+		days, ok := riskDaysFrom(today, date)
+		if !ok || days > 0 || -days > riskReductionDays {
+			continue
+		}
+		ratio := toFloat(data["changeFreeRatio"])
+		signal := models.RiskSignal{Type: "reduction", Date: date, Level: "medium", Score: 12}
+		if ratio >= 1 {
+			signal.Level, signal.Score = "high", 25
+		}
+		signal.Title = fmt.Sprintf("%s减持，占流通股%.2f%%", toStringLocal(data["holderName"]), ratio)
+		signals = append(signals, signal)
+	}
+	return signals
+}
+
+// stNameRiskSignals 根据证券简称识别风险警示与退市整理
+func stNameRiskSignals(name string) []models.RiskSignal {
+	upper := strings.ToUpper(name)
+	switch {
+	case strings.HasSuffix(name, "退"):
+		return []models.RiskSignal{{Type: "st", Level: "high", Score: 50, Title: "处于退市整理期"}}
+	case strings.Contains(upper, "ST"):
+		return []models.RiskSignal{{Type: "st", Level: "high", Score: 40, Title: "已被实施风险警示"}}
+	}
+	return nil
+}
+
+// announcementRiskSignals 从近期公告中识别 ST/退市风险与减持计划
+func announcementRiskSignals(items []models.StockAnnouncement, today time.Time) []models.RiskSignal {
+	var signals []models.RiskSignal
+	for _, item := range items {
+		date := announceDate(item.NoticeDate)
+		days, ok := riskDaysFrom(today, date)
+		if !ok || -days > riskAnnouncementDays {
+			continue
+		}
+		switch classifyAnnouncement(item.Title).category {
+		case AnnounceSTRisk:
+			signals = append(signals, models.RiskSignal{Type: "st", Level: "high", Score: 40, Title: item.Title, Date: date})
+		case AnnounceReduction:
+			if strings.Contains(item.Title, "计划") {
+				signals = append(signals, models.RiskSignal{Type: "reduction", Level: "medium", Score: 15, Title: item.Title, Date: date})
+			}
+		}
+	}
+	return signals
+}
+
+// scoreRiskProfile 汇总风险分（上限100）并确定风险等级
+func scoreRiskProfile(profile *models.StockRiskProfile) {
+	sort.SliceStable(profile.Signals, func(i, j int) bool {
+		return profile.Signals[i].Score > profile.Signals[j].Score
+	})
+	score := 0.0
+	for _, signal := range profile.Signals {
+		score += signal.Score
+	}
+	if score > 100 {
+		score = 100
+	}
+	profile.Score = score
+	switch {
+	case score >= 40:
+		profile.Level = "high"
+	case score >= 15:
+		profile.Level = "medium"
+	case score > 0:
+		profile.Level = "low"
+	default:
+		profile.Level = "none"
+	}
+	if profile.Signals == nil {
+		profile.Signals = []models.RiskSignal{}
+	}
+}
+
+// riskDaysFrom 计算日期距今天数（未来为正）
+func riskDaysFrom(today time.Time, date string) (int, bool) {
+	t, err := time.ParseInLocation("2006-01-02", date, today.Location())
+	if err != nil {
+		return 0, false
+	}
+	base := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	return int(t.Sub(base).Hours() / 24), true
+}
+
+// riskSignalKey 风险信号去重键（解禁与质押标题含变动数值，按等级区分）
+func riskSignalKey(code string, signal models.RiskSignal) string {
+	switch signal.Type {
+	case "lockup":
+		return strings.Join([]string{code, signal.Type, signal.Level, signal.Date}, "|")
+	case "pledge":
+		return strings.Join([]string{code, signal.Type, signal.Level}, "|")
+	}
+	return strings.Join([]string{code, signal.Type, signal.Title, signal.Date}, "|")
+}
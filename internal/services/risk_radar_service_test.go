@@ -0,0 +1,58 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestRiskSignals(t *testing.T) {
+	today := time.Date(2025, 10, 20, 9, 0, 0, 0, time.FixedZone("CST", 8*3600))
+
+	lockup := lockupRiskSignals([]map[string]any{
+		{"normalized": map[string]any{"freeDate": "2025-11-03 00:00:00", "freeRatio": 0.125, "freeSharesType": "首发原股东限售股份"}},
+		{"normalized": map[string]any{"freeDate": "2025-12-29", "freeRatio": 0.05}},
+		{"normalized": map[string]any{"freeDate": "2025-09-01", "freeRatio": 0.3}},
+	}, today)
+	if len(lockup) != 2 || lockup[0].Level != "high" || lockup[0].Score != 30 || lockup[1].Level != "medium" || lockup[1].Score != 9 {
+		t.Fatalf("unexpected lockup signals: %+v", lockup)
+	}
+
+	pledge, ok := pledgeRiskSignal(map[string]any{"normalized": map[string]any{"pledgeRatio": 35.0, "yearChangeRate": -42.0}})
+	if !ok || pledge.Level != "high" || pledge.Score != 33 || pledge.Detail == "" {
+		t.Fatalf("unexpected pledge signal: %+v", pledge)
+	}
+	if _, ok := pledgeRiskSignal(map[string]any{"normalized": map[string]any{"pledgeRatio": 8.0}}); ok {
+		t.Fatal("low pledge ratio should not produce a signal")
+	}
+
+	reduction := reductionRiskSignals([]map[string]any{
+		{"normalized": map[string]any{"direction": "减持", "holderName": "某投资", "changeFreeRatio": 1.5, "noticeDate": "2025-10-10"}},
+		{"normalized": map[string]any{"direction": "增持", "holderName": "控股股东", "noticeDate": "2025-10-12"}},
+		{"normalized": map[string]any{"direction": "减持", "holderName": "老股东", "noticeDate": "2025-03-01"}},
+	}, today)
+	if len(reduction) != 1 || reduction[0].Level != "high" {
+		t.Fatalf("unexpected reduction signals: %+v", reduction)
+	}
+
+	announcements := announcementRiskSignals([]models.StockAnnouncement{
+		{Title: "关于公司股票可能被终止上市的风险提示公告", NoticeDate: "2025-10-15 00:00:00"},
+		{Title: "关于股东减持股份计划的公告", NoticeDate: "2025-10-16 00:00:00"},
+		{Title: "关于股东减持股份结果的公告", NoticeDate: "2025-10-17 00:00:00"},
+	}, today)
+	if len(announcements) != 2 || announcements[0].Type != "st" || announcements[1].Type != "reduction" {
+		t.Fatalf("unexpected announcement signals: %+v", announcements)
+	}
+
+	profile := models.StockRiskProfile{Signals: append(append(lockup, pledge), stNameRiskSignals("*ST某某")...)}
+	scoreRiskProfile(&profile)
+	if profile.Score != 100 || profile.Level != "high" || profile.Signals[0].Type != "st" {
+		t.Fatalf("unexpected profile: %+v", profile)
+	}
+	empty := models.StockRiskProfile{}
+	scoreRiskProfile(&empty)
+	if empty.Level != "none" || empty.Signals == nil {
+		t.Fatalf("unexpected empty profile: %+v", empty)
+	}
+}
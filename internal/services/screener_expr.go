@@ -0,0 +1,423 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// screenExpr 选股表达式语法树节点
+// 求值采用三值逻辑：字段缺失时结果为未知，便于先用行情字段初筛、再补充明细字段。
+type screenExpr interface {
+	isBool() bool
+	eval(env screenEnv) screenValue
+	collectFields(fields map[string]bool)
+}
+
+// screenEnv 字段取值环境，第二个返回值表示字段是否可用
+type screenEnv func(name string) (float64, bool)
+
+type screenValue struct {
+	num   float64
+	truth bool
+	known bool
+}
+
+type screenNumber struct{ value float64 }
+
+type screenField struct{ name string }
+
+type screenUnary struct {
+	op      string
+	operand screenExpr
+}
+
+type screenBinary struct {
+	op          string
+	left, right screenExpr
+}
+
+func (n screenNumber) isBool() bool { return false }
+func (n screenNumber) eval(screenEnv) screenValue {
+	return screenValue{num: n.value, known: true}
+}
+func (n screenNumber) collectFields(map[string]bool) {}
+
+func (n screenField) isBool() bool { return false }
+func (n screenField) eval(env screenEnv) screenValue {
+	v, ok := env(n.name)
+	return screenValue{num: v, known: ok}
+}
+func (n screenField) collectFields(fields map[string]bool) { fields[n.name] = true }
+
+func (n screenUnary) isBool() bool { return n.op == "not" }
+func (n screenUnary) eval(env screenEnv) screenValue {
+	v := n.operand.eval(env)
+	if !v.known {
+		return v
+	}
+	if n.op == "not" {
+		return screenValue{truth: !v.truth, known: true}
+	}
+	return screenValue{num: -v.num, known: true}
+}
+func (n screenUnary) collectFields(fields map[string]bool) { n.operand.collectFields(fields) }
+
+func (n screenBinary) isBool() bool {
+	switch n.op {
+	case "+", "-", "*", "/":
+		return false
+	}
+	return true
+}
+
+func (n screenBinary) eval(env screenEnv) screenValue {
+	switch n.op {
+	case "and":
+		l := n.left.eval(env)
+		if l.known && !l.truth {
+			return screenValue{known: true}
+		}
+		r := n.right.eval(env)
+		if r.known && !r.truth {
+			return screenValue{known: true}
+		}
+		return screenValue{truth: true, known: l.known && r.known}
+	case "or":
+		l := n.left.eval(env)
+		if l.known && l.truth {
+			return screenValue{truth: true, known: true}
+		}
+		r := n.right.eval(env)
+		if r.known && r.truth {
+			return screenValue{truth: true, known: true}
+		}
+		return screenValue{known: l.known && r.known}
+	}
+
+	l := n.left.eval(env)
+	r := n.right.eval(env)
+	if !l.known || !r.known {
+		return screenValue{}
+	}
+	switch n.op {
+	case "+":
+		return screenValue{num: l.num + r.num, known: true}
+	case "-":
+		return screenValue{num: l.num - r.num, known: true}
+	case "*":
+		return screenValue{num: l.num * r.num, known: true}
+	case "/":
+		if r.num == 0 {
+			return screenValue{}
+		}
+		return screenValue{num: l.num / r.num, known: true}
+	case "<":
+		return screenValue{truth: l.num < r.num, known: true}
+	case "<=":
+		return screenValue{truth: l.num <= r.num, known: true}
+	case ">":
+		return screenValue{truth: l.num > r.num, known: true}
+	case ">=":
+		return screenValue{truth: l.num >= r.num, known: true}
+	case "==":
+		return screenValue{truth: l.num == r.num, known: true}
+	case "!=":
+		return screenValue{truth: l.num != r.num, known: true}
+	}
+	return screenValue{}
+}
+
+func (n screenBinary) collectFields(fields map[string]bool) {
+	n.left.collectFields(fields)
+	n.right.collectFields(fields)
+}
+
+type screenToken struct {
+	kind string // num / ident / op / eof
+	text string
+	pos  int
+}
+
+// tokenizeScreenExpr 词法分析
+func tokenizeScreenExpr(input string) ([]screenToken, error) {
+	var tokens []screenToken
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		ch := runes[i]
+		switch {
+		case unicode.IsSpace(ch):
+			i++
+		case unicode.IsDigit(ch) || (ch == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, screenToken{kind: "num", text: string(runes[start:i]), pos: start})
+			// 数值后的百分号仅作为书写习惯，按原值处理（如 roe > 15%）
+			if i < len(runes) && runes[i] == '%' {
+				i++
+			}
+		case unicode.IsLetter(ch) || ch == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			word := strings.ToLower(string(runes[start:i]))
+			switch word {
+			case "and", "or", "not":
+				tokens = append(tokens, screenToken{kind: "op", text: word, pos: start})
+			default:
+				tokens = append(tokens, screenToken{kind: "ident", text: word, pos: start})
+			}
+		default:
+			two := ""
+			if i+1 < len(runes) {
+				two = string(runes[i : i+2])
+			}
+			switch two {
+			case "<=", ">=", "==", "!=":
+				tokens = append(tokens, screenToken{kind: "op", text: two, pos: i})
+				i += 2
+				continue
+			case "&&":
+				tokens = append(tokens, screenToken{kind: "op", text: "and", pos: i})
+				i += 2
+				continue
+			case "||":
+				tokens = append(tokens, screenToken{kind: "op", text: "or", pos: i})
+				i += 2
+				continue
+			}
+			switch ch {
+			case '<', '>', '+', '-', '*', '/', '(', ')':
+				tokens = append(tokens, screenToken{kind: "op", text: string(ch), pos: i})
+			case '=':
+				tokens = append(tokens, screenToken{kind: "op", text: "==", pos: i})
+			case '!':
+				tokens = append(tokens, screenToken{kind: "op", text: "not", pos: i})
+			default:
+				return nil, fmt.Errorf("表达式第 %d 个字符无法识别: %q", i+1, ch)
+			}
+			i++
+		}
+	}
+	tokens = append(tokens, screenToken{kind: "eof", pos: len(runes)})
+	return tokens, nil
+}
+
+type screenParser struct {
+	tokens []screenToken
+	pos    int
+	fields map[string]bool
+}
+
+// parseScreenExpr 解析选股表达式，fields 为允许使用的字段集合
+func parseScreenExpr(input string, fields map[string]bool) (screenExpr, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, fmt.Errorf("筛选表达式为空")
+	}
+	tokens, err := tokenizeScreenExpr(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &screenParser{tokens: tokens, fields: fields}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != "eof" {
+		return nil, fmt.Errorf("表达式第 %d 个字符附近存在多余内容: %s", tok.pos+1, tok.text)
+	}
+	if !expr.isBool() {
+		return nil, fmt.Errorf("表达式必须是条件判断，如 pe < 20")
+	}
+	return expr, nil
+}
+
+func (p *screenParser) peek() screenToken {
+	return p.tokens[p.pos]
+}
+
+func (p *screenParser) next() screenToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != "eof" {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *screenParser) acceptOp(ops ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != "op" {
+		return "", false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *screenParser) parseOr() (screenExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOp("or"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if !left.isBool() || !right.isBool() {
+			return nil, fmt.Errorf("or 两侧必须是条件判断")
+		}
+		left = screenBinary{op: "or", left: left, right: right}
+	}
+}
+
+func (p *screenParser) parseAnd() (screenExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOp("and"); !ok {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if !left.isBool() || !right.isBool() {
+			return nil, fmt.Errorf("and 两侧必须是条件判断")
+		}
+		left = screenBinary{op: "and", left: left, right: right}
+	}
+}
+
+func (p *screenParser) parseNot() (screenExpr, error) {
+	if _, ok := p.acceptOp("not"); ok {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if !operand.isBool() {
+			return nil, fmt.Errorf("not 后必须是条件判断")
+		}
+		return screenUnary{op: "not", operand: operand}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *screenParser) parseCompare() (screenExpr, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.acceptOp("<", "<=", ">", ">=", "==", "!=")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if left.isBool() || right.isBool() {
+		return nil, fmt.Errorf("比较运算 %s 两侧必须是数值", op)
+	}
+	return screenBinary{op: op, left: left, right: right}, nil
+}
+
+func (p *screenParser) parseSum() (screenExpr, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOp("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		if left.isBool() || right.isBool() {
+			return nil, fmt.Errorf("算术运算 %s 两侧必须是数值", op)
+		}
+		left = screenBinary{op: op, left: left, right: right}
+	}
+}
+
+func (p *screenParser) parseTerm() (screenExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOp("*", "/")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if left.isBool() || right.isBool() {
+			return nil, fmt.Errorf("算术运算 %s 两侧必须是数值", op)
+		}
+		left = screenBinary{op: op, left: left, right: right}
+	}
+}
+
+func (p *screenParser) parseUnary() (screenExpr, error) {
+	if _, ok := p.acceptOp("-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if operand.isBool() {
+			return nil, fmt.Errorf("负号后必须是数值")
+		}
+		return screenUnary{op: "-", operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *screenParser) parsePrimary() (screenExpr, error) {
+	tok := p.next()
+	switch tok.kind {
+	case "num":
+		v, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("无效数值: %s", tok.text)
+		}
+		return screenNumber{value: v}, nil
+	case "ident":
+		if p.fields != nil && !p.fields[tok.text] {
+			return nil, fmt.Errorf("未知字段: %s", tok.text)
+		}
+		return screenField{name: tok.text}, nil
+	case "op":
+		if tok.text == "(" {
+			expr, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.acceptOp(")"); !ok {
+				return nil, fmt.Errorf("缺少右括号")
+			}
+			return expr, nil
+		}
+	case "eof":
+		return nil, fmt.Errorf("表达式不完整")
+	}
+	return nil, fmt.Errorf("表达式第 %d 个字符附近语法错误: %s", tok.pos+1, tok.text)
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+func TestParseScreenExpr(t *testing.T) {
+	fields := screenFieldSet()
+	metrics := map[string]float64{
+		"pe":          15,
+		"roe":         18,
+		"main_net_5d": 1200,
+		"close":       10.5,
+		"ma20":        10,
+	}
+
+	tests := []struct {
+		name  string
+		expr  string
+		truth bool
+	}{
+		{name: "and", expr: "pe < 20 AND roe > 15%", truth: true},
+		{name: "or", expr: "pe > 30 or roe >= 18", truth: true},
+		{name: "not", expr: "not (close > ma20)", truth: false},
+		{name: "arith", expr: "close / ma20 - 1 > 0.04", truth: true},
+		{name: "symbols", expr: "pe<20 && main_net_5d>0 || pe == 0", truth: true},
+		{name: "negative", expr: "-pe < -10", truth: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parseScreenExpr(tc.expr, fields)
+			if err != nil {
+				t.Fatalf("parse %q: %v", tc.expr, err)
+			}
+			v := expr.eval(screenEnvOf(metrics))
+			if !v.known || v.truth != tc.truth {
+				t.Fatalf("eval %q = %+v, want truth=%v", tc.expr, v, tc.truth)
+			}
+		})
+	}
+}
+
+func TestParseScreenExprErrors(t *testing.T) {
+	fields := screenFieldSet()
+	for _, expr := range []string{"", "foo > 1", "pe +", "pe > 1)", "pe", "(pe > 1) + 2 > 0", "pe > 1 and 2"} {
+		if _, err := parseScreenExpr(expr, fields); err == nil {
+			t.Errorf("parse %q: expected error", expr)
+		}
+	}
+}
+
+func TestScreenExprUnknownFields(t *testing.T) {
+	expr, err := parseScreenExpr("pe < 20 and close > ma20", screenFieldSet())
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	// 行情字段不满足时，无需明细即可排除
+	v := expr.eval(screenEnvOf(map[string]float64{"pe": 30}))
+	if !v.known || v.truth {
+		t.Fatalf("expected known false, got %+v", v)
+	}
+
+	// 行情字段满足但缺少技术面字段时，结果未知
+	v = expr.eval(screenEnvOf(map[string]float64{"pe": 10}))
+	if v.known {
+		t.Fatalf("expected unknown, got %+v", v)
+	}
+}
+
+func TestComputeScreenTechnicals(t *testing.T) {
+	klines := make([]models.KLineData, 0, 25)
+	for i := 1; i <= 25; i++ {
+		price := float64(i)
+		klines = append(klines, models.KLineData{Close: price, High: price + 0.5, Low: price - 0.5, Volume: 100})
+	}
+	klines[24].Volume = 300
+
+	metrics := computeScreenTechnicals(klines)
+	want := map[string]float64{
+		"close":        25,
+		"ma5":          23,
+		"ma20":         15.5,
+		"ret_5d":       25,
+		"ret_20d":      400,
+		"high_20d":     24.5,
+		"low_20d":      4.5,
+		"vol_ratio_5d": 3,
+	}
+	for key, expected := range want {
+		if got, ok := metrics[key]; !ok || got != expected {
+			t.Errorf("%s = %v (ok=%v), want %v", key, got, ok, expected)
+		}
+	}
+	if _, ok := metrics["ma60"]; ok {
+		t.Errorf("ma60 should be absent with only 25 bars")
+	}
+}
@@ -0,0 +1,741 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/run-bigpig/jcp/internal/logger"
+	"github.com/run-bigpig/jcp/internal/models"
+)
+
+var screenerLog = logger.New("screener")
+
+const (
+	screenerQuoteCacheTTL     = 60 * time.Second
+	screenerDetailConcurrency = 8
+	screenerMaxCandidates     = 300
+	screenerDefaultLimit      = 50
+	screenerMaxLimit          = 200
+	screenerScheduleTick      = time.Minute
+)
+
+// 字段数据来源
+const (
+	screenSourceQuote     = "quote"
+	screenSourceFundFlow  = "fundflow"
+	screenSourceKLine     = "kline"
+	screenSourceIndicator = "indicator"
+)
+
+// screenQuoteColumn 行情快照字段与东财 clist 字段的映射
+type screenQuoteColumn struct {
+	key   string
+	scale float64
+}
+
+var screenQuoteColumns = map[string]screenQuoteColumn{
+	"price":          {"f2", 1},
+	"change_pct":     {"f3", 1},
+	"amplitude":      {"f7", 1},
+	"turnover":       {"f8", 1},
+	"volume_ratio":   {"f10", 1},
+	"pe":             {"f115", 1},
+	"pe_dynamic":     {"f9", 1},
+	"pb":             {"f23", 1},
+	"total_mv":       {"f20", 1e-8},
+	"float_mv":       {"f21", 1e-8},
+	"amount":         {"f6", 1e-8},
+	"main_net":       {"f62", 1e-4},
+	"main_net_ratio": {"f184", 1},
+	"change_60d":     {"f24", 1},
+	"change_ytd":     {"f25", 1},
+}
+
+// screenIndicatorKeys 财务指标字段与主要指标报表字段的映射
+var screenIndicatorKeys = map[string][]string{
+	"roe":          {"ROEJQ", "ROE_WEIGHT", "ROEKCJQ"},
+	"gross_margin": {"XSMLL", "GROSS_PROFIT_RATIO"},
+	"net_margin":   {"XSJLL", "NET_PROFIT_RATIO"},
+	"revenue_yoy":  {"TOTALOPERATEREVETZ", "YYZSRGDHBZC"},
+	"profit_yoy":   {"PARENTNETPROFITTZ", "GSJLRTBZZ"},
+	"debt_ratio":   {"ZCFZL", "DEBT_ASSET_RATIO"},
+	"eps":          {"EPSJB", "BASIC_EPS"},
+}
+
+var screenFields = []models.ScreenField{
+	{Name: "price", Label: "最新价", Source: screenSourceQuote},
+	{Name: "change_pct", Label: "涨跌幅(%)", Source: screenSourceQuote},
+	{Name: "amplitude", Label: "振幅(%)", Source: screenSourceQuote},
+	{Name: "turnover", Label: "换手率(%)", Source: screenSourceQuote},
+	{Name: "volume_ratio", Label: "量比", Source: screenSourceQuote},
+	{Name: "pe", Label: "市盈率TTM", Source: screenSourceQuote},
+	{Name: "pe_dynamic", Label: "动态市盈率", Source: screenSourceQuote},
+	{Name: "pb", Label: "市净率", Source: screenSourceQuote},
+	{Name: "total_mv", Label: "总市值(亿)", Source: screenSourceQuote},
+	{Name: "float_mv", Label: "流通市值(亿)", Source: screenSourceQuote},
+	{Name: "amount", Label: "成交额(亿)", Source: screenSourceQuote},
+	{Name: "main_net", Label: "今日主力净流入(万)", Source: screenSourceQuote},
+	{Name: "main_net_ratio", Label: "今日主力净占比(%)", Source: screenSourceQuote},
+	{Name: "change_60d", Label: "60日涨跌幅(%)", Source: screenSourceQuote},
+	{Name: "change_ytd", Label: "年初至今涨跌幅(%)", Source: screenSourceQuote},
+	{Name: "main_net_5d", Label: "5日主力净流入(万)", Source: screenSourceFundFlow},
+	{Name: "main_net_10d", Label: "10日主力净流入(万)", Source: screenSourceFundFlow},
+	{Name: "close", Label: "日线收盘价", Source: screenSourceKLine},
+	{Name: "ma5", Label: "5日均线", Source: screenSourceKLine},
+	{Name: "ma10", Label: "10日均线", Source: screenSourceKLine},
+	{Name: "ma20", Label: "20日均线", Source: screenSourceKLine},
+	{Name: "ma60", Label: "60日均线", Source: screenSourceKLine},
+	{Name: "ret_5d", Label: "5日涨幅(%)", Source: screenSourceKLine},
+	{Name: "ret_20d", Label: "20日涨幅(%)", Source: screenSourceKLine},
+	{Name: "high_20d", Label: "前20日最高价", Source: screenSourceKLine, Description: "不含最新一根K线"},
+	{Name: "low_20d", Label: "前20日最低价", Source: screenSourceKLine, Description: "不含最新一根K线"},
+	{Name: "vol_ratio_5d", Label: "成交量/前5日均量", Source: screenSourceKLine},
+	{Name: "roe", Label: "ROE(%)", Source: screenSourceIndicator},
+	{Name: "gross_margin", Label: "毛利率(%)", Source: screenSourceIndicator},
+	{Name: "net_margin", Label: "净利率(%)", Source: screenSourceIndicator},
+	{Name: "revenue_yoy", Label: "营收同比(%)", Source: screenSourceIndicator},
+	{Name: "profit_yoy", Label: "归母净利同比(%)", Source: screenSourceIndicator},
+	{Name: "debt_ratio", Label: "资产负债率(%)", Source: screenSourceIndicator},
+	{Name: "eps", Label: "基本每股收益", Source: screenSourceIndicator},
+}
+
+// screenBaseMetrics 结果中默认展示的字段
+var screenBaseMetrics = []string{"price", "change_pct", "pe", "pb", "total_mv"}
+
+// ScreenerService 条件选股服务
+type ScreenerService struct {
+	marketService *MarketService
+	f10Service    *F10Service
+	storePath     string
+
+	mu    sync.RWMutex
+	store models.ScreenStore
+
+	quoteMu       sync.Mutex
+	quoteSnapshot map[string]map[string]float64
+	quoteAt       time.Time
+
+	resultHandler func(models.ScreenResult)
+	stopChan      chan struct{}
+	stopOnce      sync.Once
+}
+
+// NewScreenerService 创建选股服务
+func NewScreenerService(dataDir string, marketService *MarketService, f10Service *F10Service) *ScreenerService {
+	s := &ScreenerService{
+		marketService: marketService,
+		f10Service:    f10Service,
+		storePath:     filepath.Join(dataDir, "screener.json"),
+		store:         models.ScreenStore{Results: make(map[string]models.ScreenResult)},
+		stopChan:      make(chan struct{}),
+	}
+	s.load()
+	return s
+}
+
+// load 从文件加载选股方案
+func (s *ScreenerService) load() {
+	data, err := os.ReadFile(s.storePath)
+	if err != nil {
+		return
+	}
+	var store models.ScreenStore
+	if err := json.Unmarshal(data, &store); err != nil {
+		screenerLog.Warn("解析选股方案失败: %v", err)
+		return
+	}
+	if store.Results == nil {
+		store.Results = make(map[string]models.ScreenResult)
+	}
+	s.store = store
+}
+
+// saveLocked 保存选股方案，调用方需持有写锁
+func (s *ScreenerService) saveLocked() error {
+	if err := os.MkdirAll(filepath.Dir(s.storePath), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s.store, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.storePath, data, 0644)
+}
+
+// SetResultHandler 设置定时运行结果回调
+func (s *ScreenerService) SetResultHandler(handler func(models.ScreenResult)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resultHandler = handler
+}
+
+// GetFields 获取可用于筛选表达式的字段
+func (s *ScreenerService) GetFields() []models.ScreenField {
+	return append([]models.ScreenField(nil), screenFields...)
+}
+
+// ValidateExpression 校验筛选表达式
+func (s *ScreenerService) ValidateExpression(expression string) error {
+	_, err := parseScreenExpr(expression, screenFieldSet())
+	return err
+}
+
+// GetScreens 获取所有已保存的选股方案
+func (s *ScreenerService) GetScreens() []models.StockScreen {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]models.StockScreen(nil), s.store.Screens...)
+}
+
+// SaveScreen 新建或更新选股方案
+func (s *ScreenerService) SaveScreen(screen models.StockScreen) (models.StockScreen, error) {
+	screen.Name = strings.TrimSpace(screen.Name)
+	screen.Expression = strings.TrimSpace(screen.Expression)
+	if screen.Name == "" {
+		return screen, fmt.Errorf("方案名称不能为空")
+	}
+	if err := s.ValidateExpression(screen.Expression); err != nil {
+		return screen, err
+	}
+	if screen.SortBy != "" && !screenFieldSet()[screen.SortBy] {
+		return screen, fmt.Errorf("未知排序字段: %s", screen.SortBy)
+	}
+	if screen.Schedule < 0 {
+		screen.Schedule = 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UnixMilli()
+	screen.UpdatedAt = now
+	for i := range s.store.Screens {
+		if s.store.Screens[i].ID == screen.ID && screen.ID != "" {
+			screen.CreatedAt = s.store.Screens[i].CreatedAt
+			screen.LastRunAt = s.store.Screens[i].LastRunAt
+			s.store.Screens[i] = screen
+			return screen, s.saveLocked()
+		}
+	}
+	screen.ID = uuid.New().String()
+	screen.CreatedAt = now
+	s.store.Screens = append(s.store.Screens, screen)
+	return screen, s.saveLocked()
+}
+
+// DeleteScreen 删除选股方案
+func (s *ScreenerService) DeleteScreen(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.store.Screens {
+		if s.store.Screens[i].ID == id {
+			s.store.Screens = append(s.store.Screens[:i], s.store.Screens[i+1:]...)
+			delete(s.store.Results, id)
+			return s.saveLocked()
+		}
+	}
+	return fmt.Errorf("选股方案不存在: %s", id)
+}
+
+// GetLastResult 获取方案最近一次运行结果
+func (s *ScreenerService) GetLastResult(id string) (models.ScreenResult, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result, ok := s.store.Results[id]
+	return result, ok
+}
+
+// RunScreen 运行已保存的选股方案并记录结果
+func (s *ScreenerService) RunScreen(ctx context.Context, id string) (models.ScreenResult, error) {
+	s.mu.RLock()
+	var screen *models.StockScreen
+	for i := range s.store.Screens {
+		if s.store.Screens[i].ID == id {
+			copied := s.store.Screens[i]
+			screen = &copied
+			break
+		}
+	}
+	s.mu.RUnlock()
+	if screen == nil {
+		return models.ScreenResult{}, fmt.Errorf("选股方案不存在: %s", id)
+	}
+
+	result, err := s.Run(ctx, screen.Expression, screen.SortBy, screen.SortDesc, screen.Limit)
+	if err != nil {
+		return result, err
+	}
+	result.ScreenID = id
+
+	s.mu.Lock()
+	for i := range s.store.Screens {
+		if s.store.Screens[i].ID == id {
+			s.store.Screens[i].LastRunAt = result.RunAt
+			break
+		}
+	}
+	s.store.Results[id] = result
+	if err := s.saveLocked(); err != nil {
+		screenerLog.Warn("保存选股结果失败: %v", err)
+	}
+	s.mu.Unlock()
+	return result, nil
+}
+
+// Run 按表达式执行选股
+// 先用全市场行情快照初筛，再仅对候选股补充资金流/K线/财务指标明细。
+func (s *ScreenerService) Run(ctx context.Context, expression string, sortBy string, sortDesc bool, limit int) (models.ScreenResult, error) {
+	fieldSet := screenFieldSet()
+	expr, err := parseScreenExpr(expression, fieldSet)
+	if err != nil {
+		return models.ScreenResult{}, err
+	}
+	sortBy = strings.ToLower(strings.TrimSpace(sortBy))
+	if sortBy == "" {
+		sortBy = "amount"
+		sortDesc = true
+	} else if !fieldSet[sortBy] {
+		return models.ScreenResult{}, fmt.Errorf("未知排序字段: %s", sortBy)
+	}
+	if limit <= 0 {
+		limit = screenerDefaultLimit
+	}
+	if limit > screenerMaxLimit {
+		limit = screenerMaxLimit
+	}
+
+	used := make(map[string]bool)
+	expr.collectFields(used)
+	used[sortBy] = true
+	sources := screenSourcesOf(used)
+
+	result := models.ScreenResult{
+		Expression: expression,
+		RunAt:      time.Now().UnixMilli(),
+		Errors:     make(map[string]string),
+	}
+
+	catalog := loadEmbeddedStockCatalog()
+	if len(catalog) == 0 {
+		return result, fmt.Errorf("股票目录为空")
+	}
+	result.Universe = len(catalog)
+
+	quotes, err := s.getQuoteSnapshot()
+	if err != nil {
+		if sources[screenSourceQuote] {
+			return result, fmt.Errorf("获取行情快照失败: %w", err)
+		}
+		result.Errors[screenSourceQuote] = err.Error()
+	}
+
+	type screenRow struct {
+		stock   StockSearchResult
+		metrics map[string]float64
+	}
+
+	var matched, candidates []screenRow
+	needDetail := sources[screenSourceFundFlow] || sources[screenSourceKLine] || sources[screenSourceIndicator]
+	for _, stock := range catalog {
+		metrics := make(map[string]float64)
+		for k, v := range quotes[normalizeStockListCode(stock.Symbol)] {
+			metrics[k] = v
+		}
+		v := expr.eval(screenEnvOf(metrics))
+		switch {
+		case v.known && !v.truth:
+			continue
+		case v.known && v.truth && !needDetail:
+			matched = append(matched, screenRow{stock: stock, metrics: metrics})
+		default:
+			candidates = append(candidates, screenRow{stock: stock, metrics: metrics})
+		}
+	}
+
+	if needDetail {
+		// 候选过多时优先保留成交活跃的股票，避免逐只请求明细耗时过长
+		if len(candidates) > screenerMaxCandidates {
+			sort.SliceStable(candidates, func(i, j int) bool {
+				return candidates[i].metrics["amount"] > candidates[j].metrics["amount"]
+			})
+			result.Errors["candidates"] = fmt.Sprintf("初筛候选 %d 只，仅对成交额前 %d 只补充明细，建议增加行情类条件缩小范围", len(candidates), screenerMaxCandidates)
+			candidates = candidates[:screenerMaxCandidates]
+		}
+		result.Candidates = len(candidates)
+
+		var (
+			wg       sync.WaitGroup
+			failMu   sync.Mutex
+			failures = make(map[string]int)
+			sem      = make(chan struct{}, screenerDetailConcurrency)
+			detailed = make([]map[string]float64, len(candidates))
+		)
+		for i := range candidates {
+			if ctx.Err() != nil {
+				break
+			}
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(i int) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				metrics, failed := s.fetchDetailMetrics(candidates[i].stock.Symbol, sources)
+				detailed[i] = metrics
+				if len(failed) > 0 {
+					failMu.Lock()
+					for _, source := range failed {
+						failures[source]++
+					}
+					failMu.Unlock()
+				}
+			}(i)
+		}
+		wg.Wait()
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		for source, count := range failures {
+			result.Errors[source] = fmt.Sprintf("%d 只股票获取数据失败，已跳过", count)
+		}
+
+		for i, row := range candidates {
+			for k, v := range detailed[i] {
+				row.metrics[k] = v
+			}
+			if v := expr.eval(screenEnvOf(row.metrics)); v.known && v.truth {
+				matched = append(matched, row)
+			}
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		vi, okI := matched[i].metrics[sortBy]
+		vj, okJ := matched[j].metrics[sortBy]
+		if okI != okJ {
+			return okI
+		}
+		if sortDesc {
+			return vi > vj
+		}
+		return vi < vj
+	})
+
+	result.Matched = len(matched)
+	if len(matched) > limit {
+		matched = matched[:limit]
+	}
+	result.Items = make([]models.ScreenMatch, 0, len(matched))
+	for _, row := range matched {
+		metrics := make(map[string]float64)
+		for _, name := range screenBaseMetrics {
+			if v, ok := row.metrics[name]; ok {
+				metrics[name] = v
+			}
+		}
+		for name := range used {
+			if v, ok := row.metrics[name]; ok {
+				metrics[name] = v
+			}
+		}
+		result.Items = append(result.Items, models.ScreenMatch{
+			Code:     row.stock.Symbol,
+			Name:     row.stock.Name,
+			Industry: row.stock.Industry,
+			Metrics:  metrics,
+		})
+	}
+	if len(result.Errors) == 0 {
+		result.Errors = nil
+	}
+	screenerLog.Info("选股完成: expr=%s, candidates=%d, matched=%d", expression, result.Candidates, result.Matched)
+	return result, nil
+}
+
+// Start 启动定时选股任务
+func (s *ScreenerService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(screenerScheduleTick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.stopChan:
+				return
+			case <-ticker.C:
+				s.runDueScreens(ctx)
+			}
+		}
+	}()
+}
+
+// Stop 停止定时选股任务
+func (s *ScreenerService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopChan)
+	})
+}
+
+// runDueScreens 运行到期的定时方案
+func (s *ScreenerService) runDueScreens(ctx context.Context) {
+	now := time.Now()
+	var due []models.StockScreen
+	s.mu.RLock()
+	for _, screen := range s.store.Screens {
+		if !screen.Enabled || screen.Schedule <= 0 {
+			continue
+		}
+		next := time.UnixMilli(screen.LastRunAt).Add(time.Duration(screen.Schedule) * time.Minute)
+		if screen.LastRunAt == 0 || !now.Before(next) {
+			due = append(due, screen)
+		}
+	}
+	handler := s.resultHandler
+	s.mu.RUnlock()
+
+	for _, screen := range due {
+		if screen.TradingOnly && s.marketService != nil && s.marketService.GetMarketStatus().Status != "trading" {
+			continue
+		}
+		result, err := s.RunScreen(ctx, screen.ID)
+		if err != nil {
+			screenerLog.Warn("定时选股失败 [%s]: %v", screen.Name, err)
+			continue
+		}
+		if handler != nil {
+			handler(result)
+		}
+	}
+}
+
+// getQuoteSnapshot 获取全市场行情快照（按纯数字代码索引）
+func (s *ScreenerService) getQuoteSnapshot() (map[string]map[string]float64, error) {
+	s.quoteMu.Lock()
+	defer s.quoteMu.Unlock()
+	if s.quoteSnapshot != nil && time.Since(s.quoteAt) < screenerQuoteCacheTTL {
+		return s.quoteSnapshot, nil
+	}
+	if s.marketService == nil {
+		return nil, fmt.Errorf("Market 服务未初始化")
+	}
+
+	rows, err := s.marketService.fetchAShareQuotes(screenQuoteFields())
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := make(map[string]map[string]float64, len(rows))
+	for _, row := range rows {
+		code := strings.TrimSpace(toStringLocal(row["f12"]))
+		if code == "" {
+			continue
+		}
+		metrics := make(map[string]float64)
+		for name, col := range screenQuoteColumns {
+			if v, ok := screenNumberAny(row[col.key]); ok {
+				metrics[name] = v * col.scale
+			}
+		}
+		snapshot[code] = metrics
+	}
+	s.quoteSnapshot = snapshot
+	s.quoteAt = time.Now()
+	return snapshot, nil
+}
+
+// screenQuoteFields 行情快照需要请求的东财字段
+func screenQuoteFields() []string {
+	keys := make([]string, 0, len(screenQuoteColumns)+1)
+	keys = append(keys, "f12")
+	for _, col := range screenQuoteColumns {
+		keys = append(keys, col.key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// fetchDetailMetrics 获取单只股票的明细字段，返回失败的数据源
+func (s *ScreenerService) fetchDetailMetrics(code string, sources map[string]bool) (map[string]float64, []string) {
+	metrics := make(map[string]float64)
+	var failed []string
+
+	if sources[screenSourceKLine] && s.marketService != nil {
+		klines, err := s.marketService.GetKLineData(code, "1d", 80)
+		if err != nil || len(klines) == 0 {
+			failed = append(failed, screenSourceKLine)
+		} else {
+			for k, v := range computeScreenTechnicals(klines) {
+				metrics[k] = v
+			}
+		}
+	}
+
+	if sources[screenSourceFundFlow] && s.f10Service != nil {
+		flow, err := s.f10Service.GetFundFlowByCode(code)
+		if err != nil && len(flow.Lines) == 0 {
+			failed = append(failed, screenSourceFundFlow)
+		} else {
+			for k, v := range computeScreenFundFlow(flow) {
+				metrics[k] = v
+			}
+		}
+	}
+
+	if sources[screenSourceIndicator] && s.f10Service != nil {
+		indicators, err := s.f10Service.GetMainIndicators(code)
+		if err != nil || len(indicators.Latest) == 0 {
+			failed = append(failed, screenSourceIndicator)
+		} else {
+			latest := indicators.Latest[0]
+			for name, keys := range screenIndicatorKeys {
+				for _, key := range keys {
+					if v, ok := screenNumberAny(latest[key]); ok {
+						metrics[name] = v
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return metrics, failed
+}
+
+// computeScreenTechnicals 由日K线计算技术指标
+func computeScreenTechnicals(klines []models.KLineData) map[string]float64 {
+	metrics := make(map[string]float64)
+	n := len(klines)
+	if n == 0 {
+		return metrics
+	}
+	last := klines[n-1]
+	metrics["close"] = last.Close
+
+	for _, period := range []int{5, 10, 20, 60} {
+		if n < period {
+			continue
+		}
+		var sum float64
+		for _, k := range klines[n-period:] {
+			sum += k.Close
+		}
+		metrics[fmt.Sprintf("ma%d", period)] = round2(sum / float64(period))
+	}
+
+	for _, period := range []int{5, 20} {
+		if n > period && klines[n-1-period].Close > 0 {
+			base := klines[n-1-period].Close
+			metrics[fmt.Sprintf("ret_%dd", period)] = round2((last.Close/base - 1) * 100)
+		}
+	}
+
+	if n > 20 {
+		high, low := math.Inf(-1), math.Inf(1)
+		for _, k := range klines[n-21 : n-1] {
+			high = math.Max(high, k.High)
+			low = math.Min(low, k.Low)
+		}
+		metrics["high_20d"] = high
+		metrics["low_20d"] = low
+	}
+
+	if n > 5 {
+		var sum float64
+		for _, k := range klines[n-6 : n-1] {
+			sum += float64(k.Volume)
+		}
+		if sum > 0 {
+			metrics["vol_ratio_5d"] = round2(float64(last.Volume) / (sum / 5))
+		}
+	}
+	return metrics
+}
+
+// computeScreenFundFlow 由日度资金流计算多日主力净流入（万元）
+func computeScreenFundFlow(flow models.FundFlowSeries) map[string]float64 {
+	metrics := make(map[string]float64)
+	idx := -1
+	for i, field := range flow.Fields {
+		if flow.Labels[field] == "mainNet" {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return metrics
+	}
+	// Lines 已按日期倒序，lines[0] 为最近交易日
+	var sum float64
+	for i, line := range flow.Lines {
+		if i >= 10 || idx >= len(line) {
+			break
+		}
+		sum += parseFloat64Safe(line[idx])
+		if i == 4 {
+			metrics["main_net_5d"] = round2(sum / 1e4)
+		}
+	}
+	if len(flow.Lines) >= 10 {
+		metrics["main_net_10d"] = round2(sum / 1e4)
+	}
+	return metrics
+}
+
+func screenFieldSet() map[string]bool {
+	set := make(map[string]bool, len(screenFields))
+	for _, f := range screenFields {
+		set[f.Name] = true
+	}
+	return set
+}
+
+func screenSourcesOf(used map[string]bool) map[string]bool {
+	sources := make(map[string]bool)
+	for _, f := range screenFields {
+		if used[f.Name] {
+			sources[f.Source] = true
+		}
+	}
+	return sources
+}
+
+func screenEnvOf(metrics map[string]float64) screenEnv {
+	return func(name string) (float64, bool) {
+		v, ok := metrics[name]
+		return v, ok
+	}
+}
+
+// screenNumberAny 解析数值，东财缺失值为 "-"
+func screenNumberAny(value any) (float64, bool) {
+	switch v := value.(type) {
+	case nil:
+		return 0, false
+	case float64:
+		return v, true
+	case string:
+		v = strings.TrimSpace(v)
+		if v == "" || v == "-" {
+			return 0, false
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	default:
+		return toFloat64Any(value), true
+	}
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
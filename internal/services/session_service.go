@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,6 +15,12 @@ import (
 	"github.com/google/uuid"
 )
 
+// 非单股会话键前缀
+const (
+	compareSessionPrefix = "compare_" // 多股对比会话
+	boardSessionPrefix   = "board_"   // 板块会话
+)
+
 // SessionService Session服务
 type SessionService struct {
 	sessionsDir string
@@ -74,6 +82,69 @@ func (ss *SessionService) GetOrCreateSession(stockCode, stockName string) (*mode
 	return session, ss.saveSession(session)
 }
 
+// CompareSessionKey 生成多股对比会话键（与股票顺序无关）
+func CompareSessionKey(stockCodes []string) string {
+	codes := append([]string(nil), stockCodes...)
+	sort.Strings(codes)
+	return compareSessionPrefix + strings.Join(codes, "_")
+}
+
+// GetOrCreateCompareSession 获取或创建多股对比会话
+func (ss *SessionService) GetOrCreateCompareSession(stockCodes []string, stockNames []string) (*models.StockSession, error) {
+	if len(stockCodes) < 2 {
+		return nil, fmt.Errorf("对比会话至少需要两只股票")
+	}
+	key := CompareSessionKey(stockCodes)
+	name := strings.Join(stockNames, " vs ")
+	if name == "" {
+		name = strings.Join(stockCodes, " vs ")
+	}
+
+	session, err := ss.GetOrCreateSession(key, name)
+	if err != nil {
+		return session, err
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if len(session.CompareCodes) == 0 {
+		session.CompareCodes = append([]string(nil), stockCodes...)
+		session.UpdatedAt = time.Now().UnixMilli()
+		return session, ss.saveSession(session)
+	}
+	return session, nil
+}
+
+// BoardSessionKey 生成板块会话键（同时用作板块记忆键）
+func BoardSessionKey(boardCode string) string {
+	return boardSessionPrefix + strings.ToUpper(strings.TrimSpace(boardCode))
+}
+
+// GetOrCreateBoardSession 获取或创建板块会话
+func (ss *SessionService) GetOrCreateBoardSession(boardCode, boardName string) (*models.StockSession, error) {
+	boardCode = strings.ToUpper(strings.TrimSpace(boardCode))
+	if boardCode == "" {
+		return nil, fmt.Errorf("板块代码为空")
+	}
+	if boardName == "" {
+		boardName = boardCode
+	}
+
+	session, err := ss.GetOrCreateSession(BoardSessionKey(boardCode), boardName)
+	if err != nil {
+		return session, err
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if session.BoardCode == "" {
+		session.BoardCode = boardCode
+		session.UpdatedAt = time.Now().UnixMilli()
+		return session, ss.saveSession(session)
+	}
+	return session, nil
+}
+
 // loadSession 从文件加载Session
 func (ss *SessionService) loadSession(stockCode string) (*models.StockSession, error) {
 	path := ss.getSessionPath(stockCode)
@@ -251,3 +322,36 @@ func (ss *SessionService) GetPosition(stockCode string) *models.StockPosition {
 	}
 	return session.Position
 }
+
+// GetPositionStocks 获取所有有持仓的个股（扫描会话文件）
+func (ss *SessionService) GetPositionStocks() []models.Stock {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	entries, err := os.ReadDir(ss.sessionsDir)
+	if err != nil {
+		return nil
+	}
+	var stocks []models.Stock
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		stockCode := strings.TrimSuffix(name, ".json")
+		if strings.HasPrefix(stockCode, compareSessionPrefix) || strings.HasPrefix(stockCode, boardSessionPrefix) {
+			continue
+		}
+		session, ok := ss.sessions[stockCode]
+		if !ok {
+			if session, err = ss.loadSession(stockCode); err != nil {
+				continue
+			}
+			ss.sessions[stockCode] = session
+		}
+		if session.Position != nil && session.Position.Shares > 0 {
+			stocks = append(stocks, models.Stock{Symbol: session.StockCode, Name: session.StockName})
+		}
+	}
+	return stocks
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"encoding/json"
 	"strings"
+	"sync"
 
 	"github.com/run-bigpig/jcp/internal/embed"
 )
@@ -30,7 +31,11 @@ func searchEmbeddedStocks(keyword string, limit int) []StockSearchResult {
 	if err := json.Unmarshal(embed.StockBasicJSON, &basicData); err != nil {
 		return nil
 	}
+	return searchEmbeddedStocksFrom(basicData, keyword, limit)
+}
 
+// searchEmbeddedStocksFrom 在已解析的目录中按关键词检索，keyword 为空时返回全部
+func searchEmbeddedStocksFrom(basicData stockBasicData, keyword string, limit int) []StockSearchResult {
 	var symbolIdx, nameIdx, industryIdx, tsCodeIdx int = -1, -1, -1, -1
 	for i, field := range basicData.Data.Fields {
 		switch field {
@@ -93,6 +98,23 @@ func searchEmbeddedStocks(keyword string, limit int) []StockSearchResult {
 	return results
 }
 
+var (
+	embeddedCatalogOnce sync.Once
+	embeddedCatalog     []StockSearchResult
+)
+
+// loadEmbeddedStockCatalog 加载内置股票全量目录（仅解析一次）
+func loadEmbeddedStockCatalog() []StockSearchResult {
+	embeddedCatalogOnce.Do(func() {
+		var basicData stockBasicData
+		if err := json.Unmarshal(embed.StockBasicJSON, &basicData); err != nil {
+			return
+		}
+		embeddedCatalog = searchEmbeddedStocksFrom(basicData, "", 0)
+	})
+	return embeddedCatalog
+}
+
 func filterStockCatalog(catalog []StockSearchResult, keyword string, limit int) []StockSearchResult {
 	if keyword == "" {
 		return nil
@@ -42,8 +42,8 @@ func getDefaultStrategyAgents() []models.StrategyAgent {
 			Role:        "基本面研究员",
 			Avatar:      "财",
 			Color:       "#10B981",
-			Instruction: "你是老陈，一位在券商研究所深耕15年的基本面研究员。你说话沉稳务实，喜欢用数据说话。\n\n【分析框架】\n1. 盈利能力：ROE、毛利率、净利率趋势\n2. 成长性：营收/利润增速，行业天花板\n3. 估值水平：PE/PB分位，与同行对比\n4. 财务健康：现金流、负债率、商誉风险\n\n【回复风格】简洁专业，150字以内。先给结论，再用核心数据支撑。",
-			Tools:       []string{"get_research_report", "get_report_content", "get_stock_realtime"},
+			Instruction: "你是老陈，一位在券商研究所深耕15年的基本面研究员。你说话沉稳务实，喜欢用数据说话。\n\n【分析框架】\n1. 盈利能力：ROE、毛利率、净利率趋势\n2. 成长性：营收/利润增速，行业天花板\n3. 估值水平：PE/PB分位、DCF/DDM内在价值（引用 get_intrinsic_value 计算结果），与同行对比（引用 get_peer_comparison 百分位）\n4. 财务健康：现金流、负债率、商誉风险\n\n【回复风格】简洁专业，150字以内。先给结论，再用核心数据支撑。",
+			Tools:       []string{"get_research_report", "get_report_content", "search_report_chunks", "get_stock_realtime", "get_financial_analysis", "get_intrinsic_value", "get_peer_comparison"},
 			Enabled:     true,
 		},
 		{
@@ -63,7 +63,7 @@ func getDefaultStrategyAgents() []models.StrategyAgent {
 			Avatar:      "资",
 			Color:       "#F59E0B",
 			Instruction: "你是钱姐，私募圈出身的资金流向专家。你深谙'跟着主力走'的生存法则。\n\n【分析框架】\n1. 主力动向：大单净流入、主力持仓变化\n2. 北向资金：外资流向、重仓股变化\n3. 筹码分布：集中度、套牢盘、获利盘\n4. 盘口异动：大单托盘、压盘信号\n\n【回复风格】直白实在，150字以内。重点说清资金动向和主力意图。",
-			Tools:       []string{"get_orderbook", "get_stock_realtime", "get_kline_data"},
+			Tools:       []string{"get_orderbook", "get_stock_realtime", "get_kline_data", "query_lhb_seats", "get_northbound_holdings", "get_margin_trading"},
 			Enabled:     true,
 		},
 		{
@@ -580,16 +580,19 @@ func (s *StrategyService) getAgentConfigsFromStrategy() []models.AgentConfig {
 	agents := make([]models.AgentConfig, len(strategy.Agents))
 	for i, sa := range strategy.Agents {
 		agents[i] = models.AgentConfig{
-			ID:          sa.ID,
-			Name:        sa.Name,
-			Role:        sa.Role,
-			Avatar:      sa.Avatar,
-			Color:       sa.Color,
-			Instruction: sa.Instruction,
-			Tools:       sa.Tools,
-			MCPServers:  sa.MCPServers,
-			Enabled:     sa.Enabled,
-			AIConfigID:  sa.AIConfigID,
+			ID:           sa.ID,
+			Name:         sa.Name,
+			Role:         sa.Role,
+			Avatar:       sa.Avatar,
+			Color:        sa.Color,
+			Instruction:  sa.Instruction,
+			Tools:        sa.Tools,
+			MCPServers:   sa.MCPServers,
+			Enabled:      sa.Enabled,
+			AIConfigID:   sa.AIConfigID,
+			Reasoning:    sa.Reasoning,
+			Vision:       sa.Vision,
+			MCPResources: sa.MCPResources,
 		}
 	}
 	return agents